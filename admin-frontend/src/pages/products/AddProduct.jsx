@@ -3,6 +3,7 @@ import { toast, ToastContainer } from "react-toastify";
 import "react-toastify/dist/ReactToastify.css";
 import CategorySearch from "../../components/CategorySearch";
 import { productService } from "../../services/productService";
+import { fromMajor } from "../../utils/money";
 import { useNavigate } from "react-router-dom";
 
 const ProductAdmin = () => {
@@ -144,7 +145,7 @@ const ProductAdmin = () => {
   const normalizeProductData = (data) => {
     return {
       ...data,
-      price: data.price ? Number(data.price) : 0,
+      price: fromMajor(data.price),
       discount: data.discount ? Number(data.discount) : 0,
       stock: data.stock ? Number(data.stock) : 0,
       category_id: data.category_id ? Number(data.category_id) : 0,
@@ -152,7 +153,7 @@ const ProductAdmin = () => {
       quantity_value: data.quantity_value ? Number(data.quantity_value) : 0,
       variants: data.variants.map((variant) => ({
         ...variant,
-        price: variant.price ? Number(variant.price) : 0,
+        price: fromMajor(variant.price),
         stock: variant.stock ? Number(variant.stock) : 0,
         uom_id: variant.uom_id ? Number(variant.uom_id) : 0,
         quantity_value: variant.quantity_value ? Number(variant.quantity_value) : 0,
@@ -1,5 +1,6 @@
 import { useState } from "react";
 import { XMarkIcon } from "@heroicons/react/24/solid";
+import { formatMoney } from "../../utils/money";
 
 const ProductDetailModal = ({ product, onClose }) => {
   const [activeTab, setActiveTab] = useState("details");
@@ -7,10 +8,6 @@ const ProductDetailModal = ({ product, onClose }) => {
   const env=import.meta.env;
   const IMAGE_URL = env.VITE_IMAGE_ROOT;
 
-  const formatPrice = (price, discount = 0) => {
-    const actualPrice = price - (price * discount / 100);
-    return new Intl.NumberFormat('en-US', { style: 'currency', currency: 'INR' }).format(actualPrice);
-  };
 
   const formatDate = (dateString) => {
     return new Date(dateString).toLocaleDateString('en-US', {
@@ -71,11 +68,11 @@ const ProductDetailModal = ({ product, onClose }) => {
                   <h4 className="text-sm font-medium text-gray-500 mb-1">Price</h4>
                   <div className="flex items-center">
                     <p className="text-lg font-semibold text-gray-800">
-                      {formatPrice(product.price, product.discount)}
+                      {formatMoney(product.price, product.discount)}
                     </p>
                     {product.discount > 0 && (
                       <p className="ml-2 text-sm text-gray-500 line-through">
-                        {formatPrice(product.price)}
+                        {formatMoney(product.price)}
                       </p>
                     )}
                   </div>
@@ -225,7 +222,7 @@ const ProductDetailModal = ({ product, onClose }) => {
                             {variant.sku}
                           </td>
                           <td className="px-6 py-4 whitespace-nowrap text-sm text-gray-500">
-                            {formatPrice(variant.price)}
+                            {formatMoney(variant.price)}
                           </td>
                           <td className="px-6 py-4 whitespace-nowrap text-sm text-gray-500">
                             {variant.stock}
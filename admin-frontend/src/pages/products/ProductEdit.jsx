@@ -3,6 +3,7 @@ import { toast, ToastContainer } from "react-toastify";
 import "react-toastify/dist/ReactToastify.css";
 import CategorySearch from "../../components/CategorySearch";
 import { productService } from "../../services/productService";
+import { fromMajor, toMajor } from "../../utils/money";
 import { useNavigate, useParams } from "react-router-dom";
 
 const EditProduct = () => {
@@ -57,7 +58,7 @@ const EditProduct = () => {
           name: data.name || "",
           category_id: data.category_id || "",
           description: data.description || "",
-          price: data.price || "",
+          price: data.price ? toMajor(data.price) : "",
           discount: data.discount || "",
           stock: data.stock || "",
           brand: data.brand || "",
@@ -65,7 +66,10 @@ const EditProduct = () => {
           sku: data.sku || "",
           uom_id: data.uom_id || "",
           quantity_value: data.quantity_value || "",
-          variants: data.variants || [],
+          variants: (data.variants || []).map(variant => ({
+            ...variant,
+            price: variant.price ? toMajor(variant.price) : ""
+          })),
           attributes: data.attributes || []
         });
 
@@ -158,7 +162,7 @@ const EditProduct = () => {
   const normalizeProductData = (data) => {
     return {
       ...data,
-      price: data.price ? Number(data.price) : 0,
+      price: fromMajor(data.price),
       discount: data.discount ? Number(data.discount) : 0,
       stock: data.stock ? Number(data.stock) : 0,
       category_id: data.category_id ? Number(data.category_id) : undefined,
@@ -166,7 +170,7 @@ const EditProduct = () => {
       quantity_value: data.quantity_value ? Number(data.quantity_value) : undefined,
       variants: data.variants.map(variant => ({
         ...variant,
-        price: variant.price ? Number(variant.price) : 0,
+        price: fromMajor(variant.price),
         stock: variant.stock ? Number(variant.stock) : 0,
         uom_id: variant.uom_id ? Number(variant.uom_id) : undefined,
         quantity_value: variant.quantity_value ? Number(variant.quantity_value) : 0 // or null if reverted to *float64
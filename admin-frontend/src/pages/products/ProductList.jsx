@@ -5,6 +5,7 @@ import { api } from "../../lib/api";
 import Pagination from "../../components/Pagination";
 import ProductFilter from "./ProductFilter";
 import ProductDetailModal from "./ProductDetailModal";
+import { formatMoney } from "../../utils/money";
 
 const ProductList = () => {
   const env = import.meta.env;
@@ -118,11 +119,6 @@ const ProductList = () => {
     }
   };
 
-  const formatPrice = (price, discount = 0) => {
-    const actualPrice = price - (price * discount / 100);
-    return new Intl.NumberFormat('en-US', { style: 'currency', currency: 'INR' }).format(actualPrice);
-  };
-
   // Sort indicator function
   const getSortIndicator = (field) => {
     if (field !== sortField) return null;
@@ -213,7 +209,7 @@ const ProductList = () => {
                     </td>
 
                     <td className="px-4 py-3 text-sm font-medium">{product.name}</td>
-                    <td className="px-4 py-3 text-sm">{formatPrice(product.price, 0)}</td>
+                    <td className="px-4 py-3 text-sm">{formatMoney(product.price, 0)}</td>
                     <td className="px-4 py-3 text-sm">
                       {product.discount > 0 ? (
                         <span className="px-2 py-1 bg-red-100 text-red-800 rounded-full text-xs">
@@ -224,10 +220,10 @@ const ProductList = () => {
                       )}
                     </td>
                     <td className="px-4 py-3 text-sm font-medium">
-                      {formatPrice(product.price, product.discount)}
+                      {formatMoney(product.price, product.discount)}
                       {product.discount > 0 && (
                         <span className="text-xs text-gray-500 line-through ml-2">
-                          {formatPrice(product.price)}
+                          {formatMoney(product.price)}
                         </span>
                       )}
                     </td>
@@ -12,12 +12,18 @@
  * @property {string} updatedAt - Update timestamp
  */
 
+/**
+ * @typedef {Object} Money
+ * @property {number} amount - Amount in minor units (paisa, cents)
+ * @property {string} currency - ISO 4217 currency code
+ */
+
 /**
  * @typedef {Object} Product
  * @property {number} id - Product ID
  * @property {string} name - Product name
  * @property {string} description - Product description
- * @property {number} price - Product price
+ * @property {Money} price - Product price
  * @property {number} discount - Discount percentage
  * @property {number} stock - Available stock
  * @property {string} sku - Stock keeping unit
@@ -52,7 +58,7 @@
  * @property {string} order_number - Order number
  * @property {string} status - Order status
  * @property {string} payment_status - Payment status
- * @property {number} total_amount - Total order amount
+ * @property {Money} total_amount - Total order amount
  * @property {string} currency - Currency code
  * @property {Array<Object>} items - Order items
  * @property {Object} shipping_address - Shipping address
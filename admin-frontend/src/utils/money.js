@@ -0,0 +1,38 @@
+// Amounts come from the API as { amount, currency } with amount in minor units
+// (paisa, cents), so 199900 INR is ₹1,999.00.
+
+const DEFAULT_CURRENCY = 'INR';
+
+// Currencies without a minor unit; all others have two decimal places
+const ZERO_DECIMAL_CURRENCIES = ['JPY'];
+
+const minorDigits = (currency) =>
+  ZERO_DECIMAL_CURRENCIES.includes(currency) ? 0 : 2;
+
+/**
+ * Convert a money object to a major unit number for display or form fields
+ */
+export const toMajor = (money) => {
+  if (!money || typeof money.amount !== 'number') return 0;
+  const currency = money.currency || DEFAULT_CURRENCY;
+  return money.amount / 10 ** minorDigits(currency);
+};
+
+/**
+ * Convert a major unit value typed into a form, such as "19.99", to a money object
+ */
+export const fromMajor = (value, currency = DEFAULT_CURRENCY) => {
+  const major = Number(value) || 0;
+  return { amount: Math.round(major * 10 ** minorDigits(currency)), currency };
+};
+
+/**
+ * Format a money object, less a percentage discount, as a currency string
+ */
+export const formatMoney = (money, discount = 0) => {
+  const currency = money?.currency || DEFAULT_CURRENCY;
+  const major = toMajor(money);
+  return new Intl.NumberFormat('en-IN', { style: 'currency', currency }).format(
+    major - (major * discount) / 100
+  );
+};
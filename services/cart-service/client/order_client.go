@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/apiclient"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+// PromotionLine is a priced cart line offered to order-service for promotion evaluation
+type PromotionLine struct {
+	ProductID  uint64      `json:"product_id"`
+	VariantID  uint64      `json:"variant_id"`
+	CategoryID uint64      `json:"category_id"`
+	Quantity   int         `json:"quantity"`
+	UnitPrice  money.Money `json:"unit_price"`
+}
+
+// AppliedPromotion is a promotion that discounts the cart
+type AppliedPromotion struct {
+	PromotionID uint        `json:"promotion_id"`
+	Code        string      `json:"code,omitempty"`
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	Amount      money.Money `json:"amount"`
+}
+
+// RejectedPromotion is an entered code that does not apply, with the reason
+type RejectedPromotion struct {
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// PromotionEvaluation is the subset of an order-service promotion evaluation used by the cart summary
+type PromotionEvaluation struct {
+	Applied          []AppliedPromotion  `json:"applied"`
+	Rejected         []RejectedPromotion `json:"rejected"`
+	Discount         money.Money         `json:"discount"`
+	Tax              money.Money         `json:"tax"`
+	ShippingFee      money.Money         `json:"shipping_fee"`
+	ShippingDiscount money.Money         `json:"shipping_discount"`
+	Total            money.Money         `json:"total"`
+}
+
+// OrderClient handles communication with the order service
+type OrderClient struct {
+	api *apiclient.Client
+}
+
+// NewOrderClient creates a new order service client
+func NewOrderClient(cfg *config.Config) *OrderClient {
+	return &OrderClient{
+		api: apiclient.New("order-service", cfg.Services.OrderService.URL, cfg.Services.OrderService.Timeout),
+	}
+}
+
+// EvaluatePromotions asks order-service which promotions apply to the priced
+// lines and entered codes. Nothing is redeemed.
+func (c *OrderClient) EvaluatePromotions(ctx context.Context, identity Identity, lines []PromotionLine, codes []string) (*PromotionEvaluation, error) {
+	payload := map[string]interface{}{
+		"lines": lines,
+		"codes": codes,
+	}
+
+	var evaluation PromotionEvaluation
+	if err := c.api.Do(ctx, http.MethodPost, "/order/promotions/evaluate", identity, payload, &evaluation); err != nil {
+		return nil, err
+	}
+	return &evaluation, nil
+}
@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/idempotency"
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/client"
 	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/database"
 	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/models"
+	repositoryImpl "github.com/DurgaPratapRajbhar/e-commerce/cart-service/repository/impl"
 	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/routes"
+	serviceImpl "github.com/DurgaPratapRajbhar/e-commerce/cart-service/services/impl"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 
@@ -26,16 +32,30 @@ func main() {
 
 	database.ConnectDB(config)
 
-	err = database.DB.AutoMigrate(&models.Cart{})
+	err = database.DB.AutoMigrate(&models.Cart{}, &models.GuestCart{}, &models.SavedItem{},
+		&models.Wishlist{}, &models.WishlistItem{}, &models.PriceDropNotification{}, &idempotency.Key{})
 	if err != nil {
 		log.Fatal("Failed to run migrations: ", err)
 	}
 
 	fmt.Println("Database migrated successfully.")
 
+	// Compare wishlist prices with the catalogue every hour and record price drops
+	wishlistService := serviceImpl.NewWishlistService(repositoryImpl.NewWishlistRepository(), client.NewProductClient(&config))
+	go wishlistService.RunPriceDropWatcher(context.Background(), time.Hour)
+
+	// Delete guest carts that have expired every hour
+	cartService := serviceImpl.NewCartService(repositoryImpl.NewCartRepository(), client.NewProductClient(&config), client.NewOrderClient(&config), config.Tax.GSTPercent)
+	go cartService.RunGuestCartCleanup(context.Background(), time.Hour)
+
 	router := gin.Default()
+	// The guest cart and wishlist rate limits are keyed on the client IP, which
+	// must not be taken from X-Forwarded-For sent by anyone but our proxies
+	if err := router.SetTrustedProxies(config.Server.TrustedProxies); err != nil {
+		log.Fatal("Invalid trusted proxies: ", err)
+	}
 
-	routes.SetupCartRoutes(router)
+	routes.SetupCartRoutes(router, &config)
 
 	port := config.Services.CartService.Port
 	if port == "" {
@@ -1,9 +1,12 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/client"
 	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/models"
 	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/services"
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
@@ -28,7 +31,12 @@ func (ctrl *CartController) AddToCart(c *gin.Context) {
 		return
 	}
 
-	if err := ctrl.cartService.AddToCart(&cart); err != nil {
+	if err := ctrl.cartService.AddToCart(c.Request.Context(), identityFrom(c), &cart); err != nil {
+		if errors.Is(err, services.ErrProductUnavailable) {
+			response := utils.ErrorResponse(utils.ErrValidationFailed, "Product cannot be added to cart", err.Error(), utils.GenerateRequestID())
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
 		response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to add to cart", err.Error(), utils.GenerateRequestID())
 		c.JSON(http.StatusInternalServerError, response)
 		return
@@ -89,7 +97,12 @@ func (ctrl *CartController) UpdateCart(c *gin.Context) {
 	}
 
 	cart.ID = id
-	if err := ctrl.cartService.UpdateCart(&cart); err != nil {
+	if err := ctrl.cartService.UpdateCart(c.Request.Context(), identityFrom(c), &cart); err != nil {
+		if errors.Is(err, services.ErrProductUnavailable) {
+			response := utils.ErrorResponse(utils.ErrValidationFailed, "Product cannot be added to cart", err.Error(), utils.GenerateRequestID())
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
 		response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to update cart", err.Error(), utils.GenerateRequestID())
 		c.JSON(http.StatusInternalServerError, response)
 		return
@@ -130,4 +143,79 @@ func (ctrl *CartController) ClearCart(c *gin.Context) {
 	}
 
 	c.Status(http.StatusNoContent)
-}
\ No newline at end of file
+}
+
+// GetCartSummary prices the user's cart against current product prices, with
+// GST and promotions, and flags lines that are unavailable, short of stock or
+// repriced. Coupon codes are passed as repeated coupon query parameters.
+func (ctrl *CartController) GetCartSummary(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		response := utils.ErrorResponse(utils.ErrValidationFailed, "Invalid input", "Invalid User ID", utils.GenerateRequestID())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	codes, ok := couponCodes(c)
+	if !ok {
+		return
+	}
+
+	summary, err := ctrl.cartService.GetCartSummary(c.Request.Context(), identityFrom(c), userID, codes)
+	if err != nil {
+		response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to price cart", err.Error(), utils.GenerateRequestID())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(summary, "Cart summary retrieved successfully", utils.GenerateRequestID()))
+}
+
+// maxCouponCodes is how many coupon codes can be entered at once
+const maxCouponCodes = 5
+
+// couponCodes reads the coupon query parameters, writing a 400 when there are too many
+func couponCodes(c *gin.Context) ([]string, bool) {
+	codes := c.QueryArray("coupon")
+	if len(codes) > maxCouponCodes {
+		details := fmt.Sprintf("At most %d coupon codes can be entered", maxCouponCodes)
+		response := utils.ErrorResponse(utils.ErrValidationFailed, "Invalid input", details, utils.GenerateRequestID())
+		c.JSON(http.StatusBadRequest, response)
+		return nil, false
+	}
+	return codes, true
+}
+
+// identityFrom reads the caller set by ServiceAuthMiddleware, passed on to product-service
+func identityFrom(c *gin.Context) client.Identity {
+	return client.Identity{
+		UserID: c.GetUint("user_id"),
+		Email:  c.GetString("email"),
+		Role:   c.GetString("role"),
+	}
+}
+
+// callerID returns the logged-in caller, or writes 401 with message when there is none
+func callerID(c *gin.Context, message string) (uint64, bool) {
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		response := utils.ErrorResponse(utils.ErrUnauthorized, message, nil, utils.GenerateRequestID())
+		c.JSON(http.StatusUnauthorized, response)
+		return 0, false
+	}
+	return uint64(userID), true
+}
+
+// serviceError writes the response for a failed cart or wishlist operation
+func serviceError(c *gin.Context, message string, err error) {
+	requestID := utils.GenerateRequestID()
+	switch {
+	case errors.Is(err, services.ErrGuestCartNotFound), errors.Is(err, services.ErrCartItemNotFound),
+		errors.Is(err, services.ErrSavedItemNotFound), errors.Is(err, services.ErrWishlistNotFound),
+		errors.Is(err, services.ErrWishlistItemNotFound), errors.Is(err, services.ErrPriceDropNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(utils.ErrNotFound, message, err.Error(), requestID))
+	case errors.Is(err, services.ErrProductUnavailable):
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrValidationFailed, message, err.Error(), requestID))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, message, err.Error(), requestID))
+	}
+}
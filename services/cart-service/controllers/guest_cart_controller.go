@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/client"
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// CartTokenHeader carries the token of a guest cart
+const CartTokenHeader = "X-Cart-Token"
+
+// guestIdentity is sent to product-service for shoppers who have not logged in
+var guestIdentity = client.Identity{Role: "guest"}
+
+// CreateGuestCart starts a cart for an anonymous shopper. The token in the
+// response is shown only once and goes in the X-Cart-Token header afterwards.
+func (ctrl *CartController) CreateGuestCart(c *gin.Context) {
+	token, err := ctrl.cartService.CreateGuestCart()
+	if err != nil {
+		response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to create guest cart", err.Error(), utils.GenerateRequestID())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse(token, "Guest cart created successfully", utils.GenerateRequestID()))
+}
+
+func (ctrl *CartController) GetGuestCart(c *gin.Context) {
+	carts, err := ctrl.cartService.GetGuestCart(c.GetHeader(CartTokenHeader))
+	if err != nil {
+		serviceError(c, "Failed to retrieve guest cart", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(carts, "Cart items retrieved successfully", utils.GenerateRequestID()))
+}
+
+func (ctrl *CartController) GetGuestCartSummary(c *gin.Context) {
+	codes, ok := couponCodes(c)
+	if !ok {
+		return
+	}
+
+	summary, err := ctrl.cartService.GetGuestCartSummary(c.Request.Context(), guestIdentity, c.GetHeader(CartTokenHeader), codes)
+	if err != nil {
+		serviceError(c, "Failed to price cart", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(summary, "Cart summary retrieved successfully", utils.GenerateRequestID()))
+}
+
+func (ctrl *CartController) AddToGuestCart(c *gin.Context) {
+	var cart models.Cart
+	if err := c.ShouldBindJSON(&cart); err != nil {
+		response := utils.ErrorResponse(utils.ErrValidationFailed, "Invalid input", err.Error(), utils.GenerateRequestID())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := ctrl.cartService.AddToGuestCart(c.Request.Context(), guestIdentity, c.GetHeader(CartTokenHeader), &cart); err != nil {
+		serviceError(c, "Failed to add to cart", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse(cart, "Item added to cart successfully", utils.GenerateRequestID()))
+}
+
+func (ctrl *CartController) UpdateGuestCartItem(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response := utils.ErrorResponse(utils.ErrValidationFailed, "Invalid input", "Invalid ID", utils.GenerateRequestID())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var cart models.Cart
+	if err := c.ShouldBindJSON(&cart); err != nil {
+		response := utils.ErrorResponse(utils.ErrValidationFailed, "Invalid input", err.Error(), utils.GenerateRequestID())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	cart.ID = id
+	if err := ctrl.cartService.UpdateGuestCartItem(c.Request.Context(), guestIdentity, c.GetHeader(CartTokenHeader), &cart); err != nil {
+		serviceError(c, "Failed to update cart", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(cart, "Cart updated successfully", utils.GenerateRequestID()))
+}
+
+func (ctrl *CartController) RemoveFromGuestCart(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response := utils.ErrorResponse(utils.ErrValidationFailed, "Invalid input", "Invalid ID", utils.GenerateRequestID())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := ctrl.cartService.RemoveFromGuestCart(c.GetHeader(CartTokenHeader), id); err != nil {
+		serviceError(c, "Failed to remove from cart", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// MergeGuestCart moves a guest cart into the logged-in caller's cart, summing
+// the quantities of lines for the same product and variant up to the stock
+// available. The guest cart is deleted.
+func (ctrl *CartController) MergeGuestCart(c *gin.Context) {
+	userID, ok := callerID(c, "Log in to merge a guest cart")
+	if !ok {
+		return
+	}
+
+	var req models.MergeGuestCartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := utils.ErrorResponse(utils.ErrValidationFailed, "Invalid input", err.Error(), utils.GenerateRequestID())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	carts, err := ctrl.cartService.MergeGuestCart(c.Request.Context(), identityFrom(c), req.Token, userID)
+	if err != nil {
+		serviceError(c, "Failed to merge guest cart", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(carts, "Guest cart merged successfully", utils.GenerateRequestID()))
+}
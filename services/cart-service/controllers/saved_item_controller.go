@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// SaveForLater moves one of the caller's cart lines to their saved-for-later list
+func (ctrl *CartController) SaveForLater(c *gin.Context) {
+	userID, ok := callerID(c, "Log in to save items for later")
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response := utils.ErrorResponse(utils.ErrValidationFailed, "Invalid input", "Invalid ID", utils.GenerateRequestID())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	item, err := ctrl.cartService.SaveForLater(userID, id)
+	if err != nil {
+		serviceError(c, "Failed to save item for later", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(item, "Item saved for later", utils.GenerateRequestID()))
+}
+
+func (ctrl *CartController) GetSavedItems(c *gin.Context) {
+	userID, ok := callerID(c, "Log in to see saved items")
+	if !ok {
+		return
+	}
+
+	items, err := ctrl.cartService.GetSavedItems(userID)
+	if err != nil {
+		serviceError(c, "Failed to retrieve saved items", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(items, "Saved items retrieved successfully", utils.GenerateRequestID()))
+}
+
+// MoveToCart moves a saved item back into the caller's cart at the current price
+func (ctrl *CartController) MoveToCart(c *gin.Context) {
+	userID, ok := callerID(c, "Log in to move saved items")
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response := utils.ErrorResponse(utils.ErrValidationFailed, "Invalid input", "Invalid ID", utils.GenerateRequestID())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	cart, err := ctrl.cartService.MoveToCart(c.Request.Context(), identityFrom(c), userID, id)
+	if err != nil {
+		serviceError(c, "Failed to move item to cart", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(cart, "Item moved to cart", utils.GenerateRequestID()))
+}
+
+func (ctrl *CartController) RemoveSavedItem(c *gin.Context) {
+	userID, ok := callerID(c, "Log in to remove saved items")
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response := utils.ErrorResponse(utils.ErrValidationFailed, "Invalid input", "Invalid ID", utils.GenerateRequestID())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := ctrl.cartService.RemoveSavedItem(userID, id); err != nil {
+		serviceError(c, "Failed to remove saved item", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/services"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type WishlistController struct {
+	wishlistService services.WishlistService
+}
+
+func NewWishlistController(service services.WishlistService) *WishlistController {
+	return &WishlistController{
+		wishlistService: service,
+	}
+}
+
+// idParam parses a numeric path parameter, writing 400 when it is invalid
+func idParam(c *gin.Context, name string) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil {
+		response := utils.ErrorResponse(utils.ErrValidationFailed, "Invalid input", "Invalid ID", utils.GenerateRequestID())
+		c.JSON(http.StatusBadRequest, response)
+		return 0, false
+	}
+	return id, true
+}
+
+// callerWishlist reads the caller and the wishlist ID from the path
+func callerWishlist(c *gin.Context) (uint64, uint64, bool) {
+	userID, ok := callerID(c, "Log in to manage wishlists")
+	if !ok {
+		return 0, 0, false
+	}
+	id, ok := idParam(c, "id")
+	return userID, id, ok
+}
+
+func (ctrl *WishlistController) CreateWishlist(c *gin.Context) {
+	userID, ok := callerID(c, "Log in to manage wishlists")
+	if !ok {
+		return
+	}
+	var req models.WishlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := utils.ErrorResponse(utils.ErrValidationFailed, "Invalid input", err.Error(), utils.GenerateRequestID())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	wishlist, err := ctrl.wishlistService.CreateWishlist(userID, req.Name)
+	if err != nil {
+		serviceError(c, "Failed to create wishlist", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse(wishlist, "Wishlist created successfully", utils.GenerateRequestID()))
+}
+
+func (ctrl *WishlistController) GetWishlists(c *gin.Context) {
+	userID, ok := callerID(c, "Log in to manage wishlists")
+	if !ok {
+		return
+	}
+
+	wishlists, err := ctrl.wishlistService.GetWishlists(userID)
+	if err != nil {
+		serviceError(c, "Failed to retrieve wishlists", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(wishlists, "Wishlists retrieved successfully", utils.GenerateRequestID()))
+}
+
+func (ctrl *WishlistController) GetWishlist(c *gin.Context) {
+	userID, id, ok := callerWishlist(c)
+	if !ok {
+		return
+	}
+
+	wishlist, err := ctrl.wishlistService.GetWishlist(userID, id)
+	if err != nil {
+		serviceError(c, "Failed to retrieve wishlist", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(wishlist, "Wishlist retrieved successfully", utils.GenerateRequestID()))
+}
+
+func (ctrl *WishlistController) RenameWishlist(c *gin.Context) {
+	userID, id, ok := callerWishlist(c)
+	if !ok {
+		return
+	}
+	var req models.WishlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := utils.ErrorResponse(utils.ErrValidationFailed, "Invalid input", err.Error(), utils.GenerateRequestID())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	wishlist, err := ctrl.wishlistService.RenameWishlist(userID, id, req.Name)
+	if err != nil {
+		serviceError(c, "Failed to update wishlist", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(wishlist, "Wishlist updated successfully", utils.GenerateRequestID()))
+}
+
+func (ctrl *WishlistController) DeleteWishlist(c *gin.Context) {
+	userID, id, ok := callerWishlist(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.wishlistService.DeleteWishlist(userID, id); err != nil {
+		serviceError(c, "Failed to delete wishlist", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (ctrl *WishlistController) AddItem(c *gin.Context) {
+	userID, id, ok := callerWishlist(c)
+	if !ok {
+		return
+	}
+	var req models.WishlistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := utils.ErrorResponse(utils.ErrValidationFailed, "Invalid input", err.Error(), utils.GenerateRequestID())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	item, err := ctrl.wishlistService.AddItem(c.Request.Context(), identityFrom(c), userID, id, req)
+	if err != nil {
+		serviceError(c, "Failed to add item to wishlist", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse(item, "Item added to wishlist successfully", utils.GenerateRequestID()))
+}
+
+func (ctrl *WishlistController) RemoveItem(c *gin.Context) {
+	userID, id, ok := callerWishlist(c)
+	if !ok {
+		return
+	}
+	itemID, ok := idParam(c, "itemId")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.wishlistService.RemoveItem(userID, id, itemID); err != nil {
+		serviceError(c, "Failed to remove item from wishlist", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ShareWishlist returns the wishlist with a share token anyone can view it with
+func (ctrl *WishlistController) ShareWishlist(c *gin.Context) {
+	userID, id, ok := callerWishlist(c)
+	if !ok {
+		return
+	}
+
+	wishlist, err := ctrl.wishlistService.ShareWishlist(userID, id)
+	if err != nil {
+		serviceError(c, "Failed to share wishlist", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(wishlist, "Wishlist shared successfully", utils.GenerateRequestID()))
+}
+
+// UnshareWishlist revokes a wishlist's share token
+func (ctrl *WishlistController) UnshareWishlist(c *gin.Context) {
+	userID, id, ok := callerWishlist(c)
+	if !ok {
+		return
+	}
+
+	wishlist, err := ctrl.wishlistService.UnshareWishlist(userID, id)
+	if err != nil {
+		serviceError(c, "Failed to stop sharing wishlist", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(wishlist, "Wishlist is no longer shared", utils.GenerateRequestID()))
+}
+
+// GetSharedWishlist is public: anyone with the share token can view the wishlist
+func (ctrl *WishlistController) GetSharedWishlist(c *gin.Context) {
+	wishlist, err := ctrl.wishlistService.GetSharedWishlist(c.Param("token"))
+	if err != nil {
+		serviceError(c, "Failed to retrieve wishlist", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(wishlist, "Wishlist retrieved successfully", utils.GenerateRequestID()))
+}
+
+// GetPriceDrops lists the caller's price drop notifications, newest first.
+// ?unread=true leaves out those already read.
+func (ctrl *WishlistController) GetPriceDrops(c *gin.Context) {
+	userID, ok := callerID(c, "Log in to see price drops")
+	if !ok {
+		return
+	}
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+
+	notifications, err := ctrl.wishlistService.GetPriceDrops(userID, unreadOnly)
+	if err != nil {
+		serviceError(c, "Failed to retrieve price drops", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(notifications, "Price drops retrieved successfully", utils.GenerateRequestID()))
+}
+
+func (ctrl *WishlistController) MarkPriceDropRead(c *gin.Context) {
+	userID, ok := callerID(c, "Log in to see price drops")
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.wishlistService.MarkPriceDropRead(userID, id); err != nil {
+		serviceError(c, "Failed to mark price drop read", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CheckPriceDrops runs the price drop check now. Admin only.
+func (ctrl *WishlistController) CheckPriceDrops(c *gin.Context) {
+	if c.GetString("role") != utils.RoleAdmin {
+		response := utils.ErrorResponse(utils.ErrForbidden, "Only admins can check price drops", nil, utils.GenerateRequestID())
+		c.JSON(http.StatusForbidden, response)
+		return
+	}
+
+	notifications, err := ctrl.wishlistService.CheckPriceDrops(c.Request.Context())
+	if err != nil {
+		serviceError(c, "Failed to check price drops", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(notifications, "Price drops checked successfully", utils.GenerateRequestID()))
+}
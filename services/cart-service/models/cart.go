@@ -2,14 +2,21 @@ package models
 
 import (
 	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
 )
 
+// Cart is one product line in a user's cart, or in a guest cart when
+// GuestCartID is set. UnitPrice is the discounted price of the product when the
+// line was added, used to spot price changes.
 type Cart struct {
-	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
-	UserID    uint64    `gorm:"index;not null" json:"user_id"`
-	ProductID uint64    `gorm:"index;not null" json:"product_id"`
-	VariantID *uint64   `gorm:"index" json:"variant_id,omitempty"`
-	Quantity  int       `gorm:"type:int;not null;default:1" json:"quantity"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
-}
\ No newline at end of file
+	ID          uint64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID      uint64      `gorm:"index;not null" json:"user_id"`
+	GuestCartID *uint64     `gorm:"index" json:"-"`
+	ProductID   uint64      `gorm:"index;not null" json:"product_id"`
+	VariantID   *uint64     `gorm:"index" json:"variant_id,omitempty"`
+	Quantity    int         `gorm:"type:int;not null;default:1" json:"quantity"`
+	UnitPrice   money.Money `gorm:"embedded;embeddedPrefix:unit_price_" json:"unit_price"`
+	CreatedAt   time.Time   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
+}
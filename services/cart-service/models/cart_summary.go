@@ -0,0 +1,68 @@
+package models
+
+import (
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/client"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+// Cart line issues reported by the cart summary
+const (
+	LineIssueUnavailable       = "unavailable"
+	LineIssueInactive          = "inactive"
+	LineIssueOutOfStock        = "out_of_stock"
+	LineIssueInsufficientStock = "insufficient_stock"
+	LineIssuePriceChanged      = "price_changed"
+)
+
+// CartLineSummary is a cart line priced against the current product price.
+// ListPrice is the product or variant price before the product discount and
+// UnitPrice the price after it. AddedUnitPrice is what the line cost when it
+// was added, if known.
+type CartLineSummary struct {
+	CartID          uint64       `json:"cart_id"`
+	ProductID       uint64       `json:"product_id"`
+	VariantID       *uint64      `json:"variant_id,omitempty"`
+	Name            string       `json:"name"`
+	Quantity        int          `json:"quantity"`
+	Stock           int          `json:"stock"`
+	ListPrice       money.Money  `json:"list_price"`
+	DiscountPercent float64      `json:"discount_percent"`
+	UnitPrice       money.Money  `json:"unit_price"`
+	AddedUnitPrice  *money.Money `json:"added_unit_price,omitempty"`
+	Subtotal        money.Money  `json:"subtotal"`
+	Discount        money.Money  `json:"discount"`
+	Tax             money.Money  `json:"tax"`
+	Total           money.Money  `json:"total"`
+	Purchasable     bool         `json:"purchasable"`
+	Issues          []string     `json:"issues,omitempty"`
+}
+
+// CartSummary is a user's cart priced server-side. Only purchasable lines count
+// towards the totals; lines that are unavailable, inactive or short of stock
+// are listed with their issues but left out.
+//
+// Promotions are the order-service promotions that apply to the purchasable
+// lines and entered codes, and RejectedCodes the codes that do not apply.
+// Tax is GST on Subtotal less Discount and PromotionDiscount, worked out once
+// for the whole cart as checkout charges it, so it may differ from the sum of
+// the lines' Tax by rounding. Total is Subtotal less Discount and
+// PromotionDiscount, plus Tax and Shipping, less ShippingDiscount.
+// PromotionError is set when promotions could not be evaluated; the cart is
+// then priced without them.
+type CartSummary struct {
+	UserID            uint64                     `json:"user_id"`
+	Lines             []CartLineSummary          `json:"lines"`
+	ItemCount         int                        `json:"item_count"`
+	GSTPercent        float64                    `json:"gst_percent"`
+	Subtotal          money.Money                `json:"subtotal"`
+	Discount          money.Money                `json:"discount"`
+	Promotions        []client.AppliedPromotion  `json:"promotions"`
+	RejectedCodes     []client.RejectedPromotion `json:"rejected_codes,omitempty"`
+	PromotionDiscount money.Money                `json:"promotion_discount"`
+	PromotionError    string                     `json:"promotion_error,omitempty"`
+	Tax               money.Money                `json:"tax"`
+	Shipping          money.Money                `json:"shipping"`
+	ShippingDiscount  money.Money                `json:"shipping_discount"`
+	Total             money.Money                `json:"total"`
+	HasIssues         bool                       `json:"has_issues"`
+}
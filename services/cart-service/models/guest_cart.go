@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// GuestCart is a cart for a shopper who has not logged in. The shopper holds an
+// opaque token for it; only the token's SHA-256 hash is stored. Its lines are
+// Cart rows with GuestCartID set and no user.
+type GuestCart struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	TokenHash string    `gorm:"type:char(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time `gorm:"index;not null" json:"expires_at"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// GuestCartToken is returned once, when a guest cart is created
+type GuestCartToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// MergeGuestCartRequest is the payload for merging a guest cart into the caller's cart
+type MergeGuestCartRequest struct {
+	Token string `json:"token" binding:"required,max=128"`
+}
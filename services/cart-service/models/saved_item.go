@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+// SavedItem is a cart line a user has saved for later. It keeps the line's
+// quantity and the unit price it was added to the cart at.
+type SavedItem struct {
+	ID        uint64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uint64      `gorm:"index;not null" json:"user_id"`
+	ProductID uint64      `gorm:"index;not null" json:"product_id"`
+	VariantID *uint64     `gorm:"index" json:"variant_id,omitempty"`
+	Quantity  int         `gorm:"type:int;not null;default:1" json:"quantity"`
+	UnitPrice money.Money `gorm:"embedded;embeddedPrefix:unit_price_" json:"unit_price"`
+	CreatedAt time.Time   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+// Wishlist is a named list of products kept by a user. A wishlist with a
+// ShareToken can be viewed by anyone holding the token.
+type Wishlist struct {
+	ID         uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     uint64         `gorm:"index;not null" json:"user_id"`
+	Name       string         `gorm:"type:varchar(100);not null" json:"name"`
+	ShareToken *string        `gorm:"type:char(64);uniqueIndex" json:"share_token,omitempty"`
+	Items      []WishlistItem `gorm:"foreignKey:WishlistID" json:"items"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// WishlistItem is a product, or one of its variants, on a wishlist. Price is
+// the list price when the item was added. NotifiedPrice is the lowest price a
+// price drop has been reported at, or zero before the first drop.
+type WishlistItem struct {
+	ID            uint64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	WishlistID    uint64      `gorm:"index;not null" json:"wishlist_id"`
+	ProductID     uint64      `gorm:"index;not null" json:"product_id"`
+	VariantID     *uint64     `gorm:"index" json:"variant_id,omitempty"`
+	Name          string      `gorm:"type:varchar(255)" json:"name"`
+	Price         money.Money `gorm:"embedded;embeddedPrefix:price_" json:"price"`
+	NotifiedPrice money.Money `gorm:"embedded;embeddedPrefix:notified_price_" json:"-"`
+	CreatedAt     time.Time   `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// SharedWishlist is the public view of a shared wishlist
+type SharedWishlist struct {
+	Name      string         `json:"name"`
+	Items     []WishlistItem `json:"items"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// PriceDropNotification tells a user that a wishlist item's list price fell
+// from OldPrice to NewPrice
+type PriceDropNotification struct {
+	ID             uint64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID         uint64      `gorm:"index;not null" json:"user_id"`
+	WishlistID     uint64      `gorm:"not null" json:"wishlist_id"`
+	WishlistItemID uint64      `gorm:"index;not null" json:"wishlist_item_id"`
+	ProductID      uint64      `gorm:"not null" json:"product_id"`
+	VariantID      *uint64     `json:"variant_id,omitempty"`
+	Name           string      `gorm:"type:varchar(255)" json:"name"`
+	OldPrice       money.Money `gorm:"embedded;embeddedPrefix:old_price_" json:"old_price"`
+	NewPrice       money.Money `gorm:"embedded;embeddedPrefix:new_price_" json:"new_price"`
+	ReadAt         *time.Time  `json:"read_at,omitempty"`
+	CreatedAt      time.Time   `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// WishlistRequest is the payload for creating or renaming a wishlist
+type WishlistRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// WishlistItemRequest is the payload for adding a product to a wishlist
+type WishlistItemRequest struct {
+	ProductID uint64  `json:"product_id" binding:"required"`
+	VariantID *uint64 `json:"variant_id"`
+}
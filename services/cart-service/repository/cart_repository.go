@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/models"
 )
 
@@ -11,4 +13,26 @@ type CartRepository interface {
 	Update(cart *models.Cart) error
 	Delete(id uint64) error
 	DeleteByUserID(userID uint64) error
-}
\ No newline at end of file
+
+	CreateGuestCart(guest *models.GuestCart) error
+	GetGuestCartByTokenHash(tokenHash string) (*models.GuestCart, error)
+	ExtendGuestCart(guestCartID uint64, expiresAt time.Time) error
+	GetByGuestCartID(guestCartID uint64) ([]models.Cart, error)
+	// DeleteExpiredGuestCarts removes guest carts that expired before now, with their lines
+	DeleteExpiredGuestCarts(now time.Time) error
+	// MergeGuestCart deletes a guest cart in one transaction with saving the
+	// merged lines, which include guest lines handed over to the user, and
+	// deleting the guest lines that were left. It returns gorm.ErrRecordNotFound
+	// when the guest cart is already gone.
+	MergeGuestCart(guestCartID uint64, merged []models.Cart) error
+
+	GetSavedItemsByUserID(userID uint64) ([]models.SavedItem, error)
+	GetSavedItemByID(id uint64) (*models.SavedItem, error)
+	DeleteSavedItem(id uint64) error
+	// SaveForLater deletes a cart line and saves the saved item in one
+	// transaction. It returns gorm.ErrRecordNotFound when the line is already gone.
+	SaveForLater(cartID uint64, item *models.SavedItem) error
+	// MoveToCart deletes a saved item and saves the cart line in one
+	// transaction. It returns gorm.ErrRecordNotFound when the item is already gone.
+	MoveToCart(savedItemID uint64, cart *models.Cart) error
+}
@@ -1,9 +1,13 @@
 package impl
 
 import (
+	"time"
+
 	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/database"
 	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/models"
 	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/repository"
+
+	"gorm.io/gorm"
 )
 
 type cartRepositoryImpl struct{}
@@ -28,7 +32,7 @@ func (r *cartRepositoryImpl) GetByID(id uint64) (*models.Cart, error) {
 
 func (r *cartRepositoryImpl) GetByUserID(userID uint64) ([]models.Cart, error) {
 	var carts []models.Cart
-	result := database.DB.Where("user_id = ?", userID).Find(&carts)
+	result := database.DB.Where("user_id = ? AND guest_cart_id IS NULL", userID).Find(&carts)
 	return carts, result.Error
 }
 
@@ -43,6 +47,104 @@ func (r *cartRepositoryImpl) Delete(id uint64) error {
 }
 
 func (r *cartRepositoryImpl) DeleteByUserID(userID uint64) error {
-	result := database.DB.Where("user_id = ?", userID).Delete(&models.Cart{})
+	result := database.DB.Where("user_id = ? AND guest_cart_id IS NULL", userID).Delete(&models.Cart{})
 	return result.Error
+}
+
+func (r *cartRepositoryImpl) CreateGuestCart(guest *models.GuestCart) error {
+	return database.DB.Create(guest).Error
+}
+
+func (r *cartRepositoryImpl) GetGuestCartByTokenHash(tokenHash string) (*models.GuestCart, error) {
+	var guest models.GuestCart
+	result := database.DB.Where("token_hash = ?", tokenHash).First(&guest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &guest, nil
+}
+
+func (r *cartRepositoryImpl) ExtendGuestCart(guestCartID uint64, expiresAt time.Time) error {
+	return database.DB.Model(&models.GuestCart{}).Where("id = ?", guestCartID).Update("expires_at", expiresAt).Error
+}
+
+func (r *cartRepositoryImpl) GetByGuestCartID(guestCartID uint64) ([]models.Cart, error) {
+	var carts []models.Cart
+	result := database.DB.Where("guest_cart_id = ?", guestCartID).Find(&carts)
+	return carts, result.Error
+}
+
+func (r *cartRepositoryImpl) DeleteExpiredGuestCarts(now time.Time) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		expired := tx.Model(&models.GuestCart{}).Select("id").Where("expires_at < ?", now)
+		if err := tx.Where("guest_cart_id IN (?)", expired).Delete(&models.Cart{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("expires_at < ?", now).Delete(&models.GuestCart{}).Error
+	})
+}
+
+func (r *cartRepositoryImpl) MergeGuestCart(guestCartID uint64, merged []models.Cart) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		// Deleting the guest cart first makes a concurrent merge of the same cart fail
+		result := tx.Delete(&models.GuestCart{}, guestCartID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		for i := range merged {
+			if err := tx.Save(&merged[i]).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("guest_cart_id = ?", guestCartID).Delete(&models.Cart{}).Error
+	})
+}
+
+func (r *cartRepositoryImpl) GetSavedItemsByUserID(userID uint64) ([]models.SavedItem, error) {
+	var items []models.SavedItem
+	result := database.DB.Where("user_id = ?", userID).Order("id").Find(&items)
+	return items, result.Error
+}
+
+func (r *cartRepositoryImpl) GetSavedItemByID(id uint64) (*models.SavedItem, error) {
+	var item models.SavedItem
+	result := database.DB.First(&item, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &item, nil
+}
+
+func (r *cartRepositoryImpl) DeleteSavedItem(id uint64) error {
+	return database.DB.Delete(&models.SavedItem{}, id).Error
+}
+
+func (r *cartRepositoryImpl) SaveForLater(cartID uint64, item *models.SavedItem) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Cart{}, cartID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Save(item).Error
+	})
+}
+
+func (r *cartRepositoryImpl) MoveToCart(savedItemID uint64, cart *models.Cart) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.SavedItem{}, savedItemID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Save(cart).Error
+	})
 }
\ No newline at end of file
@@ -0,0 +1,125 @@
+package impl
+
+import (
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/database"
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/repository"
+
+	"gorm.io/gorm"
+)
+
+type wishlistRepositoryImpl struct{}
+
+func NewWishlistRepository() repository.WishlistRepository {
+	return &wishlistRepositoryImpl{}
+}
+
+func (r *wishlistRepositoryImpl) Create(wishlist *models.Wishlist) error {
+	return database.DB.Create(wishlist).Error
+}
+
+func (r *wishlistRepositoryImpl) GetByID(id uint64) (*models.Wishlist, error) {
+	var wishlist models.Wishlist
+	result := database.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).First(&wishlist, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &wishlist, nil
+}
+
+func (r *wishlistRepositoryImpl) GetByUserID(userID uint64) ([]models.Wishlist, error) {
+	var wishlists []models.Wishlist
+	result := database.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Where("user_id = ?", userID).Order("id").Find(&wishlists)
+	return wishlists, result.Error
+}
+
+func (r *wishlistRepositoryImpl) GetByShareToken(token string) (*models.Wishlist, error) {
+	var wishlist models.Wishlist
+	result := database.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Where("share_token = ?", token).First(&wishlist)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &wishlist, nil
+}
+
+func (r *wishlistRepositoryImpl) Update(wishlist *models.Wishlist) error {
+	return database.DB.Omit("Items").Save(wishlist).Error
+}
+
+func (r *wishlistRepositoryImpl) Delete(id uint64) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("wishlist_id = ?", id).Delete(&models.WishlistItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Wishlist{}, id).Error
+	})
+}
+
+func (r *wishlistRepositoryImpl) CreateItem(item *models.WishlistItem) error {
+	return database.DB.Create(item).Error
+}
+
+func (r *wishlistRepositoryImpl) GetItemByID(id uint64) (*models.WishlistItem, error) {
+	var item models.WishlistItem
+	result := database.DB.First(&item, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &item, nil
+}
+
+func (r *wishlistRepositoryImpl) DeleteItem(id uint64) error {
+	return database.DB.Delete(&models.WishlistItem{}, id).Error
+}
+
+func (r *wishlistRepositoryImpl) GetItemsAfter(afterID uint64, limit int) ([]models.WishlistItem, error) {
+	var items []models.WishlistItem
+	result := database.DB.Where("id > ?", afterID).Order("id").Limit(limit).Find(&items)
+	return items, result.Error
+}
+
+func (r *wishlistRepositoryImpl) RecordPriceDrop(item *models.WishlistItem, notification *models.PriceDropNotification) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.WishlistItem{}).Where("id = ?", item.ID).Updates(map[string]interface{}{
+			"notified_price_minor":    item.NotifiedPrice.Amount,
+			"notified_price_currency": item.NotifiedPrice.Currency,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		// The item was removed while prices were being checked
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(notification).Error
+	})
+}
+
+func (r *wishlistRepositoryImpl) GetPriceDropsByUserID(userID uint64, unreadOnly bool) ([]models.PriceDropNotification, error) {
+	var notifications []models.PriceDropNotification
+	query := database.DB.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	result := query.Order("id DESC").Find(&notifications)
+	return notifications, result.Error
+}
+
+func (r *wishlistRepositoryImpl) MarkPriceDropRead(id, userID uint64) error {
+	var notification models.PriceDropNotification
+	if err := database.DB.Where("id = ? AND user_id = ?", id, userID).First(&notification).Error; err != nil {
+		return err
+	}
+	if notification.ReadAt != nil {
+		return nil
+	}
+	return database.DB.Model(&notification).Update("read_at", time.Now()).Error
+}
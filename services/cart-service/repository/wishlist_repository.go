@@ -0,0 +1,28 @@
+package repository
+
+import "github.com/DurgaPratapRajbhar/e-commerce/cart-service/models"
+
+type WishlistRepository interface {
+	Create(wishlist *models.Wishlist) error
+	// GetByID loads a wishlist with its items
+	GetByID(id uint64) (*models.Wishlist, error)
+	GetByUserID(userID uint64) ([]models.Wishlist, error)
+	GetByShareToken(token string) (*models.Wishlist, error)
+	// Update saves a wishlist's own fields, not its items
+	Update(wishlist *models.Wishlist) error
+	// Delete removes a wishlist with its items
+	Delete(id uint64) error
+
+	CreateItem(item *models.WishlistItem) error
+	GetItemByID(id uint64) (*models.WishlistItem, error)
+	DeleteItem(id uint64) error
+	// GetItemsAfter pages through every wishlist item in ID order
+	GetItemsAfter(afterID uint64, limit int) ([]models.WishlistItem, error)
+
+	// RecordPriceDrop saves the price an item was notified at with its notification
+	RecordPriceDrop(item *models.WishlistItem, notification *models.PriceDropNotification) error
+	GetPriceDropsByUserID(userID uint64, unreadOnly bool) ([]models.PriceDropNotification, error)
+	// MarkPriceDropRead marks a user's notification read. It returns
+	// gorm.ErrRecordNotFound when the user has no such notification.
+	MarkPriceDropRead(id, userID uint64) error
+}
@@ -1,28 +1,74 @@
 package routes
 
 import (
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/client"
 	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/controllers"
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/database"
 	repositoryImpl "github.com/DurgaPratapRajbhar/e-commerce/cart-service/repository/impl"
 	serviceImpl "github.com/DurgaPratapRajbhar/e-commerce/cart-service/services/impl"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/idempotency"
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupCartRoutes(router *gin.Engine) {
+func SetupCartRoutes(router *gin.Engine, cfg *config.Config) {
 	 
 	cartRepo := repositoryImpl.NewCartRepository()
-	cartService := serviceImpl.NewCartService(cartRepo)
+	cartService := serviceImpl.NewCartService(cartRepo, client.NewProductClient(cfg), client.NewOrderClient(cfg), cfg.Tax.GSTPercent)
 	cartController := controllers.NewCartController(cartService)
+	wishlistService := serviceImpl.NewWishlistService(repositoryImpl.NewWishlistRepository(), client.NewProductClient(cfg))
+	wishlistController := controllers.NewWishlistController(wishlistService)
 
 	cartRoutes := router.Group("cart")
 	cartRoutes.Use(middleware.ServiceAuthMiddleware())
 	{
-		cartRoutes.POST("", cartController.AddToCart)
+		cartRoutes.POST("", middleware.IdempotencyMiddleware(idempotency.NewStore(database.DB)), cartController.AddToCart)
 		cartRoutes.GET("/:id", cartController.GetCartByID)
 		cartRoutes.GET("/user/:userId", cartController.GetCartByUserID)
+		cartRoutes.GET("/user/:userId/summary", cartController.GetCartSummary)
 		cartRoutes.PUT("/:id", cartController.UpdateCart)
 		cartRoutes.DELETE("/:id", cartController.RemoveFromCart)
 		cartRoutes.DELETE("/user/:userId", cartController.ClearCart)
+		cartRoutes.POST("/merge", cartController.MergeGuestCart)
+
+		cartRoutes.POST("/:id/save", cartController.SaveForLater)
+		cartRoutes.GET("/saved", cartController.GetSavedItems)
+		cartRoutes.POST("/saved/:id/move", cartController.MoveToCart)
+		cartRoutes.DELETE("/saved/:id", cartController.RemoveSavedItem)
+
+		cartRoutes.POST("/wishlists", wishlistController.CreateWishlist)
+		cartRoutes.GET("/wishlists", wishlistController.GetWishlists)
+		cartRoutes.GET("/wishlists/:id", wishlistController.GetWishlist)
+		cartRoutes.PUT("/wishlists/:id", wishlistController.RenameWishlist)
+		cartRoutes.DELETE("/wishlists/:id", wishlistController.DeleteWishlist)
+		cartRoutes.POST("/wishlists/:id/items", wishlistController.AddItem)
+		cartRoutes.DELETE("/wishlists/:id/items/:itemId", wishlistController.RemoveItem)
+		cartRoutes.POST("/wishlists/:id/share", wishlistController.ShareWishlist)
+		cartRoutes.DELETE("/wishlists/:id/share", wishlistController.UnshareWishlist)
+
+		cartRoutes.GET("/price-drops", wishlistController.GetPriceDrops)
+		cartRoutes.POST("/price-drops/:id/read", wishlistController.MarkPriceDropRead)
+		cartRoutes.POST("/price-drops/check", wishlistController.CheckPriceDrops)
+	}
+
+	// Shared wishlists are public to anyone holding the share token
+	sharedRoutes := router.Group("cart/wishlists/shared")
+	sharedRoutes.Use(middleware.RateLimitMiddleware(cfg.RateLimit.RequestsPerMinute, cfg.RateLimit.BurstSize))
+	{
+		sharedRoutes.GET("/:token", wishlistController.GetSharedWishlist)
+	}
+
+	// Guest carts are public and identified by the X-Cart-Token header
+	guestRoutes := router.Group("cart/guest")
+	guestRoutes.Use(middleware.RateLimitMiddleware(cfg.RateLimit.RequestsPerMinute, cfg.RateLimit.BurstSize))
+	{
+		guestRoutes.POST("", cartController.CreateGuestCart)
+		guestRoutes.GET("", cartController.GetGuestCart)
+		guestRoutes.GET("/summary", cartController.GetGuestCartSummary)
+		guestRoutes.POST("/items", cartController.AddToGuestCart)
+		guestRoutes.PUT("/items/:id", cartController.UpdateGuestCartItem)
+		guestRoutes.DELETE("/items/:id", cartController.RemoveFromGuestCart)
 	}
 }
\ No newline at end of file
@@ -1,14 +1,47 @@
 package services
 
 import (
+	"context"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/client"
 	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/models"
 )
 
 type CartService interface {
-	AddToCart(cart *models.Cart) error
+	// AddToCart adds a line priced at the product's current discounted price
+	AddToCart(ctx context.Context, identity client.Identity, cart *models.Cart) error
 	GetCartByID(id uint64) (*models.Cart, error)
 	GetCartByUserID(userID uint64) ([]models.Cart, error)
-	UpdateCart(cart *models.Cart) error
+	// UpdateCart saves a line, pricing it again when its product or variant changes
+	UpdateCart(ctx context.Context, identity client.Identity, cart *models.Cart) error
 	RemoveFromCart(id uint64) error
 	ClearCart(userID uint64) error
-}
\ No newline at end of file
+	// GetCartSummary prices a user's cart against the current product prices,
+	// with the promotions that apply and the entered coupon codes
+	GetCartSummary(ctx context.Context, identity client.Identity, userID uint64, codes []string) (*models.CartSummary, error)
+
+	// CreateGuestCart starts a cart for an anonymous shopper and returns its token
+	CreateGuestCart() (*models.GuestCartToken, error)
+	// RunGuestCartCleanup deletes expired guest carts every interval until ctx is done
+	RunGuestCartCleanup(ctx context.Context, interval time.Duration)
+	GetGuestCart(token string) ([]models.Cart, error)
+	AddToGuestCart(ctx context.Context, identity client.Identity, token string, cart *models.Cart) error
+	UpdateGuestCartItem(ctx context.Context, identity client.Identity, token string, cart *models.Cart) error
+	RemoveFromGuestCart(token string, id uint64) error
+	GetGuestCartSummary(ctx context.Context, identity client.Identity, token string, codes []string) (*models.CartSummary, error)
+	// MergeGuestCart moves a guest cart into a user's cart and deletes it. Lines
+	// for a product and variant already in the user's cart are combined by
+	// summing the quantities, capped at the stock available.
+	MergeGuestCart(ctx context.Context, identity client.Identity, token string, userID uint64) ([]models.Cart, error)
+
+	// SaveForLater moves one of a user's cart lines to their saved-for-later
+	// list, adding to the quantity of an item already saved for the same
+	// product and variant
+	SaveForLater(userID, cartID uint64) (*models.SavedItem, error)
+	GetSavedItems(userID uint64) ([]models.SavedItem, error)
+	// MoveToCart moves a saved item back into the user's cart at the current
+	// price, adding to the quantity of a line already in the cart
+	MoveToCart(ctx context.Context, identity client.Identity, userID, savedItemID uint64) (*models.Cart, error)
+	RemoveSavedItem(userID, savedItemID uint64) error
+}
@@ -0,0 +1,25 @@
+package services
+
+import "errors"
+
+// ErrProductUnavailable is returned when a product cannot be added to a cart
+// because it does not exist, is not active or lacks the requested variant
+var ErrProductUnavailable = errors.New("product is not available")
+
+// ErrGuestCartNotFound is returned for a guest cart token that is unknown or expired
+var ErrGuestCartNotFound = errors.New("guest cart not found")
+
+// ErrCartItemNotFound is returned for a cart line that is not in the cart
+var ErrCartItemNotFound = errors.New("cart item not found")
+
+// ErrSavedItemNotFound is returned for a saved item that is not on the caller's list
+var ErrSavedItemNotFound = errors.New("saved item not found")
+
+// ErrWishlistNotFound is returned for a wishlist that does not exist or is not the caller's
+var ErrWishlistNotFound = errors.New("wishlist not found")
+
+// ErrWishlistItemNotFound is returned for an item that is not on the wishlist
+var ErrWishlistItemNotFound = errors.New("wishlist item not found")
+
+// ErrPriceDropNotFound is returned for a price drop notification that is not the caller's
+var ErrPriceDropNotFound = errors.New("price drop notification not found")
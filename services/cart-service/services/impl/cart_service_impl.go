@@ -1,6 +1,9 @@
 package impl
 
 import (
+	"context"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/client"
 	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/models"
 	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/repository"
 	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/services"
@@ -8,15 +11,24 @@ import (
 
 type cartServiceImpl struct {
 	cartRepository repository.CartRepository
+	productClient  *client.ProductClient
+	orderClient    *client.OrderClient
+	gstPercent     float64
 }
 
-func NewCartService(repo repository.CartRepository) services.CartService {
+func NewCartService(repo repository.CartRepository, productClient *client.ProductClient, orderClient *client.OrderClient, gstPercent float64) services.CartService {
 	return &cartServiceImpl{
 		cartRepository: repo,
+		productClient:  productClient,
+		orderClient:    orderClient,
+		gstPercent:     gstPercent,
 	}
 }
 
-func (s *cartServiceImpl) AddToCart(cart *models.Cart) error {
+func (s *cartServiceImpl) AddToCart(ctx context.Context, identity client.Identity, cart *models.Cart) error {
+	if err := s.priceLine(ctx, identity, cart); err != nil {
+		return err
+	}
 	return s.cartRepository.Create(cart)
 }
 
@@ -28,7 +40,23 @@ func (s *cartServiceImpl) GetCartByUserID(userID uint64) ([]models.Cart, error)
 	return s.cartRepository.GetByUserID(userID)
 }
 
-func (s *cartServiceImpl) UpdateCart(cart *models.Cart) error {
+func (s *cartServiceImpl) UpdateCart(ctx context.Context, identity client.Identity, cart *models.Cart) error {
+	existing, err := s.cartRepository.GetByID(cart.ID)
+	if err != nil {
+		return err
+	}
+	return s.updateLine(ctx, identity, existing, cart)
+}
+
+// updateLine saves changes to an existing line. A line keeps the price it was
+// added at until it points at another product or variant.
+func (s *cartServiceImpl) updateLine(ctx context.Context, identity client.Identity, existing, cart *models.Cart) error {
+	if existing.ProductID == cart.ProductID && variantID(existing.VariantID) == variantID(cart.VariantID) {
+		cart.UnitPrice = existing.UnitPrice
+	} else if err := s.priceLine(ctx, identity, cart); err != nil {
+		return err
+	}
+	cart.CreatedAt = existing.CreatedAt
 	return s.cartRepository.Update(cart)
 }
 
@@ -38,4 +66,4 @@ func (s *cartServiceImpl) RemoveFromCart(id uint64) error {
 
 func (s *cartServiceImpl) ClearCart(userID uint64) error {
 	return s.cartRepository.DeleteByUserID(userID)
-}
\ No newline at end of file
+}
@@ -0,0 +1,297 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/client"
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/services"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+)
+
+const productStatusActive = "active"
+
+// variantID treats a missing variant and variant 0 alike
+func variantID(id *uint64) uint64 {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+// quote is the current price and stock of a product or one of its variants
+type quote struct {
+	name      string
+	listPrice money.Money
+	discount  float64
+	unitPrice money.Money
+	stock     int
+}
+
+// quoteProduct prices a product, or the given variant of it, after the product discount
+func quoteProduct(product *client.Product, variant uint64) (*quote, error) {
+	q := &quote{
+		name:      product.Name,
+		listPrice: product.Price,
+		discount:  product.Discount,
+		stock:     product.Stock,
+	}
+	if variant != 0 {
+		v, found := product.FindVariant(variant)
+		if !found {
+			return nil, fmt.Errorf("%w: variant %d not found for product %d", services.ErrProductUnavailable, variant, product.ID)
+		}
+		q.name = fmt.Sprintf("%s (%s)", product.Name, v.Name)
+		q.listPrice = v.Price
+		q.stock = v.Stock
+	}
+
+	unitPrice, err := q.listPrice.Sub(q.listPrice.Percent(q.discount))
+	if err != nil {
+		return nil, err
+	}
+	q.unitPrice = unitPrice
+	return q, nil
+}
+
+// fetchProduct loads a product, reporting a product that no longer exists as unavailable
+func fetchProduct(ctx context.Context, productClient *client.ProductClient, identity client.Identity, productID uint64) (*client.Product, error) {
+	product, err := productClient.GetProduct(ctx, identity, productID)
+	if err != nil {
+		var serviceErr *client.ServiceError
+		if errors.As(err, &serviceErr) && serviceErr.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: product %d not found", services.ErrProductUnavailable, productID)
+		}
+		return nil, fmt.Errorf("product %d: %w", productID, err)
+	}
+	return product, nil
+}
+
+// priceLine records the current discounted price of an active product on a cart line
+func (s *cartServiceImpl) priceLine(ctx context.Context, identity client.Identity, cart *models.Cart) error {
+	product, err := fetchProduct(ctx, s.productClient, identity, cart.ProductID)
+	if err != nil {
+		return err
+	}
+	if product.Status != productStatusActive {
+		return fmt.Errorf("%w: product %d is %s", services.ErrProductUnavailable, product.ID, product.Status)
+	}
+	q, err := quoteProduct(product, variantID(cart.VariantID))
+	if err != nil {
+		return err
+	}
+	cart.UnitPrice = q.unitPrice
+	return nil
+}
+
+// gst works out GST on an amount with utils.GST, as checkout does
+func (s *cartServiceImpl) gst(amount money.Money) money.Money {
+	return utils.GST(amount, s.gstPercent)
+}
+
+// productCache fetches each product once. Products that are unavailable are
+// cached as nil.
+type productCache struct {
+	productClient *client.ProductClient
+	identity      client.Identity
+	products      map[uint64]*client.Product
+}
+
+func newProductCache(productClient *client.ProductClient, identity client.Identity) *productCache {
+	return &productCache{productClient: productClient, identity: identity, products: make(map[uint64]*client.Product)}
+}
+
+func (c *productCache) get(ctx context.Context, productID uint64) (*client.Product, error) {
+	if product, ok := c.products[productID]; ok {
+		return product, nil
+	}
+	product, err := fetchProduct(ctx, c.productClient, c.identity, productID)
+	if err != nil && !errors.Is(err, services.ErrProductUnavailable) {
+		return nil, err
+	}
+	c.products[productID] = product
+	return product, nil
+}
+
+func (s *cartServiceImpl) GetCartSummary(ctx context.Context, identity client.Identity, userID uint64, codes []string) (*models.CartSummary, error) {
+	carts, err := s.cartRepository.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	// Promotions for new customers or per-customer limits depend on who owns
+	// the cart, not on the admin looking at it
+	if uint64(identity.UserID) != userID {
+		identity = client.Identity{UserID: uint(userID), Role: utils.RoleUser}
+	}
+	summary, err := s.summarize(ctx, identity, carts, codes)
+	if err != nil {
+		return nil, err
+	}
+	summary.UserID = userID
+	return summary, nil
+}
+
+// summarize prices cart lines against the current product prices and applies
+// the promotions
+func (s *cartServiceImpl) summarize(ctx context.Context, identity client.Identity, carts []models.Cart, codes []string) (*models.CartSummary, error) {
+	summary := &models.CartSummary{
+		Lines:      make([]models.CartLineSummary, 0, len(carts)),
+		GSTPercent: s.gstPercent,
+		Promotions: []client.AppliedPromotion{},
+	}
+	products := newProductCache(s.productClient, identity)
+	var totals []models.CartLineSummary
+	var promotionLines []client.PromotionLine
+	var err error
+
+	for _, cart := range carts {
+		line := models.CartLineSummary{
+			CartID:    cart.ID,
+			ProductID: cart.ProductID,
+			VariantID: cart.VariantID,
+			Quantity:  cart.Quantity,
+		}
+
+		product, err := products.get(ctx, cart.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if product == nil {
+			line.Issues = append(line.Issues, models.LineIssueUnavailable)
+			summary.Lines = append(summary.Lines, line)
+			continue
+		}
+
+		q, err := quoteProduct(product, variantID(cart.VariantID))
+		if err != nil {
+			if !errors.Is(err, services.ErrProductUnavailable) {
+				return nil, fmt.Errorf("cart line %d: %w", cart.ID, err)
+			}
+			line.Name = product.Name
+			line.Issues = append(line.Issues, models.LineIssueUnavailable)
+			summary.Lines = append(summary.Lines, line)
+			continue
+		}
+
+		line.Name = q.name
+		line.Stock = q.stock
+		line.ListPrice = q.listPrice
+		line.DiscountPercent = q.discount
+		line.UnitPrice = q.unitPrice
+		line.Subtotal = q.listPrice.Mul(int64(cart.Quantity))
+		line.Discount = q.listPrice.Percent(q.discount).Mul(int64(cart.Quantity))
+		taxable := q.unitPrice.Mul(int64(cart.Quantity))
+		line.Tax = s.gst(taxable)
+		if line.Total, err = taxable.Add(line.Tax); err != nil {
+			return nil, err
+		}
+
+		if product.Status != productStatusActive {
+			line.Issues = append(line.Issues, models.LineIssueInactive)
+		}
+		switch {
+		case q.stock <= 0:
+			line.Issues = append(line.Issues, models.LineIssueOutOfStock)
+		case q.stock < cart.Quantity:
+			line.Issues = append(line.Issues, models.LineIssueInsufficientStock)
+		}
+		// Lines added before prices were recorded have no price to compare with
+		if !cart.UnitPrice.IsZero() {
+			added := cart.UnitPrice
+			line.AddedUnitPrice = &added
+			if cmp, err := added.Cmp(q.unitPrice); err != nil || cmp != 0 {
+				line.Issues = append(line.Issues, models.LineIssuePriceChanged)
+			}
+		}
+
+		// A changed price is reported but does not stop the line being bought
+		line.Purchasable = len(line.Issues) == 0 ||
+			(len(line.Issues) == 1 && line.Issues[0] == models.LineIssuePriceChanged)
+		if line.Purchasable {
+			totals = append(totals, line)
+			promotionLines = append(promotionLines, client.PromotionLine{
+				ProductID:  cart.ProductID,
+				VariantID:  variantID(cart.VariantID),
+				CategoryID: product.CategoryID,
+				Quantity:   cart.Quantity,
+				UnitPrice:  q.unitPrice,
+			})
+		}
+		summary.Lines = append(summary.Lines, line)
+	}
+
+	currency := money.DefaultCurrency
+	if len(totals) > 0 {
+		currency = totals[0].Total.Currency
+	}
+	summary.Subtotal = money.Zero(currency)
+	summary.Discount = money.Zero(currency)
+	summary.PromotionDiscount = money.Zero(currency)
+	summary.Tax = money.Zero(currency)
+	summary.Shipping = money.Zero(currency)
+	summary.ShippingDiscount = money.Zero(currency)
+	summary.Total = money.Zero(currency)
+	for _, line := range totals {
+		summary.ItemCount += line.Quantity
+		if summary.Subtotal, err = summary.Subtotal.Add(line.Subtotal); err != nil {
+			return nil, err
+		}
+		if summary.Discount, err = summary.Discount.Add(line.Discount); err != nil {
+			return nil, err
+		}
+	}
+	taxable, err := summary.Subtotal.Sub(summary.Discount)
+	if err != nil {
+		return nil, err
+	}
+	summary.Tax = s.gst(taxable)
+	if summary.Total, err = taxable.Add(summary.Tax); err != nil {
+		return nil, err
+	}
+	for _, line := range summary.Lines {
+		if len(line.Issues) > 0 {
+			summary.HasIssues = true
+			break
+		}
+	}
+
+	if len(promotionLines) > 0 {
+		if err := s.applyPromotions(ctx, identity, summary, promotionLines, codes); err != nil {
+			return nil, err
+		}
+	}
+	return summary, nil
+}
+
+// applyPromotions adds the promotions order-service finds for the purchasable
+// lines to the summary. The cart is still priced when order-service cannot be
+// reached, without promotions.
+func (s *cartServiceImpl) applyPromotions(ctx context.Context, identity client.Identity, summary *models.CartSummary, lines []client.PromotionLine, codes []string) error {
+	evaluation, err := s.orderClient.EvaluatePromotions(ctx, identity, lines, codes)
+	if err != nil {
+		log.Printf("cart summary: evaluating promotions: %v", err)
+		summary.PromotionError = "Promotions are unavailable right now"
+		return nil
+	}
+	if !evaluation.Total.SameCurrency(summary.Total) {
+		return fmt.Errorf("promotions priced in %s for a cart in %s", evaluation.Total.Currency, summary.Total.Currency)
+	}
+
+	if evaluation.Applied != nil {
+		summary.Promotions = evaluation.Applied
+	}
+	summary.RejectedCodes = evaluation.Rejected
+	summary.PromotionDiscount = evaluation.Discount
+	summary.Shipping = evaluation.ShippingFee
+	summary.ShippingDiscount = evaluation.ShippingDiscount
+
+	// order-service works out the tax and total the way checkout charges them
+	summary.Tax = evaluation.Tax
+	summary.Total = evaluation.Total
+	return nil
+}
@@ -0,0 +1,261 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/client"
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/services"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// guestCartTTL is how long a guest cart is kept after it was last changed
+	guestCartTTL = 30 * 24 * time.Hour
+	// tokenBytes is the length of guest cart and wishlist share tokens before hex encoding
+	tokenBytes = 32
+)
+
+// newToken returns a random hex-encoded token
+func newToken() (string, error) {
+	raw := make([]byte, tokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// hashGuestToken returns the hash a guest cart is stored under
+func hashGuestToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *cartServiceImpl) CreateGuestCart() (*models.GuestCartToken, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	guest := &models.GuestCart{
+		TokenHash: hashGuestToken(token),
+		ExpiresAt: time.Now().Add(guestCartTTL),
+	}
+	if err := s.cartRepository.CreateGuestCart(guest); err != nil {
+		return nil, err
+	}
+	return &models.GuestCartToken{Token: token, ExpiresAt: guest.ExpiresAt}, nil
+}
+
+// guestCart looks up the live guest cart for a token
+func (s *cartServiceImpl) guestCart(token string) (*models.GuestCart, error) {
+	if token == "" {
+		return nil, services.ErrGuestCartNotFound
+	}
+	guest, err := s.cartRepository.GetGuestCartByTokenHash(hashGuestToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, services.ErrGuestCartNotFound
+		}
+		return nil, err
+	}
+	if time.Now().After(guest.ExpiresAt) {
+		return nil, services.ErrGuestCartNotFound
+	}
+	return guest, nil
+}
+
+// extendGuestCart keeps a guest cart alive for guestCartTTL after a change
+func (s *cartServiceImpl) extendGuestCart(guest *models.GuestCart) error {
+	guest.ExpiresAt = time.Now().Add(guestCartTTL)
+	return s.cartRepository.ExtendGuestCart(guest.ID, guest.ExpiresAt)
+}
+
+// guestLine loads a line and checks that it is in the guest cart
+func (s *cartServiceImpl) guestLine(guest *models.GuestCart, id uint64) (*models.Cart, error) {
+	line, err := s.cartRepository.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, services.ErrCartItemNotFound
+		}
+		return nil, err
+	}
+	if line.GuestCartID == nil || *line.GuestCartID != guest.ID {
+		return nil, services.ErrCartItemNotFound
+	}
+	return line, nil
+}
+
+func (s *cartServiceImpl) RunGuestCartCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.cartRepository.DeleteExpiredGuestCarts(now); err != nil {
+				log.Printf("failed to delete expired guest carts: %v", err)
+			}
+		}
+	}
+}
+
+func (s *cartServiceImpl) GetGuestCart(token string) ([]models.Cart, error) {
+	guest, err := s.guestCart(token)
+	if err != nil {
+		return nil, err
+	}
+	return s.cartRepository.GetByGuestCartID(guest.ID)
+}
+
+func (s *cartServiceImpl) AddToGuestCart(ctx context.Context, identity client.Identity, token string, cart *models.Cart) error {
+	guest, err := s.guestCart(token)
+	if err != nil {
+		return err
+	}
+	if err := s.priceLine(ctx, identity, cart); err != nil {
+		return err
+	}
+
+	cart.ID = 0
+	cart.UserID = 0
+	cart.GuestCartID = &guest.ID
+	if err := s.cartRepository.Create(cart); err != nil {
+		return err
+	}
+	return s.extendGuestCart(guest)
+}
+
+func (s *cartServiceImpl) UpdateGuestCartItem(ctx context.Context, identity client.Identity, token string, cart *models.Cart) error {
+	guest, err := s.guestCart(token)
+	if err != nil {
+		return err
+	}
+	existing, err := s.guestLine(guest, cart.ID)
+	if err != nil {
+		return err
+	}
+
+	cart.UserID = 0
+	cart.GuestCartID = &guest.ID
+	if err := s.updateLine(ctx, identity, existing, cart); err != nil {
+		return err
+	}
+	return s.extendGuestCart(guest)
+}
+
+func (s *cartServiceImpl) RemoveFromGuestCart(token string, id uint64) error {
+	guest, err := s.guestCart(token)
+	if err != nil {
+		return err
+	}
+	if _, err := s.guestLine(guest, id); err != nil {
+		return err
+	}
+	return s.cartRepository.Delete(id)
+}
+
+func (s *cartServiceImpl) GetGuestCartSummary(ctx context.Context, identity client.Identity, token string, codes []string) (*models.CartSummary, error) {
+	carts, err := s.GetGuestCart(token)
+	if err != nil {
+		return nil, err
+	}
+	return s.summarize(ctx, identity, carts, codes)
+}
+
+// lineKey identifies the product and variant of a cart line
+type lineKey struct {
+	productID uint64
+	variantID uint64
+}
+
+func (s *cartServiceImpl) MergeGuestCart(ctx context.Context, identity client.Identity, token string, userID uint64) ([]models.Cart, error) {
+	guest, err := s.guestCart(token)
+	if err != nil {
+		return nil, err
+	}
+	guestLines, err := s.cartRepository.GetByGuestCartID(guest.ID)
+	if err != nil {
+		return nil, err
+	}
+	userLines, err := s.cartRepository.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := userLines
+	index := make(map[lineKey]int, len(lines)+len(guestLines))
+	for i, line := range lines {
+		key := lineKey{line.ProductID, variantID(line.VariantID)}
+		if _, ok := index[key]; !ok {
+			index[key] = i
+		}
+	}
+
+	changed := make(map[int]bool)
+	products := newProductCache(s.productClient, identity)
+	for _, line := range guestLines {
+		key := lineKey{line.ProductID, variantID(line.VariantID)}
+		i, ok := index[key]
+		if !ok {
+			line.UserID = userID
+			line.GuestCartID = nil
+			index[key] = len(lines)
+			changed[len(lines)] = true
+			lines = append(lines, line)
+			continue
+		}
+
+		stock, err := s.availableStock(ctx, products, line)
+		if err != nil {
+			return nil, err
+		}
+		// Quantities are summed up to the stock available, but a line that
+		// already held more than that is not cut back
+		quantity := min(lines[i].Quantity+line.Quantity, max(stock, lines[i].Quantity))
+		if quantity != lines[i].Quantity {
+			lines[i].Quantity = quantity
+			changed[i] = true
+		}
+	}
+
+	merged := make([]models.Cart, 0, len(changed))
+	for i := range lines {
+		if changed[i] {
+			merged = append(merged, lines[i])
+		}
+	}
+	if err := s.cartRepository.MergeGuestCart(guest.ID, merged); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, services.ErrGuestCartNotFound
+		}
+		return nil, err
+	}
+	return s.cartRepository.GetByUserID(userID)
+}
+
+// availableStock returns the stock of a line's product or variant, or 0 when
+// the product is no longer available
+func (s *cartServiceImpl) availableStock(ctx context.Context, products *productCache, line models.Cart) (int, error) {
+	product, err := products.get(ctx, line.ProductID)
+	if err != nil || product == nil {
+		return 0, err
+	}
+	q, err := quoteProduct(product, variantID(line.VariantID))
+	if err != nil {
+		if errors.Is(err, services.ErrProductUnavailable) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return q.stock, nil
+}
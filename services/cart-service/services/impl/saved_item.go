@@ -0,0 +1,128 @@
+package impl
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/client"
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/services"
+
+	"gorm.io/gorm"
+)
+
+// userLine loads a line and checks that it is in the user's cart
+func (s *cartServiceImpl) userLine(userID, id uint64) (*models.Cart, error) {
+	line, err := s.cartRepository.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, services.ErrCartItemNotFound
+		}
+		return nil, err
+	}
+	if line.UserID != userID || line.GuestCartID != nil {
+		return nil, services.ErrCartItemNotFound
+	}
+	return line, nil
+}
+
+// savedItem loads a saved item and checks that it is the user's
+func (s *cartServiceImpl) savedItem(userID, id uint64) (*models.SavedItem, error) {
+	item, err := s.cartRepository.GetSavedItemByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, services.ErrSavedItemNotFound
+		}
+		return nil, err
+	}
+	if item.UserID != userID {
+		return nil, services.ErrSavedItemNotFound
+	}
+	return item, nil
+}
+
+func (s *cartServiceImpl) SaveForLater(userID, cartID uint64) (*models.SavedItem, error) {
+	line, err := s.userLine(userID, cartID)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := s.cartRepository.GetSavedItemsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &models.SavedItem{
+		UserID:    userID,
+		ProductID: line.ProductID,
+		VariantID: line.VariantID,
+	}
+	key := lineKey{line.ProductID, variantID(line.VariantID)}
+	for i := range saved {
+		if (lineKey{saved[i].ProductID, variantID(saved[i].VariantID)}) == key {
+			item = &saved[i]
+			break
+		}
+	}
+	item.Quantity += line.Quantity
+	item.UnitPrice = line.UnitPrice
+
+	if err := s.cartRepository.SaveForLater(line.ID, item); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, services.ErrCartItemNotFound
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+func (s *cartServiceImpl) GetSavedItems(userID uint64) ([]models.SavedItem, error) {
+	return s.cartRepository.GetSavedItemsByUserID(userID)
+}
+
+func (s *cartServiceImpl) MoveToCart(ctx context.Context, identity client.Identity, userID, savedItemID uint64) (*models.Cart, error) {
+	item, err := s.savedItem(userID, savedItemID)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := s.cartRepository.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var cart *models.Cart
+	key := lineKey{item.ProductID, variantID(item.VariantID)}
+	for i := range lines {
+		if (lineKey{lines[i].ProductID, variantID(lines[i].VariantID)}) == key {
+			cart = &lines[i]
+			break
+		}
+	}
+	if cart != nil {
+		cart.Quantity += item.Quantity
+	} else {
+		cart = &models.Cart{
+			UserID:    userID,
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+		}
+		if err := s.priceLine(ctx, identity, cart); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.cartRepository.MoveToCart(item.ID, cart); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, services.ErrSavedItemNotFound
+		}
+		return nil, err
+	}
+	return cart, nil
+}
+
+func (s *cartServiceImpl) RemoveSavedItem(userID, savedItemID uint64) error {
+	if _, err := s.savedItem(userID, savedItemID); err != nil {
+		return err
+	}
+	return s.cartRepository.DeleteSavedItem(savedItemID)
+}
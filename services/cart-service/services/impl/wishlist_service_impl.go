@@ -0,0 +1,304 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/client"
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/repository"
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/services"
+
+	"gorm.io/gorm"
+)
+
+// priceDropBatchSize is how many wishlist items are loaded at a time when checking prices
+const priceDropBatchSize = 200
+
+// systemIdentity is sent to product-service when prices are checked in the background
+var systemIdentity = client.Identity{Role: "system"}
+
+type wishlistServiceImpl struct {
+	wishlistRepository repository.WishlistRepository
+	productClient      *client.ProductClient
+}
+
+func NewWishlistService(repo repository.WishlistRepository, productClient *client.ProductClient) services.WishlistService {
+	return &wishlistServiceImpl{
+		wishlistRepository: repo,
+		productClient:      productClient,
+	}
+}
+
+func (s *wishlistServiceImpl) CreateWishlist(userID uint64, name string) (*models.Wishlist, error) {
+	wishlist := &models.Wishlist{UserID: userID, Name: name, Items: []models.WishlistItem{}}
+	if err := s.wishlistRepository.Create(wishlist); err != nil {
+		return nil, err
+	}
+	return wishlist, nil
+}
+
+func (s *wishlistServiceImpl) GetWishlists(userID uint64) ([]models.Wishlist, error) {
+	return s.wishlistRepository.GetByUserID(userID)
+}
+
+func (s *wishlistServiceImpl) GetWishlist(userID, id uint64) (*models.Wishlist, error) {
+	wishlist, err := s.wishlistRepository.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, services.ErrWishlistNotFound
+		}
+		return nil, err
+	}
+	if wishlist.UserID != userID {
+		return nil, services.ErrWishlistNotFound
+	}
+	return wishlist, nil
+}
+
+func (s *wishlistServiceImpl) RenameWishlist(userID, id uint64, name string) (*models.Wishlist, error) {
+	wishlist, err := s.GetWishlist(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	wishlist.Name = name
+	if err := s.wishlistRepository.Update(wishlist); err != nil {
+		return nil, err
+	}
+	return wishlist, nil
+}
+
+func (s *wishlistServiceImpl) DeleteWishlist(userID, id uint64) error {
+	if _, err := s.GetWishlist(userID, id); err != nil {
+		return err
+	}
+	return s.wishlistRepository.Delete(id)
+}
+
+func (s *wishlistServiceImpl) AddItem(ctx context.Context, identity client.Identity, userID, wishlistID uint64, req models.WishlistItemRequest) (*models.WishlistItem, error) {
+	wishlist, err := s.GetWishlist(userID, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	key := lineKey{req.ProductID, variantID(req.VariantID)}
+	for i := range wishlist.Items {
+		if (lineKey{wishlist.Items[i].ProductID, variantID(wishlist.Items[i].VariantID)}) == key {
+			return &wishlist.Items[i], nil
+		}
+	}
+
+	product, err := fetchProduct(ctx, s.productClient, identity, req.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if product.Status != productStatusActive {
+		return nil, fmt.Errorf("%w: product %d is %s", services.ErrProductUnavailable, product.ID, product.Status)
+	}
+	q, err := quoteProduct(product, variantID(req.VariantID))
+	if err != nil {
+		return nil, err
+	}
+
+	item := &models.WishlistItem{
+		WishlistID: wishlist.ID,
+		ProductID:  req.ProductID,
+		VariantID:  req.VariantID,
+		Name:       q.name,
+		Price:      q.listPrice,
+	}
+	if err := s.wishlistRepository.CreateItem(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (s *wishlistServiceImpl) RemoveItem(userID, wishlistID, itemID uint64) error {
+	if _, err := s.GetWishlist(userID, wishlistID); err != nil {
+		return err
+	}
+	item, err := s.wishlistRepository.GetItemByID(itemID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return services.ErrWishlistItemNotFound
+		}
+		return err
+	}
+	if item.WishlistID != wishlistID {
+		return services.ErrWishlistItemNotFound
+	}
+	return s.wishlistRepository.DeleteItem(itemID)
+}
+
+func (s *wishlistServiceImpl) ShareWishlist(userID, id uint64) (*models.Wishlist, error) {
+	wishlist, err := s.GetWishlist(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if wishlist.ShareToken != nil {
+		return wishlist, nil
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	wishlist.ShareToken = &token
+	if err := s.wishlistRepository.Update(wishlist); err != nil {
+		return nil, err
+	}
+	return wishlist, nil
+}
+
+func (s *wishlistServiceImpl) UnshareWishlist(userID, id uint64) (*models.Wishlist, error) {
+	wishlist, err := s.GetWishlist(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if wishlist.ShareToken == nil {
+		return wishlist, nil
+	}
+
+	wishlist.ShareToken = nil
+	if err := s.wishlistRepository.Update(wishlist); err != nil {
+		return nil, err
+	}
+	return wishlist, nil
+}
+
+func (s *wishlistServiceImpl) GetSharedWishlist(token string) (*models.SharedWishlist, error) {
+	if token == "" {
+		return nil, services.ErrWishlistNotFound
+	}
+	wishlist, err := s.wishlistRepository.GetByShareToken(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, services.ErrWishlistNotFound
+		}
+		return nil, err
+	}
+	return &models.SharedWishlist{
+		Name:      wishlist.Name,
+		Items:     wishlist.Items,
+		UpdatedAt: wishlist.UpdatedAt,
+	}, nil
+}
+
+func (s *wishlistServiceImpl) CheckPriceDrops(ctx context.Context) ([]models.PriceDropNotification, error) {
+	products := newProductCache(s.productClient, systemIdentity)
+	owners := make(map[uint64]uint64)
+	var notifications []models.PriceDropNotification
+
+	var afterID uint64
+	for {
+		items, err := s.wishlistRepository.GetItemsAfter(afterID, priceDropBatchSize)
+		if err != nil {
+			return notifications, err
+		}
+		for _, item := range items {
+			afterID = item.ID
+			notification, err := s.checkPriceDrop(ctx, products, owners, item)
+			if err != nil {
+				return notifications, err
+			}
+			if notification != nil {
+				log.Printf("price drop: wishlist item %d (%s) fell from %s to %s",
+					item.ID, notification.Name, notification.OldPrice.Format(), notification.NewPrice.Format())
+				notifications = append(notifications, *notification)
+			}
+		}
+		if len(items) < priceDropBatchSize {
+			return notifications, nil
+		}
+	}
+}
+
+// checkPriceDrop records a notification when an item's current list price is
+// below the lowest price it has been seen at. Items whose product is no longer
+// on sale are skipped.
+func (s *wishlistServiceImpl) checkPriceDrop(ctx context.Context, products *productCache, owners map[uint64]uint64, item models.WishlistItem) (*models.PriceDropNotification, error) {
+	product, err := products.get(ctx, item.ProductID)
+	if err != nil || product == nil || product.Status != productStatusActive {
+		return nil, err
+	}
+	q, err := quoteProduct(product, variantID(item.VariantID))
+	if err != nil {
+		if errors.Is(err, services.ErrProductUnavailable) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	lowest := item.Price
+	if !item.NotifiedPrice.IsZero() {
+		lowest = item.NotifiedPrice
+	}
+	// Prices in another currency than the item was added in are not compared
+	if cmp, err := q.listPrice.Cmp(lowest); err != nil || cmp >= 0 {
+		return nil, nil
+	}
+
+	userID, ok := owners[item.WishlistID]
+	if !ok {
+		wishlist, err := s.wishlistRepository.GetByID(item.WishlistID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		userID = wishlist.UserID
+		owners[item.WishlistID] = userID
+	}
+
+	notification := &models.PriceDropNotification{
+		UserID:         userID,
+		WishlistID:     item.WishlistID,
+		WishlistItemID: item.ID,
+		ProductID:      item.ProductID,
+		VariantID:      item.VariantID,
+		Name:           q.name,
+		OldPrice:       lowest,
+		NewPrice:       q.listPrice,
+	}
+	item.NotifiedPrice = q.listPrice
+	if err := s.wishlistRepository.RecordPriceDrop(&item, notification); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return notification, nil
+}
+
+func (s *wishlistServiceImpl) RunPriceDropWatcher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.CheckPriceDrops(ctx); err != nil {
+				log.Printf("price drop check failed: %v", err)
+			}
+		}
+	}
+}
+
+func (s *wishlistServiceImpl) GetPriceDrops(userID uint64, unreadOnly bool) ([]models.PriceDropNotification, error) {
+	return s.wishlistRepository.GetPriceDropsByUserID(userID, unreadOnly)
+}
+
+func (s *wishlistServiceImpl) MarkPriceDropRead(userID, id uint64) error {
+	if err := s.wishlistRepository.MarkPriceDropRead(id, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return services.ErrPriceDropNotFound
+		}
+		return err
+	}
+	return nil
+}
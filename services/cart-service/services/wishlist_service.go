@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/client"
+	"github.com/DurgaPratapRajbhar/e-commerce/cart-service/models"
+)
+
+type WishlistService interface {
+	CreateWishlist(userID uint64, name string) (*models.Wishlist, error)
+	GetWishlists(userID uint64) ([]models.Wishlist, error)
+	GetWishlist(userID, id uint64) (*models.Wishlist, error)
+	RenameWishlist(userID, id uint64, name string) (*models.Wishlist, error)
+	DeleteWishlist(userID, id uint64) error
+	// AddItem adds an active product or variant at its current list price.
+	// Adding an item already on the wishlist returns the existing item.
+	AddItem(ctx context.Context, identity client.Identity, userID, wishlistID uint64, req models.WishlistItemRequest) (*models.WishlistItem, error)
+	RemoveItem(userID, wishlistID, itemID uint64) error
+
+	// ShareWishlist gives a wishlist a share token, keeping one it already has
+	ShareWishlist(userID, id uint64) (*models.Wishlist, error)
+	// UnshareWishlist drops the share token so existing links stop working
+	UnshareWishlist(userID, id uint64) (*models.Wishlist, error)
+	GetSharedWishlist(token string) (*models.SharedWishlist, error)
+
+	// CheckPriceDrops compares every wishlist item with its product's current
+	// list price and records a notification for each item whose price is below
+	// both the price it was added at and the last price drop reported for it
+	CheckPriceDrops(ctx context.Context) ([]models.PriceDropNotification, error)
+	// RunPriceDropWatcher runs CheckPriceDrops every interval until ctx is done
+	RunPriceDropWatcher(ctx context.Context, interval time.Duration)
+	GetPriceDrops(userID uint64, unreadOnly bool) ([]models.PriceDropNotification, error)
+	MarkPriceDropRead(userID, id uint64) error
+}
@@ -0,0 +1,61 @@
+// Command alert-stub is a local receiver for inventory-service stock alerts.
+// It logs every alert it receives and, when STOCK_ALERT_WEBHOOK_SECRET is set,
+// rejects requests whose signature does not match.
+//
+//	ALERT_STUB_ADDR=:9099 go run ./cmd/alert-stub
+//	STOCK_ALERT_WEBHOOK_URL=http://localhost:9099/alerts go run cmd/main.go
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+)
+
+func main() {
+	addr := os.Getenv("ALERT_STUB_ADDR")
+	if addr == "" {
+		addr = ":9099"
+	}
+	secret := os.Getenv("STOCK_ALERT_WEBHOOK_SECRET")
+
+	http.HandleFunc("/alerts", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if secret != "" {
+			err := utils.VerifyWebhookSignature(secret, r.Header.Get(utils.WebhookSignatureHeader), r.Header.Get(utils.WebhookTimestampHeader), body, 5*time.Minute, time.Now())
+			if err != nil {
+				log.Printf("rejected alert: %v", err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+
+		var alert entity.StockAlert
+		if err := json.Unmarshal(body, &alert); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s := alert.Suggestion
+		log.Printf("received %s alert: product %d variant %d warehouse %d, %d available, suggest ordering %d",
+			alert.Type, s.ProductID, s.VariantID, s.WarehouseID, s.Available, s.SuggestedQuantity)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	log.Printf("alert stub listening on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, nil))
+}
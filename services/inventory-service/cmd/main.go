@@ -15,11 +15,18 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
+
 	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/delivery/http"
 	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/infrastructure/database"
 	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/infrastructure/database/mysql"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/infrastructure/notification"
 	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/usecase"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/idempotency"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/middleware"
 )
 
 func main() {
@@ -37,12 +44,29 @@ func main() {
 	// Initialize repositories
 	inventoryRepo := mysql.NewInventoryRepository(db)
 	transactionRepo := mysql.NewInventoryTransactionRepository(db)
+	reservationRepo := mysql.NewStockReservationRepository(db)
+	warehouseRepo := mysql.NewWarehouseRepository(db)
+	transferRepo := mysql.NewStockTransferRepository(db)
+	cycleCountRepo := mysql.NewCycleCountRepository(db)
+	suggestionRepo := mysql.NewReorderSuggestionRepository(db)
+
+	// Initialize stock alert sinks
+	alertSink := notification.NewAlertSink(config.LoadConfig())
 	
 	// Initialize use cases
-	inventoryUseCase := usecase.NewInventoryUseCase(inventoryRepo, transactionRepo)
+	inventoryUseCase := usecase.NewInventoryUseCase(inventoryRepo, transactionRepo, reservationRepo, warehouseRepo, transferRepo, cycleCountRepo, suggestionRepo, alertSink)
+
+	// Expire abandoned stock reservations in the background
+	go inventoryUseCase.RunReservationSweeper(context.Background(), time.Minute)
+
+	// Compare stored stock with the transaction ledger and log any drift
+	go inventoryUseCase.RunReconciliation(context.Background(), time.Hour)
+
+	// Raise reorder suggestions and alerts for variants at their reorder point
+	go inventoryUseCase.RunReorderEvaluator(context.Background(), 15*time.Minute)
 	
 	// Initialize HTTP server
-	server := http.NewServer(inventoryUseCase)
+	server := http.NewServer(inventoryUseCase, middleware.IdempotencyMiddleware(idempotency.NewStore(db)))
 	
 	// The actual port is configured via environment variables and logged in the server.Start() method
 	log.Fatal(server.Start())
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/usecase"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetReconciliation reports stock that disagrees with the transaction ledger
+// @Summary Reconcile inventory
+// @Description Rebuild expected stock from the transaction ledger and held reservations and list the inventory rows that disagree
+// @Tags inventory-reconciliation
+// @Accept json
+// @Produce json
+// @Success 200 {object} entity.ReconciliationReport
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/reconciliation [get]
+func GetReconciliation(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report, err := useCase.Reconcile(c.Request.Context())
+		if err != nil {
+			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to reconcile inventory", err.Error(), utils.GenerateRequestID())
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(report, "Inventory reconciled successfully", utils.GenerateRequestID()))
+	}
+}
+
+// OpenCycleCount opens a stock count of a warehouse
+// @Summary Open cycle count
+// @Description Open a physical stock count of a warehouse, optionally limited to some products
+// @Tags inventory-reconciliation
+// @Accept json
+// @Produce json
+// @Param count body entity.CycleCountRequest true "Cycle Count Request"
+// @Success 200 {object} entity.CycleCount
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/counts [post]
+func OpenCycleCount(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req entity.CycleCountRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			validationErrors := utils.ParseValidationErrors(err.Error())
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		count, err := useCase.OpenCycleCount(c.Request.Context(), &req, c.GetUint("user_id"))
+		if err != nil {
+			respondStockError(c, "Failed to open cycle count", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(count, "Cycle count opened successfully", utils.GenerateRequestID()))
+	}
+}
+
+// GetCycleCounts lists cycle counts
+// @Summary Get cycle counts
+// @Description List cycle counts, newest first
+// @Tags inventory-reconciliation
+// @Accept json
+// @Produce json
+// @Param status query string false "Filter by status (open, posted, cancelled)"
+// @Success 200 {array} entity.CycleCount
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/counts [get]
+func GetCycleCounts(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		counts, err := useCase.GetCycleCounts(c.Request.Context(), c.Query("status"))
+		if err != nil {
+			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to get cycle counts", err.Error(), utils.GenerateRequestID())
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(counts, "Cycle counts retrieved successfully", utils.GenerateRequestID()))
+	}
+}
+
+// GetCycleCount gets a cycle count with its lines
+// @Summary Get cycle count
+// @Description Get a cycle count with its lines
+// @Tags inventory-reconciliation
+// @Accept json
+// @Produce json
+// @Param id path int true "Cycle Count ID"
+// @Success 200 {object} entity.CycleCount
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/inventory/counts/{id} [get]
+func GetCycleCount(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCycleCountID(c)
+		if !ok {
+			return
+		}
+
+		count, err := useCase.GetCycleCount(c.Request.Context(), id)
+		if err != nil {
+			respondStockError(c, "Failed to get cycle count", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(count, "Cycle count retrieved successfully", utils.GenerateRequestID()))
+	}
+}
+
+// RecordCycleCount records counted quantities
+// @Summary Record counted quantities
+// @Description Record the counted quantity of product variants in an open cycle count
+// @Tags inventory-reconciliation
+// @Accept json
+// @Produce json
+// @Param id path int true "Cycle Count ID"
+// @Param lines body entity.CycleCountEntriesRequest true "Counted Quantities"
+// @Success 200 {object} entity.CycleCount
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/counts/{id}/lines [put]
+func RecordCycleCount(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCycleCountID(c)
+		if !ok {
+			return
+		}
+
+		var req entity.CycleCountEntriesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			validationErrors := utils.ParseValidationErrors(err.Error())
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		count, err := useCase.RecordCycleCount(c.Request.Context(), id, &req)
+		if err != nil {
+			respondStockError(c, "Failed to record cycle count", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(count, "Cycle count recorded successfully", utils.GenerateRequestID()))
+	}
+}
+
+// PostCycleCount posts the adjustments of a cycle count
+// @Summary Post cycle count
+// @Description Book the difference of every counted line as an adjustment transaction and close the count
+// @Tags inventory-reconciliation
+// @Accept json
+// @Produce json
+// @Param id path int true "Cycle Count ID"
+// @Success 200 {object} entity.CycleCount
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/counts/{id}/post [post]
+func PostCycleCount(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCycleCountID(c)
+		if !ok {
+			return
+		}
+
+		count, err := useCase.PostCycleCount(c.Request.Context(), id, c.GetUint("user_id"))
+		if err != nil {
+			respondStockError(c, "Failed to post cycle count", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(count, "Cycle count posted successfully", utils.GenerateRequestID()))
+	}
+}
+
+// CancelCycleCount cancels an open cycle count
+// @Summary Cancel cycle count
+// @Description Close an open cycle count without changing stock
+// @Tags inventory-reconciliation
+// @Accept json
+// @Produce json
+// @Param id path int true "Cycle Count ID"
+// @Success 200 {object} entity.CycleCount
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/inventory/counts/{id}/cancel [post]
+func CancelCycleCount(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCycleCountID(c)
+		if !ok {
+			return
+		}
+
+		count, err := useCase.CancelCycleCount(c.Request.Context(), id)
+		if err != nil {
+			respondStockError(c, "Failed to cancel cycle count", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(count, "Cycle count cancelled successfully", utils.GenerateRequestID()))
+	}
+}
+
+func parseCycleCountID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		validationErrors := []utils.ValidationError{
+			{Field: "id", Message: "invalid cycle count ID"},
+		}
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, utils.GenerateRequestID()))
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -2,9 +2,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
 	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/usecase"
 	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
@@ -33,8 +35,7 @@ func CreateInventory(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
 		}
 
 		if err := useCase.CreateInventory(c.Request.Context(), &inventory); err != nil {
-			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to create inventory", err.Error(), utils.GenerateRequestID())
-			c.JSON(http.StatusInternalServerError, response)
+			respondStockError(c, "Failed to create inventory", err)
 			return
 		}
 
@@ -44,14 +45,15 @@ func CreateInventory(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
 
 // GetInventory gets inventory by product and variant ID
 // @Summary Get inventory
-// @Description Get inventory by product and variant ID
+// @Description Get the stock of a product variant in every warehouse, with totals
 // @Tags inventory
 // @Accept json
 // @Produce json
 // @Param product_id path string true "Product ID"
 // @Param variant_id path string true "Variant ID"
-// @Success 200 {object} entity.Inventory
+// @Success 200 {object} entity.StockLevel
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/inventory/product/{product_id}/variant/{variant_id} [get]
 func GetInventory(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
@@ -78,8 +80,7 @@ func GetInventory(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
 
 		inventory, err := useCase.GetInventory(c.Request.Context(), uint(productID), uint(variantID))
 		if err != nil {
-			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to get inventory", err.Error(), utils.GenerateRequestID())
-			c.JSON(http.StatusInternalServerError, response)
+			respondStockError(c, "Failed to get inventory", err)
 			return
 		}
 
@@ -96,6 +97,7 @@ func GetInventory(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
 // @Param inventory body entity.InventoryUpdateRequest true "Inventory Update Request"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/inventory/update [put]
 func UpdateInventory(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
@@ -109,8 +111,7 @@ func UpdateInventory(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
 		}
 
 		if err := useCase.UpdateInventory(c.Request.Context(), &req); err != nil {
-			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to update inventory", err.Error(), utils.GenerateRequestID())
-			c.JSON(http.StatusInternalServerError, response)
+			respondStockError(c, "Failed to update inventory", err)
 			return
 		}
 
@@ -120,20 +121,20 @@ func UpdateInventory(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
 
 // GetLowStockItems gets low stock items
 // @Summary Get low stock items
-// @Description Get low stock items
+// @Description Get low stock items, either below a global threshold or at or below each row's own reorder point
 // @Tags inventory
 // @Accept json
 // @Produce json
-// @Param threshold query int false "Stock threshold" default=10
+// @Param threshold query int false "Stock threshold; when omitted each row's reorder point is used, or 10 for rows without one"
 // @Success 200 {array} entity.Inventory
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/inventory/low-stock [get]
 func GetLowStockItems(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		thresholdStr := c.Query("threshold")
-		threshold := 10 // default threshold
+		threshold := -1 // per-row reorder points
 		if thresholdStr != "" {
-			if t, err := strconv.Atoi(thresholdStr); err == nil {
+			if t, err := strconv.Atoi(thresholdStr); err == nil && t >= 0 {
 				threshold = t
 			}
 		}
@@ -149,16 +150,40 @@ func GetLowStockItems(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
 	}
 }
 
+// GetInStockProducts gets the products in stock
+// @Summary Get products in stock
+// @Description Get the IDs of the products with stock that is not reserved in any warehouse
+// @Tags inventory
+// @Produce json
+// @Success 200 {array} int
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/in-stock [get]
+func GetInStockProducts(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		productIDs, err := useCase.GetInStockProductIDs(c.Request.Context())
+		if err != nil {
+			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to get products in stock", err.Error(), utils.GenerateRequestID())
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(productIDs, "Products in stock retrieved successfully", utils.GenerateRequestID()))
+	}
+}
+
 // DeleteInventory deletes inventory
 // @Summary Delete inventory
-// @Description Delete inventory by product and variant ID
+// @Description Delete inventory by product and variant ID in one warehouse
 // @Tags inventory
 // @Accept json
 // @Produce json
 // @Param product_id path string true "Product ID"
 // @Param variant_id path string true "Variant ID"
+// @Param warehouse_id query int false "Warehouse ID; the default warehouse when omitted"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/inventory/product/{product_id}/variant/{variant_id} [delete]
 func DeleteInventory(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
@@ -183,10 +208,22 @@ func DeleteInventory(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
 			return
 		}
 
-		err = useCase.DeleteInventory(c.Request.Context(), uint(productID), uint(variantID))
+		var warehouseID uint64
+		if raw := c.Query("warehouse_id"); raw != "" {
+			warehouseID, err = strconv.ParseUint(raw, 10, 32)
+			if err != nil {
+				validationErrors := []utils.ValidationError{
+					{Field: "warehouse_id", Message: "invalid warehouse ID"},
+				}
+				requestID := utils.GenerateRequestID()
+				c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+				return
+			}
+		}
+
+		err = useCase.DeleteInventory(c.Request.Context(), uint(productID), uint(variantID), uint(warehouseID))
 		if err != nil {
-			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to delete inventory", err.Error(), utils.GenerateRequestID())
-			c.JSON(http.StatusInternalServerError, response)
+			respondStockError(c, "Failed to delete inventory", err)
 			return
 		}
 
@@ -214,4 +251,182 @@ func GetInventoryList(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
 
 		c.JSON(http.StatusOK, utils.SuccessResponse(inventories, "Low stock items retrieved successfully", utils.GenerateRequestID()))
 	}
-}
\ No newline at end of file
+}
+// ReserveInventory holds stock against a reference until it is confirmed, released or expires
+// @Summary Reserve inventory
+// @Description Hold available stock for a product variant against a reference such as an order. Holds expire after ttl_seconds (default 15 minutes). Repeating a held line returns it again; asking for another quantity or warehouse is a conflict.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param reservation body entity.InventoryReservationRequest true "Reservation Request"
+// @Success 200 {object} entity.StockReservation
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/reserve [post]
+func ReserveInventory(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req entity.InventoryReservationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			validationErrors := utils.ParseValidationErrors(err.Error())
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		reservation, err := useCase.ReserveInventory(c.Request.Context(), &req)
+		if err != nil {
+			respondStockError(c, "Failed to reserve inventory", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(reservation, "Inventory reserved successfully", utils.GenerateRequestID()))
+	}
+}
+
+// ReleaseInventory releases the stock a reference holds for one product variant
+// @Summary Release inventory
+// @Description Release stock previously reserved against a reference for one product variant
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param reservation body entity.InventoryReservationRequest true "Reservation Request"
+// @Success 200 {array} entity.StockReservation
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/release [post]
+func ReleaseInventory(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req entity.InventoryReservationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			validationErrors := utils.ParseValidationErrors(err.Error())
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		reservations, err := useCase.ReleaseReservedInventory(c.Request.Context(), &req)
+		if err != nil {
+			respondStockError(c, "Failed to release inventory", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(reservations, "Inventory released successfully", utils.GenerateRequestID()))
+	}
+}
+
+// GetReservations gets the reservations held by a reference
+// @Summary Get reservations
+// @Description Get all stock reservations of a reference
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param reference_id path int true "Reference ID"
+// @Success 200 {array} entity.StockReservation
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/reservations/{reference_id} [get]
+func GetReservations(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		referenceID, ok := parseReferenceID(c)
+		if !ok {
+			return
+		}
+
+		reservations, err := useCase.GetReservations(c.Request.Context(), referenceID)
+		if err != nil {
+			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to get reservations", err.Error(), utils.GenerateRequestID())
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(reservations, "Reservations retrieved successfully", utils.GenerateRequestID()))
+	}
+}
+
+// ConfirmReservations deducts the stock held by a reference
+// @Summary Confirm reservations
+// @Description Confirm all held reservations of a reference, deducting their stock. Confirming again is a no-op.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param reference_id path int true "Reference ID"
+// @Success 200 {array} entity.StockReservation
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/reservations/{reference_id}/confirm [post]
+func ConfirmReservations(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		referenceID, ok := parseReferenceID(c)
+		if !ok {
+			return
+		}
+
+		reservations, err := useCase.ConfirmReservations(c.Request.Context(), referenceID)
+		if err != nil {
+			respondStockError(c, "Failed to confirm reservations", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(reservations, "Reservations confirmed successfully", utils.GenerateRequestID()))
+	}
+}
+
+// ReleaseReservations returns all stock held by a reference
+// @Summary Release reservations
+// @Description Release all held reservations of a reference
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param reference_id path int true "Reference ID"
+// @Success 200 {array} entity.StockReservation
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/reservations/{reference_id}/release [post]
+func ReleaseReservations(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		referenceID, ok := parseReferenceID(c)
+		if !ok {
+			return
+		}
+
+		reservations, err := useCase.ReleaseReservations(c.Request.Context(), referenceID)
+		if err != nil {
+			respondStockError(c, "Failed to release reservations", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(reservations, "Reservations released successfully", utils.GenerateRequestID()))
+	}
+}
+
+func parseReferenceID(c *gin.Context) (uint, bool) {
+	referenceID, err := strconv.ParseUint(c.Param("reference_id"), 10, 32)
+	if err != nil || referenceID == 0 {
+		validationErrors := []utils.ValidationError{
+			{Field: "reference_id", Message: "invalid reference ID"},
+		}
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, utils.GenerateRequestID()))
+		return 0, false
+	}
+	return uint(referenceID), true
+}
+
+// respondStockError maps domain errors of stock operations to HTTP statuses
+func respondStockError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, utils.ErrorResponse(utils.ErrInsufficientStock, message, err.Error(), utils.GenerateRequestID()))
+	case errors.Is(err, domain.ErrWarehouseInactive), errors.Is(err, domain.ErrNoWarehouses), errors.Is(err, domain.ErrCycleCountClosed), errors.Is(err, domain.ErrReservationConflict):
+		c.JSON(http.StatusConflict, utils.ErrorResponse(utils.ErrConflict, message, err.Error(), utils.GenerateRequestID()))
+	case errors.Is(err, domain.ErrTransactionTypeNotAllowed), errors.Is(err, domain.ErrQuantitySign):
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, message, err.Error(), utils.GenerateRequestID()))
+	case errors.Is(err, domain.ErrInventoryNotFound), errors.Is(err, domain.ErrReservationNotFound), errors.Is(err, domain.ErrWarehouseNotFound), errors.Is(err, domain.ErrCycleCountNotFound), errors.Is(err, domain.ErrSuggestionNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(utils.ErrNotFound, message, err.Error(), utils.GenerateRequestID()))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, message, err.Error(), utils.GenerateRequestID()))
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/usecase"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// SetReorderSettings sets the reorder point of a variant
+// @Summary Set reorder settings
+// @Description Set the reorder point and reorder quantity of a product variant in a warehouse. A reorder point of 0 disables reorder alerts.
+// @Tags inventory-reorder
+// @Accept json
+// @Produce json
+// @Param settings body entity.ReorderSettingsRequest true "Reorder Settings"
+// @Success 200 {object} entity.Inventory
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/reorder-settings [put]
+func SetReorderSettings(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req entity.ReorderSettingsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			validationErrors := utils.ParseValidationErrors(err.Error())
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		inventory, err := useCase.SetReorderSettings(c.Request.Context(), &req)
+		if err != nil {
+			respondStockError(c, "Failed to update reorder settings", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(inventory, "Reorder settings updated successfully", utils.GenerateRequestID()))
+	}
+}
+
+// GetReorderSuggestions lists reorder suggestions
+// @Summary Get reorder suggestions
+// @Description List reorder suggestions, newest first
+// @Tags inventory-reorder
+// @Accept json
+// @Produce json
+// @Param status query string false "Filter by status (open, resolved, dismissed)"
+// @Success 200 {array} entity.ReorderSuggestion
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/reorder-suggestions [get]
+func GetReorderSuggestions(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		suggestions, err := useCase.GetReorderSuggestions(c.Request.Context(), c.Query("status"))
+		if err != nil {
+			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to get reorder suggestions", err.Error(), utils.GenerateRequestID())
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(suggestions, "Reorder suggestions retrieved successfully", utils.GenerateRequestID()))
+	}
+}
+
+// EvaluateReorders runs the reorder evaluation now
+// @Summary Evaluate reorder points
+// @Description Compare stock with reorder points now instead of waiting for the scheduled run, and return the open suggestions
+// @Tags inventory-reorder
+// @Accept json
+// @Produce json
+// @Success 200 {array} entity.ReorderSuggestion
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/reorder-suggestions/evaluate [post]
+func EvaluateReorders(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		suggestions, err := useCase.EvaluateReorders(c.Request.Context())
+		if err != nil {
+			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to evaluate reorder points", err.Error(), utils.GenerateRequestID())
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(suggestions, "Reorder points evaluated successfully", utils.GenerateRequestID()))
+	}
+}
+
+// DismissReorderSuggestion dismisses a reorder suggestion
+// @Summary Dismiss reorder suggestion
+// @Description Close an open reorder suggestion without restocking. The variant gets no new suggestion or alert until its stock recovers above the reorder point.
+// @Tags inventory-reorder
+// @Accept json
+// @Produce json
+// @Param id path int true "Suggestion ID"
+// @Success 200 {object} entity.ReorderSuggestion
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/reorder-suggestions/{id}/dismiss [post]
+func DismissReorderSuggestion(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil || id == 0 {
+			validationErrors := []utils.ValidationError{
+				{Field: "id", Message: "invalid suggestion ID"},
+			}
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		suggestion, err := useCase.DismissReorderSuggestion(c.Request.Context(), uint(id))
+		if err != nil {
+			respondStockError(c, "Failed to dismiss reorder suggestion", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(suggestion, "Reorder suggestion dismissed successfully", utils.GenerateRequestID()))
+	}
+}
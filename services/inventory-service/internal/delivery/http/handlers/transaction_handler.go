@@ -20,6 +20,8 @@ import (
 // @Param transaction body entity.InventoryTransactionRequest true "Inventory Transaction Request"
 // @Success 200 {object} entity.InventoryTransaction
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/inventory/transactions [post]
 func CreateTransaction(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
@@ -36,19 +38,19 @@ func CreateTransaction(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
 		updateReq := &entity.InventoryUpdateRequest{
 			ProductID:       req.ProductID,
 			VariantID:       req.VariantID,
+			WarehouseID:     req.WarehouseID,
 			QuantityChange:  req.Quantity,
 			TransactionType: req.TransactionType,
 			ReferenceID:     req.ReferenceID,
+			Notes:           req.Notes,
 		}
 
-		if req.TransactionType == "out" || req.TransactionType == "reserved" {
-			updateReq.QuantityChange = -req.Quantity // Negative for out/reserved
+		if req.TransactionType == "out" {
+			updateReq.QuantityChange = -req.Quantity // Negative for out
 		}
 
 		if err := useCase.UpdateInventory(c.Request.Context(), updateReq); err != nil {
-			requestID := utils.GenerateRequestID()
-			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to process transaction", err.Error(), requestID)
-			c.JSON(http.StatusInternalServerError, response)
+			respondStockError(c, "Failed to process transaction", err)
 			return
 		}
 
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/usecase"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateWarehouse creates a new warehouse
+// @Summary Create warehouse
+// @Description Create a new fulfilment warehouse
+// @Tags warehouses
+// @Accept json
+// @Produce json
+// @Param warehouse body entity.Warehouse true "Warehouse"
+// @Success 200 {object} entity.Warehouse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/warehouses [post]
+func CreateWarehouse(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var warehouse entity.Warehouse
+		if err := c.ShouldBindJSON(&warehouse); err != nil {
+			validationErrors := utils.ParseValidationErrors(err.Error())
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		if err := useCase.CreateWarehouse(c.Request.Context(), &warehouse); err != nil {
+			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to create warehouse", err.Error(), utils.GenerateRequestID())
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(warehouse, "Warehouse created successfully", utils.GenerateRequestID()))
+	}
+}
+
+// GetWarehouses gets all warehouses
+// @Summary Get warehouses
+// @Description Get all warehouses in priority order
+// @Tags warehouses
+// @Accept json
+// @Produce json
+// @Success 200 {array} entity.Warehouse
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/warehouses [get]
+func GetWarehouses(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		warehouses, err := useCase.GetWarehouses(c.Request.Context())
+		if err != nil {
+			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to get warehouses", err.Error(), utils.GenerateRequestID())
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(warehouses, "Warehouses retrieved successfully", utils.GenerateRequestID()))
+	}
+}
+
+// GetWarehouse gets a warehouse by ID
+// @Summary Get warehouse
+// @Description Get a warehouse by ID
+// @Tags warehouses
+// @Accept json
+// @Produce json
+// @Param id path int true "Warehouse ID"
+// @Success 200 {object} entity.Warehouse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/inventory/warehouses/{id} [get]
+func GetWarehouse(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseWarehouseID(c)
+		if !ok {
+			return
+		}
+
+		warehouse, err := useCase.GetWarehouse(c.Request.Context(), id)
+		if err != nil {
+			respondStockError(c, "Failed to get warehouse", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(warehouse, "Warehouse retrieved successfully", utils.GenerateRequestID()))
+	}
+}
+
+// UpdateWarehouse updates a warehouse
+// @Summary Update warehouse
+// @Description Update a warehouse's details, priority or active flag
+// @Tags warehouses
+// @Accept json
+// @Produce json
+// @Param id path int true "Warehouse ID"
+// @Param warehouse body entity.WarehouseUpdateRequest true "Warehouse Update Request"
+// @Success 200 {object} entity.Warehouse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/warehouses/{id} [put]
+func UpdateWarehouse(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseWarehouseID(c)
+		if !ok {
+			return
+		}
+
+		var req entity.WarehouseUpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			validationErrors := utils.ParseValidationErrors(err.Error())
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		warehouse, err := useCase.UpdateWarehouse(c.Request.Context(), id, &req)
+		if err != nil {
+			respondStockError(c, "Failed to update warehouse", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(warehouse, "Warehouse updated successfully", utils.GenerateRequestID()))
+	}
+}
+
+// TransferStock moves stock between warehouses
+// @Summary Transfer stock
+// @Description Move available stock of a product variant from one warehouse to another
+// @Tags warehouses
+// @Accept json
+// @Produce json
+// @Param transfer body entity.StockTransferRequest true "Stock Transfer Request"
+// @Success 200 {object} entity.StockTransfer
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/transfers [post]
+func TransferStock(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req entity.StockTransferRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			validationErrors := utils.ParseValidationErrors(err.Error())
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		transfer, err := useCase.TransferStock(c.Request.Context(), &req)
+		if err != nil {
+			respondStockError(c, "Failed to transfer stock", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(transfer, "Stock transferred successfully", utils.GenerateRequestID()))
+	}
+}
+
+// GetTransfersByProduct gets stock transfers of a product
+// @Summary Get transfers by product
+// @Description Get inter-warehouse stock transfers of a product
+// @Tags warehouses
+// @Accept json
+// @Produce json
+// @Param product_id path string true "Product ID"
+// @Success 200 {array} entity.StockTransfer
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/transfers/product/{product_id} [get]
+func GetTransfersByProduct(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+		if err != nil {
+			validationErrors := []utils.ValidationError{
+				{Field: "product_id", Message: "invalid product ID"},
+			}
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		transfers, err := useCase.GetTransfersByProduct(c.Request.Context(), uint(productID))
+		if err != nil {
+			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to get transfers", err.Error(), utils.GenerateRequestID())
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(transfers, "Transfers retrieved successfully", utils.GenerateRequestID()))
+	}
+}
+
+// AllocateInventory chooses the warehouse that fulfils each order line
+// @Summary Allocate order lines
+// @Description Choose a warehouse for each order line, either the nearest to the shipping postal code or the fewest warehouses overall. Nothing is reserved.
+// @Tags warehouses
+// @Accept json
+// @Produce json
+// @Param allocation body entity.AllocationRequest true "Allocation Request"
+// @Success 200 {object} entity.Allocation
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/inventory/allocate [post]
+func AllocateInventory(useCase *usecase.InventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req entity.AllocationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			validationErrors := utils.ParseValidationErrors(err.Error())
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		allocation, err := useCase.Allocate(c.Request.Context(), &req)
+		if err != nil {
+			respondStockError(c, "Failed to allocate inventory", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, utils.SuccessResponse(allocation, "Inventory allocated successfully", utils.GenerateRequestID()))
+	}
+}
+
+func parseWarehouseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		validationErrors := []utils.ValidationError{
+			{Field: "id", Message: "invalid warehouse ID"},
+		}
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, utils.GenerateRequestID()))
+		return 0, false
+	}
+	return uint(id), true
+}
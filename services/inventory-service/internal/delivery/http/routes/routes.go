@@ -64,7 +64,9 @@ import (
 // 	}
 // }
 
-func SetupRoutes(router *gin.Engine, inventoryUseCase *usecase.InventoryUseCase) {
+// SetupRoutes registers the inventory routes. idempotent is applied to the POST
+// that records stock movements, so that a retried call does not move stock twice.
+func SetupRoutes(router *gin.Engine, inventoryUseCase *usecase.InventoryUseCase, idempotent gin.HandlerFunc) {
 
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, utils.SuccessResponse(map[string]string{"status": "ok"}, "Service is healthy", utils.GenerateRequestID()))
@@ -80,11 +82,58 @@ func SetupRoutes(router *gin.Engine, inventoryUseCase *usecase.InventoryUseCase)
 		inventory.GET("/product/:product_id/variant/:variant_id", handlers.GetInventory(inventoryUseCase))
 		inventory.PUT("", handlers.UpdateInventory(inventoryUseCase))
 		inventory.GET("/low-stock", handlers.GetLowStockItems(inventoryUseCase))
+		inventory.GET("/in-stock", handlers.GetInStockProducts(inventoryUseCase))
 		inventory.DELETE("/product/:product_id/variant/:variant_id", handlers.DeleteInventory(inventoryUseCase))
+		inventory.POST("/reserve", handlers.ReserveInventory(inventoryUseCase))
+		inventory.POST("/release", handlers.ReleaseInventory(inventoryUseCase))
+
+		inventory.POST("/allocate", handlers.AllocateInventory(inventoryUseCase))
+
+		warehouses := inventory.Group("/warehouses")
+		{
+			warehouses.POST("", handlers.CreateWarehouse(inventoryUseCase))
+			warehouses.GET("", handlers.GetWarehouses(inventoryUseCase))
+			warehouses.GET("/:id", handlers.GetWarehouse(inventoryUseCase))
+			warehouses.PUT("/:id", handlers.UpdateWarehouse(inventoryUseCase))
+		}
+
+		transfers := inventory.Group("/transfers")
+		{
+			transfers.POST("", handlers.TransferStock(inventoryUseCase))
+			transfers.GET("/product/:product_id", handlers.GetTransfersByProduct(inventoryUseCase))
+		}
+
+		inventory.PUT("/reorder-settings", handlers.SetReorderSettings(inventoryUseCase))
+
+		suggestions := inventory.Group("/reorder-suggestions")
+		{
+			suggestions.GET("", handlers.GetReorderSuggestions(inventoryUseCase))
+			suggestions.POST("/evaluate", handlers.EvaluateReorders(inventoryUseCase))
+			suggestions.POST("/:id/dismiss", handlers.DismissReorderSuggestion(inventoryUseCase))
+		}
+
+		inventory.GET("/reconciliation", handlers.GetReconciliation(inventoryUseCase))
+
+		counts := inventory.Group("/counts")
+		{
+			counts.POST("", handlers.OpenCycleCount(inventoryUseCase))
+			counts.GET("", handlers.GetCycleCounts(inventoryUseCase))
+			counts.GET("/:id", handlers.GetCycleCount(inventoryUseCase))
+			counts.PUT("/:id/lines", handlers.RecordCycleCount(inventoryUseCase))
+			counts.POST("/:id/post", handlers.PostCycleCount(inventoryUseCase))
+			counts.POST("/:id/cancel", handlers.CancelCycleCount(inventoryUseCase))
+		}
+
+		reservations := inventory.Group("/reservations")
+		{
+			reservations.GET("/:reference_id", handlers.GetReservations(inventoryUseCase))
+			reservations.POST("/:reference_id/confirm", handlers.ConfirmReservations(inventoryUseCase))
+			reservations.POST("/:reference_id/release", handlers.ReleaseReservations(inventoryUseCase))
+		}
 
 		transactions := inventory.Group("/transactions")
 		{
-			transactions.POST("", handlers.CreateTransaction(inventoryUseCase))
+			transactions.POST("", idempotent, handlers.CreateTransaction(inventoryUseCase))
 			transactions.GET("/product/:product_id", handlers.GetTransactionsByProduct(inventoryUseCase))
 			transactions.GET("/product/:product_id/variant/:variant_id", handlers.GetTransactionsByProductAndVariant(inventoryUseCase))
 			transactions.GET("/reference/:reference_id", handlers.GetTransactionsByReference(inventoryUseCase))
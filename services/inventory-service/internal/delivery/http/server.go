@@ -18,10 +18,11 @@ import (
 
 type Server struct {
 	inventoryUseCase *usecase.InventoryUseCase
+	idempotent       gin.HandlerFunc
 	router           *gin.Engine
 }
 
-func NewServer(inventoryUseCase *usecase.InventoryUseCase) *Server {
+func NewServer(inventoryUseCase *usecase.InventoryUseCase, idempotent gin.HandlerFunc) *Server {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
 	router.Use(gin.Logger())
@@ -30,6 +31,7 @@ func NewServer(inventoryUseCase *usecase.InventoryUseCase) *Server {
 
 	s := &Server{
 		inventoryUseCase: inventoryUseCase,
+		idempotent:       idempotent,
 		router:           router,
 	}
 
@@ -38,7 +40,7 @@ func NewServer(inventoryUseCase *usecase.InventoryUseCase) *Server {
 }
 
 func (s *Server) setupRoutes() {
-	routes.SetupRoutes(s.router, s.inventoryUseCase)
+	routes.SetupRoutes(s.router, s.inventoryUseCase, s.idempotent)
 }
 
 func (s *Server) Start() error {
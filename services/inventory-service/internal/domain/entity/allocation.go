@@ -0,0 +1,49 @@
+package entity
+
+// Allocation strategies decide which warehouse fulfils each order line
+const (
+	// AllocationNearest ships every line from the closest warehouse that has it in stock
+	AllocationNearest = "nearest"
+	// AllocationFewestSplits ships the order from as few warehouses as possible
+	AllocationFewestSplits = "fewest_splits"
+)
+
+// AllocationRequest asks which warehouses should fulfil a set of order lines
+type AllocationRequest struct {
+	Strategy   string           `json:"strategy,omitempty" binding:"omitempty,oneof=nearest fewest_splits" example:"fewest_splits"`
+	PostalCode string           `json:"postal_code,omitempty" binding:"max=20" example:"560034"`
+	Lines      []AllocationLine `json:"lines" binding:"required,min=1,dive"`
+}
+
+// AllocationLine is one order line to allocate. A line is never split across warehouses.
+type AllocationLine struct {
+	ProductID uint `json:"product_id" binding:"required"`
+	VariantID uint `json:"variant_id"`
+	Quantity  int  `json:"quantity" binding:"required,gt=0"`
+}
+
+// AllocatedLine is an order line and the warehouse chosen to fulfil it
+type AllocatedLine struct {
+	AllocationLine
+	WarehouseID   uint   `json:"warehouse_id"`
+	WarehouseCode string `json:"warehouse_code"`
+}
+
+// Allocation is the result of allocating order lines to warehouses
+type Allocation struct {
+	Strategy   string          `json:"strategy"`
+	PostalCode string          `json:"postal_code,omitempty"`
+	Lines      []AllocatedLine `json:"lines"`
+	// Shipments is the number of distinct warehouses the order ships from
+	Shipments int `json:"shipments"`
+}
+
+// StockLevel is the stock of a product variant summed over all warehouses
+type StockLevel struct {
+	ProductID        uint        `json:"product_id"`
+	VariantID        uint        `json:"variant_id"`
+	Quantity         int         `json:"quantity"`
+	ReservedQuantity int         `json:"reserved_quantity"`
+	Available        int         `json:"available"`
+	Warehouses       []Inventory `json:"warehouses"`
+}
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"time"
+)
+
+// CycleCount is a physical stock count of one warehouse. It is opened with a
+// line per counted variant, lines are filled in as shelves are counted, and
+// posting it books the differences as adjustment transactions.
+type CycleCount struct {
+	ID          uint             `json:"id" gorm:"primaryKey"`
+	WarehouseID uint             `json:"warehouse_id" gorm:"not null;index"`
+	Status      string           `json:"status" gorm:"size:20;not null;default:'open';index" validate:"oneof=open posted cancelled" example:"open"`
+	Notes       string           `json:"notes,omitempty" gorm:"size:500"`
+	OpenedBy    uint             `json:"opened_by"`
+	PostedBy    *uint            `json:"posted_by,omitempty"`
+	PostedAt    *time.Time       `json:"posted_at,omitempty"`
+	Lines       []CycleCountLine `json:"lines" gorm:"foreignKey:CycleCountID"`
+	CreatedAt   time.Time        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// CycleCountLine is the count of one product variant. ExpectedQuantity is the
+// on-hand quantity at the moment the line was counted, so stock that moves
+// between counting and posting is not corrected twice.
+type CycleCountLine struct {
+	ID               uint       `json:"id" gorm:"primaryKey"`
+	CycleCountID     uint       `json:"cycle_count_id" gorm:"not null;uniqueIndex:idx_cycle_count_line"`
+	ProductID        uint       `json:"product_id" gorm:"not null;uniqueIndex:idx_cycle_count_line"`
+	VariantID        uint       `json:"variant_id" gorm:"not null;uniqueIndex:idx_cycle_count_line"`
+	ExpectedQuantity int        `json:"expected_quantity" gorm:"not null;default:0"`
+	CountedQuantity  *int       `json:"counted_quantity"`
+	Adjustment       int        `json:"adjustment" gorm:"not null;default:0"`
+	Reason           string     `json:"reason,omitempty" gorm:"size:255"`
+	CountedAt        *time.Time `json:"counted_at,omitempty"`
+}
+
+// CycleCountRequest represents the request to open a cycle count
+type CycleCountRequest struct {
+	WarehouseID uint `json:"warehouse_id" binding:"required"`
+	// ProductIDs limits the count to some products; every variant in the warehouse is counted when empty
+	ProductIDs []uint `json:"product_ids,omitempty"`
+	Notes      string `json:"notes,omitempty" binding:"max=500"`
+}
+
+// CycleCountEntry is a counted quantity for one product variant
+type CycleCountEntry struct {
+	ProductID       uint   `json:"product_id" binding:"required"`
+	VariantID       uint   `json:"variant_id"`
+	CountedQuantity *int   `json:"counted_quantity" binding:"required,min=0"`
+	Reason          string `json:"reason,omitempty" binding:"max=255" example:"damaged in storage"`
+}
+
+// CycleCountEntriesRequest represents the request to record counted quantities
+type CycleCountEntriesRequest struct {
+	Lines []CycleCountEntry `json:"lines" binding:"required,min=1,dive"`
+}
@@ -6,10 +6,17 @@ import (
 
 type Inventory struct {
 	ID               uint      `json:"id" gorm:"primaryKey"`
-	ProductID        uint      `json:"product_id" gorm:"not null;index" validate:"required"`
-	VariantID        uint      `json:"variant_id" gorm:"not null;index" validate:"required"`
+	ProductID        uint      `json:"product_id" gorm:"not null;index;uniqueIndex:idx_inventory_stock" validate:"required"`
+	VariantID        uint      `json:"variant_id" gorm:"not null;index;uniqueIndex:idx_inventory_stock" validate:"required"`
+	// WarehouseID is the warehouse holding this stock; 0 means the default warehouse on create
+	WarehouseID      uint      `json:"warehouse_id" gorm:"not null;default:0;index;uniqueIndex:idx_inventory_stock"`
 	Quantity         int       `json:"quantity" gorm:"not null;default:0" validate:"min=0"`
 	ReservedQuantity int       `json:"reserved_quantity" gorm:"not null;default:0" validate:"min=0"`
+	// ReorderPoint is the available quantity at or below which the variant should be
+	// reordered, ReorderQuantity the least amount to order; a zero ReorderPoint disables it
+	ReorderPoint     int       `json:"reorder_point" gorm:"not null;default:0" validate:"min=0"`
+	ReorderQuantity  int       `json:"reorder_quantity" gorm:"not null;default:0" validate:"min=0"`
+	// WarehouseLocation is the bin or shelf within the warehouse
 	WarehouseLocation string   `json:"warehouse_location" gorm:"size:255" validate:"max=255"`
 	LastUpdated      time.Time `json:"last_updated" gorm:"autoUpdateTime"`
 	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
@@ -19,8 +26,23 @@ type Inventory struct {
 type InventoryUpdateRequest struct {
 	ProductID         uint `json:"product_id" validate:"required"`
 	VariantID         uint `json:"variant_id" validate:"required"`
+	WarehouseID       uint `json:"warehouse_id,omitempty"`
 	QuantityChange    int  `json:"quantity_change" validate:"required,ne=0"`
-	TransactionType   string `json:"transaction_type" validate:"required,oneof=in out reserved"`
+	TransactionType   string `json:"transaction_type" validate:"required,oneof=in out"`
 	ReferenceID       *uint `json:"reference_id,omitempty"`
 	WarehouseLocation *string `json:"warehouse_location,omitempty"`
-}
\ No newline at end of file
+	Notes             string `json:"notes,omitempty" validate:"max=500"`
+}
+// InventoryReservationRequest represents the request to reserve or release stock
+type InventoryReservationRequest struct {
+	ProductID   uint `json:"product_id" binding:"required"`
+	VariantID   uint `json:"variant_id"`
+	Quantity    int  `json:"quantity" binding:"required,gt=0"`
+	ReferenceID uint `json:"reference_id" binding:"required"`
+	// WarehouseID holds the stock in a given warehouse; when empty the nearest
+	// warehouse to PostalCode with enough stock is chosen
+	WarehouseID uint   `json:"warehouse_id,omitempty"`
+	PostalCode  string `json:"postal_code,omitempty" binding:"max=20"`
+	// TTLSeconds is how long a reservation is held; it defaults to 15 minutes
+	TTLSeconds int `json:"ttl_seconds,omitempty" binding:"omitempty,gt=0"`
+}
@@ -8,8 +8,10 @@ type InventoryTransaction struct {
 	ID             uint      `json:"id" gorm:"primaryKey"`
 	ProductID      uint      `json:"product_id" gorm:"not null;index" validate:"required"`
 	VariantID      uint      `json:"variant_id" gorm:"not null;index" validate:"required"`
-	TransactionType string   `json:"transaction_type" gorm:"not null;size:20" validate:"required,oneof=in out reserved" example:"in"`
-	Quantity       int       `json:"quantity" gorm:"not null" validate:"min=1" example:"10"`
+	WarehouseID    *uint     `json:"warehouse_id,omitempty" gorm:"index"`
+	TransactionType string   `json:"transaction_type" gorm:"not null;size:20" validate:"required,oneof=in out reserved released transfer_in transfer_out adjustment" example:"in"`
+	// Quantity is positive for every type except adjustment, whose sign is the direction of the correction
+	Quantity       int       `json:"quantity" gorm:"not null" validate:"required" example:"10"`
 	ReferenceID    *uint     `json:"reference_id,omitempty" gorm:"index" example:"123"`
 	Notes          string    `json:"notes,omitempty" gorm:"size:500" validate:"max=500"`
 	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
@@ -19,7 +21,8 @@ type InventoryTransaction struct {
 type InventoryTransactionRequest struct {
 	ProductID       uint   `json:"product_id" validate:"required"`
 	VariantID       uint   `json:"variant_id" validate:"required"`
-	TransactionType string `json:"transaction_type" validate:"required,oneof=in out reserved"`
+	WarehouseID     uint   `json:"warehouse_id,omitempty"`
+	TransactionType string `json:"transaction_type" validate:"required,oneof=in out"`
 	Quantity        int    `json:"quantity" validate:"min=1"`
 	ReferenceID     *uint  `json:"reference_id,omitempty"`
 	Notes           string `json:"notes,omitempty" validate:"max=500"`
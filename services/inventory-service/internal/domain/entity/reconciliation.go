@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+)
+
+// StockKey identifies the stock of a product variant in one warehouse
+type StockKey struct {
+	ProductID   uint `json:"product_id"`
+	VariantID   uint `json:"variant_id"`
+	WarehouseID uint `json:"warehouse_id"`
+}
+
+// StockBalance is a quantity summed per stock key, e.g. from the ledger
+type StockBalance struct {
+	StockKey
+	Quantity int `json:"quantity"`
+}
+
+// StockDiscrepancy compares an inventory row with what the ledger and the held
+// reservations say it should be
+type StockDiscrepancy struct {
+	StockKey
+	// Quantity is the stored on-hand quantity and LedgerQuantity the sum of all
+	// stock movements in the transaction ledger
+	Quantity       int `json:"quantity"`
+	LedgerQuantity int `json:"ledger_quantity"`
+	// ReservedQuantity is the stored reserved quantity and HeldQuantity the sum
+	// of reservations that are still held
+	ReservedQuantity int `json:"reserved_quantity"`
+	HeldQuantity     int `json:"held_quantity"`
+}
+
+// ReconciliationReport lists the inventory rows whose stored quantities disagree
+// with the ledger or the held reservations
+type ReconciliationReport struct {
+	Checked       int                `json:"checked"`
+	Discrepancies []StockDiscrepancy `json:"discrepancies"`
+	GeneratedAt   time.Time          `json:"generated_at"`
+}
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"time"
+)
+
+// ReorderSuggestion proposes restocking a product variant in a warehouse whose
+// available stock fell to its reorder point. It stays open while the stock is
+// low and is resolved once the stock recovers. A dismissed suggestion stands
+// in for new ones until then, and gets its resolved time when it does.
+type ReorderSuggestion struct {
+	ID           uint `json:"id" gorm:"primaryKey"`
+	ProductID    uint `json:"product_id" gorm:"not null;index:idx_reorder_suggestion_stock"`
+	VariantID    uint `json:"variant_id" gorm:"not null;index:idx_reorder_suggestion_stock"`
+	WarehouseID  uint `json:"warehouse_id" gorm:"not null;index:idx_reorder_suggestion_stock"`
+	Available    int  `json:"available"`
+	ReorderPoint int  `json:"reorder_point"`
+	// DailyVelocity is the average units shipped per day over the velocity window
+	DailyVelocity float64 `json:"daily_velocity"`
+	// DaysOfCover is how many days the available stock lasts at that velocity; nil without recent sales
+	DaysOfCover       *float64   `json:"days_of_cover,omitempty"`
+	SuggestedQuantity int        `json:"suggested_quantity"`
+	Status            string     `json:"status" gorm:"size:20;not null;default:'open';index" validate:"oneof=open resolved dismissed" example:"open"`
+	AlertedAt         *time.Time `json:"alerted_at,omitempty"`
+	ResolvedAt        *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// ReorderSettingsRequest represents the request to set a variant's reorder point in a warehouse
+type ReorderSettingsRequest struct {
+	ProductID uint `json:"product_id" binding:"required"`
+	VariantID uint `json:"variant_id"`
+	// WarehouseID defaults to the default warehouse
+	WarehouseID     uint `json:"warehouse_id,omitempty"`
+	ReorderPoint    *int `json:"reorder_point" binding:"required,min=0" example:"20"`
+	ReorderQuantity *int `json:"reorder_quantity" binding:"required,min=0" example:"100"`
+}
+
+// StockAlertLowStock is raised when a variant reaches its reorder point
+const StockAlertLowStock = "low_stock"
+
+// StockAlert is a notification about stock that needs attention
+type StockAlert struct {
+	Type       string            `json:"type"`
+	Suggestion ReorderSuggestion `json:"suggestion"`
+	RaisedAt   time.Time         `json:"raised_at"`
+}
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+)
+
+// StockReservation holds stock for an order reference until it is confirmed,
+// released or expires. While held, its quantity counts in the inventory's
+// ReservedQuantity and is not available to anyone else.
+type StockReservation struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	ReferenceID uint       `json:"reference_id" gorm:"not null;uniqueIndex:idx_reservation_warehouse_line" example:"123"`
+	ProductID   uint       `json:"product_id" gorm:"not null;uniqueIndex:idx_reservation_warehouse_line"`
+	VariantID   uint       `json:"variant_id" gorm:"not null;uniqueIndex:idx_reservation_warehouse_line"`
+	WarehouseID uint       `json:"warehouse_id" gorm:"not null;default:0;uniqueIndex:idx_reservation_warehouse_line;index"`
+	Quantity    int        `json:"quantity" gorm:"not null" example:"2"`
+	Status      string     `json:"status" gorm:"size:20;not null;default:'held';index" validate:"oneof=held confirmed released expired" example:"held"`
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"not null;index"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	ReleasedAt  *time.Time `json:"released_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
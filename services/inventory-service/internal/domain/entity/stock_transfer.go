@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+)
+
+// StockTransfer moves stock of a product variant from one warehouse to another.
+// It is recorded as a transfer_out and a transfer_in transaction referencing
+// the transfer ID.
+type StockTransfer struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	ProductID       uint      `json:"product_id" gorm:"not null;index"`
+	VariantID       uint      `json:"variant_id" gorm:"not null"`
+	FromWarehouseID uint      `json:"from_warehouse_id" gorm:"not null;index"`
+	ToWarehouseID   uint      `json:"to_warehouse_id" gorm:"not null;index"`
+	Quantity        int       `json:"quantity" gorm:"not null" example:"10"`
+	Notes           string    `json:"notes,omitempty" gorm:"size:500"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// StockTransferRequest represents the request to move stock between warehouses
+type StockTransferRequest struct {
+	ProductID       uint   `json:"product_id" binding:"required"`
+	VariantID       uint   `json:"variant_id"`
+	FromWarehouseID uint   `json:"from_warehouse_id" binding:"required"`
+	ToWarehouseID   uint   `json:"to_warehouse_id" binding:"required,nefield=FromWarehouseID"`
+	Quantity        int    `json:"quantity" binding:"required,gt=0"`
+	Notes           string `json:"notes,omitempty" binding:"max=500"`
+}
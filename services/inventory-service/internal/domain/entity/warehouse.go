@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+)
+
+// Warehouse is a fulfilment site that holds stock. Lower Priority values are
+// preferred when allocation has no better way to choose between sites.
+type Warehouse struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Code       string    `json:"code" gorm:"size:50;not null;uniqueIndex" binding:"required,max=50" example:"BLR-1"`
+	Name       string    `json:"name" gorm:"size:255;not null" binding:"required,max=255" example:"Bengaluru Fulfilment Centre"`
+	Address    string    `json:"address,omitempty" gorm:"size:500" binding:"max=500"`
+	PostalCode string    `json:"postal_code" gorm:"size:20;not null" binding:"required,max=20" example:"560001"`
+	Priority   int       `json:"priority" gorm:"not null;default:100" example:"100"`
+	Active     bool      `json:"active" gorm:"not null;default:true"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// WarehouseUpdateRequest represents the request to update a warehouse
+type WarehouseUpdateRequest struct {
+	Name       *string `json:"name,omitempty" binding:"omitempty,max=255"`
+	Address    *string `json:"address,omitempty" binding:"omitempty,max=500"`
+	PostalCode *string `json:"postal_code,omitempty" binding:"omitempty,max=20"`
+	Priority   *int    `json:"priority,omitempty"`
+	Active     *bool   `json:"active,omitempty"`
+}
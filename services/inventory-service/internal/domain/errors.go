@@ -0,0 +1,33 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrInventoryNotFound is returned when a product variant has no stock row
+	ErrInventoryNotFound = errors.New("inventory not found")
+	// ErrInsufficientStock is returned when less stock is available than was asked for
+	ErrInsufficientStock = errors.New("insufficient available inventory")
+	// ErrTransactionTypeNotAllowed is returned when a transaction type cannot
+	// be applied directly, as it belongs to reservations or transfers
+	ErrTransactionTypeNotAllowed = errors.New("transaction type cannot be applied directly")
+	// ErrQuantitySign is returned when a quantity change points the other way
+	// from its transaction type
+	ErrQuantitySign = errors.New("quantity change must be positive for in and negative for out")
+	// ErrReservationConflict is returned when a reference already holds a
+	// product variant in another warehouse or for another quantity
+	ErrReservationConflict = errors.New("reservation conflicts with an existing one")
+	// ErrReservationNotFound is returned when a reference holds no matching reservation
+	ErrReservationNotFound = errors.New("reservation not found")
+	// ErrWarehouseNotFound is returned when a warehouse does not exist
+	ErrWarehouseNotFound = errors.New("warehouse not found")
+	// ErrWarehouseInactive is returned when stock is moved into or out of an inactive warehouse
+	ErrWarehouseInactive = errors.New("warehouse is inactive")
+	// ErrNoWarehouses is returned when no active warehouse is configured
+	ErrNoWarehouses = errors.New("no active warehouse is configured")
+	// ErrCycleCountNotFound is returned when a cycle count does not exist
+	ErrCycleCountNotFound = errors.New("cycle count not found")
+	// ErrCycleCountClosed is returned when a posted or cancelled count is changed
+	ErrCycleCountClosed = errors.New("cycle count is no longer open")
+	// ErrSuggestionNotFound is returned when a reorder suggestion does not exist
+	ErrSuggestionNotFound = errors.New("reorder suggestion not found")
+)
@@ -2,17 +2,34 @@ package domain
 
 import (
 	"context"
+	"time"
+
 	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
 )
 
 type InventoryRepository interface {
+	// Create adds a stock row and records its opening quantity in the ledger
 	Create(ctx context.Context, inventory *entity.Inventory) error
-	GetByProductAndVariant(ctx context.Context, productID, variantID uint) (*entity.Inventory, error)
-	UpdateQuantity(ctx context.Context, productID, variantID uint, quantityChange int) error
+	// GetByProductAndVariant returns the stock of a product variant in every warehouse
+	GetByProductAndVariant(ctx context.Context, productID, variantID uint) ([]entity.Inventory, error)
+	GetByWarehouse(ctx context.Context, productID, variantID, warehouseID uint) (*entity.Inventory, error)
+	// ApplyTransaction changes the stock of the transaction's warehouse by
+	// quantityChange and records the transaction, both or neither
+	ApplyTransaction(ctx context.Context, transaction *entity.InventoryTransaction, quantityChange int) error
 	Update(ctx context.Context, inventory *entity.Inventory) error
-	Delete(ctx context.Context, productID, variantID uint) error
+	// Delete removes the stock of a product variant in one warehouse
+	Delete(ctx context.Context, productID, variantID, warehouseID uint) error
 	GetLowStock(ctx context.Context, threshold int) ([]entity.Inventory, error)
+	// GetBelowReorderPoint returns rows whose available stock is at or below their reorder point
+	GetBelowReorderPoint(ctx context.Context) ([]entity.Inventory, error)
+	// GetLowStockOrBelowReorderPoint returns rows at or below their reorder point
+	// and rows without one that have at most threshold units in stock
+	GetLowStockOrBelowReorderPoint(ctx context.Context, threshold int) ([]entity.Inventory, error)
+	// GetInStockProductIDs returns the products with stock that is not reserved in any warehouse
+	GetInStockProductIDs(ctx context.Context) ([]uint, error)
+	UpdateReorderSettings(ctx context.Context, productID, variantID, warehouseID uint, reorderPoint, reorderQuantity int) error
 	GetAll(ctx context.Context) ([]entity.Inventory, error)
+	GetByWarehouseID(ctx context.Context, warehouseID uint) ([]entity.Inventory, error)
 }
 
 type InventoryTransactionRepository interface {
@@ -21,4 +38,64 @@ type InventoryTransactionRepository interface {
 	GetByProductAndVariant(ctx context.Context, productID, variantID uint) ([]entity.InventoryTransaction, error)
 	GetByReferenceID(ctx context.Context, referenceID uint) ([]entity.InventoryTransaction, error)
 	GetRecent(ctx context.Context, limit int) ([]entity.InventoryTransaction, error)
-}
\ No newline at end of file
+	// GetLedgerBalances sums the stock movements of the ledger per product variant and warehouse
+	GetLedgerBalances(ctx context.Context) ([]entity.StockBalance, error)
+	// GetOutboundSince sums the "out" quantities per product variant and warehouse since a time
+	GetOutboundSince(ctx context.Context, since time.Time) ([]entity.StockBalance, error)
+}
+
+type StockReservationRepository interface {
+	// Reserve holds stock for the reservation's reference, product and variant.
+	// Reserving the same line again while it is held or confirmed returns the
+	// existing reservation unchanged.
+	Reserve(ctx context.Context, reservation *entity.StockReservation) error
+	// Confirm deducts every held reservation of a reference from stock
+	Confirm(ctx context.Context, referenceID uint) ([]entity.StockReservation, error)
+	// Release returns held stock of a reference. A nil productID releases every line.
+	Release(ctx context.Context, referenceID uint, productID, variantID *uint) ([]entity.StockReservation, error)
+	// ExpireDue releases up to limit held reservations that expired before now
+	ExpireDue(ctx context.Context, now time.Time, limit int) (int, error)
+	GetByReferenceID(ctx context.Context, referenceID uint) ([]entity.StockReservation, error)
+	// GetHeldQuantities sums held reservations per product variant and warehouse
+	GetHeldQuantities(ctx context.Context) ([]entity.StockBalance, error)
+}
+
+type WarehouseRepository interface {
+	Create(ctx context.Context, warehouse *entity.Warehouse) error
+	GetByID(ctx context.Context, id uint) (*entity.Warehouse, error)
+	Update(ctx context.Context, warehouse *entity.Warehouse) error
+	GetAll(ctx context.Context) ([]entity.Warehouse, error)
+	// GetActive returns active warehouses ordered by priority
+	GetActive(ctx context.Context) ([]entity.Warehouse, error)
+}
+
+type StockTransferRepository interface {
+	// Transfer moves available stock between warehouses and records the transfer
+	Transfer(ctx context.Context, transfer *entity.StockTransfer) error
+	GetByProduct(ctx context.Context, productID uint) ([]entity.StockTransfer, error)
+}
+
+type CycleCountRepository interface {
+	// Create opens a count together with its lines
+	Create(ctx context.Context, count *entity.CycleCount) error
+	GetByID(ctx context.Context, id uint) (*entity.CycleCount, error)
+	GetAll(ctx context.Context, status string) ([]entity.CycleCount, error)
+	// RecordLines stores counted quantities on an open count, adding lines for
+	// variants it does not have yet
+	RecordLines(ctx context.Context, id uint, entries []entity.CycleCountEntry) error
+	UpdateStatus(ctx context.Context, id uint, status string) error
+	// Post books the adjustment of every counted line of an open count and marks it posted
+	Post(ctx context.Context, id uint, postedBy uint) (*entity.CycleCount, error)
+}
+
+type ReorderSuggestionRepository interface {
+	Save(ctx context.Context, suggestion *entity.ReorderSuggestion) error
+	GetByID(ctx context.Context, id uint) (*entity.ReorderSuggestion, error)
+	// GetAll returns suggestions with a status, or all of them when status is empty
+	GetAll(ctx context.Context, status string) ([]entity.ReorderSuggestion, error)
+}
+
+// AlertSink delivers stock alerts, e.g. to a log or a webhook
+type AlertSink interface {
+	Send(ctx context.Context, alert entity.StockAlert) error
+}
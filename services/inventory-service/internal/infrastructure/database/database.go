@@ -1,13 +1,16 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/idempotency"
 	"github.com/joho/godotenv"
 	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
-	"gorm.io/driver/mysql"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/infrastructure/database/mysql"
+	gormmysql "gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
@@ -31,7 +34,7 @@ func NewMySQLConnection() *gorm.DB {
 		globalConfig.Database.Loc,
 	)
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+	db, err := gorm.Open(gormmysql.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
 	if err != nil {
@@ -42,12 +45,119 @@ func NewMySQLConnection() *gorm.DB {
 }
 
 func RunMigrations(db *gorm.DB) {
+	// Cycle counts came with ledger reconciliation, so a database without them
+	// holds stock the ledger has never seen
+	seedLedger := !db.Migrator().HasTable(&entity.CycleCount{})
+
+	// Reservation lines used to be unique without their warehouse
+	if db.Migrator().HasIndex(&entity.StockReservation{}, "idx_reservation_line") {
+		if err := db.Migrator().DropIndex(&entity.StockReservation{}, "idx_reservation_line"); err != nil {
+			log.Fatal("Failed to drop the old reservation index:", err)
+		}
+	}
+
 	err := db.AutoMigrate(
 		&entity.Inventory{},
 		&entity.InventoryTransaction{},
+		&entity.StockReservation{},
+		&entity.Warehouse{},
+		&entity.StockTransfer{},
+		&entity.CycleCount{},
+		&entity.CycleCountLine{},
+		&entity.ReorderSuggestion{},
+		&idempotency.Key{},
 	)
 	if err != nil {
 		log.Fatal("Failed to run migrations:", err)
 	}
+	if err := assignDefaultWarehouse(db); err != nil {
+		log.Fatal("Failed to assign stock to the default warehouse:", err)
+	}
+	if seedLedger {
+		if err := seedOpeningBalances(db); err != nil {
+			log.Fatal("Failed to seed opening stock balances:", err)
+		}
+	}
 	fmt.Println("Database migrations completed successfully")
-}
\ No newline at end of file
+}
+// defaultWarehouseCode is the warehouse created for stock recorded before
+// warehouses existed
+const defaultWarehouseCode = "DEFAULT"
+
+// assignDefaultWarehouse moves stock, reservations and ledger entries without a
+// warehouse into the default one, creating it on first run
+func assignDefaultWarehouse(db *gorm.DB) error {
+	var pendingStock, pendingLedger int64
+	if err := db.Model(&entity.Inventory{}).Where("warehouse_id = ?", 0).Count(&pendingStock).Error; err != nil {
+		return err
+	}
+	if err := db.Model(&entity.InventoryTransaction{}).Where("warehouse_id IS NULL").Count(&pendingLedger).Error; err != nil {
+		return err
+	}
+	if pendingStock == 0 && pendingLedger == 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		warehouse := entity.Warehouse{Code: defaultWarehouseCode}
+		err := tx.Where(&warehouse).
+			Attrs(entity.Warehouse{Name: "Default warehouse", Priority: 100, Active: true}).
+			FirstOrCreate(&warehouse).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.Inventory{}).Where("warehouse_id = ?", 0).Update("warehouse_id", warehouse.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.StockReservation{}).Where("warehouse_id = ?", 0).Update("warehouse_id", warehouse.ID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.InventoryTransaction{}).Where("warehouse_id IS NULL").Update("warehouse_id", warehouse.ID).Error
+	})
+}
+
+// seedOpeningBalances books an opening adjustment for every inventory row whose
+// quantity the ledger does not account for, so reconciliation starts from the
+// stock on hand instead of reporting every row recorded before the ledger
+func seedOpeningBalances(db *gorm.DB) error {
+	ctx := context.Background()
+	inventories, err := mysql.NewInventoryRepository(db).GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	ledger, err := mysql.NewInventoryTransactionRepository(db).GetLedgerBalances(ctx)
+	if err != nil {
+		return err
+	}
+
+	ledgerByKey := make(map[entity.StockKey]int, len(ledger))
+	for _, balance := range ledger {
+		ledgerByKey[balance.StockKey] += balance.Quantity
+	}
+
+	var openings []entity.InventoryTransaction
+	for _, inventory := range inventories {
+		key := entity.StockKey{ProductID: inventory.ProductID, VariantID: inventory.VariantID, WarehouseID: inventory.WarehouseID}
+		adjustment := inventory.Quantity - ledgerByKey[key]
+		if adjustment == 0 {
+			continue
+		}
+		warehouseID := inventory.WarehouseID
+		openings = append(openings, entity.InventoryTransaction{
+			ProductID:       inventory.ProductID,
+			VariantID:       inventory.VariantID,
+			WarehouseID:     &warehouseID,
+			TransactionType: "adjustment",
+			Quantity:        adjustment,
+			Notes:           "opening balance",
+		})
+	}
+	if len(openings) == 0 {
+		return nil
+	}
+	if err := db.CreateInBatches(openings, 500).Error; err != nil {
+		return err
+	}
+	log.Printf("Booked opening balances for %d inventory rows", len(openings))
+	return nil
+}
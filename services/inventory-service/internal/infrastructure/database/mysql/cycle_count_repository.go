@@ -0,0 +1,195 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type cycleCountRepository struct {
+	db *gorm.DB
+}
+
+func NewCycleCountRepository(db *gorm.DB) domain.CycleCountRepository {
+	return &cycleCountRepository{db: db}
+}
+
+func (r *cycleCountRepository) Create(ctx context.Context, count *entity.CycleCount) error {
+	return r.db.WithContext(ctx).Create(count).Error
+}
+
+func (r *cycleCountRepository) GetByID(ctx context.Context, id uint) (*entity.CycleCount, error) {
+	var count entity.CycleCount
+	err := r.db.WithContext(ctx).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		First(&count, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrCycleCountNotFound
+		}
+		return nil, err
+	}
+	return &count, nil
+}
+
+func (r *cycleCountRepository) GetAll(ctx context.Context, status string) ([]entity.CycleCount, error) {
+	var counts []entity.CycleCount
+	query := r.db.WithContext(ctx).Order("created_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Find(&counts).Error
+	return counts, err
+}
+
+func (r *cycleCountRepository) RecordLines(ctx context.Context, id uint, entries []entity.CycleCountEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		count, err := lockOpenCycleCount(tx, id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, entry := range entries {
+			line := findCycleCountLine(count, entry.ProductID, entry.VariantID)
+
+			var inventory entity.Inventory
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("product_id = ? AND variant_id = ? AND warehouse_id = ?", entry.ProductID, entry.VariantID, count.WarehouseID).
+				First(&inventory).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			counted := *entry.CountedQuantity
+			line.ExpectedQuantity = inventory.Quantity
+			line.CountedQuantity = &counted
+			line.Adjustment = counted - inventory.Quantity
+			line.Reason = entry.Reason
+			line.CountedAt = &now
+			if err := tx.Save(line).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *cycleCountRepository) UpdateStatus(ctx context.Context, id uint, status string) error {
+	result := r.db.WithContext(ctx).Model(&entity.CycleCount{}).
+		Where("id = ? AND status = ?", id, "open").
+		Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrCycleCountClosed
+	}
+	return nil
+}
+
+func (r *cycleCountRepository) Post(ctx context.Context, id uint, postedBy uint) (*entity.CycleCount, error) {
+	var count entity.CycleCount
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		locked, err := lockOpenCycleCount(tx, id)
+		if err != nil {
+			return err
+		}
+		count = *locked
+
+		for _, line := range count.Lines {
+			if line.CountedQuantity == nil || line.Adjustment == 0 {
+				continue
+			}
+
+			inventory, err := lockOrCreateInventory(tx, line.ProductID, line.VariantID, count.WarehouseID)
+			if err != nil {
+				return err
+			}
+			quantity := inventory.Quantity + line.Adjustment
+			if quantity < 0 {
+				return fmt.Errorf("product %d variant %d: adjustment of %d leaves negative stock; count it again", line.ProductID, line.VariantID, line.Adjustment)
+			}
+			if quantity < inventory.ReservedQuantity {
+				return fmt.Errorf("product %d variant %d: adjustment of %d leaves %d on hand but %d reserved; release the reservations or count it again",
+					line.ProductID, line.VariantID, line.Adjustment, quantity, inventory.ReservedQuantity)
+			}
+			if err := tx.Model(inventory).Update("quantity", quantity).Error; err != nil {
+				return err
+			}
+
+			referenceID := count.ID
+			warehouseID := count.WarehouseID
+			notes := fmt.Sprintf("cycle count %d", count.ID)
+			if line.Reason != "" {
+				notes = fmt.Sprintf("%s: %s", notes, line.Reason)
+			}
+			err = tx.Create(&entity.InventoryTransaction{
+				ProductID:       line.ProductID,
+				VariantID:       line.VariantID,
+				WarehouseID:     &warehouseID,
+				TransactionType: "adjustment",
+				Quantity:        line.Adjustment,
+				ReferenceID:     &referenceID,
+				Notes:           notes,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		count.Status = "posted"
+		count.PostedBy = &postedBy
+		count.PostedAt = &now
+		return tx.Model(&entity.CycleCount{ID: count.ID}).Updates(map[string]interface{}{
+			"status":    count.Status,
+			"posted_by": postedBy,
+			"posted_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
+
+// lockOpenCycleCount loads a count and its lines with the count's row locked
+// until the transaction ends, so lines are not recorded while it is posted
+func lockOpenCycleCount(tx *gorm.DB, id uint) (*entity.CycleCount, error) {
+	var count entity.CycleCount
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		First(&count, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrCycleCountNotFound
+		}
+		return nil, err
+	}
+	if count.Status != "open" {
+		return nil, domain.ErrCycleCountClosed
+	}
+	return &count, nil
+}
+
+// findCycleCountLine returns the count's line for a variant, appending a new one when missing
+func findCycleCountLine(count *entity.CycleCount, productID, variantID uint) *entity.CycleCountLine {
+	for i := range count.Lines {
+		if count.Lines[i].ProductID == productID && count.Lines[i].VariantID == variantID {
+			return &count.Lines[i]
+		}
+	}
+	count.Lines = append(count.Lines, entity.CycleCountLine{
+		CycleCountID: count.ID,
+		ProductID:    productID,
+		VariantID:    variantID,
+	})
+	return &count.Lines[len(count.Lines)-1]
+}
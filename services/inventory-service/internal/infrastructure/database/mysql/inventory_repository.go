@@ -3,6 +3,7 @@ package mysql
 import (
 	"context"
 	"errors"
+	"fmt"
 	"gorm.io/gorm"
 	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
 	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
@@ -17,46 +18,85 @@ func NewInventoryRepository(db *gorm.DB) domain.InventoryRepository {
 }
 
 func (r *inventoryRepository) Create(ctx context.Context, inventory *entity.Inventory) error {
-	return r.db.WithContext(ctx).Create(inventory).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(inventory).Error; err != nil {
+			return err
+		}
+		if inventory.Quantity == 0 {
+			return nil
+		}
+		return tx.Create(&entity.InventoryTransaction{
+			ProductID:       inventory.ProductID,
+			VariantID:       inventory.VariantID,
+			WarehouseID:     &inventory.WarehouseID,
+			TransactionType: "in",
+			Quantity:        inventory.Quantity,
+			Notes:           "opening stock",
+		}).Error
+	})
 }
 
-func (r *inventoryRepository) GetByProductAndVariant(ctx context.Context, productID, variantID uint) (*entity.Inventory, error) {
+func (r *inventoryRepository) GetByProductAndVariant(ctx context.Context, productID, variantID uint) ([]entity.Inventory, error) {
+	var inventories []entity.Inventory
+	err := r.db.WithContext(ctx).Where("product_id = ? AND variant_id = ?", productID, variantID).Order("warehouse_id").Find(&inventories).Error
+	return inventories, err
+}
+
+func (r *inventoryRepository) GetByWarehouse(ctx context.Context, productID, variantID, warehouseID uint) (*entity.Inventory, error) {
 	var inventory entity.Inventory
-	err := r.db.WithContext(ctx).Where("product_id = ? AND variant_id = ?", productID, variantID).First(&inventory).Error
+	err := r.db.WithContext(ctx).Where("product_id = ? AND variant_id = ? AND warehouse_id = ?", productID, variantID, warehouseID).First(&inventory).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("inventory not found")
+			return nil, domain.ErrInventoryNotFound
 		}
 		return nil, err
 	}
 	return &inventory, nil
 }
 
-func (r *inventoryRepository) UpdateQuantity(ctx context.Context, productID, variantID uint, quantityChange int) error {
-	var inventory entity.Inventory
-	err := r.db.WithContext(ctx).Where("product_id = ? AND variant_id = ?", productID, variantID).First(&inventory).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("inventory not found")
-		}
-		return err
+func (r *inventoryRepository) ApplyTransaction(ctx context.Context, transaction *entity.InventoryTransaction, quantityChange int) error {
+	if transaction.WarehouseID == nil {
+		return errors.New("transaction has no warehouse")
 	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		inventory, err := lockInventory(tx, transaction.ProductID, transaction.VariantID, *transaction.WarehouseID)
+		if err != nil {
+			return err
+		}
 
-	// Update the quantity
-	inventory.Quantity += quantityChange
-	if inventory.Quantity < 0 {
-		return errors.New("insufficient inventory")
-	}
+		quantity, err := applyQuantityChange(inventory, quantityChange)
+		if err != nil {
+			return err
+		}
 
-	return r.db.WithContext(ctx).Model(&inventory).Update("quantity", inventory.Quantity).Error
+		if err := tx.Model(inventory).Update("quantity", quantity).Error; err != nil {
+			return err
+		}
+		return tx.Create(transaction).Error
+	})
+}
+
+// applyQuantityChange returns the stock left after the change; stock held by
+// reservations cannot be taken out
+func applyQuantityChange(inventory *entity.Inventory, quantityChange int) (int, error) {
+	quantity := inventory.Quantity + quantityChange
+	if quantity < 0 {
+		return 0, fmt.Errorf("%w: %d in stock, %d requested", domain.ErrInsufficientStock, inventory.Quantity, -quantityChange)
+	}
+	if quantityChange < 0 && quantity < inventory.ReservedQuantity {
+		return 0, fmt.Errorf("%w: %d reserved, %d left after the change", domain.ErrInsufficientStock, inventory.ReservedQuantity, quantity)
+	}
+	return quantity, nil
 }
 
 func (r *inventoryRepository) Update(ctx context.Context, inventory *entity.Inventory) error {
 	return r.db.WithContext(ctx).Save(inventory).Error
 }
 
-func (r *inventoryRepository) Delete(ctx context.Context, productID, variantID uint) error {
-	return r.db.WithContext(ctx).Where("product_id = ? AND variant_id = ?", productID, variantID).Delete(&entity.Inventory{}).Error
+func (r *inventoryRepository) Delete(ctx context.Context, productID, variantID, warehouseID uint) error {
+	return r.db.WithContext(ctx).
+		Where("product_id = ? AND variant_id = ? AND warehouse_id = ?", productID, variantID, warehouseID).
+		Delete(&entity.Inventory{}).Error
 }
 
 func (r *inventoryRepository) GetLowStock(ctx context.Context, threshold int) ([]entity.Inventory, error) {
@@ -69,4 +109,54 @@ func (r *inventoryRepository) GetAll(ctx context.Context) ([]entity.Inventory, e
 	var inventories []entity.Inventory
 	err := r.db.WithContext(ctx).Find(&inventories).Error
 	return inventories, err
-}
\ No newline at end of file
+}
+func (r *inventoryRepository) GetByWarehouseID(ctx context.Context, warehouseID uint) ([]entity.Inventory, error) {
+	var inventories []entity.Inventory
+	err := r.db.WithContext(ctx).Where("warehouse_id = ?", warehouseID).Order("product_id, variant_id").Find(&inventories).Error
+	return inventories, err
+}
+
+func (r *inventoryRepository) GetBelowReorderPoint(ctx context.Context) ([]entity.Inventory, error) {
+	var inventories []entity.Inventory
+	err := r.db.WithContext(ctx).
+		Where("reorder_point > 0 AND quantity - reserved_quantity <= reorder_point").
+		Order("product_id, variant_id, warehouse_id").
+		Find(&inventories).Error
+	return inventories, err
+}
+
+func (r *inventoryRepository) GetLowStockOrBelowReorderPoint(ctx context.Context, threshold int) ([]entity.Inventory, error) {
+	var inventories []entity.Inventory
+	err := r.db.WithContext(ctx).
+		Where("reorder_point > 0 AND quantity - reserved_quantity <= reorder_point").
+		Or("reorder_point = 0 AND quantity <= ?", threshold).
+		Order("product_id, variant_id, warehouse_id").
+		Find(&inventories).Error
+	return inventories, err
+}
+
+func (r *inventoryRepository) GetInStockProductIDs(ctx context.Context) ([]uint, error) {
+	var productIDs []uint
+	err := r.db.WithContext(ctx).Model(&entity.Inventory{}).
+		Where("quantity - reserved_quantity > 0").
+		Distinct("product_id").
+		Order("product_id").
+		Pluck("product_id", &productIDs).Error
+	return productIDs, err
+}
+
+func (r *inventoryRepository) UpdateReorderSettings(ctx context.Context, productID, variantID, warehouseID uint, reorderPoint, reorderQuantity int) error {
+	result := r.db.WithContext(ctx).Model(&entity.Inventory{}).
+		Where("product_id = ? AND variant_id = ? AND warehouse_id = ?", productID, variantID, warehouseID).
+		Updates(map[string]interface{}{
+			"reorder_point":    reorderPoint,
+			"reorder_quantity": reorderQuantity,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrInventoryNotFound
+	}
+	return nil
+}
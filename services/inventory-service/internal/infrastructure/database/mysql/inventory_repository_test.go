@@ -0,0 +1,46 @@
+package mysql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+)
+
+func TestApplyQuantityChange(t *testing.T) {
+	tests := []struct {
+		name             string
+		quantity         int
+		reserved         int
+		change           int
+		want             int
+		wantInsufficient bool
+	}{
+		{"stock in", 10, 0, 5, 15, false},
+		{"stock in below the reservations", 2, 5, 1, 3, false},
+		{"stock out", 10, 0, -4, 6, false},
+		{"stock out to zero", 10, 0, -10, 0, false},
+		{"stock out below zero", 10, 0, -11, 0, true},
+		{"stock out down to the reservations", 10, 4, -6, 4, false},
+		{"stock out into the reservations", 10, 4, -7, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inventory := &entity.Inventory{Quantity: tt.quantity, ReservedQuantity: tt.reserved}
+			got, err := applyQuantityChange(inventory, tt.change)
+			if tt.wantInsufficient {
+				if !errors.Is(err, domain.ErrInsufficientStock) {
+					t.Errorf("applyQuantityChange(%d of %d, %d) error = %v, want %v", tt.reserved, tt.quantity, tt.change, err, domain.ErrInsufficientStock)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyQuantityChange(%d of %d, %d): %v", tt.reserved, tt.quantity, tt.change, err)
+			}
+			if got != tt.want {
+				t.Errorf("applyQuantityChange(%d of %d, %d) = %d, want %d", tt.reserved, tt.quantity, tt.change, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package mysql
 
 import (
 	"context"
+	"time"
+
 	"gorm.io/gorm"
 	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
 	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
@@ -41,4 +43,35 @@ func (r *inventoryTransactionRepository) GetRecent(ctx context.Context, limit in
 	var transactions []entity.InventoryTransaction
 	err := r.db.WithContext(ctx).Order("created_at DESC").Limit(limit).Find(&transactions).Error
 	return transactions, err
-}
\ No newline at end of file
+}
+// ledgerQuantityExpr is the effect of a transaction on on-hand stock. Rows written
+// before reservations were tracked separately stored "out" and "reserved" with a
+// negative quantity; today "reserved" and "released" only move reserved stock.
+const ledgerQuantityExpr = `CASE transaction_type
+	WHEN 'in' THEN ABS(quantity)
+	WHEN 'transfer_in' THEN ABS(quantity)
+	WHEN 'out' THEN -ABS(quantity)
+	WHEN 'transfer_out' THEN -ABS(quantity)
+	WHEN 'adjustment' THEN quantity
+	WHEN 'reserved' THEN LEAST(quantity, 0)
+	ELSE 0 END`
+
+func (r *inventoryTransactionRepository) GetLedgerBalances(ctx context.Context) ([]entity.StockBalance, error) {
+	var balances []entity.StockBalance
+	err := r.db.WithContext(ctx).Model(&entity.InventoryTransaction{}).
+		Select("product_id, variant_id, warehouse_id, SUM(" + ledgerQuantityExpr + ") AS quantity").
+		Where("warehouse_id IS NOT NULL").
+		Group("product_id, variant_id, warehouse_id").
+		Scan(&balances).Error
+	return balances, err
+}
+
+func (r *inventoryTransactionRepository) GetOutboundSince(ctx context.Context, since time.Time) ([]entity.StockBalance, error) {
+	var balances []entity.StockBalance
+	err := r.db.WithContext(ctx).Model(&entity.InventoryTransaction{}).
+		Select("product_id, variant_id, warehouse_id, SUM(ABS(quantity)) AS quantity").
+		Where("transaction_type = ? AND created_at >= ? AND warehouse_id IS NOT NULL", "out", since).
+		Group("product_id, variant_id, warehouse_id").
+		Scan(&balances).Error
+	return balances, err
+}
@@ -0,0 +1,44 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type reorderSuggestionRepository struct {
+	db *gorm.DB
+}
+
+func NewReorderSuggestionRepository(db *gorm.DB) domain.ReorderSuggestionRepository {
+	return &reorderSuggestionRepository{db: db}
+}
+
+func (r *reorderSuggestionRepository) Save(ctx context.Context, suggestion *entity.ReorderSuggestion) error {
+	return r.db.WithContext(ctx).Save(suggestion).Error
+}
+
+func (r *reorderSuggestionRepository) GetByID(ctx context.Context, id uint) (*entity.ReorderSuggestion, error) {
+	var suggestion entity.ReorderSuggestion
+	err := r.db.WithContext(ctx).First(&suggestion, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrSuggestionNotFound
+		}
+		return nil, err
+	}
+	return &suggestion, nil
+}
+
+func (r *reorderSuggestionRepository) GetAll(ctx context.Context, status string) ([]entity.ReorderSuggestion, error) {
+	var suggestions []entity.ReorderSuggestion
+	query := r.db.WithContext(ctx).Order("created_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Find(&suggestions).Error
+	return suggestions, err
+}
@@ -0,0 +1,308 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type stockReservationRepository struct {
+	db *gorm.DB
+}
+
+func NewStockReservationRepository(db *gorm.DB) domain.StockReservationRepository {
+	return &stockReservationRepository{db: db}
+}
+
+func (r *stockReservationRepository) Reserve(ctx context.Context, reservation *entity.StockReservation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		inventory, err := lockInventory(tx, reservation.ProductID, reservation.VariantID, reservation.WarehouseID)
+		if err != nil {
+			return err
+		}
+
+		// A line held or confirmed before is returned again when the request
+		// repeats it, and is a conflict when it asks for something else
+		var active entity.StockReservation
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("reference_id = ? AND product_id = ? AND variant_id = ? AND status IN ?",
+				reservation.ReferenceID, reservation.ProductID, reservation.VariantID, []string{"held", "confirmed"}).
+			First(&active).Error
+		if err == nil {
+			if active.WarehouseID != reservation.WarehouseID || active.Quantity != reservation.Quantity {
+				return fmt.Errorf("%w: reference %d holds %d in warehouse %d, %d in warehouse %d requested",
+					domain.ErrReservationConflict, reservation.ReferenceID, active.Quantity, active.WarehouseID, reservation.Quantity, reservation.WarehouseID)
+			}
+			*reservation = active
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var existing entity.StockReservation
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("reference_id = ? AND product_id = ? AND variant_id = ? AND warehouse_id = ?",
+				reservation.ReferenceID, reservation.ProductID, reservation.VariantID, reservation.WarehouseID).
+			First(&existing).Error
+		found := err == nil
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if inventory.Quantity-inventory.ReservedQuantity < reservation.Quantity {
+			return fmt.Errorf("%w: %d available, %d requested", domain.ErrInsufficientStock, inventory.Quantity-inventory.ReservedQuantity, reservation.Quantity)
+		}
+		if err := tx.Model(inventory).Update("reserved_quantity", gorm.Expr("reserved_quantity + ?", reservation.Quantity)).Error; err != nil {
+			return err
+		}
+
+		reservation.Status = "held"
+		if found {
+			// A released or expired line is held again in place of a new row
+			reservation.ID = existing.ID
+			reservation.CreatedAt = existing.CreatedAt
+			reservation.ConfirmedAt = nil
+			reservation.ReleasedAt = nil
+			err = tx.Save(reservation).Error
+		} else {
+			err = tx.Create(reservation).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		return recordTransaction(tx, reservation, "reserved", fmt.Sprintf("reservation %d held until %s", reservation.ID, reservation.ExpiresAt.Format(time.RFC3339)))
+	})
+}
+
+func (r *stockReservationRepository) Confirm(ctx context.Context, referenceID uint) ([]entity.StockReservation, error) {
+	var reservations []entity.StockReservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("reference_id = ? AND status IN ?", referenceID, []string{"held", "confirmed"})
+		inventories, err := lockReservations(tx, query, "", &reservations)
+		if err != nil {
+			return err
+		}
+		if len(reservations) == 0 {
+			return domain.ErrReservationNotFound
+		}
+
+		now := time.Now()
+		for i := range reservations {
+			reservation := &reservations[i]
+			if reservation.Status == "confirmed" {
+				continue
+			}
+
+			inventory := inventories[keyOf(reservation)]
+			err = tx.Model(inventory).Updates(map[string]interface{}{
+				"quantity":          gorm.Expr("quantity - ?", reservation.Quantity),
+				"reserved_quantity": gorm.Expr("reserved_quantity - ?", reservation.Quantity),
+			}).Error
+			if err != nil {
+				return err
+			}
+
+			reservation.Status = "confirmed"
+			reservation.ConfirmedAt = &now
+			if err := tx.Save(reservation).Error; err != nil {
+				return err
+			}
+			if err := recordTransaction(tx, reservation, "out", fmt.Sprintf("reservation %d confirmed", reservation.ID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+func (r *stockReservationRepository) Release(ctx context.Context, referenceID uint, productID, variantID *uint) ([]entity.StockReservation, error) {
+	var reservations []entity.StockReservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("reference_id = ? AND status = ?", referenceID, "held")
+		if productID != nil {
+			query = query.Where("product_id = ?", *productID)
+		}
+		if variantID != nil {
+			query = query.Where("variant_id = ?", *variantID)
+		}
+		inventories, err := lockReservations(tx, query, "", &reservations)
+		if err != nil {
+			return err
+		}
+		if len(reservations) == 0 {
+			return domain.ErrReservationNotFound
+		}
+
+		for i := range reservations {
+			reservation := &reservations[i]
+			if err := releaseReservation(tx, reservation, inventories[keyOf(reservation)], "released"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+func (r *stockReservationRepository) ExpireDue(ctx context.Context, now time.Time, limit int) (int, error) {
+	var count int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var reservations []entity.StockReservation
+		query := tx.Where("status = ? AND expires_at < ?", "held", now).Order("expires_at").Limit(limit)
+		inventories, err := lockReservations(tx, query, "SKIP LOCKED", &reservations)
+		if err != nil {
+			return err
+		}
+
+		for i := range reservations {
+			reservation := &reservations[i]
+			if err := releaseReservation(tx, reservation, inventories[keyOf(reservation)], "expired"); err != nil {
+				return err
+			}
+		}
+		count = len(reservations)
+		return nil
+	})
+	return count, err
+}
+
+func (r *stockReservationRepository) GetByReferenceID(ctx context.Context, referenceID uint) ([]entity.StockReservation, error) {
+	var reservations []entity.StockReservation
+	err := r.db.WithContext(ctx).Where("reference_id = ?", referenceID).Order("id").Find(&reservations).Error
+	return reservations, err
+}
+
+func (r *stockReservationRepository) GetHeldQuantities(ctx context.Context) ([]entity.StockBalance, error) {
+	var balances []entity.StockBalance
+	err := r.db.WithContext(ctx).Model(&entity.StockReservation{}).
+		Select("product_id, variant_id, warehouse_id, SUM(quantity) AS quantity").
+		Where("status = ?", "held").
+		Group("product_id, variant_id, warehouse_id").
+		Scan(&balances).Error
+	return balances, err
+}
+
+// releaseReservation returns a held reservation's stock to its locked
+// inventory row and marks it released or expired
+func releaseReservation(tx *gorm.DB, reservation *entity.StockReservation, inventory *entity.Inventory, status string) error {
+	if err := tx.Model(inventory).Update("reserved_quantity", gorm.Expr("GREATEST(reserved_quantity - ?, 0)", reservation.Quantity)).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	reservation.Status = status
+	reservation.ReleasedAt = &now
+	if err := tx.Save(reservation).Error; err != nil {
+		return err
+	}
+	return recordTransaction(tx, reservation, "released", fmt.Sprintf("reservation %d %s", reservation.ID, status))
+}
+
+// inventoryKey identifies the inventory row a reservation holds stock in
+type inventoryKey struct {
+	productID, variantID, warehouseID uint
+}
+
+func keyOf(reservation *entity.StockReservation) inventoryKey {
+	return inventoryKey{reservation.ProductID, reservation.VariantID, reservation.WarehouseID}
+}
+
+// lockReservations locks the reservations query matches together with their
+// inventory rows. Like Reserve it takes the inventory locks first, in key
+// order, and only then the reservation rows, so that transactions changing
+// the same stock cannot deadlock. Reservations that changed in between are
+// left out, as query is run again under the lock.
+func lockReservations(tx *gorm.DB, query *gorm.DB, lockOptions string, reservations *[]entity.StockReservation) (map[inventoryKey]*entity.Inventory, error) {
+	var candidates []entity.StockReservation
+	if err := query.Session(&gorm.Session{}).Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		*reservations = nil
+		return nil, nil
+	}
+
+	keys := make([]inventoryKey, 0, len(candidates))
+	ids := make([]uint, 0, len(candidates))
+	inventories := make(map[inventoryKey]*entity.Inventory)
+	for i := range candidates {
+		ids = append(ids, candidates[i].ID)
+		key := keyOf(&candidates[i])
+		if _, ok := inventories[key]; !ok {
+			inventories[key] = nil
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.productID != b.productID {
+			return a.productID < b.productID
+		}
+		if a.variantID != b.variantID {
+			return a.variantID < b.variantID
+		}
+		return a.warehouseID < b.warehouseID
+	})
+	for _, key := range keys {
+		inventory, err := lockInventory(tx, key.productID, key.variantID, key.warehouseID)
+		if err != nil {
+			return nil, err
+		}
+		inventories[key] = inventory
+	}
+
+	err := query.Session(&gorm.Session{}).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: lockOptions}).
+		Where("id IN ?", ids).
+		Order("id").
+		Find(reservations).Error
+	if err != nil {
+		return nil, err
+	}
+	return inventories, nil
+}
+
+// lockInventory loads an inventory row with a row lock held until the transaction ends
+func lockInventory(tx *gorm.DB, productID, variantID, warehouseID uint) (*entity.Inventory, error) {
+	var inventory entity.Inventory
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ? AND variant_id = ? AND warehouse_id = ?", productID, variantID, warehouseID).
+		First(&inventory).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrInventoryNotFound
+		}
+		return nil, err
+	}
+	return &inventory, nil
+}
+
+func recordTransaction(tx *gorm.DB, reservation *entity.StockReservation, transactionType, notes string) error {
+	referenceID := reservation.ReferenceID
+	warehouseID := reservation.WarehouseID
+	return tx.Create(&entity.InventoryTransaction{
+		ProductID:       reservation.ProductID,
+		VariantID:       reservation.VariantID,
+		WarehouseID:     &warehouseID,
+		TransactionType: transactionType,
+		Quantity:        reservation.Quantity,
+		ReferenceID:     &referenceID,
+		Notes:           notes,
+	}).Error
+}
@@ -0,0 +1,96 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type stockTransferRepository struct {
+	db *gorm.DB
+}
+
+func NewStockTransferRepository(db *gorm.DB) domain.StockTransferRepository {
+	return &stockTransferRepository{db: db}
+}
+
+func (r *stockTransferRepository) Transfer(ctx context.Context, transfer *entity.StockTransfer) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock both rows in warehouse order so that opposite transfers cannot deadlock
+		var source, destination *entity.Inventory
+		var err error
+		if transfer.FromWarehouseID < transfer.ToWarehouseID {
+			if source, err = lockInventory(tx, transfer.ProductID, transfer.VariantID, transfer.FromWarehouseID); err != nil {
+				return err
+			}
+			destination, err = lockOrCreateInventory(tx, transfer.ProductID, transfer.VariantID, transfer.ToWarehouseID)
+		} else {
+			if destination, err = lockOrCreateInventory(tx, transfer.ProductID, transfer.VariantID, transfer.ToWarehouseID); err != nil {
+				return err
+			}
+			source, err = lockInventory(tx, transfer.ProductID, transfer.VariantID, transfer.FromWarehouseID)
+		}
+		if err != nil {
+			return err
+		}
+
+		available := source.Quantity - source.ReservedQuantity
+		if available < transfer.Quantity {
+			return fmt.Errorf("%w: %d available, %d requested", domain.ErrInsufficientStock, available, transfer.Quantity)
+		}
+		if err := tx.Model(source).Update("quantity", gorm.Expr("quantity - ?", transfer.Quantity)).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(destination).Update("quantity", gorm.Expr("quantity + ?", transfer.Quantity)).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(transfer).Error; err != nil {
+			return err
+		}
+
+		notes := fmt.Sprintf("transfer %d from warehouse %d to warehouse %d", transfer.ID, transfer.FromWarehouseID, transfer.ToWarehouseID)
+		for _, leg := range []struct {
+			transactionType string
+			warehouseID     uint
+		}{
+			{"transfer_out", transfer.FromWarehouseID},
+			{"transfer_in", transfer.ToWarehouseID},
+		} {
+			referenceID := transfer.ID
+			warehouseID := leg.warehouseID
+			err := tx.Create(&entity.InventoryTransaction{
+				ProductID:       transfer.ProductID,
+				VariantID:       transfer.VariantID,
+				WarehouseID:     &warehouseID,
+				TransactionType: leg.transactionType,
+				Quantity:        transfer.Quantity,
+				ReferenceID:     &referenceID,
+				Notes:           notes,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *stockTransferRepository) GetByProduct(ctx context.Context, productID uint) ([]entity.StockTransfer, error) {
+	var transfers []entity.StockTransfer
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("created_at DESC").Find(&transfers).Error
+	return transfers, err
+}
+
+// lockOrCreateInventory locks the stock row of a warehouse, creating an empty one
+// when the warehouse has never held the variant
+func lockOrCreateInventory(tx *gorm.DB, productID, variantID, warehouseID uint) (*entity.Inventory, error) {
+	inventory := entity.Inventory{ProductID: productID, VariantID: variantID, WarehouseID: warehouseID}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&inventory).Error; err != nil {
+		return nil, err
+	}
+	return lockInventory(tx, productID, variantID, warehouseID)
+}
@@ -0,0 +1,50 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type warehouseRepository struct {
+	db *gorm.DB
+}
+
+func NewWarehouseRepository(db *gorm.DB) domain.WarehouseRepository {
+	return &warehouseRepository{db: db}
+}
+
+func (r *warehouseRepository) Create(ctx context.Context, warehouse *entity.Warehouse) error {
+	return r.db.WithContext(ctx).Create(warehouse).Error
+}
+
+func (r *warehouseRepository) GetByID(ctx context.Context, id uint) (*entity.Warehouse, error) {
+	var warehouse entity.Warehouse
+	err := r.db.WithContext(ctx).First(&warehouse, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrWarehouseNotFound
+		}
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (r *warehouseRepository) Update(ctx context.Context, warehouse *entity.Warehouse) error {
+	return r.db.WithContext(ctx).Save(warehouse).Error
+}
+
+func (r *warehouseRepository) GetAll(ctx context.Context) ([]entity.Warehouse, error) {
+	var warehouses []entity.Warehouse
+	err := r.db.WithContext(ctx).Order("priority, id").Find(&warehouses).Error
+	return warehouses, err
+}
+
+func (r *warehouseRepository) GetActive(ctx context.Context) ([]entity.Warehouse, error) {
+	var warehouses []entity.Warehouse
+	err := r.db.WithContext(ctx).Where("active = ?", true).Order("priority, id").Find(&warehouses).Error
+	return warehouses, err
+}
@@ -0,0 +1,24 @@
+package notification
+
+import (
+	"context"
+	"log"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+)
+
+type logSink struct{}
+
+// NewLogSink writes alerts to the service log
+func NewLogSink() domain.AlertSink {
+	return &logSink{}
+}
+
+func (s *logSink) Send(ctx context.Context, alert entity.StockAlert) error {
+	suggestion := alert.Suggestion
+	log.Printf("stock alert %s: product %d variant %d warehouse %d has %d available (reorder point %d), suggest ordering %d",
+		alert.Type, suggestion.ProductID, suggestion.VariantID, suggestion.WarehouseID,
+		suggestion.Available, suggestion.ReorderPoint, suggestion.SuggestedQuantity)
+	return nil
+}
@@ -0,0 +1,40 @@
+// Package notification delivers stock alerts to logs and webhooks
+package notification
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+)
+
+// NewAlertSink builds the sinks configured for the service: alerts are always
+// logged and also posted to the stock alert webhook when one is configured
+func NewAlertSink(cfg config.Config) domain.AlertSink {
+	sinks := []domain.AlertSink{NewLogSink()}
+	if cfg.Webhooks.StockAlertURL != "" {
+		sinks = append(sinks, NewWebhookSink(cfg.Webhooks.StockAlertURL, cfg.Webhooks.StockAlertSecret))
+	}
+	return NewMultiSink(sinks...)
+}
+
+type multiSink struct {
+	sinks []domain.AlertSink
+}
+
+// NewMultiSink sends every alert to all sinks and reports every failure
+func NewMultiSink(sinks ...domain.AlertSink) domain.AlertSink {
+	return &multiSink{sinks: sinks}
+}
+
+func (s *multiSink) Send(ctx context.Context, alert entity.StockAlert) error {
+	var errs []error
+	for _, sink := range s.sinks {
+		if err := sink.Send(ctx, alert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,62 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+)
+
+const webhookTimeout = 5 * time.Second
+
+type webhookSink struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookSink posts alerts as JSON to url. When secret is set each request is
+// signed like the payment webhooks, with X-Webhook-Timestamp and X-Webhook-Signature.
+func NewWebhookSink(url, secret string) domain.AlertSink {
+	return &webhookSink{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (s *webhookSink) Send(ctx context.Context, alert entity.StockAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(utils.WebhookTimestampHeader, timestamp)
+		req.Header.Set(utils.WebhookSignatureHeader, utils.SignWebhook(s.secret, timestamp, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("stock alert webhook failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("stock alert webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+)
+
+// Allocate decides which active warehouse fulfils each order line. Every line
+// ships whole from one warehouse that has its full quantity available. It only
+// plans; stock is held by reserving each line in its chosen warehouse.
+func (uc *InventoryUseCase) Allocate(ctx context.Context, req *entity.AllocationRequest) (*entity.Allocation, error) {
+	strategy := req.Strategy
+	if strategy == "" {
+		strategy = entity.AllocationNearest
+	}
+
+	warehouses, err := uc.warehouseRepo.GetActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(warehouses) == 0 {
+		return nil, domain.ErrNoWarehouses
+	}
+	ranked := rankWarehouses(warehouses, req.PostalCode)
+
+	// candidates[i] holds the warehouses that can ship line i on their own
+	candidates := make([]map[uint]bool, len(req.Lines))
+	for i, line := range req.Lines {
+		inventories, err := uc.inventoryRepo.GetByProductAndVariant(ctx, line.ProductID, line.VariantID)
+		if err != nil {
+			return nil, err
+		}
+		candidates[i] = make(map[uint]bool)
+		for _, inventory := range inventories {
+			if inventory.Quantity-inventory.ReservedQuantity >= line.Quantity {
+				candidates[i][inventory.WarehouseID] = true
+			}
+		}
+	}
+
+	var chosen []int
+	switch strategy {
+	case entity.AllocationFewestSplits:
+		chosen = allocateFewestSplits(ranked, candidates)
+	default:
+		chosen = allocateNearest(ranked, candidates)
+	}
+
+	allocation := &entity.Allocation{Strategy: strategy, PostalCode: req.PostalCode}
+	used := make(map[uint]bool)
+	for i, line := range req.Lines {
+		if chosen[i] < 0 {
+			return nil, fmt.Errorf("%w: no warehouse has %d of product %d variant %d available", domain.ErrInsufficientStock, line.Quantity, line.ProductID, line.VariantID)
+		}
+		warehouse := ranked[chosen[i]]
+		allocation.Lines = append(allocation.Lines, entity.AllocatedLine{
+			AllocationLine: line,
+			WarehouseID:    warehouse.ID,
+			WarehouseCode:  warehouse.Code,
+		})
+		used[warehouse.ID] = true
+	}
+	allocation.Shipments = len(used)
+	return allocation, nil
+}
+
+// allocateNearest picks, for every line, the best ranked warehouse that can ship it.
+// It returns an index into ranked per line, or -1 when no warehouse can.
+func allocateNearest(ranked []entity.Warehouse, candidates []map[uint]bool) []int {
+	chosen := make([]int, len(candidates))
+	for i := range candidates {
+		chosen[i] = -1
+		for j, warehouse := range ranked {
+			if candidates[i][warehouse.ID] {
+				chosen[i] = j
+				break
+			}
+		}
+	}
+	return chosen
+}
+
+// allocateFewestSplits repeatedly picks the warehouse that can ship the most
+// unallocated lines, preferring better ranked warehouses on ties. This greedy
+// cover is not always optimal but stays close for the handful of lines in an order.
+func allocateFewestSplits(ranked []entity.Warehouse, candidates []map[uint]bool) []int {
+	chosen := make([]int, len(candidates))
+	for i := range chosen {
+		chosen[i] = -1
+	}
+
+	for {
+		best, bestCount := -1, 0
+		for j, warehouse := range ranked {
+			count := 0
+			for i := range candidates {
+				if chosen[i] < 0 && candidates[i][warehouse.ID] {
+					count++
+				}
+			}
+			if count > bestCount {
+				best, bestCount = j, count
+			}
+		}
+		if best < 0 {
+			return chosen
+		}
+		for i := range candidates {
+			if chosen[i] < 0 && candidates[i][ranked[best].ID] {
+				chosen[i] = best
+			}
+		}
+	}
+}
+
+// rankWarehouses orders warehouses by distance from a postal code. Warehouses
+// come in priority order, which breaks ties and is kept when no postal code is given.
+func rankWarehouses(warehouses []entity.Warehouse, postalCode string) []entity.Warehouse {
+	ranked := make([]entity.Warehouse, len(warehouses))
+	copy(ranked, warehouses)
+	if postalCode == "" {
+		return ranked
+	}
+
+	sort.SliceStable(ranked, func(a, b int) bool {
+		sharedA, diffA := postalDistance(ranked[a].PostalCode, postalCode)
+		sharedB, diffB := postalDistance(ranked[b].PostalCode, postalCode)
+		if sharedA != sharedB {
+			return sharedA > sharedB
+		}
+		return diffA < diffB
+	})
+	return ranked
+}
+
+// postalDistance estimates how far apart two postal codes are without a geo
+// database. Postal codes are hierarchical (the first digit of an Indian PIN is
+// the region, the first three the sorting district), so a longer shared prefix
+// means closer; codes sharing the same prefix are compared numerically.
+func postalDistance(from, to string) (shared int, diff int64) {
+	for shared < len(from) && shared < len(to) && from[shared] == to[shared] {
+		shared++
+	}
+
+	a, errA := strconv.ParseInt(from, 10, 64)
+	b, errB := strconv.ParseInt(to, 10, 64)
+	if errA != nil || errB != nil || len(from) != len(to) {
+		return shared, math.MaxInt64
+	}
+	if a > b {
+		return shared, a - b
+	}
+	return shared, b - a
+}
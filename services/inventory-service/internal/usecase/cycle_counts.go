@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+)
+
+// OpenCycleCount starts a count of a warehouse with a line per variant it stocks
+func (uc *InventoryUseCase) OpenCycleCount(ctx context.Context, req *entity.CycleCountRequest, openedBy uint) (*entity.CycleCount, error) {
+	warehouse, err := uc.warehouseRepo.GetByID(ctx, req.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+	if !warehouse.Active {
+		return nil, domain.ErrWarehouseInactive
+	}
+
+	inventories, err := uc.inventoryRepo.GetByWarehouseID(ctx, warehouse.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	products := make(map[uint]bool, len(req.ProductIDs))
+	for _, id := range req.ProductIDs {
+		products[id] = true
+	}
+
+	count := &entity.CycleCount{
+		WarehouseID: warehouse.ID,
+		Status:      "open",
+		Notes:       req.Notes,
+		OpenedBy:    openedBy,
+	}
+	for _, inventory := range inventories {
+		if len(products) > 0 && !products[inventory.ProductID] {
+			continue
+		}
+		count.Lines = append(count.Lines, entity.CycleCountLine{
+			ProductID:        inventory.ProductID,
+			VariantID:        inventory.VariantID,
+			ExpectedQuantity: inventory.Quantity,
+		})
+	}
+
+	if err := uc.cycleCountRepo.Create(ctx, count); err != nil {
+		return nil, err
+	}
+	return count, nil
+}
+
+// RecordCycleCount stores counted quantities. Each line's expected quantity is
+// taken when it is counted and the difference becomes its adjustment. Variants
+// found on the shelf but not in the count are added to it.
+func (uc *InventoryUseCase) RecordCycleCount(ctx context.Context, id uint, req *entity.CycleCountEntriesRequest) (*entity.CycleCount, error) {
+	if err := uc.cycleCountRepo.RecordLines(ctx, id, req.Lines); err != nil {
+		return nil, err
+	}
+	return uc.cycleCountRepo.GetByID(ctx, id)
+}
+
+// PostCycleCount books the adjustments of all counted lines as adjustment transactions
+func (uc *InventoryUseCase) PostCycleCount(ctx context.Context, id uint, postedBy uint) (*entity.CycleCount, error) {
+	return uc.cycleCountRepo.Post(ctx, id, postedBy)
+}
+
+// CancelCycleCount closes an open count without changing stock
+func (uc *InventoryUseCase) CancelCycleCount(ctx context.Context, id uint) (*entity.CycleCount, error) {
+	if err := uc.cycleCountRepo.UpdateStatus(ctx, id, "cancelled"); err != nil {
+		if _, getErr := uc.cycleCountRepo.GetByID(ctx, id); getErr != nil {
+			return nil, getErr
+		}
+		return nil, err
+	}
+	return uc.cycleCountRepo.GetByID(ctx, id)
+}
+
+func (uc *InventoryUseCase) GetCycleCount(ctx context.Context, id uint) (*entity.CycleCount, error) {
+	return uc.cycleCountRepo.GetByID(ctx, id)
+}
+
+func (uc *InventoryUseCase) GetCycleCounts(ctx context.Context, status string) ([]entity.CycleCount, error) {
+	return uc.cycleCountRepo.GetAll(ctx, status)
+}
@@ -4,62 +4,145 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
+
 	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
 	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
 )
 
+// DefaultReservationTTL is how long stock stays held when the caller does not ask for a TTL
+const DefaultReservationTTL = 15 * time.Minute
+
+// maxReservationTTL caps caller supplied TTLs so abandoned holds always expire
+const maxReservationTTL = 24 * time.Hour
+
+// DefaultLowStockThreshold is the low stock level of rows without a reorder point
+const DefaultLowStockThreshold = 10
+
 type InventoryUseCase struct {
 	inventoryRepo        domain.InventoryRepository
 	transactionRepo domain.InventoryTransactionRepository
+	reservationRepo domain.StockReservationRepository
+	warehouseRepo   domain.WarehouseRepository
+	transferRepo    domain.StockTransferRepository
+	cycleCountRepo  domain.CycleCountRepository
+	suggestionRepo  domain.ReorderSuggestionRepository
+	alerts          domain.AlertSink
 }
 
 func NewInventoryUseCase(
 	inventoryRepo domain.InventoryRepository,
 	transactionRepo domain.InventoryTransactionRepository,
+	reservationRepo domain.StockReservationRepository,
+	warehouseRepo domain.WarehouseRepository,
+	transferRepo domain.StockTransferRepository,
+	cycleCountRepo domain.CycleCountRepository,
+	suggestionRepo domain.ReorderSuggestionRepository,
+	alerts domain.AlertSink,
 ) *InventoryUseCase {
 	return &InventoryUseCase{
 		inventoryRepo:        inventoryRepo,
 		transactionRepo: transactionRepo,
+		reservationRepo: reservationRepo,
+		warehouseRepo:   warehouseRepo,
+		transferRepo:    transferRepo,
+		cycleCountRepo:  cycleCountRepo,
+		suggestionRepo:  suggestionRepo,
+		alerts:          alerts,
 	}
 }
 
 func (uc *InventoryUseCase) CreateInventory(ctx context.Context, inventory *entity.Inventory) error {
+	warehouse, err := uc.resolveWarehouse(ctx, inventory.WarehouseID)
+	if err != nil {
+		return err
+	}
+	inventory.WarehouseID = warehouse.ID
+
 	// Check if inventory already exists
-	existing, err := uc.inventoryRepo.GetByProductAndVariant(ctx, inventory.ProductID, inventory.VariantID)
+	existing, err := uc.inventoryRepo.GetByWarehouse(ctx, inventory.ProductID, inventory.VariantID, inventory.WarehouseID)
 	if err == nil && existing != nil {
-		return errors.New("inventory already exists for this product and variant")
+		return errors.New("inventory already exists for this product and variant in this warehouse")
 	}
 
 	return uc.inventoryRepo.Create(ctx, inventory)
 }
 
-func (uc *InventoryUseCase) DeleteInventory(ctx context.Context, productID, variantID uint) error {
-	return uc.inventoryRepo.Delete(ctx, productID, variantID)
+// DeleteInventory removes the stock of a product variant in one warehouse, the
+// default one when warehouseID is 0
+func (uc *InventoryUseCase) DeleteInventory(ctx context.Context, productID, variantID, warehouseID uint) error {
+	warehouse, err := uc.resolveWarehouse(ctx, warehouseID)
+	if err != nil {
+		return err
+	}
+	return uc.inventoryRepo.Delete(ctx, productID, variantID, warehouse.ID)
 }
 
-func (uc *InventoryUseCase) GetInventory(ctx context.Context, productID, variantID uint) (*entity.Inventory, error) {
-	return uc.inventoryRepo.GetByProductAndVariant(ctx, productID, variantID)
+// GetInventory returns the stock of a product variant in every warehouse and its totals
+func (uc *InventoryUseCase) GetInventory(ctx context.Context, productID, variantID uint) (*entity.StockLevel, error) {
+	inventories, err := uc.inventoryRepo.GetByProductAndVariant(ctx, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(inventories) == 0 {
+		return nil, domain.ErrInventoryNotFound
+	}
+
+	level := &entity.StockLevel{ProductID: productID, VariantID: variantID, Warehouses: inventories}
+	for _, inventory := range inventories {
+		level.Quantity += inventory.Quantity
+		level.ReservedQuantity += inventory.ReservedQuantity
+	}
+	level.Available = level.Quantity - level.ReservedQuantity
+	return level, nil
+}
+
+// checkQuantityChange allows only stock in with a positive change and stock out
+// with a negative one. Holds go through ReserveInventory so that they can be
+// confirmed, released and expired
+func checkQuantityChange(transactionType string, quantityChange int) error {
+	if transactionType != "in" && transactionType != "out" {
+		return fmt.Errorf("%w: %q; use the reservation endpoints", domain.ErrTransactionTypeNotAllowed, transactionType)
+	}
+	if quantityChange == 0 || (transactionType == "in") != (quantityChange > 0) {
+		return fmt.Errorf("%w: %s of %d", domain.ErrQuantitySign, transactionType, quantityChange)
+	}
+	return nil
 }
 
 func (uc *InventoryUseCase) UpdateInventory(ctx context.Context, req *entity.InventoryUpdateRequest) error {
-	// Create transaction record
+	if err := checkQuantityChange(req.TransactionType, req.QuantityChange); err != nil {
+		return err
+	}
+
+	warehouse, err := uc.resolveWarehouse(ctx, req.WarehouseID)
+	if err != nil {
+		return err
+	}
+
+	// The ledger stores the quantity moved; the transaction type gives its direction
+	quantity := req.QuantityChange
+	if quantity < 0 {
+		quantity = -quantity
+	}
 	transaction := &entity.InventoryTransaction{
 		ProductID:       req.ProductID,
 		VariantID:       req.VariantID,
+		WarehouseID:     &warehouse.ID,
 		TransactionType: req.TransactionType,
-		Quantity:        req.QuantityChange,
+		Quantity:        quantity,
 		ReferenceID:     req.ReferenceID,
+		Notes:           req.Notes,
 	}
 
-	// Update inventory quantity
-	err := uc.inventoryRepo.UpdateQuantity(ctx, req.ProductID, req.VariantID, req.QuantityChange)
-	if err != nil {
+	// Update inventory quantity and record the transaction together
+	if err := uc.inventoryRepo.ApplyTransaction(ctx, transaction, req.QuantityChange); err != nil {
 		return err
 	}
 
 	// Update warehouse location if provided
 	if req.WarehouseLocation != nil {
-		inventory, err := uc.inventoryRepo.GetByProductAndVariant(ctx, req.ProductID, req.VariantID)
+		inventory, err := uc.inventoryRepo.GetByWarehouse(ctx, req.ProductID, req.VariantID, warehouse.ID)
 		if err != nil {
 			return err
 		}
@@ -70,12 +153,6 @@ func (uc *InventoryUseCase) UpdateInventory(ctx context.Context, req *entity.Inv
 		}
 	}
 
-	// Save transaction
-	err = uc.transactionRepo.Create(ctx, transaction)
-	if err != nil {
-		return fmt.Errorf("failed to record inventory transaction: %w", err)
-	}
-
 	return nil
 }
 
@@ -88,32 +165,101 @@ func (uc *InventoryUseCase) GetInventoryTransactions(ctx context.Context, produc
 	return nil, errors.New("either productID or both productID and variantID must be provided")
 }
 
+// GetLowStockItems returns rows with at most threshold units in stock. Without a
+// threshold it returns the rows at or below their own reorder point, and rows
+// without a reorder point that have at most DefaultLowStockThreshold units.
 func (uc *InventoryUseCase) GetLowStockItems(ctx context.Context, threshold int) ([]entity.Inventory, error) {
+	if threshold < 0 {
+		return uc.inventoryRepo.GetLowStockOrBelowReorderPoint(ctx, DefaultLowStockThreshold)
+	}
 	return uc.inventoryRepo.GetLowStock(ctx, threshold)
 }
 
-func (uc *InventoryUseCase) ReserveInventory(ctx context.Context, productID, variantID uint, quantity int, referenceID uint) error {
-	req := &entity.InventoryUpdateRequest{
-		ProductID:       productID,
-		VariantID:       variantID,
-		QuantityChange:  -quantity, // Negative to reduce available quantity
-		TransactionType: "reserved",
-		ReferenceID:     &referenceID,
+// GetInStockProductIDs returns the products that can be bought from some warehouse
+func (uc *InventoryUseCase) GetInStockProductIDs(ctx context.Context) ([]uint, error) {
+	return uc.inventoryRepo.GetInStockProductIDs(ctx)
+}
+
+// ReserveInventory holds stock for a reference until it is confirmed, released
+// or expires. Only stock that is not already reserved can be held. Without a
+// warehouse the stock is held in the nearest warehouse that has enough.
+func (uc *InventoryUseCase) ReserveInventory(ctx context.Context, req *entity.InventoryReservationRequest) (*entity.StockReservation, error) {
+	ttl := DefaultReservationTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	if ttl > maxReservationTTL {
+		ttl = maxReservationTTL
+	}
+
+	warehouseID := req.WarehouseID
+	if warehouseID == 0 {
+		// A repeated request is answered from the warehouse chosen the first time
+		held, err := uc.heldWarehouse(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		warehouseID = held
+	}
+	if warehouseID == 0 {
+		allocation, err := uc.Allocate(ctx, &entity.AllocationRequest{
+			Strategy:   entity.AllocationNearest,
+			PostalCode: req.PostalCode,
+			Lines:      []entity.AllocationLine{{ProductID: req.ProductID, VariantID: req.VariantID, Quantity: req.Quantity}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		warehouseID = allocation.Lines[0].WarehouseID
 	}
-	
-	return uc.UpdateInventory(ctx, req)
+
+	reservation := &entity.StockReservation{
+		ReferenceID: req.ReferenceID,
+		ProductID:   req.ProductID,
+		VariantID:   req.VariantID,
+		WarehouseID: warehouseID,
+		Quantity:    req.Quantity,
+		ExpiresAt:   time.Now().Add(ttl),
+	}
+	if err := uc.reservationRepo.Reserve(ctx, reservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
 }
 
-func (uc *InventoryUseCase) ReleaseReservedInventory(ctx context.Context, productID, variantID uint, quantity int, referenceID uint) error {
-	req := &entity.InventoryUpdateRequest{
-		ProductID:       productID,
-		VariantID:       variantID,
-		QuantityChange:  quantity, // Positive to increase available quantity
-		TransactionType: "in",
-		ReferenceID:     &referenceID,
+// heldWarehouse returns the warehouse that holds or has confirmed the
+// requested line for its reference, or 0 when none does
+func (uc *InventoryUseCase) heldWarehouse(ctx context.Context, req *entity.InventoryReservationRequest) (uint, error) {
+	reservations, err := uc.reservationRepo.GetByReferenceID(ctx, req.ReferenceID)
+	if err != nil {
+		return 0, err
 	}
-	
-	return uc.UpdateInventory(ctx, req)
+	for _, reservation := range reservations {
+		if reservation.ProductID == req.ProductID && reservation.VariantID == req.VariantID &&
+			(reservation.Status == "held" || reservation.Status == "confirmed") {
+			return reservation.WarehouseID, nil
+		}
+	}
+	return 0, nil
+}
+
+// ReleaseReservedInventory releases the held line of a reference for one product variant
+func (uc *InventoryUseCase) ReleaseReservedInventory(ctx context.Context, req *entity.InventoryReservationRequest) ([]entity.StockReservation, error) {
+	return uc.reservationRepo.Release(ctx, req.ReferenceID, &req.ProductID, &req.VariantID)
+}
+
+// ConfirmReservations deducts all stock held by a reference, e.g. once its order is placed
+func (uc *InventoryUseCase) ConfirmReservations(ctx context.Context, referenceID uint) ([]entity.StockReservation, error) {
+	return uc.reservationRepo.Confirm(ctx, referenceID)
+}
+
+// ReleaseReservations returns all stock held by a reference
+func (uc *InventoryUseCase) ReleaseReservations(ctx context.Context, referenceID uint) ([]entity.StockReservation, error) {
+	return uc.reservationRepo.Release(ctx, referenceID, nil, nil)
+}
+
+func (uc *InventoryUseCase) GetReservations(ctx context.Context, referenceID uint) ([]entity.StockReservation, error) {
+	return uc.reservationRepo.GetByReferenceID(ctx, referenceID)
 }
 
 func (uc *InventoryUseCase) GetRecentTransactions(ctx context.Context, limit int) ([]entity.InventoryTransaction, error) {
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
+)
+
+func TestCheckQuantityChange(t *testing.T) {
+	tests := []struct {
+		name            string
+		transactionType string
+		change          int
+		want            error
+	}{
+		{"stock in", "in", 5, nil},
+		{"stock out", "out", -5, nil},
+		{"stock in with a negative change", "in", -5, domain.ErrQuantitySign},
+		{"stock out with a positive change", "out", 5, domain.ErrQuantitySign},
+		{"stock in of nothing", "in", 0, domain.ErrQuantitySign},
+		{"stock out of nothing", "out", 0, domain.ErrQuantitySign},
+		{"reservation", "reserved", -5, domain.ErrTransactionTypeNotAllowed},
+		{"release", "released", 5, domain.ErrTransactionTypeNotAllowed},
+		{"unknown type", "", 5, domain.ErrTransactionTypeNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkQuantityChange(tt.transactionType, tt.change)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("checkQuantityChange(%q, %d) = %v, want nil", tt.transactionType, tt.change, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("checkQuantityChange(%q, %d) = %v, want %v", tt.transactionType, tt.change, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+)
+
+// velocityWindow is how far back "out" transactions are averaged into a daily
+// sales velocity. Suggestions order enough to cover the same span of demand.
+const velocityWindow = 30 * 24 * time.Hour
+
+// SetReorderSettings sets the reorder point and quantity of a variant in a warehouse
+func (uc *InventoryUseCase) SetReorderSettings(ctx context.Context, req *entity.ReorderSettingsRequest) (*entity.Inventory, error) {
+	warehouse, err := uc.resolveWarehouse(ctx, req.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.inventoryRepo.UpdateReorderSettings(ctx, req.ProductID, req.VariantID, warehouse.ID, *req.ReorderPoint, *req.ReorderQuantity); err != nil {
+		return nil, err
+	}
+	return uc.inventoryRepo.GetByWarehouse(ctx, req.ProductID, req.VariantID, warehouse.ID)
+}
+
+// EvaluateReorders compares every tracked variant with its reorder point. A
+// variant that reaches it gets an open suggestion and one alert; its suggestion
+// is refreshed on later runs and resolved once stock recovers. A dismissed
+// suggestion keeps its variant quiet until then. It returns the suggestions
+// that are open after the run.
+func (uc *InventoryUseCase) EvaluateReorders(ctx context.Context) ([]entity.ReorderSuggestion, error) {
+	now := time.Now()
+
+	low, err := uc.inventoryRepo.GetBelowReorderPoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	outbound, err := uc.transactionRepo.GetOutboundSince(ctx, now.Add(-velocityWindow))
+	if err != nil {
+		return nil, err
+	}
+	open, err := uc.suggestionRepo.GetAll(ctx, "open")
+	if err != nil {
+		return nil, err
+	}
+	dismissed, err := uc.suggestionRepo.GetAll(ctx, "dismissed")
+	if err != nil {
+		return nil, err
+	}
+
+	shipped := balancesByKey(outbound)
+	openByKey := make(map[entity.StockKey]*entity.ReorderSuggestion, len(open))
+	for i := range open {
+		s := &open[i]
+		openByKey[entity.StockKey{ProductID: s.ProductID, VariantID: s.VariantID, WarehouseID: s.WarehouseID}] = s
+	}
+	// Dismissals are still in force until the stock recovers and they get a
+	// resolved time
+	dismissedByKey := make(map[entity.StockKey]*entity.ReorderSuggestion)
+	for i := range dismissed {
+		s := &dismissed[i]
+		if s.ResolvedAt == nil {
+			dismissedByKey[entity.StockKey{ProductID: s.ProductID, VariantID: s.VariantID, WarehouseID: s.WarehouseID}] = s
+		}
+	}
+
+	var current []entity.ReorderSuggestion
+	for _, inventory := range low {
+		key := entity.StockKey{ProductID: inventory.ProductID, VariantID: inventory.VariantID, WarehouseID: inventory.WarehouseID}
+		if _, ok := dismissedByKey[key]; ok {
+			delete(dismissedByKey, key)
+			continue
+		}
+		suggestion, exists := openByKey[key]
+		if !exists {
+			suggestion = &entity.ReorderSuggestion{
+				ProductID:   inventory.ProductID,
+				VariantID:   inventory.VariantID,
+				WarehouseID: inventory.WarehouseID,
+				Status:      "open",
+			}
+		}
+		delete(openByKey, key)
+		fillSuggestion(suggestion, &inventory, shipped[key])
+
+		if err := uc.suggestionRepo.Save(ctx, suggestion); err != nil {
+			return nil, err
+		}
+		if suggestion.AlertedAt == nil {
+			uc.sendAlert(ctx, suggestion, now)
+		}
+		current = append(current, *suggestion)
+	}
+
+	// Whatever is still open has recovered above its reorder point
+	for _, suggestion := range openByKey {
+		suggestion.Status = "resolved"
+		suggestion.ResolvedAt = &now
+		if err := uc.suggestionRepo.Save(ctx, suggestion); err != nil {
+			return nil, err
+		}
+	}
+	// and so have the variants of the dismissals left, which end here
+	for _, suggestion := range dismissedByKey {
+		suggestion.ResolvedAt = &now
+		if err := uc.suggestionRepo.Save(ctx, suggestion); err != nil {
+			return nil, err
+		}
+	}
+
+	return current, nil
+}
+
+// RunReorderEvaluator evaluates reorder points every interval until ctx is cancelled
+func (uc *InventoryUseCase) RunReorderEvaluator(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := uc.EvaluateReorders(ctx); err != nil {
+				log.Printf("reorder evaluation failed: %v", err)
+			}
+		}
+	}
+}
+
+func (uc *InventoryUseCase) GetReorderSuggestions(ctx context.Context, status string) ([]entity.ReorderSuggestion, error) {
+	return uc.suggestionRepo.GetAll(ctx, status)
+}
+
+// DismissReorderSuggestion closes a suggestion without restocking. No new
+// suggestion or alert is raised for the variant until its stock recovers
+// above the reorder point, when the dismissal gets its resolved time.
+func (uc *InventoryUseCase) DismissReorderSuggestion(ctx context.Context, id uint) (*entity.ReorderSuggestion, error) {
+	suggestion, err := uc.suggestionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if suggestion.Status == "open" {
+		suggestion.Status = "dismissed"
+		if err := uc.suggestionRepo.Save(ctx, suggestion); err != nil {
+			return nil, err
+		}
+	}
+	return suggestion, nil
+}
+
+// sendAlert notifies the alert sink once per suggestion. A failed delivery is
+// retried on the next evaluation.
+func (uc *InventoryUseCase) sendAlert(ctx context.Context, suggestion *entity.ReorderSuggestion, now time.Time) {
+	alert := entity.StockAlert{Type: entity.StockAlertLowStock, Suggestion: *suggestion, RaisedAt: now}
+	if err := uc.alerts.Send(ctx, alert); err != nil {
+		log.Printf("failed to send stock alert for suggestion %d: %v", suggestion.ID, err)
+		return
+	}
+
+	suggestion.AlertedAt = &now
+	if err := uc.suggestionRepo.Save(ctx, suggestion); err != nil {
+		log.Printf("failed to record stock alert for suggestion %d: %v", suggestion.ID, err)
+	}
+}
+
+// fillSuggestion works out how much to order: enough to cover the demand of
+// one velocity window on top of the reorder point, and never less than the
+// configured reorder quantity
+func fillSuggestion(suggestion *entity.ReorderSuggestion, inventory *entity.Inventory, shipped int) {
+	available := inventory.Quantity - inventory.ReservedQuantity
+	velocity := float64(shipped) / velocityWindow.Hours() * 24
+
+	suggestion.Available = available
+	suggestion.ReorderPoint = inventory.ReorderPoint
+	suggestion.DailyVelocity = math.Round(velocity*100) / 100
+	suggestion.DaysOfCover = nil
+	if velocity > 0 {
+		cover := math.Round(float64(max(available, 0))/velocity*10) / 10
+		suggestion.DaysOfCover = &cover
+	}
+
+	demand := int(math.Ceil(velocity * velocityWindow.Hours() / 24))
+	suggestion.SuggestedQuantity = max(inventory.ReorderQuantity, demand+inventory.ReorderPoint-available)
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// sweepBatchSize bounds how many reservations one sweep expires per transaction
+const sweepBatchSize = 100
+
+// ExpireReservations releases held reservations whose TTL has passed and
+// returns how many were expired
+func (uc *InventoryUseCase) ExpireReservations(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := uc.reservationRepo.ExpireDue(ctx, time.Now(), sweepBatchSize)
+		total += n
+		if err != nil || n < sweepBatchSize {
+			return total, err
+		}
+	}
+}
+
+// RunReservationSweeper expires abandoned reservations every interval until ctx is cancelled
+func (uc *InventoryUseCase) RunReservationSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := uc.ExpireReservations(ctx)
+			if err != nil {
+				log.Printf("reservation sweeper failed: %v", err)
+			}
+			if n > 0 {
+				log.Printf("reservation sweeper expired %d reservations", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/inventory-service/internal/domain/entity"
+)
+
+func (uc *InventoryUseCase) CreateWarehouse(ctx context.Context, warehouse *entity.Warehouse) error {
+	return uc.warehouseRepo.Create(ctx, warehouse)
+}
+
+func (uc *InventoryUseCase) GetWarehouse(ctx context.Context, id uint) (*entity.Warehouse, error) {
+	return uc.warehouseRepo.GetByID(ctx, id)
+}
+
+func (uc *InventoryUseCase) GetWarehouses(ctx context.Context) ([]entity.Warehouse, error) {
+	return uc.warehouseRepo.GetAll(ctx)
+}
+
+func (uc *InventoryUseCase) UpdateWarehouse(ctx context.Context, id uint, req *entity.WarehouseUpdateRequest) (*entity.Warehouse, error) {
+	warehouse, err := uc.warehouseRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		warehouse.Name = *req.Name
+	}
+	if req.Address != nil {
+		warehouse.Address = *req.Address
+	}
+	if req.PostalCode != nil {
+		warehouse.PostalCode = *req.PostalCode
+	}
+	if req.Priority != nil {
+		warehouse.Priority = *req.Priority
+	}
+	if req.Active != nil {
+		warehouse.Active = *req.Active
+	}
+
+	if err := uc.warehouseRepo.Update(ctx, warehouse); err != nil {
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+// TransferStock moves available stock of a product variant between two active warehouses
+func (uc *InventoryUseCase) TransferStock(ctx context.Context, req *entity.StockTransferRequest) (*entity.StockTransfer, error) {
+	for _, id := range []uint{req.FromWarehouseID, req.ToWarehouseID} {
+		warehouse, err := uc.warehouseRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !warehouse.Active {
+			return nil, domain.ErrWarehouseInactive
+		}
+	}
+
+	transfer := &entity.StockTransfer{
+		ProductID:       req.ProductID,
+		VariantID:       req.VariantID,
+		FromWarehouseID: req.FromWarehouseID,
+		ToWarehouseID:   req.ToWarehouseID,
+		Quantity:        req.Quantity,
+		Notes:           req.Notes,
+	}
+	if err := uc.transferRepo.Transfer(ctx, transfer); err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+func (uc *InventoryUseCase) GetTransfersByProduct(ctx context.Context, productID uint) ([]entity.StockTransfer, error) {
+	return uc.transferRepo.GetByProduct(ctx, productID)
+}
+
+// resolveWarehouse loads a warehouse by ID. ID 0 means the default warehouse,
+// the active warehouse with the highest priority.
+func (uc *InventoryUseCase) resolveWarehouse(ctx context.Context, id uint) (*entity.Warehouse, error) {
+	if id != 0 {
+		return uc.warehouseRepo.GetByID(ctx, id)
+	}
+
+	warehouses, err := uc.warehouseRepo.GetActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(warehouses) == 0 {
+		return nil, domain.ErrNoWarehouses
+	}
+	return &warehouses[0], nil
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/apiclient"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+)
+
+// CartItem is the subset of a cart-service cart line used by order-service
+type CartItem struct {
+	ID        uint64  `json:"id"`
+	UserID    uint64  `json:"user_id"`
+	ProductID uint64  `json:"product_id"`
+	VariantID *uint64 `json:"variant_id"`
+	Quantity  int     `json:"quantity"`
+}
+
+// CartClient handles communication with the cart service
+type CartClient struct {
+	api *apiclient.Client
+}
+
+// NewCartClient creates a new cart service client
+func NewCartClient(cfg *config.Config) *CartClient {
+	return &CartClient{
+		api: apiclient.New("cart-service", cfg.Services.CartService.URL, cfg.Services.CartService.Timeout),
+	}
+}
+
+// GetCart fetches all cart lines for a user
+func (c *CartClient) GetCart(ctx context.Context, identity Identity, userID uint) ([]CartItem, error) {
+	path := fmt.Sprintf("/cart/user/%d", userID)
+
+	var items []CartItem
+	if err := c.api.Do(ctx, http.MethodGet, path, identity, nil, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ClearCart removes all cart lines for a user
+func (c *CartClient) ClearCart(ctx context.Context, identity Identity, userID uint) error {
+	path := fmt.Sprintf("/cart/user/%d", userID)
+	return c.api.Do(ctx, http.MethodDelete, path, identity, nil, nil)
+}
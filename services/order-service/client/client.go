@@ -0,0 +1,9 @@
+package client
+
+import "github.com/DurgaPratapRajbhar/e-commerce/pkg/apiclient"
+
+// Identity carries the caller headers that downstream services expect from the gateway
+type Identity = apiclient.Identity
+
+// ServiceError is returned when a downstream service answers with an error response
+type ServiceError = apiclient.ServiceError
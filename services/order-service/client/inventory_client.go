@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/apiclient"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/middleware"
+)
+
+// ReservationRequest is the payload for reserving or releasing stock
+type ReservationRequest struct {
+	ProductID   uint `json:"product_id"`
+	VariantID   uint `json:"variant_id"`
+	Quantity    int  `json:"quantity"`
+	ReferenceID uint `json:"reference_id"`
+	WarehouseID uint `json:"warehouse_id,omitempty"`
+}
+
+// AllocationLine is an order line to place in a warehouse
+type AllocationLine struct {
+	ProductID uint `json:"product_id"`
+	VariantID uint `json:"variant_id"`
+	Quantity  int  `json:"quantity"`
+}
+
+// AllocationRequest is the payload for choosing warehouses for order lines
+type AllocationRequest struct {
+	Strategy   string           `json:"strategy,omitempty"`
+	PostalCode string           `json:"postal_code,omitempty"`
+	Lines      []AllocationLine `json:"lines"`
+}
+
+// AllocatedLine is an order line and the warehouse chosen to ship it
+type AllocatedLine struct {
+	AllocationLine
+	WarehouseID   uint   `json:"warehouse_id"`
+	WarehouseCode string `json:"warehouse_code"`
+}
+
+// Allocation is inventory-service's choice of warehouse per order line
+type Allocation struct {
+	Strategy  string          `json:"strategy"`
+	Lines     []AllocatedLine `json:"lines"`
+	Shipments int             `json:"shipments"`
+}
+
+// TransactionRequest is the payload for recording a stock movement
+type TransactionRequest struct {
+	ProductID       uint   `json:"product_id"`
+	VariantID       uint   `json:"variant_id"`
+	WarehouseID     uint   `json:"warehouse_id,omitempty"`
+	TransactionType string `json:"transaction_type"`
+	Quantity        int    `json:"quantity"`
+	ReferenceID     *uint  `json:"reference_id,omitempty"`
+	Notes           string `json:"notes,omitempty"`
+}
+
+// InventoryClient handles communication with the inventory service
+type InventoryClient struct {
+	api *apiclient.Client
+}
+
+// NewInventoryClient creates a new inventory service client
+func NewInventoryClient(cfg *config.Config) *InventoryClient {
+	return &InventoryClient{
+		api: apiclient.New("inventory-service", cfg.Services.InventoryService.URL, cfg.Services.InventoryService.Timeout),
+	}
+}
+
+// Allocate chooses the warehouse that ships each order line without reserving anything
+func (c *InventoryClient) Allocate(ctx context.Context, identity Identity, req AllocationRequest) (*Allocation, error) {
+	var allocation Allocation
+	if err := c.api.Do(ctx, http.MethodPost, "/inventory/allocate", identity, req, &allocation); err != nil {
+		return nil, err
+	}
+	return &allocation, nil
+}
+
+// Reserve reserves stock for a product variant
+func (c *InventoryClient) Reserve(ctx context.Context, identity Identity, req ReservationRequest) error {
+	return c.api.Do(ctx, http.MethodPost, "/inventory/reserve", identity, req, nil)
+}
+
+// Release returns previously reserved stock
+func (c *InventoryClient) Release(ctx context.Context, identity Identity, req ReservationRequest) error {
+	return c.api.Do(ctx, http.MethodPost, "/inventory/release", identity, req, nil)
+}
+
+// Confirm deducts all stock reserved against a reference
+func (c *InventoryClient) Confirm(ctx context.Context, identity Identity, referenceID uint) error {
+	path := fmt.Sprintf("/inventory/reservations/%d/confirm", referenceID)
+	return c.api.Do(ctx, http.MethodPost, path, identity, nil, nil)
+}
+
+// RecordTransaction records a stock movement, such as returned goods coming
+// back in. Repeating a call with the same idempotency key records it once.
+func (c *InventoryClient) RecordTransaction(ctx context.Context, identity Identity, idempotencyKey string, req TransactionRequest) error {
+	headers := map[string]string{middleware.IdempotencyKeyHeader: idempotencyKey}
+	return c.api.DoWithHeaders(ctx, http.MethodPost, "/inventory/transactions", identity, headers, req, nil)
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/apiclient"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/middleware"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+// PaymentRequest is the payload for creating a payment
+type PaymentRequest struct {
+	OrderID       uint        `json:"order_id"`
+	UserID        uint        `json:"user_id"`
+	Amount        money.Money `json:"amount"`
+	PaymentMethod string      `json:"payment_method"`
+}
+
+// Payment is the subset of a payment-service payment used by order-service
+type Payment struct {
+	ID            uint        `json:"id"`
+	OrderID       uint        `json:"order_id"`
+	Amount        money.Money `json:"amount"`
+	PaymentMethod string      `json:"payment_method"`
+	PaymentStatus string      `json:"payment_status"`
+	TransactionID string      `json:"transaction_id"`
+}
+
+// RefundRequest is the payload for refunding part or all of a payment
+type RefundRequest struct {
+	PaymentID uint        `json:"payment_id"`
+	OrderID   uint        `json:"order_id"`
+	Amount    money.Money `json:"amount"`
+	Reason    string      `json:"reason,omitempty"`
+}
+
+// Refund is the subset of a payment-service refund used by order-service
+type Refund struct {
+	ID        uint        `json:"id"`
+	PaymentID uint        `json:"payment_id"`
+	OrderID   uint        `json:"order_id"`
+	Amount    money.Money `json:"amount"`
+	Status    string      `json:"status"`
+}
+
+// PaymentClient handles communication with the payment service
+type PaymentClient struct {
+	api *apiclient.Client
+}
+
+// NewPaymentClient creates a new payment service client
+func NewPaymentClient(cfg *config.Config) *PaymentClient {
+	return &PaymentClient{
+		api: apiclient.New("payment-service", cfg.Services.PaymentService.URL, cfg.Services.PaymentService.Timeout),
+	}
+}
+
+// CreatePayment creates a pending payment for an order
+func (c *PaymentClient) CreatePayment(ctx context.Context, identity Identity, req PaymentRequest) (*Payment, error) {
+
+	var payment Payment
+	if err := c.api.Do(ctx, http.MethodPost, "/payment", identity, req, &payment); err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// GetPaymentByOrderID fetches the payment taken for an order
+func (c *PaymentClient) GetPaymentByOrderID(ctx context.Context, identity Identity, orderID uint) (*Payment, error) {
+	path := fmt.Sprintf("/payment/order/%d", orderID)
+
+	var payment Payment
+	if err := c.api.Do(ctx, http.MethodGet, path, identity, nil, &payment); err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// VoidPayment releases an authorized payment, or cancels a cash on delivery
+// payment that has not been collected
+func (c *PaymentClient) VoidPayment(ctx context.Context, identity Identity, paymentID uint) (*Payment, error) {
+	path := fmt.Sprintf("/payment/%d/void", paymentID)
+
+	var payment Payment
+	if err := c.api.Do(ctx, http.MethodPost, path, identity, nil, &payment); err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// CreateRefund refunds part or all of a payment. Repeating a call with the same
+// idempotency key returns the first result instead of refunding twice.
+func (c *PaymentClient) CreateRefund(ctx context.Context, identity Identity, idempotencyKey string, req RefundRequest) (*Refund, error) {
+	headers := map[string]string{middleware.IdempotencyKeyHeader: idempotencyKey}
+
+	var refund Refund
+	if err := c.api.DoWithHeaders(ctx, http.MethodPost, "/payment/refunds", identity, headers, req, &refund); err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
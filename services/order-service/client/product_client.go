@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/apiclient"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+// Product is the subset of a product-service product used for pricing
+type Product struct {
+	ID         uint64           `json:"id"`
+	Name       string           `json:"name"`
+	SKU        string           `json:"sku"`
+	Price      money.Money      `json:"price"`
+	Discount   float64          `json:"discount"`
+	Stock      int              `json:"stock"`
+	Status     string           `json:"status"`
+	CategoryID uint64           `json:"category_id"`
+	Variants   []ProductVariant `json:"variants"`
+}
+
+// ProductVariant is the subset of a product-service variant used for pricing
+type ProductVariant struct {
+	ID    uint64      `json:"id"`
+	Name  string      `json:"name"`
+	SKU   string      `json:"sku"`
+	Price money.Money `json:"price"`
+	Stock int         `json:"stock"`
+}
+
+// FindVariant returns the variant with the given ID
+func (p *Product) FindVariant(variantID uint64) (*ProductVariant, bool) {
+	for i := range p.Variants {
+		if p.Variants[i].ID == variantID {
+			return &p.Variants[i], true
+		}
+	}
+	return nil, false
+}
+
+// ProductClient handles communication with the product service
+type ProductClient struct {
+	api *apiclient.Client
+}
+
+// NewProductClient creates a new product service client
+func NewProductClient(cfg *config.Config) *ProductClient {
+	return &ProductClient{
+		api: apiclient.New("product-service", cfg.Services.ProductService.URL, cfg.Services.ProductService.Timeout),
+	}
+}
+
+// GetProduct fetches a product with its variants
+func (c *ProductClient) GetProduct(ctx context.Context, identity Identity, productID uint64) (*Product, error) {
+	path := fmt.Sprintf("/product/products/%d", productID)
+
+	var product Product
+	if err := c.api.Do(ctx, http.MethodGet, path, identity, nil, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/apiclient"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+)
+
+// ShippingAddress is an address as shipping-service prints it on a label
+type ShippingAddress struct {
+	Name       string `json:"name"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	State      string `json:"state,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country,omitempty"`
+	Phone      string `json:"phone,omitempty"`
+}
+
+// ShipmentItem is a quantity of one order item in a shipment
+type ShipmentItem struct {
+	OrderItemID uint `json:"order_item_id"`
+	Quantity    int  `json:"quantity"`
+}
+
+// ReturnShipmentRequest is the payload for booking the collection of returned items
+type ReturnShipmentRequest struct {
+	OrderID     uint            `json:"order_id"`
+	Type        string          `json:"type"`
+	Destination ShippingAddress `json:"destination"`
+	Items       []ShipmentItem  `json:"items"`
+}
+
+// Shipment is the subset of a shipping-service shipment used by order-service
+type Shipment struct {
+	ID             uint   `json:"id"`
+	OrderID        uint   `json:"order_id"`
+	TrackingNumber string `json:"tracking_number"`
+	Carrier        string `json:"carrier"`
+	Status         string `json:"status"`
+	LabelURL       string `json:"label_url"`
+}
+
+// ShippingClient handles communication with the shipping service
+type ShippingClient struct {
+	api *apiclient.Client
+}
+
+// NewShippingClient creates a new shipping service client
+func NewShippingClient(cfg *config.Config) *ShippingClient {
+	return &ShippingClient{
+		api: apiclient.New("shipping-service", cfg.Services.ShippingService.URL, cfg.Services.ShippingService.Timeout),
+	}
+}
+
+// CreateReturnShipment books a carrier to collect items from the customer and
+// bring them back to the warehouse
+func (c *ShippingClient) CreateReturnShipment(ctx context.Context, identity Identity, req ReturnShipmentRequest) (*Shipment, error) {
+	req.Type = "return"
+
+	var shipment Shipment
+	if err := c.api.Do(ctx, http.MethodPost, "/shipment", identity, req, &shipment); err != nil {
+		return nil, err
+	}
+	return &shipment, nil
+}
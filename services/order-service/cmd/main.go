@@ -11,10 +11,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/events"
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/logger"
 	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/database"
 	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/routes"
@@ -47,6 +49,11 @@ func main() {
 	}
 	database.Migrate(db)
 
+	// Deliver outbox events to the services listed in EVENT_SUBSCRIBERS_ORDER
+	broker := events.NewHTTPBroker(config.Events.Subscribers["order"], config.Events.Secret, config.Services.OrderService.Timeout)
+	broker.Subscribe(events.AllEvents, events.LogHandler)
+	go events.NewRelay(db, broker).Run(context.Background())
+
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.Default()
 	r.RemoveExtraSlash = true
@@ -66,7 +73,7 @@ func main() {
 		log.Fatalf("Failed to set trusted proxies: %v", err)
 	}
 
-	routes.SetupRoutes(r, db)
+	routes.SetupRoutes(r, db, &config)
 	port := ":" + config.Services.OrderService.Port
 
 	if err := r.Run(port); err != nil {
@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/events"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/idempotency"
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/logger"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/moneydb"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
 	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
 
 	"gorm.io/driver/mysql"
@@ -44,11 +48,42 @@ func Migrate(db *gorm.DB) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	if err := db.WithContext(ctx).AutoMigrate(&models.Order{}, &models.OrderItem{}); err != nil {
+	if err := db.WithContext(ctx).AutoMigrate(&models.Order{}, &models.OrderItem{}, &models.OrderStatusHistory{}, &models.Checkout{}, &models.CheckoutStep{}, &models.ReturnRequest{}, &models.ReturnItem{}, &models.Promotion{}, &models.PromotionRedemption{}, &models.OrderDiscount{}, &idempotency.Key{}, &events.OutboxMessage{}); err != nil {
 		logger.Logger.Error("Error migrating MySQL database:", err)
 		return fmt.Errorf("error migrating MySQL database: %w", err)
 	}
 
+	legacyAmounts := []struct{ table, legacy, prefix string }{
+		{"orders", "total_amount", "total_amount_"},
+		{"order_items", "price", "price_"},
+	}
+	for _, column := range legacyAmounts {
+		if err := moneydb.MigrateLegacyAmount(db.WithContext(ctx), column.table, column.legacy, column.prefix); err != nil {
+			logger.Logger.Error("Error migrating legacy amount column:", err)
+			return fmt.Errorf("error migrating %s.%s: %w", column.table, column.legacy, err)
+		}
+	}
+
+	// shipping_id assumed one shipment per order; fulfilment_status replaces it,
+	// starting from what the status of orders shipped before it says
+	if db.Migrator().HasColumn(&models.Order{}, "shipping_id") {
+		backfill := map[string][]string{
+			utils.FulfilmentStatusShipped:   {utils.OrderStatusShipped},
+			utils.FulfilmentStatusDelivered: {utils.OrderStatusDelivered, utils.OrderStatusReturned},
+		}
+		for fulfilment, statuses := range backfill {
+			err := db.WithContext(ctx).Model(&models.Order{}).
+				Where("status IN ? AND fulfilment_status = ?", statuses, utils.FulfilmentStatusUnfulfilled).
+				Update("fulfilment_status", fulfilment).Error
+			if err != nil {
+				return fmt.Errorf("error backfilling orders.fulfilment_status: %w", err)
+			}
+		}
+		if err := db.WithContext(ctx).Migrator().DropColumn(&models.Order{}, "shipping_id"); err != nil {
+			return fmt.Errorf("error dropping orders.shipping_id: %w", err)
+		}
+	}
+
 	logger.Logger.Info("Database migrated successfully.")
 	return nil
 }
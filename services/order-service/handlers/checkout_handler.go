@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/client"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/services"
+	"github.com/gin-gonic/gin"
+)
+
+type CheckoutHandler struct {
+	service services.CheckoutService
+}
+
+func NewCheckoutHandler(service services.CheckoutService) *CheckoutHandler {
+	return &CheckoutHandler{service: service}
+}
+
+// Checkout godoc
+// @Summary Check out the caller's cart
+// @Description Price the cart, reserve stock, create the order and payment, and clear the cart. Failed steps are compensated.
+// @Tags checkout
+// @Accept json
+// @Produce json
+// @Param checkout body models.CheckoutRequest true "Checkout data"
+// @Success 201 {object} models.Checkout
+// @Failure 400 {object} map[string]string
+// @Failure 422 {object} models.Checkout
+// @Failure 500 {object} map[string]string
+// @Router /order/checkout [post]
+func (h *CheckoutHandler) Checkout(c *gin.Context) {
+	var req models.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.ParseValidationErrors(err.Error())
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return
+	}
+
+	identity := client.Identity{
+		UserID: c.GetUint("user_id"),
+		Email:  c.GetString("email"),
+		Role:   c.GetString("role"),
+	}
+
+	checkout, err := h.service.Checkout(c.Request.Context(), identity, &req)
+	if err != nil {
+		requestID := utils.GenerateRequestID()
+		if checkout == nil {
+			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to start checkout", err.Error(), requestID)
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+		response := utils.ErrorResponse(utils.ErrInvalidOrder, "Checkout failed", checkout, requestID)
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusCreated, utils.SuccessResponse(checkout, "Checkout completed successfully", requestID))
+}
+
+// GetCheckout godoc
+// @Summary Get checkout progress
+// @Description Get a checkout and the status of each of its steps. Customers can only see their own checkouts.
+// @Tags checkout
+// @Accept json
+// @Produce json
+// @Param id path int true "Checkout ID"
+// @Success 200 {object} models.Checkout
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /order/checkout/{id} [get]
+func (h *CheckoutHandler) GetCheckout(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		validationErrors := []utils.ValidationError{
+			{Field: "id", Message: "invalid checkout ID"},
+		}
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return
+	}
+
+	checkout, err := h.service.GetCheckout(uint(id))
+	if err != nil {
+		requestID := utils.GenerateRequestID()
+		response := utils.ErrorResponse(utils.ErrNotFound, "Checkout not found", err.Error(), requestID)
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+	if c.GetString("role") != utils.RoleAdmin && checkout.UserID != c.GetUint("user_id") {
+		requestID := utils.GenerateRequestID()
+		response := utils.ErrorResponse(utils.ErrForbidden, "You can only view your own checkouts", nil, requestID)
+		c.JSON(http.StatusForbidden, response)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(checkout, "Checkout retrieved successfully", requestID))
+}
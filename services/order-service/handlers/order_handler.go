@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/repository"
 	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/services"
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
 	"github.com/gin-gonic/gin"
@@ -37,6 +40,8 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
 		return
 	}
+	// Discount lines only come from promotions applied at checkout
+	order.Discounts = nil
 
 	if err := h.service.CreateOrder(&order); err != nil {
 		requestID := utils.GenerateRequestID()
@@ -139,6 +144,56 @@ func (h *OrderHandler) GetAllOrders(c *gin.Context) {
 	c.JSON(http.StatusOK, utils.SuccessResponse(orders, "Orders retrieved successfully", requestID))
 }
 
+// The default and longest windows, in days, for counting orders by product
+const (
+	defaultProductOrderCountDays = 90
+	maxProductOrderCountDays     = 365
+)
+
+// GetProductOrderCounts godoc
+// @Summary Count orders by product
+// @Description Count, for each product, the orders that included it over the last days, leaving out cancelled orders. Used to rank products by popularity. Admin and system callers only.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param days query int false "Days to count over, up to 365 (default 90)"
+// @Success 200 {array} models.ProductOrderCount
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /orders/products/order-counts [get]
+func (h *OrderHandler) GetProductOrderCounts(c *gin.Context) {
+	if role := c.GetString("role"); role != utils.RoleAdmin && role != "system" {
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusForbidden, utils.ErrorResponse(utils.ErrForbidden, "Only admins can count orders by product", nil, requestID))
+		return
+	}
+
+	days := defaultProductOrderCountDays
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxProductOrderCountDays {
+			validationErrors := []utils.ValidationError{
+				{Field: "days", Message: fmt.Sprintf("days must be between 1 and %d", maxProductOrderCountDays)},
+			}
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+		days = parsed
+	}
+
+	counts, err := h.service.GetProductOrderCounts(days)
+	if err != nil {
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to count orders by product", err.Error(), requestID))
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(counts, "Product order counts retrieved successfully", requestID))
+}
+
 // UpdateOrder godoc
 // @Summary Update an order
 // @Description Update an order by ID
@@ -221,9 +276,11 @@ func (h *OrderHandler) DeleteOrder(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Order ID"
-// @Param status body object{status="string"} true "Status data"
+// @Param status body object{status="string",reason="string"} true "Status data"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /orders/{id}/status [patch]
 func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
@@ -239,6 +296,7 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 
 	var request struct {
 		Status string `json:"status" binding:"required"`
+		Reason string `json:"reason"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -248,10 +306,14 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.UpdateOrderStatus(uint(id), request.Status); err != nil {
-		requestID := utils.GenerateRequestID()
-		response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to update order status", err.Error(), requestID)
-		c.JSON(http.StatusInternalServerError, response)
+	change := models.OrderStatusChange{
+		Status:        request.Status,
+		ChangedBy:     c.GetUint("user_id"),
+		ChangedByRole: c.GetString("role"),
+		Reason:        request.Reason,
+	}
+	if err := h.service.UpdateOrderStatus(uint(id), change); err != nil {
+		h.statusError(c, "Failed to update order status", err)
 		return
 	}
 
@@ -269,6 +331,8 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 // @Param payment body object{status="string",payment_id="string"} true "Payment data"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /orders/{id}/payment [patch]
 func (h *OrderHandler) UpdatePaymentStatus(c *gin.Context) {
@@ -295,12 +359,114 @@ func (h *OrderHandler) UpdatePaymentStatus(c *gin.Context) {
 	}
 
 	if err := h.service.UpdatePaymentStatus(uint(id), request.Status, request.PaymentID); err != nil {
-		requestID := utils.GenerateRequestID()
-		response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to update payment status", err.Error(), requestID)
-		c.JSON(http.StatusInternalServerError, response)
+		h.statusError(c, "Failed to update payment status", err)
 		return
 	}
 
 	requestID := utils.GenerateRequestID()
 	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Payment status updated successfully", requestID))
 }
+
+// UpdateFulfilmentStatus godoc
+// @Summary Update fulfilment status
+// @Description Set the fulfilment rollup of an order (unfulfilled, partially_shipped, shipped, delivered). Called by shipping-service as the order's shipments move. Admin or system only.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param fulfilment body object{status="string"} true "Fulfilment data"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /order/{id}/fulfilment [patch]
+func (h *OrderHandler) UpdateFulfilmentStatus(c *gin.Context) {
+	if role := c.GetString("role"); role != utils.RoleAdmin && role != "system" {
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusForbidden, utils.ErrorResponse(utils.ErrForbidden, "Only admins and shipping-service can update fulfilment", nil, requestID))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		validationErrors := []utils.ValidationError{
+			{Field: "id", Message: "invalid order ID"},
+		}
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return
+	}
+
+	var request struct {
+		Status string `json:"status" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		validationErrors := utils.ParseValidationErrors(err.Error())
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return
+	}
+
+	if err := h.service.UpdateFulfilmentStatus(uint(id), request.Status); err != nil {
+		h.statusError(c, "Failed to update fulfilment status", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Fulfilment status updated successfully", requestID))
+}
+
+// GetOrderHistory godoc
+// @Summary Get order status history
+// @Description Get every status change of an order, oldest first
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {array} models.OrderStatusHistory
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /orders/{id}/history [get]
+func (h *OrderHandler) GetOrderHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		validationErrors := []utils.ValidationError{
+			{Field: "id", Message: "invalid order ID"},
+		}
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return
+	}
+
+	history, err := h.service.GetStatusHistory(uint(id))
+	if err != nil {
+		requestID := utils.GenerateRequestID()
+		response := utils.ErrorResponse(utils.ErrNotFound, "Order not found", err.Error(), requestID)
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(history, "Order history retrieved successfully", requestID))
+}
+
+// statusError writes the response for a failed status, payment or fulfilment status change
+func (h *OrderHandler) statusError(c *gin.Context, message string, err error) {
+	requestID := utils.GenerateRequestID()
+
+	var transitionErr *services.InvalidTransitionError
+	switch {
+	case errors.Is(err, services.ErrInvalidFulfilmentStatus):
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, message, err.Error(), requestID))
+	case errors.As(err, &transitionErr):
+		c.JSON(http.StatusConflict, utils.ErrorResponse(utils.ErrInvalidOrder, message, transitionErr.Error(), requestID))
+	case errors.Is(err, repository.ErrOrderStatusChanged):
+		c.JSON(http.StatusConflict, utils.ErrorResponse(utils.ErrConflict, message, err.Error(), requestID))
+	case errors.Is(err, repository.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(utils.ErrNotFound, message, err.Error(), requestID))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, message, err.Error(), requestID))
+	}
+}
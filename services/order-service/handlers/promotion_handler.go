@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/repository"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/services"
+	"github.com/gin-gonic/gin"
+)
+
+type PromotionHandler struct {
+	service services.PromotionService
+}
+
+func NewPromotionHandler(service services.PromotionService) *PromotionHandler {
+	return &PromotionHandler{service: service}
+}
+
+// CreatePromotion godoc
+// @Summary Create a promotion
+// @Description Create a percentage, flat, buy-X-get-Y or free-shipping promotion. Without a code it applies automatically. Admin only.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param promotion body models.PromotionRequest true "Promotion data"
+// @Success 201 {object} models.Promotion
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /orders/promotions [post]
+func (h *PromotionHandler) CreatePromotion(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+	var req models.PromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.ParseValidationErrors(err.Error())
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return
+	}
+
+	promotion, err := h.service.CreatePromotion(&req)
+	if err != nil {
+		promotionError(c, "Failed to create promotion", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusCreated, utils.SuccessResponse(promotion, "Promotion created successfully", requestID))
+}
+
+// GetPromotions godoc
+// @Summary List promotions
+// @Description List every promotion, newest first. Admin only.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Promotion
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /orders/promotions [get]
+func (h *PromotionHandler) GetPromotions(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	promotions, err := h.service.GetPromotions()
+	if err != nil {
+		promotionError(c, "Failed to get promotions", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(promotions, "Promotions retrieved successfully", requestID))
+}
+
+// GetPromotion godoc
+// @Summary Get a promotion
+// @Description Get a promotion by its ID. Admin only.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} models.Promotion
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /orders/promotions/{id} [get]
+func (h *PromotionHandler) GetPromotion(c *gin.Context) {
+	id, ok := adminPromotionID(c)
+	if !ok {
+		return
+	}
+
+	promotion, err := h.service.GetPromotion(id)
+	if err != nil {
+		promotionError(c, "Failed to get promotion", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(promotion, "Promotion retrieved successfully", requestID))
+}
+
+// UpdatePromotion godoc
+// @Summary Replace a promotion
+// @Description Replace a promotion's rules. Redemptions already made are kept. Admin only.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Param promotion body models.PromotionRequest true "Promotion data"
+// @Success 200 {object} models.Promotion
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /orders/promotions/{id} [put]
+func (h *PromotionHandler) UpdatePromotion(c *gin.Context) {
+	id, ok := adminPromotionID(c)
+	if !ok {
+		return
+	}
+	var req models.PromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.ParseValidationErrors(err.Error())
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return
+	}
+
+	promotion, err := h.service.UpdatePromotion(id, &req)
+	if err != nil {
+		promotionError(c, "Failed to update promotion", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(promotion, "Promotion updated successfully", requestID))
+}
+
+// DeletePromotion godoc
+// @Summary Delete a promotion
+// @Description Delete a promotion. Orders keep their discount lines. Admin only.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /orders/promotions/{id} [delete]
+func (h *PromotionHandler) DeletePromotion(c *gin.Context) {
+	id, ok := adminPromotionID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeletePromotion(id); err != nil {
+		promotionError(c, "Failed to delete promotion", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Promotion deleted successfully", requestID))
+}
+
+// GetRedemptions godoc
+// @Summary List a promotion's redemptions
+// @Description List the orders that used a promotion, newest first, including voided redemptions of cancelled orders. Admin only.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 200 {array} models.PromotionRedemption
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /orders/promotions/{id}/redemptions [get]
+func (h *PromotionHandler) GetRedemptions(c *gin.Context) {
+	id, ok := adminPromotionID(c)
+	if !ok {
+		return
+	}
+
+	redemptions, err := h.service.GetRedemptions(id)
+	if err != nil {
+		promotionError(c, "Failed to get redemptions", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(redemptions, "Redemptions retrieved successfully", requestID))
+}
+
+// EvaluatePromotions godoc
+// @Summary Evaluate promotions for a cart
+// @Description Work out the promotions that apply to the caller's priced cart lines and entered codes, with the shipping fee. Nothing is redeemed.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param evaluation body models.PromotionEvaluationRequest true "Cart lines and codes"
+// @Success 200 {object} models.PromotionEvaluation
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /orders/promotions/evaluate [post]
+func (h *PromotionHandler) EvaluatePromotions(c *gin.Context) {
+	var req models.PromotionEvaluationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.ParseValidationErrors(err.Error())
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return
+	}
+
+	evaluation, err := h.service.Evaluate(identityFrom(c), &req)
+	if err != nil {
+		promotionError(c, "Failed to evaluate promotions", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(evaluation, "Promotions evaluated successfully", requestID))
+}
+
+// requireAdmin writes a 403 unless the caller is an admin
+func requireAdmin(c *gin.Context) bool {
+	if c.GetString("role") != utils.RoleAdmin {
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusForbidden, utils.ErrorResponse(utils.ErrForbidden, "Only admins can manage promotions", nil, requestID))
+		return false
+	}
+	return true
+}
+
+// adminPromotionID parses the promotion ID path parameter for an admin, writing
+// a 403 or 400 when the caller is not an admin or the ID is invalid
+func adminPromotionID(c *gin.Context) (uint, bool) {
+	if !requireAdmin(c) {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		validationErrors := []utils.ValidationError{
+			{Field: "id", Message: "invalid promotion ID"},
+		}
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// promotionError writes the response for a failed promotion operation
+func promotionError(c *gin.Context, message string, err error) {
+	requestID := utils.GenerateRequestID()
+	switch {
+	case errors.Is(err, services.ErrInvalidPromotion):
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, message, err.Error(), requestID))
+	case errors.Is(err, repository.ErrPromotionNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(utils.ErrNotFound, message, err.Error(), requestID))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, message, err.Error(), requestID))
+	}
+}
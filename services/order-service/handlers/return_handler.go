@@ -0,0 +1,368 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/client"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/repository"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ReturnHandler struct {
+	service services.ReturnService
+}
+
+func NewReturnHandler(service services.ReturnService) *ReturnHandler {
+	return &ReturnHandler{service: service}
+}
+
+// CreateReturn godoc
+// @Summary Request a return
+// @Description Request the return (RMA) of items from one of the caller's shipped orders. The return waits for an admin to approve it.
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param return body models.ReturnCreateRequest true "Return data"
+// @Success 201 {object} models.ReturnRequest
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /orders/returns [post]
+func (h *ReturnHandler) CreateReturn(c *gin.Context) {
+	var req models.ReturnCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.ParseValidationErrors(err.Error())
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return
+	}
+
+	ret, err := h.service.CreateReturn(c.Request.Context(), identityFrom(c), &req)
+	if err != nil {
+		h.returnError(c, "Failed to request return", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusCreated, utils.SuccessResponse(ret, "Return requested successfully", requestID))
+}
+
+// GetReturns godoc
+// @Summary List returns
+// @Description List every return for admins and the caller's own returns for everyone else
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param status query string false "Return status"
+// @Success 200 {array} models.ReturnRequest
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /orders/returns [get]
+func (h *ReturnHandler) GetReturns(c *gin.Context) {
+	returns, err := h.service.GetReturns(identityFrom(c), c.Query("status"))
+	if err != nil {
+		h.returnError(c, "Failed to get returns", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(returns, "Returns retrieved successfully", requestID))
+}
+
+// GetReturn godoc
+// @Summary Get a return
+// @Description Get a return and its items
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path int true "Return ID"
+// @Success 200 {object} models.ReturnRequest
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /orders/returns/{id} [get]
+func (h *ReturnHandler) GetReturn(c *gin.Context) {
+	id, ok := returnID(c)
+	if !ok {
+		return
+	}
+
+	ret, err := h.service.GetReturn(identityFrom(c), id)
+	if err != nil {
+		h.returnError(c, "Failed to get return", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(ret, "Return retrieved successfully", requestID))
+}
+
+// GetOrderReturns godoc
+// @Summary List the returns of an order
+// @Description List every return requested for an order, oldest first
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {array} models.ReturnRequest
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /orders/{id}/returns [get]
+func (h *ReturnHandler) GetOrderReturns(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		validationErrors := []utils.ValidationError{
+			{Field: "id", Message: "invalid order ID"},
+		}
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return
+	}
+
+	returns, err := h.service.GetOrderReturns(identityFrom(c), uint(id))
+	if err != nil {
+		h.returnError(c, "Failed to get order returns", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(returns, "Order returns retrieved successfully", requestID))
+}
+
+// ApproveReturn godoc
+// @Summary Approve a return
+// @Description Approve a requested return and book the carrier that collects it. Admin only. Without refund_amount the full value of the items is refunded on receipt.
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path int true "Return ID"
+// @Param approval body models.ReturnApproveRequest false "Approval data"
+// @Success 200 {object} models.ReturnRequest
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /orders/returns/{id}/approve [post]
+func (h *ReturnHandler) ApproveReturn(c *gin.Context) {
+	id, ok := adminReturnID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReturnApproveRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			validationErrors := utils.ParseValidationErrors(err.Error())
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+	}
+
+	ret, err := h.service.ApproveReturn(c.Request.Context(), identityFrom(c), id, &req)
+	if err != nil {
+		h.returnError(c, "Failed to approve return", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(ret, "Return approved successfully", requestID))
+}
+
+// RejectReturn godoc
+// @Summary Reject a return
+// @Description Reject a requested return. Admin only.
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path int true "Return ID"
+// @Param rejection body models.ReturnRejectRequest true "Rejection data"
+// @Success 200 {object} models.ReturnRequest
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /orders/returns/{id}/reject [post]
+func (h *ReturnHandler) RejectReturn(c *gin.Context) {
+	id, ok := adminReturnID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReturnRejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.ParseValidationErrors(err.Error())
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return
+	}
+
+	ret, err := h.service.RejectReturn(c.Request.Context(), identityFrom(c), id, &req)
+	if err != nil {
+		h.returnError(c, "Failed to reject return", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(ret, "Return rejected successfully", requestID))
+}
+
+// CancelReturn godoc
+// @Summary Cancel a return
+// @Description Withdraw a return that has not been approved yet
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path int true "Return ID"
+// @Success 200 {object} models.ReturnRequest
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /orders/returns/{id}/cancel [post]
+func (h *ReturnHandler) CancelReturn(c *gin.Context) {
+	id, ok := returnID(c)
+	if !ok {
+		return
+	}
+
+	ret, err := h.service.CancelReturn(c.Request.Context(), identityFrom(c), id)
+	if err != nil {
+		h.returnError(c, "Failed to cancel return", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(ret, "Return cancelled successfully", requestID))
+}
+
+// ReceiveReturn godoc
+// @Summary Receive a return
+// @Description Record the arrival of returned goods. Admin only. Restockable items go back into inventory and the approved amount is refunded; items not listed are restockable.
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path int true "Return ID"
+// @Param inspection body models.ReturnReceiveRequest false "Inspection of the returned items"
+// @Success 200 {object} models.ReturnRequest
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /orders/returns/{id}/receive [post]
+func (h *ReturnHandler) ReceiveReturn(c *gin.Context) {
+	id, ok := adminReturnID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReturnReceiveRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			validationErrors := utils.ParseValidationErrors(err.Error())
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+	}
+
+	ret, err := h.service.ReceiveReturn(c.Request.Context(), identityFrom(c), id, &req)
+	if err != nil {
+		h.returnError(c, "Failed to receive return", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(ret, "Return received successfully", requestID))
+}
+
+// RetryReturn godoc
+// @Summary Retry a return's restock and refund
+// @Description Repeat the restocks and refund that failed for a received return. Admin only.
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path int true "Return ID"
+// @Success 200 {object} models.ReturnRequest
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /orders/returns/{id}/retry [post]
+func (h *ReturnHandler) RetryReturn(c *gin.Context) {
+	id, ok := adminReturnID(c)
+	if !ok {
+		return
+	}
+
+	ret, err := h.service.RetryReturn(c.Request.Context(), id)
+	if err != nil {
+		h.returnError(c, "Failed to retry return", err)
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(ret, "Return retried successfully", requestID))
+}
+
+// identityFrom reads the caller set by ServiceAuthMiddleware
+func identityFrom(c *gin.Context) client.Identity {
+	return client.Identity{
+		UserID: c.GetUint("user_id"),
+		Email:  c.GetString("email"),
+		Role:   c.GetString("role"),
+	}
+}
+
+// returnID parses the return ID path parameter, writing a 400 when it is invalid
+func returnID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		validationErrors := []utils.ValidationError{
+			{Field: "id", Message: "invalid return ID"},
+		}
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// adminReturnID is returnID for the steps of a return that only admins take
+func adminReturnID(c *gin.Context) (uint, bool) {
+	if c.GetString("role") != utils.RoleAdmin {
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusForbidden, utils.ErrorResponse(utils.ErrForbidden, "Only admins can process returns", nil, requestID))
+		return 0, false
+	}
+	return returnID(c)
+}
+
+// returnError writes the response for a failed return operation
+func (h *ReturnHandler) returnError(c *gin.Context, message string, err error) {
+	requestID := utils.GenerateRequestID()
+
+	var transitionErr *services.ReturnTransitionError
+	switch {
+	case errors.Is(err, services.ErrInvalidReturn):
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, message, err.Error(), requestID))
+	case errors.Is(err, services.ErrReturnForbidden):
+		c.JSON(http.StatusForbidden, utils.ErrorResponse(utils.ErrForbidden, message, err.Error(), requestID))
+	case errors.As(err, &transitionErr):
+		c.JSON(http.StatusConflict, utils.ErrorResponse(utils.ErrConflict, message, transitionErr.Error(), requestID))
+	case errors.Is(err, repository.ErrReturnStatusChanged):
+		c.JSON(http.StatusConflict, utils.ErrorResponse(utils.ErrConflict, message, err.Error(), requestID))
+	case errors.Is(err, repository.ErrReturnNotFound) || errors.Is(err, repository.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(utils.ErrNotFound, message, err.Error(), requestID))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, message, err.Error(), requestID))
+	}
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+// Checkout saga statuses
+const (
+	CheckoutStatusRunning      = "running"
+	CheckoutStatusCompensating = "compensating"
+	CheckoutStatusCompleted    = "completed"
+	CheckoutStatusFailed       = "failed"
+)
+
+// Checkout saga step names, in execution order
+const (
+	CheckoutStepLoadCart      = "load_cart"
+	CheckoutStepPriceItems    = "price_items"
+	CheckoutStepApplyPromos   = "apply_promotions"
+	CheckoutStepReserveStock  = "reserve_stock"
+	CheckoutStepCreateOrder   = "create_order"
+	CheckoutStepCreatePayment = "create_payment"
+	CheckoutStepConfirmStock  = "confirm_stock"
+	CheckoutStepClearCart     = "clear_cart"
+)
+
+// Checkout saga step statuses
+const (
+	StepStatusPending            = "pending"
+	StepStatusRunning            = "running"
+	StepStatusCompleted          = "completed"
+	StepStatusFailed             = "failed"
+	StepStatusCompensated        = "compensated"
+	StepStatusCompensationFailed = "compensation_failed"
+)
+
+// Checkout records one run of the checkout saga and the progress of each step
+type Checkout struct {
+	ID            uint           `json:"id" gorm:"primaryKey"`
+	UserID        uint           `json:"user_id" gorm:"index;not null"`
+	PaymentMethod string         `json:"payment_method" gorm:"size:20"`
+	Status        string         `json:"status" gorm:"size:20;not null"`
+	TotalAmount   money.Money    `json:"total_amount" gorm:"embedded;embeddedPrefix:total_amount_"`
+	OrderID       *uint          `json:"order_id"`
+	PaymentID     *uint          `json:"payment_id"`
+	FailedStep    string         `json:"failed_step,omitempty" gorm:"size:50"`
+	Error         string         `json:"error,omitempty" gorm:"type:text"`
+	Steps         []CheckoutStep `json:"steps" gorm:"foreignKey:CheckoutID"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// CheckoutStep is a single step of a checkout saga
+type CheckoutStep struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	CheckoutID  uint       `json:"checkout_id" gorm:"index;not null"`
+	Position    int        `json:"position"`
+	Name        string     `json:"name" gorm:"size:50;not null"`
+	Status      string     `json:"status" gorm:"size:30;not null"`
+	Detail      string     `json:"detail,omitempty" gorm:"type:text"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// CheckoutRequest is the payload for starting a checkout
+type CheckoutRequest struct {
+	PaymentMethod string `json:"payment_method" binding:"required,oneof=card upi wallet cod"`
+	// PostalCode is the shipping postal code, used to ship from the nearest warehouses
+	PostalCode string `json:"postal_code,omitempty" binding:"max=20"`
+	// CouponCodes are the promotion codes entered; checkout fails if one does not apply
+	CouponCodes []string `json:"coupon_codes,omitempty" binding:"max=5,dive,max=50"`
+}
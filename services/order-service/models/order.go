@@ -3,29 +3,86 @@ package models
 import (
 	"time"
 
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+
 	"gorm.io/gorm"
 )
 
+// Order is a placed order. TotalAmount is what the customer pays: the items
+// plus TaxAmount and ShippingAmount, less DiscountAmount. DiscountAmount is the
+// sum of the Discounts lines, one for each promotion applied at checkout.
+// TaxAmount is GST on the items after their discounts.
 type Order struct {
-	ID            uint           `json:"id" gorm:"primaryKey"`
-	UserID        uint           `json:"user_id"`
-	TotalAmount   float64        `json:"total_amount"`
-	Status        string         `json:"status"`
-	PaymentStatus string         `json:"payment_status"`
-	PaymentID     *string        `json:"payment_id"`
-	ShippingID    uint           `json:"shipping_id"`
-	OrderItems    []OrderItem    `json:"order_items" gorm:"foreignKey:OrderID"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+	ID               uint            `json:"id" gorm:"primaryKey"`
+	UserID           uint            `json:"user_id"`
+	TotalAmount      money.Money     `json:"total_amount" gorm:"embedded;embeddedPrefix:total_amount_"`
+	DiscountAmount   money.Money     `json:"discount_amount" gorm:"embedded;embeddedPrefix:discount_amount_"`
+	TaxAmount        money.Money     `json:"tax_amount" gorm:"embedded;embeddedPrefix:tax_amount_"`
+	ShippingAmount   money.Money     `json:"shipping_amount" gorm:"embedded;embeddedPrefix:shipping_amount_"`
+	Status           string          `json:"status"`
+	PaymentStatus    string          `json:"payment_status"`
+	PaymentID        *string         `json:"payment_id"`
+	FulfilmentStatus string          `json:"fulfilment_status" gorm:"size:20;default:'unfulfilled'"`
+	OrderItems       []OrderItem     `json:"order_items" gorm:"foreignKey:OrderID"`
+	Discounts        []OrderDiscount `json:"discounts,omitempty" gorm:"foreignKey:OrderID"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt  `json:"deleted_at" gorm:"index"`
+}
+
+// ItemNetAmounts returns what each order item cost, by item ID, after its
+// discount and with its share of the order's GST. Orders placed before items
+// recorded their discount have DiscountAmount spread over the items in
+// proportion to their value instead. Shipping is not part of any item.
+func (o *Order) ItemNetAmounts() (map[uint]money.Money, error) {
+	totals := make([]money.Money, len(o.OrderItems))
+	discounts := make([]money.Money, len(o.OrderItems))
+	ratios := make([]int64, len(o.OrderItems))
+	itemDiscounts := false
+	for i, item := range o.OrderItems {
+		totals[i] = item.Price.Mul(int64(item.Quantity))
+		discounts[i] = item.Discount
+		ratios[i] = totals[i].Amount
+		itemDiscounts = itemDiscounts || !item.Discount.IsZero()
+	}
+	if !itemDiscounts {
+		discounts = o.DiscountAmount.Allocate(ratios...)
+	}
+
+	for i := range o.OrderItems {
+		var err error
+		if totals[i], err = totals[i].Sub(discounts[i]); err != nil {
+			return nil, err
+		}
+		ratios[i] = totals[i].Amount
+	}
+	taxes := o.TaxAmount.Allocate(ratios...)
+
+	net := make(map[uint]money.Money, len(o.OrderItems))
+	for i, item := range o.OrderItems {
+		amount, err := totals[i].Add(taxes[i])
+		if err != nil {
+			return nil, err
+		}
+		net[item.ID] = amount
+	}
+	return net, nil
+}
+
+// ProductOrderCount is how many orders included a product
+type ProductOrderCount struct {
+	ProductID uint  `json:"product_id"`
+	Orders    int64 `json:"orders"`
 }
 
 type OrderItem struct {
 	ID        uint           `json:"id" gorm:"primaryKey"`
 	OrderID   uint           `json:"order_id"`
 	ProductID uint           `json:"product_id"`
+	VariantID uint           `json:"variant_id"`
 	Quantity  int            `json:"quantity"`
-	Price     float64        `json:"price"`
+	Price     money.Money    `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	Discount  money.Money    `json:"discount" gorm:"embedded;embeddedPrefix:discount_"` // promotions taken off the line
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+)
+
+// orderTransitions lists the statuses an order may move to from each status.
+// Delivered orders can only be returned; cancelled and returned are terminal.
+var orderTransitions = map[string][]string{
+	utils.OrderStatusPending:   {utils.OrderStatusConfirmed, utils.OrderStatusCancelled},
+	utils.OrderStatusConfirmed: {utils.OrderStatusPacked, utils.OrderStatusCancelled},
+	utils.OrderStatusPacked:    {utils.OrderStatusShipped, utils.OrderStatusCancelled},
+	// processing predates packed and is kept so existing orders can still move on
+	utils.OrderStatusProcessing: {utils.OrderStatusPacked, utils.OrderStatusCancelled},
+	utils.OrderStatusShipped:    {utils.OrderStatusDelivered, utils.OrderStatusReturned},
+	utils.OrderStatusDelivered:  {utils.OrderStatusReturned},
+	utils.OrderStatusCancelled:  {},
+	utils.OrderStatusReturned:   {},
+}
+
+// paymentTransitions lists the payment statuses an order may move to from each payment status
+var paymentTransitions = map[string][]string{
+	utils.PaymentStatusPending:           {utils.PaymentStatusAuthorized, utils.PaymentStatusSuccess, utils.PaymentStatusFailed, utils.PaymentStatusVoided},
+	utils.PaymentStatusAuthorized:        {utils.PaymentStatusSuccess, utils.PaymentStatusFailed, utils.PaymentStatusVoided},
+	utils.PaymentStatusFailed:            {utils.PaymentStatusPending, utils.PaymentStatusSuccess},
+	utils.PaymentStatusSuccess:           {utils.PaymentStatusPartiallyRefunded, utils.PaymentStatusRefunded},
+	utils.PaymentStatusPartiallyRefunded: {utils.PaymentStatusRefunded},
+	utils.PaymentStatusVoided:            {},
+	utils.PaymentStatusRefunded:          {},
+}
+
+// IsValidOrderStatus reports whether status is part of the order lifecycle
+func IsValidOrderStatus(status string) bool {
+	_, ok := orderTransitions[status]
+	return ok
+}
+
+// CanTransitionOrder reports whether an order may move from one status to another
+func CanTransitionOrder(from, to string) bool {
+	return canTransition(orderTransitions, from, to)
+}
+
+// IsValidPaymentStatus reports whether status is a known order payment status
+func IsValidPaymentStatus(status string) bool {
+	_, ok := paymentTransitions[status]
+	return ok
+}
+
+// CanTransitionPayment reports whether an order's payment status may move from one status to another
+func CanTransitionPayment(from, to string) bool {
+	return canTransition(paymentTransitions, from, to)
+}
+
+// IsValidFulfilmentStatus reports whether status is a known order fulfilment status.
+// Fulfilment is a rollup of the order's shipments rather than a lifecycle, so it
+// may move in any direction, e.g. back to unfulfilled when a shipment is cancelled.
+func IsValidFulfilmentStatus(status string) bool {
+	switch status {
+	case utils.FulfilmentStatusUnfulfilled, utils.FulfilmentStatusPartiallyShipped,
+		utils.FulfilmentStatusShipped, utils.FulfilmentStatusDelivered:
+		return true
+	}
+	return false
+}
+
+func canTransition(transitions map[string][]string, from, to string) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderStatusHistory records a single order status change
+type OrderStatusHistory struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	OrderID       uint      `json:"order_id" gorm:"index;not null"`
+	FromStatus    string    `json:"from_status" gorm:"size:20"`
+	ToStatus      string    `json:"to_status" gorm:"size:20;not null"`
+	ChangedBy     uint      `json:"changed_by"`
+	ChangedByRole string    `json:"changed_by_role" gorm:"size:50"`
+	Reason        string    `json:"reason,omitempty" gorm:"type:text"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
+
+// OrderStatusChange describes a requested status change and who is making it
+type OrderStatusChange struct {
+	Status        string
+	ChangedBy     uint
+	ChangedByRole string
+	Reason        string
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+// Promotion types
+const (
+	// PromotionTypePercentage takes Percent off the eligible lines, up to MaxDiscount when set
+	PromotionTypePercentage = "percentage"
+	// PromotionTypeFlat takes Amount off the eligible lines
+	PromotionTypeFlat = "flat"
+	// PromotionTypeBOGO makes GetQuantity units free for every BuyQuantity units bought of an eligible line
+	PromotionTypeBOGO = "bogo"
+	// PromotionTypeFreeShipping waives the shipping fee
+	PromotionTypeFreeShipping = "free_shipping"
+)
+
+// Promotion is a discount rule. A promotion with a Code applies only when the
+// code is entered; one without applies automatically to every eligible cart.
+//
+// CategoryIDs limits the discount to lines in those categories, MinCartValue
+// is the cart subtotal needed, FirstOrderOnly limits it to a user's first
+// order and Roles to callers with one of those roles. UsageLimit caps the
+// redemptions overall and PerUserLimit per user; zero means unlimited.
+// Promotions that are not Stackable never combine with other promotions.
+type Promotion struct {
+	ID             uint        `json:"id" gorm:"primaryKey"`
+	Code           *string     `json:"code,omitempty" gorm:"size:50;uniqueIndex"`
+	Name           string      `json:"name" gorm:"size:100;not null"`
+	Description    string      `json:"description,omitempty" gorm:"type:text"`
+	Type           string      `json:"type" gorm:"size:20;not null"`
+	Percent        float64     `json:"percent,omitempty" gorm:"type:decimal(5,2);default:0"`
+	Amount         money.Money `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	MaxDiscount    money.Money `json:"max_discount" gorm:"embedded;embeddedPrefix:max_discount_"`
+	BuyQuantity    int         `json:"buy_quantity,omitempty"`
+	GetQuantity    int         `json:"get_quantity,omitempty"`
+	CategoryIDs    []uint64    `json:"category_ids,omitempty" gorm:"serializer:json;type:text"`
+	MinCartValue   money.Money `json:"min_cart_value" gorm:"embedded;embeddedPrefix:min_cart_value_"`
+	FirstOrderOnly bool        `json:"first_order_only"`
+	Roles          []string    `json:"roles,omitempty" gorm:"serializer:json;type:text"`
+	UsageLimit     int         `json:"usage_limit"`
+	PerUserLimit   int         `json:"per_user_limit"`
+	Stackable      bool        `json:"stackable"`
+	Active         bool        `json:"active" gorm:"default:true"`
+	StartsAt       *time.Time  `json:"starts_at"`
+	EndsAt         *time.Time  `json:"ends_at"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+// PromotionRedemption records a promotion used by an order. Redemptions of
+// cancelled orders are voided and no longer count towards the usage limits.
+type PromotionRedemption struct {
+	ID          uint        `json:"id" gorm:"primaryKey"`
+	PromotionID uint        `json:"promotion_id" gorm:"index;not null"`
+	UserID      uint        `json:"user_id" gorm:"index;not null"`
+	OrderID     uint        `json:"order_id" gorm:"index;not null"`
+	Amount      money.Money `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	VoidedAt    *time.Time  `json:"voided_at,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// PromotionUsage is how often a promotion has been redeemed, overall and by one user
+type PromotionUsage struct {
+	Total  int
+	ByUser int
+}
+
+// OrderDiscount is a discount line on an order, from the promotion that gave it
+type OrderDiscount struct {
+	ID          uint        `json:"id" gorm:"primaryKey"`
+	OrderID     uint        `json:"order_id" gorm:"index;not null"`
+	PromotionID uint        `json:"promotion_id"`
+	Code        string      `json:"code,omitempty" gorm:"size:50"`
+	Name        string      `json:"name" gorm:"size:100"`
+	Type        string      `json:"type" gorm:"size:20"`
+	Amount      money.Money `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// PromotionRequest is the payload for creating or replacing a promotion
+type PromotionRequest struct {
+	Code           string      `json:"code" binding:"max=50"`
+	Name           string      `json:"name" binding:"required,max=100"`
+	Description    string      `json:"description"`
+	Type           string      `json:"type" binding:"required,oneof=percentage flat bogo free_shipping"`
+	Percent        float64     `json:"percent" binding:"gte=0,lte=100"`
+	Amount         money.Money `json:"amount"`
+	MaxDiscount    money.Money `json:"max_discount"`
+	BuyQuantity    int         `json:"buy_quantity" binding:"gte=0"`
+	GetQuantity    int         `json:"get_quantity" binding:"gte=0"`
+	CategoryIDs    []uint64    `json:"category_ids"`
+	MinCartValue   money.Money `json:"min_cart_value"`
+	FirstOrderOnly bool        `json:"first_order_only"`
+	Roles          []string    `json:"roles"`
+	UsageLimit     int         `json:"usage_limit" binding:"gte=0"`
+	PerUserLimit   int         `json:"per_user_limit" binding:"gte=0"`
+	Stackable      bool        `json:"stackable"`
+	Active         *bool       `json:"active"`
+	StartsAt       *time.Time  `json:"starts_at"`
+	EndsAt         *time.Time  `json:"ends_at"`
+}
+
+// PromotionLine is a priced cart line offered for promotion evaluation.
+// UnitPrice is the price after the product discount.
+type PromotionLine struct {
+	ProductID  uint64      `json:"product_id" binding:"required"`
+	VariantID  uint64      `json:"variant_id"`
+	CategoryID uint64      `json:"category_id"`
+	Quantity   int         `json:"quantity" binding:"required,gt=0"`
+	UnitPrice  money.Money `json:"unit_price"`
+}
+
+// PromotionEvaluationRequest asks which promotions apply to a cart
+type PromotionEvaluationRequest struct {
+	Lines []PromotionLine `json:"lines" binding:"required,dive"`
+	Codes []string        `json:"codes" binding:"max=5,dive,max=50"`
+}
+
+// AppliedPromotion is a promotion that discounts the cart
+type AppliedPromotion struct {
+	PromotionID uint        `json:"promotion_id"`
+	Code        string      `json:"code,omitempty"`
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	Amount      money.Money `json:"amount"`
+}
+
+// RejectedPromotion is an entered code that does not apply, with the reason
+type RejectedPromotion struct {
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// PromotionEvaluation is the outcome of applying promotions to a cart. Tax is
+// GST on Subtotal less Discount, and Total is Subtotal less Discount plus Tax
+// plus ShippingFee less ShippingDiscount. LineDiscounts is the part of
+// Discount taken off each line, in the order of the request's lines.
+type PromotionEvaluation struct {
+	Applied          []AppliedPromotion  `json:"applied"`
+	Rejected         []RejectedPromotion `json:"rejected,omitempty"`
+	Subtotal         money.Money         `json:"subtotal"`
+	Discount         money.Money         `json:"discount"`
+	LineDiscounts    []money.Money       `json:"line_discounts"`
+	Tax              money.Money         `json:"tax"`
+	ShippingFee      money.Money         `json:"shipping_fee"`
+	ShippingDiscount money.Money         `json:"shipping_discount"`
+	Total            money.Money         `json:"total"`
+}
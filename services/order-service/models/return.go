@@ -0,0 +1,144 @@
+package models
+
+import (
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+// Return (RMA) statuses
+const (
+	ReturnStatusRequested = "requested"
+	ReturnStatusApproved  = "approved"
+	ReturnStatusRejected  = "rejected"
+	ReturnStatusCancelled = "cancelled"
+	ReturnStatusReceived  = "received"
+	ReturnStatusRefunded  = "refunded"
+)
+
+// returnTransitions lists the statuses a return may move to from each status.
+// A received return stays received until its refund goes through.
+var returnTransitions = map[string][]string{
+	ReturnStatusRequested: {ReturnStatusApproved, ReturnStatusRejected, ReturnStatusCancelled},
+	ReturnStatusApproved:  {ReturnStatusReceived},
+	ReturnStatusReceived:  {ReturnStatusRefunded},
+	ReturnStatusRejected:  {},
+	ReturnStatusCancelled: {},
+	ReturnStatusRefunded:  {},
+}
+
+// IsValidReturnStatus reports whether status is part of the return lifecycle
+func IsValidReturnStatus(status string) bool {
+	_, ok := returnTransitions[status]
+	return ok
+}
+
+// CanTransitionReturn reports whether a return may move from one status to another
+func CanTransitionReturn(from, to string) bool {
+	return canTransition(returnTransitions, from, to)
+}
+
+// HoldsItems reports whether a return's items count against what is left to
+// return on the order. Rejected and cancelled returns free their items.
+func (r *ReturnRequest) HoldsItems() bool {
+	return r.Status != ReturnStatusRejected && r.Status != ReturnStatusCancelled
+}
+
+// ReturnAddress is where the carrier collects the returned items
+type ReturnAddress struct {
+	Name       string `json:"name" gorm:"size:100" binding:"required,max=100"`
+	Line1      string `json:"line1" gorm:"size:200" binding:"required,max=200"`
+	Line2      string `json:"line2,omitempty" gorm:"size:200" binding:"max=200"`
+	City       string `json:"city" gorm:"size:100" binding:"required,max=100"`
+	State      string `json:"state,omitempty" gorm:"size:100" binding:"max=100"`
+	PostalCode string `json:"postal_code" gorm:"size:20" binding:"required,max=20"`
+	Country    string `json:"country,omitempty" gorm:"size:2" binding:"max=2"`
+	Phone      string `json:"phone,omitempty" gorm:"size:20" binding:"max=20"`
+}
+
+// ReturnRequest is a customer's request to send order items back (an RMA).
+// RefundAmount starts as what the items cost after the order's discounts, with
+// their GST, and may be lowered on approval. WarehouseID is where the goods were received.
+type ReturnRequest struct {
+	ID               uint          `json:"id" gorm:"primaryKey"`
+	OrderID          uint          `json:"order_id" gorm:"index;not null"`
+	UserID           uint          `json:"user_id" gorm:"index;not null"`
+	Status           string        `json:"status" gorm:"size:20;not null;index"`
+	Reason           string        `json:"reason" gorm:"type:text"`
+	PickupAddress    ReturnAddress `json:"pickup_address" gorm:"embedded;embeddedPrefix:pickup_"`
+	RefundAmount     money.Money   `json:"refund_amount" gorm:"embedded;embeddedPrefix:refund_amount_"`
+	ReviewedBy       *uint         `json:"reviewed_by,omitempty"`
+	ReviewNote       string        `json:"review_note,omitempty" gorm:"type:text"`
+	ReturnShipmentID *uint         `json:"return_shipment_id,omitempty"`
+	TrackingNumber   string        `json:"tracking_number,omitempty" gorm:"size:100"`
+	LabelURL         string        `json:"label_url,omitempty" gorm:"size:500"`
+	RefundID         *uint         `json:"refund_id,omitempty"`
+	RefundAttempts   int           `json:"refund_attempts"`
+	RefundError      string        `json:"refund_error,omitempty" gorm:"type:text"`
+	WarehouseID      *uint         `json:"warehouse_id,omitempty"`
+	Items            []ReturnItem  `json:"items" gorm:"foreignKey:ReturnRequestID"`
+	ApprovedAt       *time.Time    `json:"approved_at,omitempty"`
+	ReceivedAt       *time.Time    `json:"received_at,omitempty"`
+	RefundedAt       *time.Time    `json:"refunded_at,omitempty"`
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+}
+
+// ReturnItem is a quantity of one order item being returned. Restockable is set
+// when the goods are inspected on receipt and Restocked once they are back in
+// inventory.
+type ReturnItem struct {
+	ID              uint        `json:"id" gorm:"primaryKey"`
+	ReturnRequestID uint        `json:"return_request_id" gorm:"index;not null"`
+	OrderItemID     uint        `json:"order_item_id" gorm:"not null"`
+	ProductID       uint        `json:"product_id"`
+	VariantID       uint        `json:"variant_id"`
+	Quantity        int         `json:"quantity" gorm:"not null"`
+	UnitPrice       money.Money `json:"unit_price" gorm:"embedded;embeddedPrefix:unit_price_"`
+	Reason          string      `json:"reason,omitempty" gorm:"size:500"`
+	Restockable     *bool       `json:"restockable,omitempty"`
+	Restocked       bool        `json:"restocked"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
+// ReturnCreateRequest is the payload for requesting a return
+type ReturnCreateRequest struct {
+	OrderID       uint                `json:"order_id" binding:"required"`
+	Reason        string              `json:"reason" binding:"required,max=1000"`
+	PickupAddress ReturnAddress       `json:"pickup_address" binding:"required"`
+	Items         []ReturnItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// ReturnItemRequest names an order item and how many of it are sent back
+type ReturnItemRequest struct {
+	OrderItemID uint   `json:"order_item_id" binding:"required"`
+	Quantity    int    `json:"quantity" binding:"required,gt=0"`
+	Reason      string `json:"reason,omitempty" binding:"max=500"`
+}
+
+// ReturnApproveRequest is the payload for approving a return. Without a
+// RefundAmount the full value of the returned items is refunded.
+type ReturnApproveRequest struct {
+	RefundAmount *money.Money `json:"refund_amount,omitempty"`
+	Note         string       `json:"note,omitempty" binding:"max=1000"`
+}
+
+// ReturnRejectRequest is the payload for rejecting a return
+type ReturnRejectRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+// ReturnReceiveRequest records which warehouse received the returned goods,
+// where restockable items go back into stock, and their inspection. Items not
+// listed are restockable.
+type ReturnReceiveRequest struct {
+	WarehouseID uint               `json:"warehouse_id" binding:"required"`
+	Items       []ReturnInspection `json:"items,omitempty" binding:"omitempty,dive"`
+}
+
+// ReturnInspection says whether a returned order item can be sold again
+type ReturnInspection struct {
+	OrderItemID uint  `json:"order_item_id" binding:"required"`
+	Restockable *bool `json:"restockable" binding:"required"`
+}
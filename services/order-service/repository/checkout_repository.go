@@ -0,0 +1,10 @@
+package repository
+
+import "github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+
+type CheckoutRepository interface {
+	CreateCheckout(checkout *models.Checkout) error
+	GetCheckoutByID(checkoutID uint) (*models.Checkout, error)
+	UpdateCheckout(checkout *models.Checkout) error
+	UpdateStep(step *models.CheckoutStep) error
+}
@@ -0,0 +1,20 @@
+package repository
+
+import "errors"
+
+// ErrOrderNotFound is returned when an order does not exist
+var ErrOrderNotFound = errors.New("order not found")
+
+// ErrOrderStatusChanged is returned when an order's status was changed by
+// another request between reading and updating it
+var ErrOrderStatusChanged = errors.New("order status has changed")
+
+// ErrReturnNotFound is returned when a return does not exist
+var ErrReturnNotFound = errors.New("return not found")
+
+// ErrReturnStatusChanged is returned when a return's status was changed by
+// another request between reading and updating it
+var ErrReturnStatusChanged = errors.New("return status has changed")
+
+// ErrPromotionNotFound is returned when a promotion does not exist
+var ErrPromotionNotFound = errors.New("promotion not found")
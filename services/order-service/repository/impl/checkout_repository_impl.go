@@ -0,0 +1,44 @@
+package impl
+
+import (
+	"errors"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/repository"
+
+	"gorm.io/gorm"
+)
+
+type CheckoutRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCheckoutRepository(db *gorm.DB) repository.CheckoutRepository {
+	return &CheckoutRepositoryImpl{db: db}
+}
+
+func (r *CheckoutRepositoryImpl) CreateCheckout(checkout *models.Checkout) error {
+	return r.db.Create(checkout).Error
+}
+
+func (r *CheckoutRepositoryImpl) GetCheckoutByID(checkoutID uint) (*models.Checkout, error) {
+	var checkout models.Checkout
+	err := r.db.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).First(&checkout, checkoutID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("checkout not found")
+		}
+		return nil, err
+	}
+	return &checkout, nil
+}
+
+func (r *CheckoutRepositoryImpl) UpdateCheckout(checkout *models.Checkout) error {
+	return r.db.Omit("Steps").Save(checkout).Error
+}
+
+func (r *CheckoutRepositoryImpl) UpdateStep(step *models.CheckoutStep) error {
+	return r.db.Save(step).Error
+}
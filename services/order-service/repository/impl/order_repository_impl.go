@@ -2,10 +2,16 @@ package impl
 
 import (
 	"errors"
+	"slices"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/events"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
 	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
 	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/repository"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderRepositoryImpl struct {
@@ -16,15 +22,74 @@ func NewOrderRepository(db *gorm.DB) repository.OrderRepository {
 	return &OrderRepositoryImpl{db: db}
 }
 
-func (r *OrderRepositoryImpl) CreateOrder(order *models.Order) error {
-	return r.db.Create(order).Error
+func (r *OrderRepositoryImpl) CreateOrder(order *models.Order, check func(promotion *models.Promotion, usage models.PromotionUsage) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the promotions in ID order so that concurrent orders cannot deadlock
+		promotionIDs := make([]uint, 0, len(order.Discounts))
+		for _, discount := range order.Discounts {
+			if discount.PromotionID != 0 && !slices.Contains(promotionIDs, discount.PromotionID) {
+				promotionIDs = append(promotionIDs, discount.PromotionID)
+			}
+		}
+		slices.Sort(promotionIDs)
+		for _, promotionID := range promotionIDs {
+			var promotion models.Promotion
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&promotion, promotionID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return repository.ErrPromotionNotFound
+				}
+				return err
+			}
+
+			var usage models.PromotionUsage
+			err := tx.Model(&models.PromotionRedemption{}).
+				Select("COUNT(*) AS total, COALESCE(SUM(CASE WHEN user_id = ? THEN 1 ELSE 0 END), 0) AS by_user", order.UserID).
+				Where("promotion_id = ? AND voided_at IS NULL", promotionID).
+				Scan(&usage).Error
+			if err != nil {
+				return err
+			}
+			if err := check(&promotion, usage); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		for _, discount := range order.Discounts {
+			if discount.PromotionID == 0 {
+				continue
+			}
+			redemption := &models.PromotionRedemption{
+				PromotionID: discount.PromotionID,
+				UserID:      order.UserID,
+				OrderID:     order.ID,
+				Amount:      discount.Amount,
+			}
+			if err := tx.Create(redemption).Error; err != nil {
+				return err
+			}
+		}
+
+		event, err := events.New(events.OrderCreated, "order", order.ID, events.OrderCreatedPayload{
+			OrderID:     order.ID,
+			UserID:      order.UserID,
+			TotalAmount: order.TotalAmount,
+			ItemCount:   len(order.OrderItems),
+		})
+		if err != nil {
+			return err
+		}
+		return events.Enqueue(tx, event)
+	})
 }
 
 func (r *OrderRepositoryImpl) GetOrderByID(orderID uint) (*models.Order, error) {
 	var order models.Order
-	if err := r.db.Preload("OrderItems").First(&order, orderID).Error; err != nil {
+	if err := r.db.Preload("OrderItems").Preload("Discounts").First(&order, orderID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("order not found")
+			return nil, repository.ErrOrderNotFound
 		}
 		return nil, err
 	}
@@ -33,19 +98,21 @@ func (r *OrderRepositoryImpl) GetOrderByID(orderID uint) (*models.Order, error)
 
 func (r *OrderRepositoryImpl) GetOrdersByUserID(userID uint) ([]models.Order, error) {
 	var orders []models.Order
-	if err := r.db.Preload("OrderItems").Where("user_id = ?", userID).Find(&orders).Error; err != nil {
+	if err := r.db.Preload("OrderItems").Preload("Discounts").Where("user_id = ?", userID).Find(&orders).Error; err != nil {
 		return nil, err
 	}
 	return orders, nil
 }
 
 func (r *OrderRepositoryImpl) UpdateOrder(orderID uint, order *models.Order) error {
-	result := r.db.Model(&models.Order{}).Where("id = ?", orderID).Updates(order)
+	// Status and payment status only change through their dedicated transitions,
+	// and discount lines are only written at checkout
+	result := r.db.Model(&models.Order{}).Where("id = ?", orderID).Omit("status", "payment_status", "Discounts").Updates(order)
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("order not found")
+		return repository.ErrOrderNotFound
 	}
 	return nil
 }
@@ -56,28 +123,70 @@ func (r *OrderRepositoryImpl) DeleteOrder(orderID uint) error {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("order not found")
+		return repository.ErrOrderNotFound
 	}
 	return nil
 }
 
 func (r *OrderRepositoryImpl) GetAllOrders() ([]models.Order, error) {
 	var orders []models.Order
-	if err := r.db.Preload("OrderItems").Find(&orders).Error; err != nil {
+	if err := r.db.Preload("OrderItems").Preload("Discounts").Find(&orders).Error; err != nil {
 		return nil, err
 	}
 	return orders, nil
 }
 
-func (r *OrderRepositoryImpl) UpdateOrderStatus(orderID uint, status string) error {
-	result := r.db.Model(&models.Order{}).Where("id = ?", orderID).Update("status", status)
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return errors.New("order not found")
+func (r *OrderRepositoryImpl) UpdateOrderStatus(history *models.OrderStatusHistory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Order{}).
+			Where("id = ? AND status = ?", history.OrderID, history.FromStatus).
+			Update("status", history.ToStatus)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var count int64
+			if err := tx.Model(&models.Order{}).Where("id = ?", history.OrderID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return repository.ErrOrderNotFound
+			}
+			return repository.ErrOrderStatusChanged
+		}
+		if err := tx.Create(history).Error; err != nil {
+			return err
+		}
+		if history.ToStatus == utils.OrderStatusCancelled {
+			err := tx.Model(&models.PromotionRedemption{}).
+				Where("order_id = ? AND voided_at IS NULL", history.OrderID).
+				Update("voided_at", time.Now()).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		event, err := events.New(events.OrderStatusChanged, "order", history.OrderID, events.OrderStatusChangedPayload{
+			OrderID:       history.OrderID,
+			From:          history.FromStatus,
+			To:            history.ToStatus,
+			ChangedBy:     history.ChangedBy,
+			ChangedByRole: history.ChangedByRole,
+			Reason:        history.Reason,
+		})
+		if err != nil {
+			return err
+		}
+		return events.Enqueue(tx, event)
+	})
+}
+
+func (r *OrderRepositoryImpl) GetStatusHistory(orderID uint) ([]models.OrderStatusHistory, error) {
+	var history []models.OrderStatusHistory
+	if err := r.db.Where("order_id = ?", orderID).Order("created_at ASC, id ASC").Find(&history).Error; err != nil {
+		return nil, err
 	}
-	return nil
+	return history, nil
 }
 
 func (r *OrderRepositoryImpl) UpdatePaymentStatus(orderID uint, status string, paymentID *string) error {
@@ -93,7 +202,43 @@ func (r *OrderRepositoryImpl) UpdatePaymentStatus(orderID uint, status string, p
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("order not found")
+		return repository.ErrOrderNotFound
+	}
+	return nil
+}
+
+func (r *OrderRepositoryImpl) UpdateFulfilmentStatus(orderID uint, status string) error {
+	result := r.db.Model(&models.Order{}).Where("id = ?", orderID).Update("fulfilment_status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := r.db.Model(&models.Order{}).Where("id = ?", orderID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return repository.ErrOrderNotFound
+		}
 	}
 	return nil
 }
+
+func (r *OrderRepositoryImpl) CountUserOrders(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Order{}).
+		Where("user_id = ? AND status <> ?", userID, utils.OrderStatusCancelled).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *OrderRepositoryImpl) GetProductOrderCounts(since time.Time) ([]models.ProductOrderCount, error) {
+	var counts []models.ProductOrderCount
+	err := r.db.Model(&models.OrderItem{}).
+		Select("order_items.product_id, COUNT(DISTINCT order_items.order_id) AS orders").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("orders.created_at >= ? AND orders.status <> ?", since, utils.OrderStatusCancelled).
+		Group("order_items.product_id").
+		Scan(&counts).Error
+	return counts, err
+}
@@ -0,0 +1,125 @@
+package impl
+
+import (
+	"errors"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/repository"
+
+	"gorm.io/gorm"
+)
+
+type PromotionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) repository.PromotionRepository {
+	return &PromotionRepositoryImpl{db: db}
+}
+
+func (r *PromotionRepositoryImpl) CreatePromotion(promotion *models.Promotion) error {
+	return r.db.Create(promotion).Error
+}
+
+func (r *PromotionRepositoryImpl) GetPromotionByID(promotionID uint) (*models.Promotion, error) {
+	var promotion models.Promotion
+	if err := r.db.First(&promotion, promotionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrPromotionNotFound
+		}
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (r *PromotionRepositoryImpl) GetPromotionByCode(code string) (*models.Promotion, error) {
+	var promotion models.Promotion
+	if err := r.db.Where("code = ?", code).First(&promotion).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrPromotionNotFound
+		}
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (r *PromotionRepositoryImpl) GetPromotions() ([]models.Promotion, error) {
+	var promotions []models.Promotion
+	if err := r.db.Order("id DESC").Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+func (r *PromotionRepositoryImpl) UpdatePromotion(promotion *models.Promotion) error {
+	result := r.db.Save(promotion)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrPromotionNotFound
+	}
+	return nil
+}
+
+func (r *PromotionRepositoryImpl) DeletePromotion(promotionID uint) error {
+	result := r.db.Delete(&models.Promotion{}, promotionID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrPromotionNotFound
+	}
+	return nil
+}
+
+func (r *PromotionRepositoryImpl) GetCandidatePromotions(codes []string, now time.Time) ([]models.Promotion, error) {
+	query := r.db.Where("active = ?", true).
+		Where("starts_at IS NULL OR starts_at <= ?", now).
+		Where("ends_at IS NULL OR ends_at > ?", now)
+	if len(codes) > 0 {
+		query = query.Where("code IS NULL OR code IN ?", codes)
+	} else {
+		query = query.Where("code IS NULL")
+	}
+
+	var promotions []models.Promotion
+	if err := query.Order("id").Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+func (r *PromotionRepositoryImpl) GetUsage(promotionIDs []uint, userID uint) (map[uint]models.PromotionUsage, error) {
+	usage := make(map[uint]models.PromotionUsage, len(promotionIDs))
+	if len(promotionIDs) == 0 {
+		return usage, nil
+	}
+
+	var counts []struct {
+		PromotionID uint
+		Total       int
+		ByUser      int
+	}
+	err := r.db.Model(&models.PromotionRedemption{}).
+		Select("promotion_id, COUNT(*) AS total, SUM(CASE WHEN user_id = ? THEN 1 ELSE 0 END) AS by_user", userID).
+		Where("promotion_id IN ? AND voided_at IS NULL", promotionIDs).
+		Group("promotion_id").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, count := range counts {
+		usage[count.PromotionID] = models.PromotionUsage{Total: count.Total, ByUser: count.ByUser}
+	}
+	return usage, nil
+}
+
+func (r *PromotionRepositoryImpl) GetRedemptions(promotionID uint) ([]models.PromotionRedemption, error) {
+	var redemptions []models.PromotionRedemption
+	if err := r.db.Where("promotion_id = ?", promotionID).Order("id DESC").Find(&redemptions).Error; err != nil {
+		return nil, err
+	}
+	return redemptions, nil
+}
@@ -0,0 +1,125 @@
+package impl
+
+import (
+	"errors"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReturnRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewReturnRepository(db *gorm.DB) repository.ReturnRepository {
+	return &ReturnRepositoryImpl{db: db}
+}
+
+func (r *ReturnRepositoryImpl) CreateReturn(ret *models.ReturnRequest, check func(returned map[uint]int) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the order so that concurrent returns cannot both take the same items
+		var order models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, ret.OrderID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return repository.ErrOrderNotFound
+			}
+			return err
+		}
+
+		var held []struct {
+			OrderItemID uint
+			Quantity    int
+		}
+		err := tx.Model(&models.ReturnItem{}).
+			Select("return_items.order_item_id, SUM(return_items.quantity) AS quantity").
+			Joins("JOIN return_requests ON return_requests.id = return_items.return_request_id").
+			Where("return_requests.order_id = ? AND return_requests.status NOT IN ?", ret.OrderID,
+				[]string{models.ReturnStatusRejected, models.ReturnStatusCancelled}).
+			Group("return_items.order_item_id").
+			Scan(&held).Error
+		if err != nil {
+			return err
+		}
+
+		returned := make(map[uint]int, len(held))
+		for _, item := range held {
+			returned[item.OrderItemID] = item.Quantity
+		}
+		if err := check(returned); err != nil {
+			return err
+		}
+		return tx.Create(ret).Error
+	})
+}
+
+func (r *ReturnRepositoryImpl) GetReturnByID(returnID uint) (*models.ReturnRequest, error) {
+	var ret models.ReturnRequest
+	if err := r.db.Preload("Items").First(&ret, returnID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrReturnNotFound
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (r *ReturnRepositoryImpl) GetReturns(status string) ([]models.ReturnRequest, error) {
+	query := r.db.Preload("Items").Order("id DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var returns []models.ReturnRequest
+	if err := query.Find(&returns).Error; err != nil {
+		return nil, err
+	}
+	return returns, nil
+}
+
+func (r *ReturnRepositoryImpl) GetReturnsByUserID(userID uint) ([]models.ReturnRequest, error) {
+	var returns []models.ReturnRequest
+	if err := r.db.Preload("Items").Where("user_id = ?", userID).Order("id DESC").Find(&returns).Error; err != nil {
+		return nil, err
+	}
+	return returns, nil
+}
+
+func (r *ReturnRepositoryImpl) GetReturnsByOrderID(orderID uint) ([]models.ReturnRequest, error) {
+	var returns []models.ReturnRequest
+	if err := r.db.Preload("Items").Where("order_id = ?", orderID).Order("id ASC").Find(&returns).Error; err != nil {
+		return nil, err
+	}
+	return returns, nil
+}
+
+func (r *ReturnRepositoryImpl) UpdateReturn(ret *models.ReturnRequest, fromStatus string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(ret).
+			Where("status = ?", fromStatus).
+			Select("*").Omit("Items", "CreatedAt").
+			Updates(ret)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var count int64
+			if err := tx.Model(&models.ReturnRequest{}).Where("id = ?", ret.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return repository.ErrReturnNotFound
+			}
+			return repository.ErrReturnStatusChanged
+		}
+
+		for i := range ret.Items {
+			if err := tx.Save(&ret.Items[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
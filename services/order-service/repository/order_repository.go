@@ -1,14 +1,32 @@
 package repository
 
-import "github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+import (
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+)
 
 type OrderRepository interface {
-	CreateOrder(order *models.Order) error
+	// CreateOrder saves an order with its items and discount lines, and records
+	// a redemption for each promotion behind a discount line. check is called
+	// with each of those promotions, locked, and its usage so far, so that
+	// concurrent orders cannot take a promotion past its limits.
+	CreateOrder(order *models.Order, check func(promotion *models.Promotion, usage models.PromotionUsage) error) error
 	GetOrderByID(orderID uint) (*models.Order, error)
 	GetOrdersByUserID(userID uint) ([]models.Order, error)
 	UpdateOrder(orderID uint, order *models.Order) error
 	DeleteOrder(orderID uint) error
 	GetAllOrders() ([]models.Order, error)
-	UpdateOrderStatus(orderID uint, status string) error
+	// UpdateOrderStatus moves an order from history.FromStatus to history.ToStatus and
+	// records the change. It fails if the order is no longer in history.FromStatus.
+	// Cancelling an order voids its promotion redemptions.
+	UpdateOrderStatus(history *models.OrderStatusHistory) error
+	GetStatusHistory(orderID uint) ([]models.OrderStatusHistory, error)
 	UpdatePaymentStatus(orderID uint, status string, paymentID *string) error
+	UpdateFulfilmentStatus(orderID uint, status string) error
+	// CountUserOrders counts a user's orders that were not cancelled
+	CountUserOrders(userID uint) (int64, error)
+	// GetProductOrderCounts counts, for each product, the orders placed since
+	// the given time that included it and were not cancelled
+	GetProductOrderCounts(since time.Time) ([]models.ProductOrderCount, error)
 }
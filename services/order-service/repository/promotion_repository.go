@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+)
+
+type PromotionRepository interface {
+	CreatePromotion(promotion *models.Promotion) error
+	GetPromotionByID(promotionID uint) (*models.Promotion, error)
+	GetPromotionByCode(code string) (*models.Promotion, error)
+	GetPromotions() ([]models.Promotion, error)
+	UpdatePromotion(promotion *models.Promotion) error
+	DeletePromotion(promotionID uint) error
+	// GetCandidatePromotions returns the active promotions running at now that
+	// apply automatically or have one of the codes
+	GetCandidatePromotions(codes []string, now time.Time) ([]models.Promotion, error)
+	// GetUsage counts the redemptions of each promotion that were not voided
+	GetUsage(promotionIDs []uint, userID uint) (map[uint]models.PromotionUsage, error)
+	GetRedemptions(promotionID uint) ([]models.PromotionRedemption, error)
+}
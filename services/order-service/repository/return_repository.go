@@ -0,0 +1,17 @@
+package repository
+
+import "github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+
+type ReturnRepository interface {
+	// CreateReturn saves a return while holding a lock on its order. check is
+	// given the quantity of each order item already held by other returns and
+	// rejects the new return by returning an error.
+	CreateReturn(ret *models.ReturnRequest, check func(returned map[uint]int) error) error
+	GetReturnByID(returnID uint) (*models.ReturnRequest, error)
+	GetReturns(status string) ([]models.ReturnRequest, error)
+	GetReturnsByUserID(userID uint) ([]models.ReturnRequest, error)
+	GetReturnsByOrderID(orderID uint) ([]models.ReturnRequest, error)
+	// UpdateReturn saves a return and its items. It fails if the return is no
+	// longer in fromStatus.
+	UpdateReturn(ret *models.ReturnRequest, fromStatus string) error
+}
@@ -1,28 +1,68 @@
 package routes
 
 import (
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/events"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/idempotency"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/middleware"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/client"
 	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/handlers"
 	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/repository/impl"
 	serviceImpl "github.com/DurgaPratapRajbhar/e-commerce/services/order-service/services/impl"
-	"github.com/DurgaPratapRajbhar/e-commerce/pkg/middleware"
 
 	"github.com/gin-gonic/gin"
-	"github.com/swaggo/gin-swagger"
 	swaggerFiles "github.com/swaggo/files"
+	"github.com/swaggo/gin-swagger"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(r *gin.Engine, db *gorm.DB) {
+func SetupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
 	orderRepo := impl.NewOrderRepository(db)
 	orderService := serviceImpl.NewOrderService(orderRepo)
 	orderHandler := handlers.NewOrderHandler(orderService)
 
+	shippingFee := money.New(cfg.Shipping.FlatFee, money.DefaultCurrency)
+	promotionService := serviceImpl.NewPromotionService(impl.NewPromotionRepository(db), orderRepo, shippingFee, cfg.Tax.GSTPercent)
+	promotionHandler := handlers.NewPromotionHandler(promotionService)
+
+	checkoutRepo := impl.NewCheckoutRepository(db)
+	checkoutService := serviceImpl.NewCheckoutService(
+		checkoutRepo,
+		orderService,
+		client.NewCartClient(cfg),
+		client.NewProductClient(cfg),
+		client.NewInventoryClient(cfg),
+		client.NewPaymentClient(cfg),
+		promotionService,
+	)
+	checkoutHandler := handlers.NewCheckoutHandler(checkoutService)
+
+	returnService := serviceImpl.NewReturnService(
+		impl.NewReturnRepository(db),
+		orderService,
+		client.NewShippingClient(cfg),
+		client.NewInventoryClient(cfg),
+		client.NewPaymentClient(cfg),
+	)
+	returnHandler := handlers.NewReturnHandler(returnService)
+
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Events from other services are signed with EVENT_SECRET instead of
+	// carrying a user, so they are received outside ServiceAuthMiddleware
+	inbox := events.NewMemoryBroker()
+	for _, eventType := range []string{events.PaymentAuthorized, events.PaymentSucceeded, events.PaymentFailed, events.PaymentVoided, events.PaymentRefunded} {
+		inbox.Subscribe(eventType, orderService.SyncPaymentStatus)
+	}
+	r.POST("/order/events", gin.WrapH(events.NewReceiver(inbox, cfg.Events.Secret, cfg.Webhooks.Tolerance)))
+
+	idempotent := middleware.IdempotencyMiddleware(idempotency.NewStore(db))
+
 	orders := r.Group("/order")
 	orders.Use(middleware.ServiceAuthMiddleware())
 	{
-		orders.POST("", orderHandler.CreateOrder)
+		orders.POST("", idempotent, orderHandler.CreateOrder)
 		orders.GET("/:id", orderHandler.GetOrder)
 		orders.GET("/user/:userId", orderHandler.GetUserOrders)
 		orders.GET("", orderHandler.GetAllOrders)
@@ -30,6 +70,30 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB) {
 		orders.DELETE("/:id", orderHandler.DeleteOrder)
 		orders.PATCH("/:id/status", orderHandler.UpdateOrderStatus)
 		orders.PATCH("/:id/payment", orderHandler.UpdatePaymentStatus)
+		orders.PATCH("/:id/fulfilment", orderHandler.UpdateFulfilmentStatus)
+		orders.GET("/:id/history", orderHandler.GetOrderHistory)
+		orders.GET("/products/order-counts", orderHandler.GetProductOrderCounts)
+
+		orders.POST("/checkout", idempotent, checkoutHandler.Checkout)
+		orders.GET("/checkout/:id", checkoutHandler.GetCheckout)
+
+		orders.POST("/returns", idempotent, returnHandler.CreateReturn)
+		orders.GET("/returns", returnHandler.GetReturns)
+		orders.GET("/returns/:id", returnHandler.GetReturn)
+		orders.GET("/:id/returns", returnHandler.GetOrderReturns)
+		orders.POST("/returns/:id/approve", returnHandler.ApproveReturn)
+		orders.POST("/returns/:id/reject", returnHandler.RejectReturn)
+		orders.POST("/returns/:id/cancel", returnHandler.CancelReturn)
+		orders.POST("/returns/:id/receive", returnHandler.ReceiveReturn)
+		orders.POST("/returns/:id/retry", returnHandler.RetryReturn)
+
+		orders.POST("/promotions", promotionHandler.CreatePromotion)
+		orders.GET("/promotions", promotionHandler.GetPromotions)
+		orders.POST("/promotions/evaluate", promotionHandler.EvaluatePromotions)
+		orders.GET("/promotions/:id", promotionHandler.GetPromotion)
+		orders.PUT("/promotions/:id", promotionHandler.UpdatePromotion)
+		orders.DELETE("/promotions/:id", promotionHandler.DeletePromotion)
+		orders.GET("/promotions/:id/redemptions", promotionHandler.GetRedemptions)
 	}
 	
-}
\ No newline at end of file
+}
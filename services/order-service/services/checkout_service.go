@@ -0,0 +1,15 @@
+package services
+
+import (
+	"context"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/client"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+)
+
+type CheckoutService interface {
+	// Checkout runs the checkout saga for the caller's cart. When a step fails the
+	// completed steps are compensated and the failed checkout is returned with the error.
+	Checkout(ctx context.Context, identity client.Identity, req *models.CheckoutRequest) (*models.Checkout, error)
+	GetCheckout(checkoutID uint) (*models.Checkout, error)
+}
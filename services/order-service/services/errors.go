@@ -0,0 +1,45 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidFulfilmentStatus is returned for a fulfilment status that is not part of the rollup
+var ErrInvalidFulfilmentStatus = errors.New("invalid fulfilment status")
+
+// InvalidTransitionError is returned when a status change is not allowed by the order lifecycle
+type InvalidTransitionError struct {
+	OrderID uint
+	Field   string
+	From    string
+	To      string
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("order %d: cannot change %s from %q to %q", e.OrderID, e.Field, e.From, e.To)
+}
+
+// ErrInvalidReturn is returned when a return request does not fit its order
+var ErrInvalidReturn = errors.New("invalid return")
+
+// ErrReturnForbidden is returned when the caller does not own the order or return
+var ErrReturnForbidden = errors.New("return belongs to another user")
+
+// ReturnTransitionError is returned when a return status change is not allowed by the return lifecycle
+type ReturnTransitionError struct {
+	ReturnID uint
+	From     string
+	To       string
+}
+
+func (e *ReturnTransitionError) Error() string {
+	return fmt.Sprintf("return %d: cannot change status from %q to %q", e.ReturnID, e.From, e.To)
+}
+
+// ErrInvalidPromotion is returned when a promotion's rules do not fit its type
+var ErrInvalidPromotion = errors.New("invalid promotion")
+
+// ErrPromotionUnavailable is returned when a promotion reached its usage limits
+// before the order using it was placed
+var ErrPromotionUnavailable = errors.New("promotion is no longer available")
@@ -0,0 +1,475 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/logger"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/client"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/repository"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/services"
+)
+
+// compensationTimeout bounds the clean-up work after a failed step so that it
+// still runs when the caller's request context has already been cancelled.
+const compensationTimeout = 30 * time.Second
+
+type CheckoutServiceImpl struct {
+	checkoutRepo     repository.CheckoutRepository
+	orderService     services.OrderService
+	cartClient       *client.CartClient
+	productClient    *client.ProductClient
+	inventoryClient  *client.InventoryClient
+	paymentClient    *client.PaymentClient
+	promotionService services.PromotionService
+}
+
+func NewCheckoutService(
+	checkoutRepo repository.CheckoutRepository,
+	orderService services.OrderService,
+	cartClient *client.CartClient,
+	productClient *client.ProductClient,
+	inventoryClient *client.InventoryClient,
+	paymentClient *client.PaymentClient,
+	promotionService services.PromotionService,
+) services.CheckoutService {
+	return &CheckoutServiceImpl{
+		checkoutRepo:     checkoutRepo,
+		orderService:     orderService,
+		cartClient:       cartClient,
+		productClient:    productClient,
+		inventoryClient:  inventoryClient,
+		paymentClient:    paymentClient,
+		promotionService: promotionService,
+	}
+}
+
+// pricedLine is a cart line priced against product-service
+type pricedLine struct {
+	ProductID  uint
+	VariantID  uint
+	CategoryID uint64
+	Quantity   int
+	UnitPrice  money.Money
+}
+
+// checkoutRun holds the in-flight state shared by the saga steps
+type checkoutRun struct {
+	checkout   *models.Checkout
+	identity   client.Identity
+	postalCode string
+	codes      []string
+	cart       []client.CartItem
+	lines      []pricedLine
+	promotions *models.PromotionEvaluation
+	reserved   []client.ReservationRequest
+	order      *models.Order
+	payment    *client.Payment
+}
+
+// checkoutStep is one step of the saga. compensate undoes a completed step and
+// is nil for steps without side effects. Steps marked bestEffort are recorded
+// as failed without rolling the checkout back.
+type checkoutStep struct {
+	name       string
+	run        func(ctx context.Context, r *checkoutRun) (string, error)
+	compensate func(ctx context.Context, r *checkoutRun) error
+	bestEffort bool
+}
+
+func (s *CheckoutServiceImpl) steps() []checkoutStep {
+	return []checkoutStep{
+		{name: models.CheckoutStepLoadCart, run: s.loadCart},
+		{name: models.CheckoutStepPriceItems, run: s.priceItems},
+		{name: models.CheckoutStepApplyPromos, run: s.applyPromotions},
+		{name: models.CheckoutStepReserveStock, run: s.reserveStock, compensate: s.releaseStock},
+		{name: models.CheckoutStepCreateOrder, run: s.createOrder, compensate: s.cancelOrder},
+		{name: models.CheckoutStepCreatePayment, run: s.createPayment, compensate: s.cancelPayment},
+		{name: models.CheckoutStepConfirmStock, run: s.confirmStock},
+		{name: models.CheckoutStepClearCart, run: s.clearCart, bestEffort: true},
+	}
+}
+
+func (s *CheckoutServiceImpl) Checkout(ctx context.Context, identity client.Identity, req *models.CheckoutRequest) (*models.Checkout, error) {
+	steps := s.steps()
+
+	checkout := &models.Checkout{
+		UserID:        identity.UserID,
+		PaymentMethod: req.PaymentMethod,
+		Status:        models.CheckoutStatusRunning,
+	}
+	for i, step := range steps {
+		checkout.Steps = append(checkout.Steps, models.CheckoutStep{
+			Position: i + 1,
+			Name:     step.name,
+			Status:   models.StepStatusPending,
+		})
+	}
+	if err := s.checkoutRepo.CreateCheckout(checkout); err != nil {
+		return nil, fmt.Errorf("failed to start checkout: %w", err)
+	}
+
+	r := &checkoutRun{checkout: checkout, identity: identity, postalCode: req.PostalCode, codes: req.CouponCodes}
+
+	for i, step := range steps {
+		record := &checkout.Steps[i]
+		s.markStep(record, models.StepStatusRunning, "")
+
+		detail, err := step.run(ctx, r)
+		if err == nil {
+			s.markStep(record, models.StepStatusCompleted, detail)
+			continue
+		}
+
+		s.markStep(record, models.StepStatusFailed, err.Error())
+		if step.bestEffort {
+			logger.Logger.Errorf("checkout %d: %s failed: %v", checkout.ID, step.name, err)
+			continue
+		}
+
+		checkout.FailedStep = step.name
+		checkout.Error = err.Error()
+		s.compensate(ctx, r, steps[:i])
+		return checkout, fmt.Errorf("checkout failed at %s: %w", step.name, err)
+	}
+
+	checkout.Status = models.CheckoutStatusCompleted
+	s.saveCheckout(checkout)
+	return checkout, nil
+}
+
+func (s *CheckoutServiceImpl) GetCheckout(checkoutID uint) (*models.Checkout, error) {
+	return s.checkoutRepo.GetCheckoutByID(checkoutID)
+}
+
+// compensate undoes the completed steps in reverse order
+func (s *CheckoutServiceImpl) compensate(ctx context.Context, r *checkoutRun, completed []checkoutStep) {
+	checkout := r.checkout
+	checkout.Status = models.CheckoutStatusCompensating
+	s.saveCheckout(checkout)
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), compensationTimeout)
+	defer cancel()
+
+	for i := len(completed) - 1; i >= 0; i-- {
+		step := completed[i]
+		if step.compensate == nil {
+			continue
+		}
+		record := &checkout.Steps[i]
+		if err := step.compensate(ctx, r); err != nil {
+			logger.Logger.Errorf("checkout %d: compensating %s failed: %v", checkout.ID, step.name, err)
+			s.markStep(record, models.StepStatusCompensationFailed, err.Error())
+			continue
+		}
+		s.markStep(record, models.StepStatusCompensated, record.Detail)
+	}
+
+	checkout.Status = models.CheckoutStatusFailed
+	s.saveCheckout(checkout)
+}
+
+func (s *CheckoutServiceImpl) markStep(step *models.CheckoutStep, status, detail string) {
+	now := time.Now()
+	switch status {
+	case models.StepStatusRunning:
+		step.StartedAt = &now
+	case models.StepStatusCompleted, models.StepStatusFailed:
+		step.CompletedAt = &now
+	}
+	step.Status = status
+	step.Detail = detail
+	if err := s.checkoutRepo.UpdateStep(step); err != nil {
+		logger.Logger.Errorf("checkout %d: failed to record step %s: %v", step.CheckoutID, step.Name, err)
+	}
+}
+
+func (s *CheckoutServiceImpl) saveCheckout(checkout *models.Checkout) {
+	if err := s.checkoutRepo.UpdateCheckout(checkout); err != nil {
+		logger.Logger.Errorf("checkout %d: failed to save progress: %v", checkout.ID, err)
+	}
+}
+
+func (s *CheckoutServiceImpl) loadCart(ctx context.Context, r *checkoutRun) (string, error) {
+	items, err := s.cartClient.GetCart(ctx, r.identity, r.identity.UserID)
+	if err != nil {
+		return "", err
+	}
+	if len(items) == 0 {
+		return "", errors.New("cart is empty")
+	}
+	r.cart = items
+	return fmt.Sprintf("%d cart item(s)", len(items)), nil
+}
+
+func (s *CheckoutServiceImpl) priceItems(ctx context.Context, r *checkoutRun) (string, error) {
+	products := make(map[uint64]*client.Product)
+	var total money.Money
+
+	for _, item := range r.cart {
+		if item.Quantity <= 0 {
+			return "", fmt.Errorf("cart item %d has an invalid quantity", item.ID)
+		}
+
+		product, ok := products[item.ProductID]
+		if !ok {
+			fetched, err := s.productClient.GetProduct(ctx, r.identity, item.ProductID)
+			if err != nil {
+				return "", fmt.Errorf("product %d: %w", item.ProductID, err)
+			}
+			product = fetched
+			products[item.ProductID] = product
+		}
+		if product.Status != "active" {
+			return "", fmt.Errorf("product %d is not available", item.ProductID)
+		}
+
+		price := product.Price
+		var variantID uint64
+		if item.VariantID != nil && *item.VariantID != 0 {
+			variant, found := product.FindVariant(*item.VariantID)
+			if !found {
+				return "", fmt.Errorf("variant %d not found for product %d", *item.VariantID, item.ProductID)
+			}
+			price = variant.Price
+			variantID = variant.ID
+		}
+
+		unitPrice, err := price.Sub(price.Percent(product.Discount))
+		if err != nil {
+			return "", err
+		}
+		r.lines = append(r.lines, pricedLine{
+			ProductID:  uint(item.ProductID),
+			VariantID:  uint(variantID),
+			CategoryID: product.CategoryID,
+			Quantity:   item.Quantity,
+			UnitPrice:  unitPrice,
+		})
+
+		if len(r.lines) == 1 {
+			total = money.Zero(unitPrice.Currency)
+		}
+		if total, err = total.Add(unitPrice.Mul(int64(item.Quantity))); err != nil {
+			return "", fmt.Errorf("product %d: %w", item.ProductID, err)
+		}
+	}
+
+	r.checkout.TotalAmount = total
+	s.saveCheckout(r.checkout)
+	return fmt.Sprintf("total %s", total.Format()), nil
+}
+
+// applyPromotions discounts the order with the automatic promotions and the
+// entered codes, and adds GST and the shipping fee as the cart summary does. An entered code that does not
+// apply fails the checkout rather than charging more than the shopper expects.
+func (s *CheckoutServiceImpl) applyPromotions(ctx context.Context, r *checkoutRun) (string, error) {
+	req := &models.PromotionEvaluationRequest{Codes: r.codes}
+	for _, line := range r.lines {
+		req.Lines = append(req.Lines, models.PromotionLine{
+			ProductID:  uint64(line.ProductID),
+			VariantID:  uint64(line.VariantID),
+			CategoryID: line.CategoryID,
+			Quantity:   line.Quantity,
+			UnitPrice:  line.UnitPrice,
+		})
+	}
+	evaluation, err := s.promotionService.Evaluate(r.identity, req)
+	if err != nil {
+		return "", err
+	}
+	if len(evaluation.Rejected) > 0 {
+		rejected := evaluation.Rejected[0]
+		return "", fmt.Errorf("%w: %s %s", services.ErrPromotionUnavailable, rejected.Code, rejected.Reason)
+	}
+
+	r.promotions = evaluation
+	r.checkout.TotalAmount = evaluation.Total
+	s.saveCheckout(r.checkout)
+	return fmt.Sprintf("%d promotion(s) applied, total %s", len(evaluation.Applied), evaluation.Total.Format()), nil
+}
+
+// reserveStock ships the order from as few warehouses as possible, preferring
+// those nearest to the shipping address, and holds each line in its warehouse
+func (s *CheckoutServiceImpl) reserveStock(ctx context.Context, r *checkoutRun) (string, error) {
+	allocationReq := client.AllocationRequest{Strategy: "fewest_splits", PostalCode: r.postalCode}
+	for _, line := range r.lines {
+		allocationReq.Lines = append(allocationReq.Lines, client.AllocationLine{
+			ProductID: line.ProductID,
+			VariantID: line.VariantID,
+			Quantity:  line.Quantity,
+		})
+	}
+	allocation, err := s.inventoryClient.Allocate(ctx, r.identity, allocationReq)
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range allocation.Lines {
+		req := client.ReservationRequest{
+			ProductID:   line.ProductID,
+			VariantID:   line.VariantID,
+			Quantity:    line.Quantity,
+			ReferenceID: r.checkout.ID,
+			WarehouseID: line.WarehouseID,
+		}
+		if err := s.inventoryClient.Reserve(ctx, r.identity, req); err != nil {
+			// Undo the partial reservation here since a failed step is not compensated
+			releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), compensationTimeout)
+			if releaseErr := s.releaseStock(releaseCtx, r); releaseErr != nil {
+				logger.Logger.Errorf("checkout %d: releasing partial reservation failed: %v", r.checkout.ID, releaseErr)
+			}
+			cancel()
+			return "", fmt.Errorf("product %d: %w", line.ProductID, err)
+		}
+		r.reserved = append(r.reserved, req)
+	}
+	return fmt.Sprintf("%d line(s) reserved in %d warehouse(s)", len(r.reserved), allocation.Shipments), nil
+}
+
+func (s *CheckoutServiceImpl) releaseStock(ctx context.Context, r *checkoutRun) error {
+	var failed []string
+	remaining := r.reserved[:0]
+	for _, req := range r.reserved {
+		if err := s.inventoryClient.Release(ctx, r.identity, req); err != nil {
+			failed = append(failed, fmt.Sprintf("product %d: %v", req.ProductID, err))
+			remaining = append(remaining, req)
+		}
+	}
+	r.reserved = remaining
+	if len(failed) > 0 {
+		return errors.New(strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// confirmStock turns the checkout's stock holds into deductions before they expire
+func (s *CheckoutServiceImpl) confirmStock(ctx context.Context, r *checkoutRun) (string, error) {
+	if err := s.inventoryClient.Confirm(ctx, r.identity, r.checkout.ID); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d line(s) confirmed", len(r.reserved)), nil
+}
+
+func (s *CheckoutServiceImpl) createOrder(ctx context.Context, r *checkoutRun) (string, error) {
+	order := &models.Order{
+		UserID:         r.identity.UserID,
+		TotalAmount:    r.checkout.TotalAmount,
+		DiscountAmount: money.Zero(r.checkout.TotalAmount.Currency),
+		TaxAmount:      r.promotions.Tax,
+		ShippingAmount: r.promotions.ShippingFee,
+		Status:         utils.OrderStatusPending,
+		PaymentStatus:  utils.PaymentStatusPending,
+	}
+	for _, applied := range r.promotions.Applied {
+		order.Discounts = append(order.Discounts, models.OrderDiscount{
+			PromotionID: applied.PromotionID,
+			Code:        applied.Code,
+			Name:        applied.Name,
+			Type:        applied.Type,
+			Amount:      applied.Amount,
+		})
+		var err error
+		if order.DiscountAmount, err = order.DiscountAmount.Add(applied.Amount); err != nil {
+			return "", err
+		}
+	}
+	for i, line := range r.lines {
+		order.OrderItems = append(order.OrderItems, models.OrderItem{
+			ProductID: line.ProductID,
+			VariantID: line.VariantID,
+			Quantity:  line.Quantity,
+			Price:     line.UnitPrice,
+			Discount:  r.promotions.LineDiscounts[i],
+		})
+	}
+	if err := s.orderService.CreateOrder(order); err != nil {
+		return "", err
+	}
+
+	r.order = order
+	r.checkout.OrderID = &order.ID
+	s.saveCheckout(r.checkout)
+	return fmt.Sprintf("order %d created", order.ID), nil
+}
+
+func (s *CheckoutServiceImpl) cancelOrder(ctx context.Context, r *checkoutRun) error {
+	if r.order == nil {
+		return nil
+	}
+	return s.orderService.UpdateOrderStatus(r.order.ID, models.OrderStatusChange{
+		Status:        utils.OrderStatusCancelled,
+		ChangedBy:     r.identity.UserID,
+		ChangedByRole: "system",
+		Reason:        fmt.Sprintf("checkout %d failed at %s", r.checkout.ID, r.checkout.FailedStep),
+	})
+}
+
+func (s *CheckoutServiceImpl) createPayment(ctx context.Context, r *checkoutRun) (string, error) {
+	payment, err := s.paymentClient.CreatePayment(ctx, r.identity, client.PaymentRequest{
+		OrderID:       r.order.ID,
+		UserID:        r.identity.UserID,
+		Amount:        r.order.TotalAmount,
+		PaymentMethod: r.checkout.PaymentMethod,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	r.payment = payment
+
+	paymentID := strconv.FormatUint(uint64(payment.ID), 10)
+	if err := s.orderService.UpdatePaymentStatus(r.order.ID, payment.PaymentStatus, &paymentID); err != nil {
+		// Give the money back here since a failed step is not compensated
+		cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), compensationTimeout)
+		if cancelErr := s.cancelPayment(cancelCtx, r); cancelErr != nil {
+			logger.Logger.Errorf("checkout %d: cancelling payment %d failed: %v", r.checkout.ID, payment.ID, cancelErr)
+		}
+		cancel()
+		return "", fmt.Errorf("payment %d created but order was not updated: %w", payment.ID, err)
+	}
+
+	r.checkout.PaymentID = &payment.ID
+	s.saveCheckout(r.checkout)
+	return fmt.Sprintf("payment %d (%s) created", payment.ID, payment.TransactionID), nil
+}
+
+// cancelPayment gives the customer their money back when a later step fails.
+// A payment that is only authorized, or cash on delivery, is voided; a
+// captured one is refunded in full.
+func (s *CheckoutServiceImpl) cancelPayment(ctx context.Context, r *checkoutRun) error {
+	if r.payment == nil {
+		return nil
+	}
+
+	switch r.payment.PaymentStatus {
+	case utils.PaymentStatusSuccess:
+		key := fmt.Sprintf("checkout-%d-payment-%d", r.checkout.ID, r.payment.ID)
+		_, err := s.paymentClient.CreateRefund(ctx, r.identity, key, client.RefundRequest{
+			PaymentID: r.payment.ID,
+			OrderID:   r.payment.OrderID,
+			Amount:    r.payment.Amount,
+			Reason:    fmt.Sprintf("checkout %d failed", r.checkout.ID),
+		})
+		return err
+	case utils.PaymentStatusFailed:
+		return nil
+	default:
+		_, err := s.paymentClient.VoidPayment(ctx, r.identity, r.payment.ID)
+		return err
+	}
+}
+
+func (s *CheckoutServiceImpl) clearCart(ctx context.Context, r *checkoutRun) (string, error) {
+	if err := s.cartClient.ClearCart(ctx, r.identity, r.identity.UserID); err != nil {
+		return "", err
+	}
+	return "cart cleared", nil
+}
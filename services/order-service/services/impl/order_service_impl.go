@@ -1,6 +1,15 @@
 package impl
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/events"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/logger"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
 	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
 	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/repository"
 	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/services"
@@ -15,7 +24,10 @@ func NewOrderService(repo repository.OrderRepository) services.OrderService {
 }
 
 func (s *OrderServiceImpl) CreateOrder(order *models.Order) error {
-	return s.repo.CreateOrder(order)
+	// New orders always enter the lifecycle at the start
+	order.Status = utils.OrderStatusPending
+	order.PaymentStatus = utils.PaymentStatusPending
+	return s.repo.CreateOrder(order, checkPromotionUsage)
 }
 
 func (s *OrderServiceImpl) GetOrderByID(orderID uint) (*models.Order, error) {
@@ -38,10 +50,80 @@ func (s *OrderServiceImpl) GetAllOrders() ([]models.Order, error) {
 	return s.repo.GetAllOrders()
 }
 
-func (s *OrderServiceImpl) UpdateOrderStatus(orderID uint, status string) error {
-	return s.repo.UpdateOrderStatus(orderID, status)
+func (s *OrderServiceImpl) UpdateOrderStatus(orderID uint, change models.OrderStatusChange) error {
+	order, err := s.repo.GetOrderByID(orderID)
+	if err != nil {
+		return err
+	}
+
+	if order.Status == change.Status {
+		return nil
+	}
+	if !models.CanTransitionOrder(order.Status, change.Status) {
+		return &services.InvalidTransitionError{OrderID: orderID, Field: "status", From: order.Status, To: change.Status}
+	}
+
+	return s.repo.UpdateOrderStatus(&models.OrderStatusHistory{
+		OrderID:       orderID,
+		FromStatus:    order.Status,
+		ToStatus:      change.Status,
+		ChangedBy:     change.ChangedBy,
+		ChangedByRole: change.ChangedByRole,
+		Reason:        change.Reason,
+	})
+}
+
+func (s *OrderServiceImpl) GetStatusHistory(orderID uint) ([]models.OrderStatusHistory, error) {
+	if _, err := s.repo.GetOrderByID(orderID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetStatusHistory(orderID)
 }
 
 func (s *OrderServiceImpl) UpdatePaymentStatus(orderID uint, status string, paymentID *string) error {
+	order, err := s.repo.GetOrderByID(orderID)
+	if err != nil {
+		return err
+	}
+
+	if order.PaymentStatus != status && !models.CanTransitionPayment(order.PaymentStatus, status) {
+		return &services.InvalidTransitionError{OrderID: orderID, Field: "payment_status", From: order.PaymentStatus, To: status}
+	}
+
 	return s.repo.UpdatePaymentStatus(orderID, status, paymentID)
 }
+
+func (s *OrderServiceImpl) UpdateFulfilmentStatus(orderID uint, status string) error {
+	if !models.IsValidFulfilmentStatus(status) {
+		return fmt.Errorf("%w: %q", services.ErrInvalidFulfilmentStatus, status)
+	}
+	return s.repo.UpdateFulfilmentStatus(orderID, status)
+}
+
+func (s *OrderServiceImpl) GetProductOrderCounts(days int) ([]models.ProductOrderCount, error) {
+	return s.repo.GetProductOrderCounts(time.Now().AddDate(0, 0, -days))
+}
+
+func (s *OrderServiceImpl) SyncPaymentStatus(ctx context.Context, event events.Event) error {
+	var payload events.PaymentStatusPayload
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	paymentID := strconv.FormatUint(uint64(payload.PaymentID), 10)
+	err := s.UpdatePaymentStatus(payload.OrderID, payload.To, &paymentID)
+
+	// Retrying cannot help an order that no longer exists, or an event that
+	// arrived after a later change, such as a failed attempt reported after
+	// the retry that succeeded
+	var transitionErr *services.InvalidTransitionError
+	switch {
+	case errors.Is(err, repository.ErrOrderNotFound):
+		logger.Logger.Infof("skipping %s %s: order %d not found", event.Type, event.ID, payload.OrderID)
+		return nil
+	case errors.As(err, &transitionErr):
+		logger.Logger.Infof("skipping %s %s: %v", event.Type, event.ID, transitionErr)
+		return nil
+	}
+	return err
+}
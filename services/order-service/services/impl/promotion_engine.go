@@ -0,0 +1,339 @@
+package impl
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/services"
+)
+
+// promotionCart is a cart as seen by the promotion rules
+type promotionCart struct {
+	lines       []models.PromotionLine
+	subtotal    money.Money
+	shippingFee money.Money
+	gstPercent  float64
+	loggedIn    bool
+	role        string
+	firstOrder  bool
+}
+
+// promotionOffer is what an eligible promotion takes off the cart
+type promotionOffer struct {
+	promotion *models.Promotion
+	discount  money.Money
+	shipping  money.Money
+	// lines are the indexes of the cart lines the discount is taken off
+	lines []int
+}
+
+func (o promotionOffer) total() money.Money {
+	total, _ := o.discount.Add(o.shipping)
+	return total
+}
+
+// normalizeCode returns a promotion code in the form it is stored in
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// normalizeCodes normalizes the entered codes and drops blanks and repeats
+func normalizeCodes(codes []string) []string {
+	normalized := make([]string, 0, len(codes))
+	for _, code := range codes {
+		code = normalizeCode(code)
+		if code != "" && !slices.Contains(normalized, code) {
+			normalized = append(normalized, code)
+		}
+	}
+	return normalized
+}
+
+func promotionCode(promotion *models.Promotion) string {
+	if promotion.Code == nil {
+		return ""
+	}
+	return *promotion.Code
+}
+
+// checkPromotionUsage rejects a promotion that has reached its usage limits
+func checkPromotionUsage(promotion *models.Promotion, usage models.PromotionUsage) error {
+	if reason := usageLimitReason(promotion, usage); reason != "" {
+		return fmt.Errorf("%w: %s %s", services.ErrPromotionUnavailable, promotion.Name, reason)
+	}
+	return nil
+}
+
+func usageLimitReason(promotion *models.Promotion, usage models.PromotionUsage) string {
+	switch {
+	case promotion.UsageLimit > 0 && usage.Total >= promotion.UsageLimit:
+		return "has reached its usage limit"
+	case promotion.PerUserLimit > 0 && usage.ByUser >= promotion.PerUserLimit:
+		return "has already been used the maximum number of times"
+	}
+	return ""
+}
+
+// ineligibleReason explains why a promotion does not apply to the cart, or
+// returns "" when it does
+func ineligibleReason(promotion *models.Promotion, usage models.PromotionUsage, cart promotionCart) string {
+	if reason := usageLimitReason(promotion, usage); reason != "" {
+		return reason
+	}
+	if !cart.loggedIn && (promotion.FirstOrderOnly || promotion.PerUserLimit > 0) {
+		return "requires logging in"
+	}
+	if len(promotion.Roles) > 0 && !slices.Contains(promotion.Roles, cart.role) {
+		return "is not available for your account"
+	}
+	if promotion.FirstOrderOnly && !cart.firstOrder {
+		return "is only valid on a first order"
+	}
+	if promotion.MinCartValue.IsPositive() {
+		cmp, err := cart.subtotal.Cmp(promotion.MinCartValue)
+		if err != nil {
+			return "is not available in this currency"
+		}
+		if cmp < 0 {
+			return fmt.Sprintf("requires a cart value of at least %s", promotion.MinCartValue.Format())
+		}
+	}
+	if len(eligibleLines(promotion, cart.lines)) == 0 {
+		return "does not apply to any item in the cart"
+	}
+	return ""
+}
+
+// eligibleLines returns the indexes of the lines in the promotion's
+// categories, or of every line when it has none
+func eligibleLines(promotion *models.Promotion, lines []models.PromotionLine) []int {
+	var eligible []int
+	for i, line := range lines {
+		if len(promotion.CategoryIDs) == 0 || slices.Contains(promotion.CategoryIDs, line.CategoryID) {
+			eligible = append(eligible, i)
+		}
+	}
+	return eligible
+}
+
+// offer works out a promotion's discount on the cart on its own
+func offer(promotion *models.Promotion, cart promotionCart) (promotionOffer, error) {
+	o := promotionOffer{
+		promotion: promotion,
+		discount:  money.Zero(cart.subtotal.Currency),
+		shipping:  money.Zero(cart.subtotal.Currency),
+		lines:     eligibleLines(promotion, cart.lines),
+	}
+
+	eligible := money.Zero(cart.subtotal.Currency)
+	for _, i := range o.lines {
+		line := cart.lines[i]
+		var err error
+		if eligible, err = eligible.Add(line.UnitPrice.Mul(int64(line.Quantity))); err != nil {
+			return o, err
+		}
+	}
+
+	switch promotion.Type {
+	case models.PromotionTypePercentage:
+		o.discount = eligible.Percent(promotion.Percent)
+		if promotion.MaxDiscount.IsPositive() {
+			if cmp, err := o.discount.Cmp(promotion.MaxDiscount); err == nil && cmp > 0 {
+				o.discount = promotion.MaxDiscount
+			}
+		}
+	case models.PromotionTypeFlat:
+		o.discount = promotion.Amount
+	case models.PromotionTypeBOGO:
+		group := promotion.BuyQuantity + promotion.GetQuantity
+		for _, i := range o.lines {
+			line := cart.lines[i]
+			free := line.Quantity / group * promotion.GetQuantity
+			var err error
+			if o.discount, err = o.discount.Add(line.UnitPrice.Mul(int64(free))); err != nil {
+				return o, err
+			}
+		}
+	case models.PromotionTypeFreeShipping:
+		o.shipping = cart.shippingFee
+	}
+
+	// A discount never exceeds what the eligible items cost
+	cmp, err := o.discount.Cmp(eligible)
+	if err != nil {
+		return o, err
+	}
+	if cmp > 0 {
+		o.discount = eligible
+	}
+	return o, nil
+}
+
+// evaluatePromotions applies the promotions to a cart. Stackable promotions
+// combine with each other; a promotion that is not stackable applies alone,
+// and is chosen only when it saves more than all the stackable ones together.
+func evaluatePromotions(promotions []models.Promotion, usage map[uint]models.PromotionUsage, codes []string, cart promotionCart) (*models.PromotionEvaluation, error) {
+	evaluation := &models.PromotionEvaluation{
+		Applied:  []models.AppliedPromotion{},
+		Subtotal: cart.subtotal,
+	}
+	reject := func(promotion *models.Promotion, reason string) {
+		// Automatic promotions that do not apply are left out silently
+		if code := promotionCode(promotion); code != "" {
+			evaluation.Rejected = append(evaluation.Rejected, models.RejectedPromotion{Code: code, Reason: reason})
+		}
+	}
+
+	for _, code := range codes {
+		found := slices.ContainsFunc(promotions, func(p models.Promotion) bool { return promotionCode(&p) == code })
+		if !found {
+			evaluation.Rejected = append(evaluation.Rejected, models.RejectedPromotion{Code: code, Reason: "is not a valid or current code"})
+		}
+	}
+
+	var stackable []promotionOffer
+	var best *promotionOffer
+	for i := range promotions {
+		promotion := &promotions[i]
+		if reason := ineligibleReason(promotion, usage[promotion.ID], cart); reason != "" {
+			reject(promotion, reason)
+			continue
+		}
+		o, err := offer(promotion, cart)
+		if err != nil {
+			reject(promotion, "is not available in this currency")
+			continue
+		}
+		if o.discount.IsZero() && promotion.Type != models.PromotionTypeFreeShipping {
+			reject(promotion, "does not discount any item in the cart")
+			continue
+		}
+
+		if promotion.Stackable {
+			stackable = append(stackable, o)
+			continue
+		}
+		if best == nil {
+			best = &o
+			continue
+		}
+		if cmp, err := o.total().Cmp(best.total()); err == nil && cmp > 0 {
+			reject(best.promotion, fmt.Sprintf("cannot be combined with %s", promotion.Name))
+			best = &o
+		} else {
+			reject(promotion, fmt.Sprintf("cannot be combined with %s", best.promotion.Name))
+		}
+	}
+
+	// Only the first free-shipping promotion among the stackable ones counts
+	stacked := money.Zero(cart.subtotal.Currency)
+	freeShipping := false
+	kept := stackable[:0]
+	for _, o := range stackable {
+		if o.promotion.Type == models.PromotionTypeFreeShipping {
+			if freeShipping {
+				reject(o.promotion, "cannot be used as shipping is already free")
+				continue
+			}
+			freeShipping = true
+		}
+		var err error
+		if stacked, err = stacked.Add(o.total()); err != nil {
+			return nil, err
+		}
+		kept = append(kept, o)
+	}
+	stackable = kept
+
+	applied := stackable
+	if best != nil {
+		cmp, err := best.total().Cmp(stacked)
+		if err != nil {
+			return nil, err
+		}
+		if cmp > 0 || len(stackable) == 0 {
+			for _, o := range stackable {
+				reject(o.promotion, fmt.Sprintf("cannot be combined with %s", best.promotion.Name))
+			}
+			applied = []promotionOffer{*best}
+		} else {
+			reject(best.promotion, "cannot be combined with the other promotions applied")
+		}
+	}
+
+	// Apply the discounts in turn, each limited to what is left to pay for the
+	// lines it applies to and spread over them by that amount
+	lineLeft := make([]money.Money, len(cart.lines))
+	evaluation.LineDiscounts = make([]money.Money, len(cart.lines))
+	for i, line := range cart.lines {
+		lineLeft[i] = line.UnitPrice.Mul(int64(line.Quantity))
+		evaluation.LineDiscounts[i] = money.Zero(cart.subtotal.Currency)
+	}
+	remaining := cart.subtotal
+	shippingLeft := cart.shippingFee
+	evaluation.Discount = money.Zero(cart.subtotal.Currency)
+	evaluation.ShippingFee = cart.shippingFee
+	evaluation.ShippingDiscount = money.Zero(cart.subtotal.Currency)
+	for _, o := range applied {
+		ratios := make([]int64, len(o.lines))
+		eligibleLeft := money.Zero(cart.subtotal.Currency)
+		for j, i := range o.lines {
+			ratios[j] = lineLeft[i].Amount
+			eligibleLeft.Amount += lineLeft[i].Amount
+		}
+		discount := minMoney(o.discount, eligibleLeft)
+		shipping := minMoney(o.shipping, shippingLeft)
+
+		var err error
+		for j, share := range discount.Allocate(ratios...) {
+			i := o.lines[j]
+			if lineLeft[i], err = lineLeft[i].Sub(share); err != nil {
+				return nil, err
+			}
+			if evaluation.LineDiscounts[i], err = evaluation.LineDiscounts[i].Add(share); err != nil {
+				return nil, err
+			}
+		}
+		if remaining, err = remaining.Sub(discount); err != nil {
+			return nil, err
+		}
+		if shippingLeft, err = shippingLeft.Sub(shipping); err != nil {
+			return nil, err
+		}
+		if evaluation.Discount, err = evaluation.Discount.Add(discount); err != nil {
+			return nil, err
+		}
+		if evaluation.ShippingDiscount, err = evaluation.ShippingDiscount.Add(shipping); err != nil {
+			return nil, err
+		}
+		amount, _ := discount.Add(shipping)
+		evaluation.Applied = append(evaluation.Applied, models.AppliedPromotion{
+			PromotionID: o.promotion.ID,
+			Code:        promotionCode(o.promotion),
+			Name:        o.promotion.Name,
+			Type:        o.promotion.Type,
+			Amount:      amount,
+		})
+	}
+
+	// GST is charged on what is paid for the items; shipping is not taxed
+	evaluation.Tax = utils.GST(remaining, cart.gstPercent)
+	total, err := money.Sum(cart.subtotal.Currency, remaining, evaluation.Tax, shippingLeft)
+	if err != nil {
+		return nil, err
+	}
+	evaluation.Total = total
+	return evaluation, nil
+}
+
+// minMoney returns the smaller amount, or a when the currencies differ
+func minMoney(a, b money.Money) money.Money {
+	if cmp, err := a.Cmp(b); err == nil && cmp > 0 {
+		return b
+	}
+	return a
+}
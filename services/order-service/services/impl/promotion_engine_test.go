@@ -0,0 +1,235 @@
+package impl
+
+import (
+	"testing"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+)
+
+func inr(minor int64) money.Money {
+	return money.New(minor, "INR")
+}
+
+// testCart holds two units at ₹500 in category 1 and one at ₹300 in
+// category 2, with ₹50 shipping and 18% GST
+func testCart() promotionCart {
+	return promotionCart{
+		lines: []models.PromotionLine{
+			{ProductID: 1, CategoryID: 1, Quantity: 2, UnitPrice: inr(50000)},
+			{ProductID: 2, CategoryID: 2, Quantity: 1, UnitPrice: inr(30000)},
+		},
+		subtotal:    inr(130000),
+		shippingFee: inr(5000),
+		gstPercent:  18,
+		loggedIn:    true,
+	}
+}
+
+func TestEvaluatePromotions(t *testing.T) {
+	tests := []struct {
+		name       string
+		promotions []models.Promotion
+		// want* are in paise
+		wantLines    []int64
+		wantDiscount int64
+		wantShipping int64
+		wantTax      int64
+		wantTotal    int64
+		wantApplied  []string
+	}{
+		{
+			name:        "no promotions",
+			wantLines:   []int64{0, 0},
+			wantTax:     23400,
+			wantTotal:   158400,
+			wantApplied: []string{},
+		},
+		{
+			name: "percentage on one category",
+			promotions: []models.Promotion{
+				{ID: 1, Name: "shoes", Type: models.PromotionTypePercentage, Percent: 10, CategoryIDs: []uint64{1}},
+			},
+			wantLines:    []int64{10000, 0},
+			wantDiscount: 10000,
+			wantTax:      21600,
+			wantTotal:    146600,
+			wantApplied:  []string{"shoes"},
+		},
+		{
+			name: "flat spread over the lines by value",
+			promotions: []models.Promotion{
+				{ID: 1, Name: "flat", Type: models.PromotionTypeFlat, Amount: inr(13000)},
+			},
+			wantLines:    []int64{10000, 3000},
+			wantDiscount: 13000,
+			wantTax:      21060,
+			wantTotal:    143060,
+			wantApplied:  []string{"flat"},
+		},
+		{
+			name: "flat capped at its lines",
+			promotions: []models.Promotion{
+				{ID: 1, Name: "flat", Type: models.PromotionTypeFlat, Amount: inr(50000), CategoryIDs: []uint64{2}},
+			},
+			wantLines:    []int64{0, 30000},
+			wantDiscount: 30000,
+			wantTax:      18000,
+			wantTotal:    123000,
+			wantApplied:  []string{"flat"},
+		},
+		{
+			name: "stacked discounts capped at what is left of their lines",
+			promotions: []models.Promotion{
+				{ID: 1, Name: "half", Type: models.PromotionTypePercentage, Percent: 50, CategoryIDs: []uint64{2}, Stackable: true},
+				{ID: 2, Name: "flat", Type: models.PromotionTypeFlat, Amount: inr(20000), CategoryIDs: []uint64{2}, Stackable: true},
+			},
+			wantLines:    []int64{0, 30000},
+			wantDiscount: 30000,
+			wantTax:      18000,
+			wantTotal:    123000,
+			wantApplied:  []string{"half", "flat"},
+		},
+		{
+			name: "bogo",
+			promotions: []models.Promotion{
+				{ID: 1, Name: "bogo", Type: models.PromotionTypeBOGO, BuyQuantity: 1, GetQuantity: 1, CategoryIDs: []uint64{1}},
+			},
+			wantLines:    []int64{50000, 0},
+			wantDiscount: 50000,
+			wantTax:      14400,
+			wantTotal:    99400,
+			wantApplied:  []string{"bogo"},
+		},
+		{
+			name: "free shipping is not taxed",
+			promotions: []models.Promotion{
+				{ID: 1, Name: "ship", Type: models.PromotionTypeFreeShipping},
+			},
+			wantLines:    []int64{0, 0},
+			wantShipping: 5000,
+			wantTax:      23400,
+			wantTotal:    153400,
+			wantApplied:  []string{"ship"},
+		},
+		{
+			name: "a larger exclusive promotion beats the stackable ones",
+			promotions: []models.Promotion{
+				{ID: 1, Name: "exclusive", Type: models.PromotionTypePercentage, Percent: 10},
+				{ID: 2, Name: "small", Type: models.PromotionTypeFlat, Amount: inr(5000), Stackable: true},
+			},
+			wantLines:    []int64{10000, 3000},
+			wantDiscount: 13000,
+			wantTax:      21060,
+			wantTotal:    143060,
+			wantApplied:  []string{"exclusive"},
+		},
+		{
+			name: "stackable promotions beat a smaller exclusive one",
+			promotions: []models.Promotion{
+				{ID: 1, Name: "exclusive", Type: models.PromotionTypeFlat, Amount: inr(1000)},
+				{ID: 2, Name: "small", Type: models.PromotionTypeFlat, Amount: inr(2000), Stackable: true},
+				{ID: 3, Name: "ship", Type: models.PromotionTypeFreeShipping, Stackable: true},
+			},
+			wantLines:    []int64{1539, 461},
+			wantDiscount: 2000,
+			wantShipping: 5000,
+			wantTax:      23040,
+			wantTotal:    151040,
+			wantApplied:  []string{"small", "ship"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluation, err := evaluatePromotions(tt.promotions, nil, nil, testCart())
+			if err != nil {
+				t.Fatalf("evaluatePromotions: %v", err)
+			}
+
+			if len(evaluation.LineDiscounts) != len(tt.wantLines) {
+				t.Fatalf("got %d line discounts, want %d", len(evaluation.LineDiscounts), len(tt.wantLines))
+			}
+			var lineTotal int64
+			for i, discount := range evaluation.LineDiscounts {
+				if discount.Amount != tt.wantLines[i] {
+					t.Errorf("line %d discount = %d, want %d", i, discount.Amount, tt.wantLines[i])
+				}
+				lineTotal += discount.Amount
+			}
+			if lineTotal != evaluation.Discount.Amount {
+				t.Errorf("line discounts add up to %d, discount is %d", lineTotal, evaluation.Discount.Amount)
+			}
+
+			if evaluation.Discount.Amount != tt.wantDiscount {
+				t.Errorf("Discount = %d, want %d", evaluation.Discount.Amount, tt.wantDiscount)
+			}
+			if evaluation.ShippingDiscount.Amount != tt.wantShipping {
+				t.Errorf("ShippingDiscount = %d, want %d", evaluation.ShippingDiscount.Amount, tt.wantShipping)
+			}
+			if evaluation.Tax.Amount != tt.wantTax {
+				t.Errorf("Tax = %d, want %d", evaluation.Tax.Amount, tt.wantTax)
+			}
+			if evaluation.Total.Amount != tt.wantTotal {
+				t.Errorf("Total = %d, want %d", evaluation.Total.Amount, tt.wantTotal)
+			}
+
+			applied := make([]string, len(evaluation.Applied))
+			for i, promotion := range evaluation.Applied {
+				applied[i] = promotion.Name
+			}
+			if len(applied) != len(tt.wantApplied) {
+				t.Fatalf("applied %v, want %v", applied, tt.wantApplied)
+			}
+			for i := range applied {
+				if applied[i] != tt.wantApplied[i] {
+					t.Errorf("applied %v, want %v", applied, tt.wantApplied)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluatePromotionsGSTRounding(t *testing.T) {
+	tests := []struct {
+		name       string
+		unitPrice  int64
+		gstPercent float64
+		wantTax    int64
+	}{
+		{"rounds up", 999, 18, 180},
+		{"rounds down", 1001, 18, 180},
+		{"half rounds up", 250, 18, 45},
+		{"no gst", 999, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cart := promotionCart{
+				lines:       []models.PromotionLine{{ProductID: 1, Quantity: 1, UnitPrice: inr(tt.unitPrice)}},
+				subtotal:    inr(tt.unitPrice),
+				shippingFee: inr(0),
+				gstPercent:  tt.gstPercent,
+			}
+			evaluation, err := evaluatePromotions(nil, nil, nil, cart)
+			if err != nil {
+				t.Fatalf("evaluatePromotions: %v", err)
+			}
+			if evaluation.Tax.Amount != tt.wantTax {
+				t.Errorf("Tax = %d, want %d", evaluation.Tax.Amount, tt.wantTax)
+			}
+			if want := tt.unitPrice + tt.wantTax; evaluation.Total.Amount != want {
+				t.Errorf("Total = %d, want %d", evaluation.Total.Amount, want)
+			}
+		})
+	}
+}
+
+func TestEvaluatePromotionsRejectsUnknownCodes(t *testing.T) {
+	evaluation, err := evaluatePromotions(nil, nil, []string{"NOPE"}, testCart())
+	if err != nil {
+		t.Fatalf("evaluatePromotions: %v", err)
+	}
+	if len(evaluation.Rejected) != 1 || evaluation.Rejected[0].Code != "NOPE" {
+		t.Errorf("Rejected = %+v, want NOPE", evaluation.Rejected)
+	}
+}
@@ -0,0 +1,178 @@
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/client"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/repository"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/services"
+)
+
+type PromotionServiceImpl struct {
+	promotionRepo repository.PromotionRepository
+	orderRepo     repository.OrderRepository
+	shippingFee   money.Money
+	gstPercent    float64
+}
+
+// NewPromotionService creates the promotion service. shippingFee is the
+// shipping charge on every order, waived by free-shipping promotions, and
+// gstPercent the GST charged on the items.
+func NewPromotionService(promotionRepo repository.PromotionRepository, orderRepo repository.OrderRepository, shippingFee money.Money, gstPercent float64) services.PromotionService {
+	return &PromotionServiceImpl{
+		promotionRepo: promotionRepo,
+		orderRepo:     orderRepo,
+		shippingFee:   shippingFee,
+		gstPercent:    gstPercent,
+	}
+}
+
+// applyPromotionRequest validates a promotion request and copies it onto promotion
+func (s *PromotionServiceImpl) applyPromotionRequest(promotion *models.Promotion, req *models.PromotionRequest) error {
+	switch req.Type {
+	case models.PromotionTypePercentage:
+		if req.Percent <= 0 {
+			return fmt.Errorf("%w: a percentage promotion needs a percent above 0", services.ErrInvalidPromotion)
+		}
+	case models.PromotionTypeFlat:
+		if !req.Amount.IsPositive() {
+			return fmt.Errorf("%w: a flat promotion needs an amount above 0", services.ErrInvalidPromotion)
+		}
+	case models.PromotionTypeBOGO:
+		if req.BuyQuantity < 1 || req.GetQuantity < 1 {
+			return fmt.Errorf("%w: a bogo promotion needs buy_quantity and get_quantity of at least 1", services.ErrInvalidPromotion)
+		}
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", services.ErrInvalidPromotion)
+	}
+
+	promotion.Code = nil
+	if code := normalizeCode(req.Code); code != "" {
+		existing, err := s.promotionRepo.GetPromotionByCode(code)
+		if err == nil && existing.ID != promotion.ID {
+			return fmt.Errorf("%w: code %s is already in use", services.ErrInvalidPromotion, code)
+		}
+		if err != nil && !errors.Is(err, repository.ErrPromotionNotFound) {
+			return err
+		}
+		promotion.Code = &code
+	}
+
+	promotion.Name = req.Name
+	promotion.Description = req.Description
+	promotion.Type = req.Type
+	promotion.Percent = req.Percent
+	promotion.Amount = money.New(req.Amount.Amount, req.Amount.Currency)
+	promotion.MaxDiscount = money.New(req.MaxDiscount.Amount, req.MaxDiscount.Currency)
+	promotion.BuyQuantity = req.BuyQuantity
+	promotion.GetQuantity = req.GetQuantity
+	promotion.CategoryIDs = req.CategoryIDs
+	promotion.MinCartValue = money.New(req.MinCartValue.Amount, req.MinCartValue.Currency)
+	promotion.FirstOrderOnly = req.FirstOrderOnly
+	promotion.Roles = req.Roles
+	promotion.UsageLimit = req.UsageLimit
+	promotion.PerUserLimit = req.PerUserLimit
+	promotion.Stackable = req.Stackable
+	promotion.Active = req.Active == nil || *req.Active
+	promotion.StartsAt = req.StartsAt
+	promotion.EndsAt = req.EndsAt
+	return nil
+}
+
+func (s *PromotionServiceImpl) CreatePromotion(req *models.PromotionRequest) (*models.Promotion, error) {
+	promotion := &models.Promotion{}
+	if err := s.applyPromotionRequest(promotion, req); err != nil {
+		return nil, err
+	}
+	if err := s.promotionRepo.CreatePromotion(promotion); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (s *PromotionServiceImpl) GetPromotion(promotionID uint) (*models.Promotion, error) {
+	return s.promotionRepo.GetPromotionByID(promotionID)
+}
+
+func (s *PromotionServiceImpl) GetPromotions() ([]models.Promotion, error) {
+	return s.promotionRepo.GetPromotions()
+}
+
+func (s *PromotionServiceImpl) UpdatePromotion(promotionID uint, req *models.PromotionRequest) (*models.Promotion, error) {
+	promotion, err := s.promotionRepo.GetPromotionByID(promotionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyPromotionRequest(promotion, req); err != nil {
+		return nil, err
+	}
+	if err := s.promotionRepo.UpdatePromotion(promotion); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (s *PromotionServiceImpl) DeletePromotion(promotionID uint) error {
+	return s.promotionRepo.DeletePromotion(promotionID)
+}
+
+func (s *PromotionServiceImpl) GetRedemptions(promotionID uint) ([]models.PromotionRedemption, error) {
+	if _, err := s.promotionRepo.GetPromotionByID(promotionID); err != nil {
+		return nil, err
+	}
+	return s.promotionRepo.GetRedemptions(promotionID)
+}
+
+func (s *PromotionServiceImpl) Evaluate(identity client.Identity, req *models.PromotionEvaluationRequest) (*models.PromotionEvaluation, error) {
+	currency := money.DefaultCurrency
+	if len(req.Lines) > 0 {
+		currency = money.New(0, req.Lines[0].UnitPrice.Currency).Currency
+	}
+	subtotal := money.Zero(currency)
+	for _, line := range req.Lines {
+		var err error
+		if subtotal, err = subtotal.Add(line.UnitPrice.Mul(int64(line.Quantity))); err != nil {
+			return nil, fmt.Errorf("product %d: %w", line.ProductID, err)
+		}
+	}
+
+	shippingFee := s.shippingFee
+	if len(req.Lines) == 0 || !shippingFee.SameCurrency(subtotal) {
+		shippingFee = money.Zero(currency)
+	}
+	cart := promotionCart{
+		lines:       req.Lines,
+		subtotal:    subtotal,
+		shippingFee: shippingFee,
+		gstPercent:  s.gstPercent,
+		loggedIn:    identity.UserID != 0,
+		role:        identity.Role,
+	}
+	if cart.loggedIn {
+		orders, err := s.orderRepo.CountUserOrders(identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		cart.firstOrder = orders == 0
+	}
+
+	codes := normalizeCodes(req.Codes)
+	promotions, err := s.promotionRepo.GetCandidatePromotions(codes, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(promotions))
+	for i, promotion := range promotions {
+		ids[i] = promotion.ID
+	}
+	usage, err := s.promotionRepo.GetUsage(ids, identity.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return evaluatePromotions(promotions, usage, codes, cart)
+}
@@ -0,0 +1,446 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/logger"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/client"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/repository"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/services"
+)
+
+// systemIdentity is used for the calls made on behalf of a return, so that
+// idempotency keys stay in one scope whichever admin triggers a retry
+var systemIdentity = client.Identity{Role: "system"}
+
+type ReturnServiceImpl struct {
+	returnRepo      repository.ReturnRepository
+	orderService    services.OrderService
+	shippingClient  *client.ShippingClient
+	inventoryClient *client.InventoryClient
+	paymentClient   *client.PaymentClient
+}
+
+func NewReturnService(
+	returnRepo repository.ReturnRepository,
+	orderService services.OrderService,
+	shippingClient *client.ShippingClient,
+	inventoryClient *client.InventoryClient,
+	paymentClient *client.PaymentClient,
+) services.ReturnService {
+	return &ReturnServiceImpl{
+		returnRepo:      returnRepo,
+		orderService:    orderService,
+		shippingClient:  shippingClient,
+		inventoryClient: inventoryClient,
+		paymentClient:   paymentClient,
+	}
+}
+
+func isAdmin(identity client.Identity) bool {
+	return identity.Role == utils.RoleAdmin
+}
+
+// hasShipped reports whether any of an order has left the warehouse
+func hasShipped(order *models.Order) bool {
+	switch order.Status {
+	case utils.OrderStatusShipped, utils.OrderStatusDelivered:
+		return true
+	}
+	return order.FulfilmentStatus != "" && order.FulfilmentStatus != utils.FulfilmentStatusUnfulfilled
+}
+
+func (s *ReturnServiceImpl) CreateReturn(ctx context.Context, identity client.Identity, req *models.ReturnCreateRequest) (*models.ReturnRequest, error) {
+	order, err := s.orderService.GetOrderByID(req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != identity.UserID && !isAdmin(identity) {
+		return nil, services.ErrReturnForbidden
+	}
+	if !hasShipped(order) {
+		return nil, fmt.Errorf("%w: order %d has not shipped", services.ErrInvalidReturn, order.ID)
+	}
+
+	orderItems := make(map[uint]models.OrderItem, len(order.OrderItems))
+	for _, item := range order.OrderItems {
+		orderItems[item.ID] = item
+	}
+
+	ret := &models.ReturnRequest{
+		OrderID:       order.ID,
+		UserID:        order.UserID,
+		Status:        models.ReturnStatusRequested,
+		Reason:        req.Reason,
+		PickupAddress: req.PickupAddress,
+		RefundAmount:  money.Zero(order.TotalAmount.Currency),
+	}
+	index := make(map[uint]int, len(req.Items))
+	for _, item := range req.Items {
+		orderItem, ok := orderItems[item.OrderItemID]
+		if !ok {
+			return nil, fmt.Errorf("%w: order item %d is not on order %d", services.ErrInvalidReturn, item.OrderItemID, order.ID)
+		}
+		if i, seen := index[item.OrderItemID]; seen {
+			ret.Items[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.OrderItemID] = len(ret.Items)
+		ret.Items = append(ret.Items, models.ReturnItem{
+			OrderItemID: orderItem.ID,
+			ProductID:   orderItem.ProductID,
+			VariantID:   orderItem.VariantID,
+			Quantity:    item.Quantity,
+			UnitPrice:   orderItem.Price,
+			Reason:      item.Reason,
+		})
+	}
+
+	// Refund what the items were paid for, so the order's discounts are not paid out again
+	netAmounts, err := order.ItemNetAmounts()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range ret.Items {
+		orderItem := orderItems[item.OrderItemID]
+		net := netAmounts[item.OrderItemID]
+		value := money.New(net.Amount*int64(item.Quantity)/int64(orderItem.Quantity), net.Currency)
+		if ret.RefundAmount, err = ret.RefundAmount.Add(value); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.returnRepo.CreateReturn(ret, func(returned map[uint]int) error {
+		for _, item := range ret.Items {
+			left := orderItems[item.OrderItemID].Quantity - returned[item.OrderItemID]
+			if item.Quantity > left {
+				return fmt.Errorf("%w: only %d of order item %d can still be returned", services.ErrInvalidReturn, max(left, 0), item.OrderItemID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *ReturnServiceImpl) GetReturn(identity client.Identity, returnID uint) (*models.ReturnRequest, error) {
+	ret, err := s.returnRepo.GetReturnByID(returnID)
+	if err != nil {
+		return nil, err
+	}
+	if ret.UserID != identity.UserID && !isAdmin(identity) {
+		return nil, services.ErrReturnForbidden
+	}
+	return ret, nil
+}
+
+func (s *ReturnServiceImpl) GetReturns(identity client.Identity, status string) ([]models.ReturnRequest, error) {
+	if status != "" && !models.IsValidReturnStatus(status) {
+		return nil, fmt.Errorf("%w: unknown status %q", services.ErrInvalidReturn, status)
+	}
+	if isAdmin(identity) {
+		return s.returnRepo.GetReturns(status)
+	}
+
+	returns, err := s.returnRepo.GetReturnsByUserID(identity.UserID)
+	if err != nil || status == "" {
+		return returns, err
+	}
+	filtered := returns[:0]
+	for _, ret := range returns {
+		if ret.Status == status {
+			filtered = append(filtered, ret)
+		}
+	}
+	return filtered, nil
+}
+
+func (s *ReturnServiceImpl) GetOrderReturns(identity client.Identity, orderID uint) ([]models.ReturnRequest, error) {
+	order, err := s.orderService.GetOrderByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != identity.UserID && !isAdmin(identity) {
+		return nil, services.ErrReturnForbidden
+	}
+	return s.returnRepo.GetReturnsByOrderID(orderID)
+}
+
+func (s *ReturnServiceImpl) ApproveReturn(ctx context.Context, identity client.Identity, returnID uint, req *models.ReturnApproveRequest) (*models.ReturnRequest, error) {
+	ret, err := s.loadForTransition(returnID, models.ReturnStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.RefundAmount != nil {
+		if !req.RefundAmount.IsPositive() {
+			return nil, fmt.Errorf("%w: refund amount must be positive", services.ErrInvalidReturn)
+		}
+		cmp, err := req.RefundAmount.Cmp(ret.RefundAmount)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", services.ErrInvalidReturn, err)
+		}
+		if cmp > 0 {
+			return nil, fmt.Errorf("%w: refund amount is more than the %s the items cost", services.ErrInvalidReturn, ret.RefundAmount.Format())
+		}
+		ret.RefundAmount = *req.RefundAmount
+	}
+
+	shipmentReq := client.ReturnShipmentRequest{
+		OrderID: ret.OrderID,
+		Destination: client.ShippingAddress{
+			Name:       ret.PickupAddress.Name,
+			Line1:      ret.PickupAddress.Line1,
+			Line2:      ret.PickupAddress.Line2,
+			City:       ret.PickupAddress.City,
+			State:      ret.PickupAddress.State,
+			PostalCode: ret.PickupAddress.PostalCode,
+			Country:    ret.PickupAddress.Country,
+			Phone:      ret.PickupAddress.Phone,
+		},
+	}
+	for _, item := range ret.Items {
+		shipmentReq.Items = append(shipmentReq.Items, client.ShipmentItem{OrderItemID: item.OrderItemID, Quantity: item.Quantity})
+	}
+	shipment, err := s.shippingClient.CreateReturnShipment(ctx, systemIdentity, shipmentReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to book the return shipment: %w", err)
+	}
+
+	now := time.Now()
+	ret.Status = models.ReturnStatusApproved
+	ret.ReviewedBy = &identity.UserID
+	ret.ReviewNote = req.Note
+	ret.ApprovedAt = &now
+	ret.ReturnShipmentID = &shipment.ID
+	ret.TrackingNumber = shipment.TrackingNumber
+	ret.LabelURL = shipment.LabelURL
+	if err := s.returnRepo.UpdateReturn(ret, models.ReturnStatusRequested); err != nil {
+		logger.Logger.Errorf("return %d: return shipment %d booked but the return was not approved: %v", ret.ID, shipment.ID, err)
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *ReturnServiceImpl) RejectReturn(ctx context.Context, identity client.Identity, returnID uint, req *models.ReturnRejectRequest) (*models.ReturnRequest, error) {
+	ret, err := s.loadForTransition(returnID, models.ReturnStatusRejected)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Status = models.ReturnStatusRejected
+	ret.ReviewedBy = &identity.UserID
+	ret.ReviewNote = req.Reason
+	if err := s.returnRepo.UpdateReturn(ret, models.ReturnStatusRequested); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *ReturnServiceImpl) CancelReturn(ctx context.Context, identity client.Identity, returnID uint) (*models.ReturnRequest, error) {
+	ret, err := s.loadForTransition(returnID, models.ReturnStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	if ret.UserID != identity.UserID && !isAdmin(identity) {
+		return nil, services.ErrReturnForbidden
+	}
+
+	ret.Status = models.ReturnStatusCancelled
+	if err := s.returnRepo.UpdateReturn(ret, models.ReturnStatusRequested); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *ReturnServiceImpl) ReceiveReturn(ctx context.Context, identity client.Identity, returnID uint, req *models.ReturnReceiveRequest) (*models.ReturnRequest, error) {
+	ret, err := s.loadForTransition(returnID, models.ReturnStatusReceived)
+	if err != nil {
+		return nil, err
+	}
+
+	inspected := make(map[uint]bool, len(req.Items))
+	for _, item := range req.Items {
+		inspected[item.OrderItemID] = *item.Restockable
+	}
+	for i := range ret.Items {
+		restockable, ok := inspected[ret.Items[i].OrderItemID]
+		if !ok {
+			restockable = true
+		}
+		ret.Items[i].Restockable = &restockable
+		delete(inspected, ret.Items[i].OrderItemID)
+	}
+	for orderItemID := range inspected {
+		return nil, fmt.Errorf("%w: order item %d is not in return %d", services.ErrInvalidReturn, orderItemID, ret.ID)
+	}
+
+	now := time.Now()
+	ret.Status = models.ReturnStatusReceived
+	ret.ReceivedAt = &now
+	ret.WarehouseID = &req.WarehouseID
+	if err := s.returnRepo.UpdateReturn(ret, models.ReturnStatusApproved); err != nil {
+		return nil, err
+	}
+
+	s.settle(ctx, ret)
+	return ret, nil
+}
+
+func (s *ReturnServiceImpl) RetryReturn(ctx context.Context, returnID uint) (*models.ReturnRequest, error) {
+	ret, err := s.returnRepo.GetReturnByID(returnID)
+	if err != nil {
+		return nil, err
+	}
+	if ret.Status != models.ReturnStatusReceived {
+		return nil, &services.ReturnTransitionError{ReturnID: ret.ID, From: ret.Status, To: models.ReturnStatusRefunded}
+	}
+
+	s.settle(ctx, ret)
+	return ret, nil
+}
+
+// loadForTransition loads a return and checks that it may move to status
+func (s *ReturnServiceImpl) loadForTransition(returnID uint, status string) (*models.ReturnRequest, error) {
+	ret, err := s.returnRepo.GetReturnByID(returnID)
+	if err != nil {
+		return nil, err
+	}
+	if !models.CanTransitionReturn(ret.Status, status) {
+		return nil, &services.ReturnTransitionError{ReturnID: ret.ID, From: ret.Status, To: status}
+	}
+	return ret, nil
+}
+
+// settle restocks the received items that have not been restocked yet and
+// raises the refund. Failures are logged and kept on the return so that the
+// return can be retried; the return only becomes refunded once every
+// restockable item is back in inventory and the refund has been raised.
+func (s *ReturnServiceImpl) settle(ctx context.Context, ret *models.ReturnRequest) {
+	restocked := true
+	for i := range ret.Items {
+		item := &ret.Items[i]
+		if item.Restocked || item.Restockable == nil || !*item.Restockable {
+			continue
+		}
+		req := client.TransactionRequest{
+			ProductID:       item.ProductID,
+			VariantID:       item.VariantID,
+			TransactionType: "in",
+			Quantity:        item.Quantity,
+			ReferenceID:     &ret.OrderID,
+			Notes:           fmt.Sprintf("return %d of order %d", ret.ID, ret.OrderID),
+		}
+		if ret.WarehouseID != nil {
+			req.WarehouseID = *ret.WarehouseID
+		}
+		// The key makes a retry after a lost response restock the item once
+		key := fmt.Sprintf("return-%d-item-%d", ret.ID, item.OrderItemID)
+		if err := s.inventoryClient.RecordTransaction(ctx, systemIdentity, key, req); err != nil {
+			logger.Logger.Errorf("return %d: restocking order item %d failed: %v", ret.ID, item.OrderItemID, err)
+			restocked = false
+			continue
+		}
+		item.Restocked = true
+	}
+
+	if ret.RefundID == nil {
+		s.refund(ctx, ret)
+	}
+
+	if restocked && ret.RefundID != nil {
+		now := time.Now()
+		ret.Status = models.ReturnStatusRefunded
+		ret.RefundedAt = &now
+	}
+	if err := s.returnRepo.UpdateReturn(ret, models.ReturnStatusReceived); err != nil {
+		logger.Logger.Errorf("return %d: failed to save progress: %v", ret.ID, err)
+		return
+	}
+	if ret.Status == models.ReturnStatusRefunded {
+		s.markOrderReturned(ret)
+	}
+}
+
+// refund raises the refund for a received return. The idempotency key only
+// changes after payment-service has turned a refund down, so a refund whose
+// response was lost is not raised twice.
+func (s *ReturnServiceImpl) refund(ctx context.Context, ret *models.ReturnRequest) {
+	payment, err := s.paymentClient.GetPaymentByOrderID(ctx, systemIdentity, ret.OrderID)
+	if err != nil {
+		ret.RefundError = fmt.Sprintf("failed to find the order's payment: %v", err)
+		logger.Logger.Errorf("return %d: %s", ret.ID, ret.RefundError)
+		return
+	}
+
+	key := fmt.Sprintf("order-return-%d-%d", ret.ID, ret.RefundAttempts)
+	refund, err := s.paymentClient.CreateRefund(ctx, systemIdentity, key, client.RefundRequest{
+		PaymentID: payment.ID,
+		OrderID:   ret.OrderID,
+		Amount:    ret.RefundAmount,
+		Reason:    fmt.Sprintf("return %d", ret.ID),
+	})
+	if err != nil {
+		var serviceErr *client.ServiceError
+		if errors.As(err, &serviceErr) && serviceErr.StatusCode < http.StatusInternalServerError {
+			ret.RefundAttempts++
+		}
+		ret.RefundError = err.Error()
+		logger.Logger.Errorf("return %d: refund failed: %v", ret.ID, err)
+		return
+	}
+
+	ret.RefundID = &refund.ID
+	ret.RefundError = ""
+}
+
+// markOrderReturned moves the order to returned once every item on it has
+// been returned and refunded
+func (s *ReturnServiceImpl) markOrderReturned(ret *models.ReturnRequest) {
+	order, err := s.orderService.GetOrderByID(ret.OrderID)
+	if err != nil {
+		logger.Logger.Errorf("return %d: failed to load order %d: %v", ret.ID, ret.OrderID, err)
+		return
+	}
+	if !models.CanTransitionOrder(order.Status, utils.OrderStatusReturned) {
+		return
+	}
+
+	returns, err := s.returnRepo.GetReturnsByOrderID(ret.OrderID)
+	if err != nil {
+		logger.Logger.Errorf("return %d: failed to load the returns of order %d: %v", ret.ID, ret.OrderID, err)
+		return
+	}
+	refunded := make(map[uint]int)
+	for _, other := range returns {
+		if other.Status != models.ReturnStatusRefunded {
+			continue
+		}
+		for _, item := range other.Items {
+			refunded[item.OrderItemID] += item.Quantity
+		}
+	}
+	for _, item := range order.OrderItems {
+		if refunded[item.ID] < item.Quantity {
+			return
+		}
+	}
+
+	err = s.orderService.UpdateOrderStatus(order.ID, models.OrderStatusChange{
+		Status:        utils.OrderStatusReturned,
+		ChangedByRole: "system",
+		Reason:        fmt.Sprintf("every item returned, last by return %d", ret.ID),
+	})
+	if err != nil {
+		logger.Logger.Errorf("return %d: failed to mark order %d returned: %v", ret.ID, ret.OrderID, err)
+	}
+}
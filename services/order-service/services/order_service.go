@@ -1,6 +1,11 @@
 package services
 
-import "github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+import (
+	"context"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/events"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+)
 
 type OrderService interface {
 	CreateOrder(order *models.Order) error
@@ -9,6 +14,14 @@ type OrderService interface {
 	UpdateOrder(orderID uint, order *models.Order) error
 	DeleteOrder(orderID uint) error
 	GetAllOrders() ([]models.Order, error)
-	UpdateOrderStatus(orderID uint, status string) error
+	UpdateOrderStatus(orderID uint, change models.OrderStatusChange) error
+	GetStatusHistory(orderID uint) ([]models.OrderStatusHistory, error)
 	UpdatePaymentStatus(orderID uint, status string, paymentID *string) error
+	UpdateFulfilmentStatus(orderID uint, status string) error
+	// GetProductOrderCounts counts the orders that included each product over the last days
+	GetProductOrderCounts(days int) ([]models.ProductOrderCount, error)
+	// SyncPaymentStatus handles the payment.* events of payment-service by
+	// copying the payment's status onto its order. Returning an error makes the
+	// sender deliver the event again.
+	SyncPaymentStatus(ctx context.Context, event events.Event) error
 }
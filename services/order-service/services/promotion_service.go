@@ -0,0 +1,19 @@
+package services
+
+import (
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/client"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+)
+
+type PromotionService interface {
+	CreatePromotion(req *models.PromotionRequest) (*models.Promotion, error)
+	GetPromotion(promotionID uint) (*models.Promotion, error)
+	GetPromotions() ([]models.Promotion, error)
+	UpdatePromotion(promotionID uint, req *models.PromotionRequest) (*models.Promotion, error)
+	DeletePromotion(promotionID uint) error
+	GetRedemptions(promotionID uint) ([]models.PromotionRedemption, error)
+	// Evaluate works out the promotions that apply to the caller's cart: every
+	// eligible automatic promotion and the entered codes, within the stacking
+	// rules. Codes that do not apply are reported with the reason.
+	Evaluate(identity client.Identity, req *models.PromotionEvaluationRequest) (*models.PromotionEvaluation, error)
+}
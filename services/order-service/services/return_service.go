@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/client"
+	"github.com/DurgaPratapRajbhar/e-commerce/services/order-service/models"
+)
+
+type ReturnService interface {
+	// CreateReturn requests the return of items from one of the caller's shipped orders
+	CreateReturn(ctx context.Context, identity client.Identity, req *models.ReturnCreateRequest) (*models.ReturnRequest, error)
+	GetReturn(identity client.Identity, returnID uint) (*models.ReturnRequest, error)
+	// GetReturns lists every return for admins, optionally by status, and the
+	// caller's own returns for everyone else
+	GetReturns(identity client.Identity, status string) ([]models.ReturnRequest, error)
+	GetOrderReturns(identity client.Identity, orderID uint) ([]models.ReturnRequest, error)
+	// ApproveReturn accepts a requested return and books the carrier that collects it
+	ApproveReturn(ctx context.Context, identity client.Identity, returnID uint, req *models.ReturnApproveRequest) (*models.ReturnRequest, error)
+	RejectReturn(ctx context.Context, identity client.Identity, returnID uint, req *models.ReturnRejectRequest) (*models.ReturnRequest, error)
+	CancelReturn(ctx context.Context, identity client.Identity, returnID uint) (*models.ReturnRequest, error)
+	// ReceiveReturn records the arrival of the goods, puts restockable items back
+	// into inventory and refunds the approved amount. A failed restock or refund
+	// is kept on the return for RetryReturn.
+	ReceiveReturn(ctx context.Context, identity client.Identity, returnID uint, req *models.ReturnReceiveRequest) (*models.ReturnRequest, error)
+	// RetryReturn repeats the restocks and refund that failed for a received return
+	RetryReturn(ctx context.Context, returnID uint) (*models.ReturnRequest, error)
+}
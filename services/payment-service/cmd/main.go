@@ -15,11 +15,19 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+
 	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/delivery/http"
 	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/infrastructure/database"
 	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/infrastructure/database/mysql"
+	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/infrastructure/gateway"
 	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/usecase"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/events"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/idempotency"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/middleware"
 
 )
 
@@ -35,15 +43,31 @@ func main() {
 	// Run migrations
 	database.RunMigrations(db)
 
+	globalConfig := config.LoadConfig()
+
+	// Deliver outbox events to the services listed in EVENT_SUBSCRIBERS_PAYMENT;
+	// order-service keeps the order's payment status in step from them
+	broker := events.NewHTTPBroker(globalConfig.Events.Subscribers["payment"], globalConfig.Events.Secret, globalConfig.Services.PaymentService.Timeout)
+	broker.Subscribe(events.AllEvents, events.LogHandler)
+	go events.NewRelay(db, broker).Run(context.Background())
+
 	// Initialize repositories
 	paymentRepo := mysql.NewPaymentRepository(db)
 	refundRepo := mysql.NewRefundRepository(db)
+	paymentEventRepo := mysql.NewPaymentEventRepository(db)
+
+	// Initialize the payment gateway
+	paymentGateway := gateway.NewSimulator()
+	if outcome := os.Getenv("PAYMENT_SIMULATOR_OUTCOME"); outcome != "" {
+		paymentGateway.SetDefaultOutcome(gateway.Outcome(outcome))
+	}
 
 	// Initialize use cases
-	paymentUseCase := usecase.NewPaymentUseCase(paymentRepo, refundRepo)
+	paymentUseCase := usecase.NewPaymentUseCase(paymentRepo, refundRepo, paymentGateway)
+	webhookUseCase := usecase.NewWebhookUseCase(paymentRepo, paymentEventRepo, globalConfig.Webhooks.PaymentSecrets, globalConfig.Webhooks.Tolerance)
 
 	// Initialize HTTP server
-	server := http.NewServer(paymentUseCase)
+	server := http.NewServer(paymentUseCase, webhookUseCase, middleware.IdempotencyMiddleware(idempotency.NewStore(db)))
 
 	// The actual port is configured via environment variables and logged in the server.Start() method
 	log.Fatal(server.Start())
@@ -2,9 +2,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain"
 	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/usecase"
 	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain/entity"
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
@@ -34,6 +36,10 @@ func CreatePayment(useCase *usecase.PaymentUseCase) gin.HandlerFunc {
 
 		payment, err := useCase.CreatePayment(c.Request.Context(), &req)
 		if err != nil {
+			if payment != nil {
+				gatewayError(c, "Failed to create payment", payment, err)
+				return
+			}
 			requestID := utils.GenerateRequestID()
 			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to create payment", err.Error(), requestID)
 			c.JSON(http.StatusInternalServerError, response)
@@ -181,19 +187,19 @@ func GetPaymentsByUserID(useCase *usecase.PaymentUseCase) gin.HandlerFunc {
 	}
 }
 
-// UpdatePayment updates a payment
-// @Summary Update payment
-// @Description Update a payment
+// CapturePayment captures an authorized payment
+// @Summary Capture payment
+// @Description Capture an authorized payment, or record collection of a cash on delivery payment
 // @Tags payments
 // @Accept json
 // @Produce json
 // @Param id path string true "Payment ID"
-// @Param payment body entity.PaymentUpdateRequest true "Payment Update Request"
 // @Success 200 {object} entity.Payment
 // @Failure 400 {object} map[string]string
+// @Failure 402 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /api/v1/payments/{id} [put]
-func UpdatePayment(useCase *usecase.PaymentUseCase) gin.HandlerFunc {
+// @Router /api/v1/payments/{id}/capture [post]
+func CapturePayment(useCase *usecase.PaymentUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
@@ -205,40 +211,30 @@ func UpdatePayment(useCase *usecase.PaymentUseCase) gin.HandlerFunc {
 			return
 		}
 
-		var req entity.PaymentUpdateRequest
-		if err := c.ShouldBindJSON(&req); err != nil {
-			validationErrors := utils.ParseValidationErrors(err.Error())
-			requestID := utils.GenerateRequestID()
-			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
-			return
-		}
-
-		payment, err := useCase.UpdatePayment(c.Request.Context(), uint(id), &req)
+		payment, err := useCase.CapturePayment(c.Request.Context(), uint(id))
 		if err != nil {
-			requestID := utils.GenerateRequestID()
-			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to update payment", err.Error(), requestID)
-			c.JSON(http.StatusInternalServerError, response)
+			gatewayError(c, "Failed to capture payment", payment, err)
 			return
 		}
 
 		requestID := utils.GenerateRequestID()
-		c.JSON(http.StatusOK, utils.SuccessResponse(payment, "Payment updated successfully", requestID))
+		c.JSON(http.StatusOK, utils.SuccessResponse(payment, "Payment captured successfully", requestID))
 	}
 }
 
-// UpdatePaymentStatus updates a payment status
-// @Summary Update payment status
-// @Description Update a payment status
+// VoidPayment voids an authorized payment
+// @Summary Void payment
+// @Description Release an authorization that has not been captured
 // @Tags payments
 // @Accept json
 // @Produce json
 // @Param id path string true "Payment ID"
-// @Param status body map[string]string true "Status"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} entity.Payment
 // @Failure 400 {object} map[string]string
+// @Failure 402 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /api/v1/payments/{id}/status [patch]
-func UpdatePaymentStatus(useCase *usecase.PaymentUseCase) gin.HandlerFunc {
+// @Router /api/v1/payments/{id}/void [post]
+func VoidPayment(useCase *usecase.PaymentUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
@@ -250,34 +246,29 @@ func UpdatePaymentStatus(useCase *usecase.PaymentUseCase) gin.HandlerFunc {
 			return
 		}
 
-		var req struct {
-			Status string `json:"status" validate:"required,oneof=pending success failed refunded"`
-		}
-		if err := c.ShouldBindJSON(&req); err != nil {
-			validationErrors := utils.ParseValidationErrors(err.Error())
-			requestID := utils.GenerateRequestID()
-			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
-			return
-		}
-
-		err = useCase.UpdatePaymentStatus(c.Request.Context(), uint(id), req.Status)
+		payment, err := useCase.VoidPayment(c.Request.Context(), uint(id))
 		if err != nil {
-			requestID := utils.GenerateRequestID()
-			// Check if this is a validation error
-			if err.Error() == "invalid payment status" {
-				validationErrors := []utils.ValidationError{
-					{Field: "status", Message: "invalid payment status"},
-				}
-				c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
-			} else {
-				response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to update payment status", err.Error(), requestID)
-				c.JSON(http.StatusInternalServerError, response)
-			}
+			gatewayError(c, "Failed to void payment", payment, err)
 			return
 		}
 
 		requestID := utils.GenerateRequestID()
-		c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Payment status updated successfully", requestID))
+		c.JSON(http.StatusOK, utils.SuccessResponse(payment, "Payment voided successfully", requestID))
+	}
+}
+
+// gatewayError writes the response for a failed gateway-backed operation. Declines
+// and timeouts are reported as 402 with the payment as it now stands.
+func gatewayError(c *gin.Context, message string, payment *entity.Payment, err error) {
+	requestID := utils.GenerateRequestID()
+	switch {
+	case errors.Is(err, domain.ErrPaymentDeclined), errors.Is(err, domain.ErrGatewayTimeout):
+		details := map[string]interface{}{"reason": err.Error(), "payment": payment}
+		c.JSON(http.StatusPaymentRequired, utils.ErrorResponse(utils.ErrPaymentFailed, message, details, requestID))
+	case payment == nil:
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, message, err.Error(), requestID))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, message, err.Error(), requestID))
 	}
 }
 
@@ -2,9 +2,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain"
 	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/usecase"
 	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain/entity"
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
@@ -20,6 +22,8 @@ import (
 // @Param refund body entity.RefundCreateRequest true "Refund Create Request"
 // @Success 200 {object} entity.Refund
 // @Failure 400 {object} map[string]string
+// @Failure 402 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/refunds [post]
 func CreateRefund(useCase *usecase.PaymentUseCase) gin.HandlerFunc {
@@ -35,8 +39,17 @@ func CreateRefund(useCase *usecase.PaymentUseCase) gin.HandlerFunc {
 		refund, err := useCase.CreateRefund(c.Request.Context(), &req)
 		if err != nil {
 			requestID := utils.GenerateRequestID()
+			if errors.Is(err, domain.ErrPaymentDeclined) || errors.Is(err, domain.ErrGatewayTimeout) {
+				details := map[string]interface{}{"reason": err.Error(), "refund": refund}
+				c.JSON(http.StatusPaymentRequired, utils.ErrorResponse(utils.ErrPaymentFailed, "Refund was not processed", details, requestID))
+				return
+			}
+			if errors.Is(err, domain.ErrRefundExceedsBalance) {
+				c.JSON(http.StatusConflict, utils.ErrorResponse(utils.ErrConflict, "Refund exceeds refundable balance", err.Error(), requestID))
+				return
+			}
 			// Check if this is a validation error
-			if err.Error() == "payment not found" || err.Error() == "payment must be successful to create a refund" {
+			if errors.Is(err, domain.ErrPaymentNotFound) || errors.Is(err, domain.ErrInvalidRefund) {
 				validationErrors := []utils.ValidationError{
 					{Field: "payment", Message: err.Error()},
 				}
@@ -62,6 +75,7 @@ func CreateRefund(useCase *usecase.PaymentUseCase) gin.HandlerFunc {
 // @Param id path string true "Refund ID"
 // @Success 200 {object} entity.Refund
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/refunds/{id} [get]
 func GetRefund(useCase *usecase.PaymentUseCase) gin.HandlerFunc {
@@ -79,6 +93,10 @@ func GetRefund(useCase *usecase.PaymentUseCase) gin.HandlerFunc {
 		refund, err := useCase.GetRefund(c.Request.Context(), uint(id))
 		if err != nil {
 			requestID := utils.GenerateRequestID()
+			if errors.Is(err, domain.ErrRefundNotFound) {
+				c.JSON(http.StatusNotFound, utils.ErrorResponse(utils.ErrNotFound, "Refund not found", err.Error(), requestID))
+				return
+			}
 			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to get refund", err.Error(), requestID)
 			c.JSON(http.StatusInternalServerError, response)
 			return
@@ -89,6 +107,89 @@ func GetRefund(useCase *usecase.PaymentUseCase) gin.HandlerFunc {
 	}
 }
 
+// RetryRefund resubmits a pending refund to the gateway
+// @Summary Retry refund
+// @Description Send a pending refund to the gateway again, e.g. after a timeout
+// @Tags refunds
+// @Accept json
+// @Produce json
+// @Param id path string true "Refund ID"
+// @Success 200 {object} entity.Refund
+// @Failure 400 {object} map[string]string
+// @Failure 402 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/refunds/{id}/retry [post]
+func RetryRefund(useCase *usecase.PaymentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			validationErrors := []utils.ValidationError{
+				{Field: "id", Message: "invalid refund ID"},
+			}
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		refund, err := useCase.RetryRefund(c.Request.Context(), uint(id))
+		if err != nil {
+			requestID := utils.GenerateRequestID()
+			switch {
+			case errors.Is(err, domain.ErrPaymentDeclined), errors.Is(err, domain.ErrGatewayTimeout):
+				details := map[string]interface{}{"reason": err.Error(), "refund": refund}
+				c.JSON(http.StatusPaymentRequired, utils.ErrorResponse(utils.ErrPaymentFailed, "Refund was not processed", details, requestID))
+			case errors.Is(err, domain.ErrRefundNotFound):
+				c.JSON(http.StatusNotFound, utils.ErrorResponse(utils.ErrNotFound, "Refund not found", err.Error(), requestID))
+			case errors.Is(err, domain.ErrInvalidRefund):
+				c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, "Failed to retry refund", err.Error(), requestID))
+			default:
+				c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to retry refund", err.Error(), requestID))
+			}
+			return
+		}
+
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusOK, utils.SuccessResponse(refund, "Refund processed successfully", requestID))
+	}
+}
+
+// GetRefundBalance gets the refunded and refundable amounts of a payment
+// @Summary Get refund balance
+// @Description Get how much of a payment has been refunded, is pending refund and can still be refunded
+// @Tags refunds
+// @Accept json
+// @Produce json
+// @Param payment_id path string true "Payment ID"
+// @Success 200 {object} entity.RefundBalance
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/refunds/payment/{payment_id}/balance [get]
+func GetRefundBalance(useCase *usecase.PaymentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		paymentID, err := strconv.ParseUint(c.Param("payment_id"), 10, 32)
+		if err != nil {
+			validationErrors := []utils.ValidationError{
+				{Field: "payment_id", Message: "invalid payment ID"},
+			}
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		balance, err := useCase.GetRefundBalance(c.Request.Context(), uint(paymentID))
+		if err != nil {
+			requestID := utils.GenerateRequestID()
+			response := utils.ErrorResponse(utils.ErrInternalServer, "Failed to get refund balance", err.Error(), requestID)
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusOK, utils.SuccessResponse(balance, "Refund balance retrieved successfully", requestID))
+	}
+}
+
 // GetRefundsByPaymentID gets refunds by payment ID
 // @Summary Get refunds by payment ID
 // @Description Get refunds by payment ID
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/usecase"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookBody bounds the size of a provider event
+const maxWebhookBody = 1 << 20
+
+// PaymentWebhook receives a signed event from a payment provider
+// @Summary Payment provider webhook
+// @Description Verify, store and apply a payment provider event. The body is signed with HMAC-SHA256 over "<timestamp>.<body>".
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider"
+// @Param X-Webhook-Signature header string true "sha256=<hex signature>"
+// @Param X-Webhook-Timestamp header string true "Unix timestamp"
+// @Param event body entity.WebhookEvent true "Webhook Event"
+// @Success 200 {object} entity.PaymentEvent
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/payments/webhooks/{provider} [post]
+func PaymentWebhook(useCase *usecase.WebhookUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBody))
+		if err != nil {
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, "Failed to read webhook body", err.Error(), requestID))
+			return
+		}
+
+		event, duplicate, err := useCase.HandleWebhook(
+			c.Request.Context(),
+			c.Param("provider"),
+			c.GetHeader(utils.WebhookSignatureHeader),
+			c.GetHeader(utils.WebhookTimestampHeader),
+			body,
+		)
+		if err != nil {
+			requestID := utils.GenerateRequestID()
+			switch {
+			case errors.Is(err, domain.ErrUnknownProvider):
+				c.JSON(http.StatusNotFound, utils.ErrorResponse(utils.ErrNotFound, "Unknown webhook provider", err.Error(), requestID))
+			case errors.Is(err, domain.ErrInvalidSignature):
+				c.JSON(http.StatusUnauthorized, utils.ErrorResponse(utils.ErrUnauthorized, "Invalid webhook signature", err.Error(), requestID))
+			case errors.Is(err, domain.ErrInvalidWebhook):
+				c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, "Invalid webhook payload", err.Error(), requestID))
+			case errors.Is(err, domain.ErrUnmatchedWebhook):
+				// Not found yet, so the provider retries and the event is processed again
+				c.JSON(http.StatusNotFound, utils.ErrorResponse(utils.ErrNotFound, "Payment not found for webhook", err.Error(), requestID))
+			default:
+				c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to process webhook", err.Error(), requestID))
+			}
+			return
+		}
+
+		message := "Webhook processed successfully"
+		if duplicate {
+			message = "Webhook already processed"
+		}
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusOK, utils.SuccessResponse(event, message, requestID))
+	}
+}
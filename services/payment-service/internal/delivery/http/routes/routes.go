@@ -9,7 +9,9 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRoutes(router *gin.Engine, paymentUseCase *usecase.PaymentUseCase) {
+// SetupRoutes registers the payment routes. idempotent is applied to the POST
+// endpoints that create payments and refunds.
+func SetupRoutes(router *gin.Engine, paymentUseCase *usecase.PaymentUseCase, webhookUseCase *usecase.WebhookUseCase, idempotent gin.HandlerFunc) {
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
@@ -17,17 +19,20 @@ func SetupRoutes(router *gin.Engine, paymentUseCase *usecase.PaymentUseCase) {
 
 	// Swagger docs
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// Provider webhooks authenticate with their signature instead of service headers
+	router.POST("/payment/webhooks/:provider", handlers.PaymentWebhook(webhookUseCase))
  
 		payments := router.Group("/payment")
 		payments.Use(middleware.ServiceAuthMiddleware())
 		{
-			payments.POST("", handlers.CreatePayment(paymentUseCase))
+			payments.POST("", idempotent, handlers.CreatePayment(paymentUseCase))
 			payments.GET("/:id", handlers.GetPayment(paymentUseCase))
 			payments.GET("/order/:order_id", handlers.GetPaymentByOrderID(paymentUseCase))
 			payments.GET("/transaction/:transaction_id", handlers.GetPaymentByTransactionID(paymentUseCase))
 			payments.GET("/user/:user_id", handlers.GetPaymentsByUserID(paymentUseCase))
-			payments.PUT("/:id", handlers.UpdatePayment(paymentUseCase))
-			payments.PATCH("/:id/status", handlers.UpdatePaymentStatus(paymentUseCase))
+			payments.POST("/:id/capture", handlers.CapturePayment(paymentUseCase))
+			payments.POST("/:id/void", handlers.VoidPayment(paymentUseCase))
 			payments.DELETE("/:id", handlers.DeletePayment(paymentUseCase))
 			payments.GET("", handlers.GetPayments(paymentUseCase))
 			payments.GET("/status/:status", handlers.GetPaymentsByStatus(paymentUseCase))
@@ -35,9 +40,11 @@ func SetupRoutes(router *gin.Engine, paymentUseCase *usecase.PaymentUseCase) {
 
 		refunds := payments.Group("/refunds")
 		{
-			refunds.POST("", handlers.CreateRefund(paymentUseCase))
+			refunds.POST("", idempotent, handlers.CreateRefund(paymentUseCase))
 			refunds.GET("/:id", handlers.GetRefund(paymentUseCase))
+			refunds.POST("/:id/retry", handlers.RetryRefund(paymentUseCase))
 			refunds.GET("/payment/:payment_id", handlers.GetRefundsByPaymentID(paymentUseCase))
+			refunds.GET("/payment/:payment_id/balance", handlers.GetRefundBalance(paymentUseCase))
 			refunds.GET("/order/:order_id", handlers.GetRefundsByOrderID(paymentUseCase))
 			refunds.GET("", handlers.GetRefunds(paymentUseCase))
 			refunds.GET("/status/:status", handlers.GetRefundsByStatus(paymentUseCase))
@@ -18,10 +18,12 @@ import (
 
 type Server struct {
 	paymentUseCase *usecase.PaymentUseCase
+	webhookUseCase *usecase.WebhookUseCase
+	idempotent     gin.HandlerFunc
 	router         *gin.Engine
 }
 
-func NewServer(paymentUseCase *usecase.PaymentUseCase) *Server {
+func NewServer(paymentUseCase *usecase.PaymentUseCase, webhookUseCase *usecase.WebhookUseCase, idempotent gin.HandlerFunc) *Server {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
 	router.Use(gin.Logger())
@@ -29,6 +31,8 @@ func NewServer(paymentUseCase *usecase.PaymentUseCase) *Server {
 
 	s := &Server{
 		paymentUseCase: paymentUseCase,
+		webhookUseCase: webhookUseCase,
+		idempotent:     idempotent,
 		router:         router,
 	}
 
@@ -37,7 +41,7 @@ func NewServer(paymentUseCase *usecase.PaymentUseCase) *Server {
 }
 
 func (s *Server) setupRoutes() {
-	routes.SetupRoutes(s.router, s.paymentUseCase)
+	routes.SetupRoutes(s.router, s.paymentUseCase, s.webhookUseCase, s.idempotent)
 }
 
 func (s *Server) Start() error {
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+// Gateway result statuses
+const (
+	GatewayStatusApproved = "approved"
+	GatewayStatusDeclined = "declined"
+)
+
+// GatewayAuthorizeRequest is sent to a payment gateway to place a hold on funds
+type GatewayAuthorizeRequest struct {
+	PaymentID     uint
+	OrderID       uint
+	UserID        uint
+	Amount        money.Money
+	PaymentMethod string
+}
+
+// GatewayResult is the outcome of a single gateway operation
+type GatewayResult struct {
+	Provider      string      `json:"provider"`
+	Operation     string      `json:"operation"`
+	TransactionID string      `json:"transaction_id"`
+	Status        string      `json:"status"`
+	Code          string      `json:"code,omitempty"`
+	Message       string      `json:"message,omitempty"`
+	Amount        money.Money `json:"amount"`
+	ProcessedAt   time.Time   `json:"processed_at"`
+}
+
+// Approved reports whether the gateway accepted the operation
+func (r *GatewayResult) Approved() bool {
+	return r != nil && r.Status == GatewayStatusApproved
+}
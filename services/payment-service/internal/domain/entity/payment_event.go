@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// PaymentEvent is a webhook received from a payment provider, stored verbatim
+type PaymentEvent struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	Provider      string     `json:"provider" gorm:"size:50;not null;uniqueIndex:idx_payment_events_provider_event"`
+	EventID       string     `json:"event_id" gorm:"size:100;not null;uniqueIndex:idx_payment_events_provider_event"`
+	EventType     string     `json:"event_type" gorm:"size:50;not null"`
+	TransactionID string     `json:"transaction_id" gorm:"size:100;index"`
+	PaymentID     *uint      `json:"payment_id,omitempty" gorm:"index"`
+	Payload       string     `json:"payload" gorm:"type:text;not null"`
+	Status        string     `json:"status" gorm:"size:20;not null;default:'received'" example:"processed"`
+	Error         string     `json:"error,omitempty" gorm:"size:255"`
+	ProcessedAt   *time.Time `json:"processed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// WebhookEvent is the body a provider posts to /payment/webhooks/:provider
+type WebhookEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type" example:"payment.captured"`
+	Data struct {
+		TransactionID string `json:"transaction_id"`
+		Code          string `json:"code,omitempty"`
+		Message       string `json:"message,omitempty"`
+	} `json:"data"`
+}
@@ -2,22 +2,36 @@ package entity
 
 import (
 	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
 )
 
 type Refund struct {
 	ID          uint      `json:"id" gorm:"primaryKey"`
 	PaymentID   uint      `json:"payment_id" gorm:"not null;index" validate:"required"`
 	OrderID     uint      `json:"order_id" gorm:"not null;index" validate:"required"`
-	Amount      float64   `json:"amount" gorm:"not null" validate:"required,gt=0"`
+	Amount      money.Money `json:"amount" gorm:"embedded;embeddedPrefix:amount_" validate:"required,gt=0"`
 	Reason      string    `json:"reason" gorm:"size:200" validate:"max=200"`
 	Status      string    `json:"status" gorm:"size:20;default:'pending'" validate:"oneof=pending processed failed" example:"pending"`
+	GatewayResponse *string `json:"gateway_response,omitempty" gorm:"type:text"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
 	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
 // RefundCreateRequest represents the request to create a refund
 type RefundCreateRequest struct {
 	PaymentID uint      `json:"payment_id" validate:"required"`
 	OrderID   uint      `json:"order_id" validate:"required"`
-	Amount    float64   `json:"amount" validate:"required,gt=0"`
+	Amount    money.Money `json:"amount" validate:"required,gt=0"`
 	Reason    string    `json:"reason" validate:"max=200"`
-}
\ No newline at end of file
+}
+// RefundBalance is how much of a payment has been refunded and how much is left.
+// Pending refunds count against the balance until they fail.
+type RefundBalance struct {
+	PaymentID  uint        `json:"payment_id"`
+	Paid       money.Money `json:"paid"`
+	Refunded   money.Money `json:"refunded"`
+	Pending    money.Money `json:"pending"`
+	Refundable money.Money `json:"refundable"`
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "github.com/DurgaPratapRajbhar/e-commerce/pkg/events"
+
+// paymentEventTypes maps a payment status to the event published when a payment enters it
+var paymentEventTypes = map[string]string{
+	"authorized":         events.PaymentAuthorized,
+	"success":            events.PaymentSucceeded,
+	"failed":             events.PaymentFailed,
+	"voided":             events.PaymentVoided,
+	"partially_refunded": events.PaymentRefunded,
+	"refunded":           events.PaymentRefunded,
+}
+
+// PaymentEventType returns the event type for a payment entering status, or "" if none is published
+func PaymentEventType(status string) string {
+	return paymentEventTypes[status]
+}
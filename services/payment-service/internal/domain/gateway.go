@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain/entity"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+var (
+	// ErrGatewayTimeout is returned when the gateway did not answer in time. The
+	// outcome of the operation at the provider is unknown.
+	ErrGatewayTimeout = errors.New("payment gateway timed out")
+	// ErrPaymentDeclined is returned when the gateway rejected an operation
+	ErrPaymentDeclined = errors.New("payment declined")
+)
+
+// PaymentGateway is a payment provider. A declined operation is reported through
+// GatewayResult.Status; errors are reserved for transport failures and timeouts.
+type PaymentGateway interface {
+	Name() string
+	Authorize(ctx context.Context, req entity.GatewayAuthorizeRequest) (*entity.GatewayResult, error)
+	Capture(ctx context.Context, transactionID string, amount money.Money) (*entity.GatewayResult, error)
+	Void(ctx context.Context, transactionID string) (*entity.GatewayResult, error)
+	Refund(ctx context.Context, transactionID string, amount money.Money) (*entity.GatewayResult, error)
+}
@@ -0,0 +1,6 @@
+package domain
+
+import "errors"
+
+// ErrPaymentNotFound is returned when no payment matches an ID, order or transaction
+var ErrPaymentNotFound = errors.New("payment not found")
@@ -0,0 +1,13 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrRefundExceedsBalance is returned when a refund is larger than what is left to refund
+	ErrRefundExceedsBalance = errors.New("refund amount exceeds refundable balance")
+	// ErrRefundNotFound is returned when no refund has the ID
+	ErrRefundNotFound = errors.New("refund not found")
+	// ErrInvalidRefund is returned when a refund does not fit its payment, such
+	// as a refund of an uncaptured payment or in another currency
+	ErrInvalidRefund = errors.New("invalid refund")
+)
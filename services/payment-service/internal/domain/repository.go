@@ -20,6 +20,11 @@ type PaymentRepository interface {
 
 type RefundRepository interface {
 	Create(ctx context.Context, refund *entity.Refund) error
+	// CreateWithinBalance locks the payment and inserts the refund only if the
+	// payment's pending and processed refunds plus this one stay within its amount
+	CreateWithinBalance(ctx context.Context, refund *entity.Refund) error
+	// SumByPaymentID totals a payment's refunds in the given status, in minor units
+	SumByPaymentID(ctx context.Context, paymentID uint, status string) (int64, error)
 	GetByID(ctx context.Context, id uint) (*entity.Refund, error)
 	GetByPaymentID(ctx context.Context, paymentID uint) ([]entity.Refund, error)
 	GetByOrderID(ctx context.Context, orderID uint) ([]entity.Refund, error)
@@ -27,4 +32,9 @@ type RefundRepository interface {
 	Update(ctx context.Context, refund *entity.Refund) error
 	UpdateStatus(ctx context.Context, id uint, status string) error
 	GetAll(ctx context.Context, limit, offset int) ([]entity.Refund, error)
-}
\ No newline at end of file
+}
+type PaymentEventRepository interface {
+	Create(ctx context.Context, event *entity.PaymentEvent) error
+	GetByProviderEventID(ctx context.Context, provider, eventID string) (*entity.PaymentEvent, error)
+	Update(ctx context.Context, event *entity.PaymentEvent) error
+}
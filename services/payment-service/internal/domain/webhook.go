@@ -0,0 +1,15 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrUnknownProvider is returned for webhooks from a provider without a configured secret
+	ErrUnknownProvider = errors.New("unknown webhook provider")
+	// ErrInvalidSignature is returned when a webhook signature is missing, wrong or stale
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrInvalidWebhook is returned when a signed webhook body cannot be understood
+	ErrInvalidWebhook = errors.New("invalid webhook payload")
+	// ErrUnmatchedWebhook is returned when no payment has the event's transaction ID.
+	// The event is kept as failed so that the provider's retry is processed again.
+	ErrUnmatchedWebhook = errors.New("no payment matches webhook transaction")
+)
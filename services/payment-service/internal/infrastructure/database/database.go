@@ -6,6 +6,9 @@ import (
 
 	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain/entity"
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/events"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/idempotency"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/moneydb"
 	"github.com/joho/godotenv"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -45,9 +48,18 @@ func RunMigrations(db *gorm.DB) {
 	err := db.AutoMigrate(
 		&entity.Payment{},
 		&entity.Refund{},
+		&entity.PaymentEvent{},
+		&idempotency.Key{},
+		&events.OutboxMessage{},
 	)
 	if err != nil {
 		log.Fatal("Failed to run migrations:", err)
 	}
+
+	for _, table := range []string{"payments", "refunds"} {
+		if err := moneydb.MigrateLegacyAmount(db, table, "amount", "amount_"); err != nil {
+			log.Fatalf("Failed to migrate %s.amount: %v", table, err)
+		}
+	}
 	fmt.Println("Database migrations completed successfully")
-}
\ No newline at end of file
+}
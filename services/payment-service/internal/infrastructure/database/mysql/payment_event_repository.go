@@ -0,0 +1,38 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type paymentEventRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentEventRepository(db *gorm.DB) domain.PaymentEventRepository {
+	return &paymentEventRepository{db: db}
+}
+
+func (r *paymentEventRepository) Create(ctx context.Context, event *entity.PaymentEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *paymentEventRepository) GetByProviderEventID(ctx context.Context, provider, eventID string) (*entity.PaymentEvent, error) {
+	var event entity.PaymentEvent
+	err := r.db.WithContext(ctx).Where("provider = ? AND event_id = ?", provider, eventID).First(&event).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("payment event not found")
+		}
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *paymentEventRepository) Update(ctx context.Context, event *entity.PaymentEvent) error {
+	return r.db.WithContext(ctx).Save(event).Error
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain/entity"
 	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/events"
 )
 
 type paymentRepository struct {
@@ -25,7 +27,7 @@ func (r *paymentRepository) GetByID(ctx context.Context, id uint) (*entity.Payme
 	err := r.db.WithContext(ctx).Where("id = ?", id).First(&payment).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("payment not found")
+			return nil, domain.ErrPaymentNotFound
 		}
 		return nil, err
 	}
@@ -34,10 +36,11 @@ func (r *paymentRepository) GetByID(ctx context.Context, id uint) (*entity.Payme
 
 func (r *paymentRepository) GetByOrderID(ctx context.Context, orderID uint) (*entity.Payment, error) {
 	var payment entity.Payment
-	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&payment).Error
+	// An order has a payment per attempt; the latest one is the one that counts
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Last(&payment).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("payment not found")
+			return nil, domain.ErrPaymentNotFound
 		}
 		return nil, err
 	}
@@ -55,7 +58,7 @@ func (r *paymentRepository) GetByTransactionID(ctx context.Context, transactionI
 	err := r.db.WithContext(ctx).Where("transaction_id = ?", transactionID).First(&payment).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("payment not found")
+			return nil, domain.ErrPaymentNotFound
 		}
 		return nil, err
 	}
@@ -68,12 +71,61 @@ func (r *paymentRepository) GetByStatus(ctx context.Context, status string) ([]e
 	return payments, err
 }
 
+// Update saves the payment and, when its status changed, writes the matching
+// event to the outbox in the same transaction
 func (r *paymentRepository) Update(ctx context.Context, payment *entity.Payment) error {
-	return r.db.WithContext(ctx).Save(payment).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previous string
+		err := tx.Model(&entity.Payment{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", payment.ID).Select("payment_status").Scan(&previous).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Save(payment).Error; err != nil {
+			return err
+		}
+		return enqueueStatusEvent(tx, payment, previous)
+	})
 }
 
 func (r *paymentRepository) UpdateStatus(ctx context.Context, id uint, status string) error {
-	return r.db.WithContext(ctx).Model(&entity.Payment{}).Where("id = ?", id).Update("payment_status", status).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var payment entity.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&payment).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrPaymentNotFound
+			}
+			return err
+		}
+		previous := payment.PaymentStatus
+		if err := tx.Model(&payment).Update("payment_status", status).Error; err != nil {
+			return err
+		}
+		payment.PaymentStatus = status
+		return enqueueStatusEvent(tx, &payment, previous)
+	})
+}
+
+func enqueueStatusEvent(tx *gorm.DB, payment *entity.Payment, previous string) error {
+	eventType := domain.PaymentEventType(payment.PaymentStatus)
+	if previous == payment.PaymentStatus || eventType == "" {
+		return nil
+	}
+
+	event, err := events.New(eventType, "payment", payment.ID, events.PaymentStatusPayload{
+		PaymentID:     payment.ID,
+		OrderID:       payment.OrderID,
+		UserID:        payment.UserID,
+		Amount:        payment.Amount,
+		Provider:      payment.Provider,
+		TransactionID: payment.TransactionID,
+		From:          previous,
+		To:            payment.PaymentStatus,
+	})
+	if err != nil {
+		return err
+	}
+	return events.Enqueue(tx, event)
 }
 
 func (r *paymentRepository) Delete(ctx context.Context, id uint) error {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain/entity"
 	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain"
 )
@@ -20,12 +21,47 @@ func (r *refundRepository) Create(ctx context.Context, refund *entity.Refund) er
 	return r.db.WithContext(ctx).Create(refund).Error
 }
 
+func (r *refundRepository) CreateWithinBalance(ctx context.Context, refund *entity.Refund) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var payment entity.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", refund.PaymentID).First(&payment).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrPaymentNotFound
+			}
+			return err
+		}
+
+		var committed int64
+		err := tx.Model(&entity.Refund{}).
+			Where("payment_id = ? AND status IN ?", refund.PaymentID, []string{"pending", "processed"}).
+			Select("COALESCE(SUM(amount_minor), 0)").
+			Scan(&committed).Error
+		if err != nil {
+			return err
+		}
+		if committed+refund.Amount.Amount > payment.Amount.Amount {
+			return domain.ErrRefundExceedsBalance
+		}
+
+		return tx.Create(refund).Error
+	})
+}
+
+func (r *refundRepository) SumByPaymentID(ctx context.Context, paymentID uint, status string) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&entity.Refund{}).
+		Where("payment_id = ? AND status = ?", paymentID, status).
+		Select("COALESCE(SUM(amount_minor), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
 func (r *refundRepository) GetByID(ctx context.Context, id uint) (*entity.Refund, error) {
 	var refund entity.Refund
 	err := r.db.WithContext(ctx).Where("id = ?", id).First(&refund).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("refund not found")
+			return nil, domain.ErrRefundNotFound
 		}
 		return nil, err
 	}
@@ -0,0 +1,242 @@
+// Package gateway provides payment gateway implementations
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain/entity"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+)
+
+// Outcome is what the simulator does with an operation
+type Outcome string
+
+const (
+	OutcomeApprove Outcome = "approve"
+	OutcomeDecline Outcome = "decline"
+	OutcomeTimeout Outcome = "timeout"
+)
+
+// Operation names a gateway operation
+type Operation string
+
+const (
+	OperationAuthorize Operation = "authorize"
+	OperationCapture   Operation = "capture"
+	OperationVoid      Operation = "void"
+	OperationRefund    Operation = "refund"
+)
+
+// SimulatorName is the provider name recorded on payments handled by the simulator
+const SimulatorName = "simulator"
+
+type simulatedTransaction struct {
+	authorized money.Money
+	captured   money.Money
+	refunded   money.Money
+	voided     bool
+}
+
+// Simulator is a deterministic in-process payment gateway. Every operation
+// approves by default; Script queues outcomes per operation so that callers can
+// make the next authorization decline or the next refund time out. Transaction
+// IDs are sequential within a run, after a random prefix chosen at start so that
+// they do not repeat the IDs stored before a restart. Transactions live in
+// memory only, so captures and refunds of payments authorized before a restart
+// are declined.
+type Simulator struct {
+	mu             sync.Mutex
+	run            string
+	sequence       int
+	defaultOutcome Outcome
+	scripts        map[Operation][]Outcome
+	transactions   map[string]*simulatedTransaction
+	now            func() time.Time
+}
+
+// NewSimulator creates a simulator that approves every operation until scripted otherwise
+func NewSimulator() *Simulator {
+	return &Simulator{
+		run:            strings.ToLower(utils.GenerateRandomString(8)),
+		defaultOutcome: OutcomeApprove,
+		scripts:        make(map[Operation][]Outcome),
+		transactions:   make(map[string]*simulatedTransaction),
+		now:            time.Now,
+	}
+}
+
+// SetDefaultOutcome changes the outcome used when no scripted outcome is queued
+func (s *Simulator) SetDefaultOutcome(outcome Outcome) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.defaultOutcome = outcome
+}
+
+// Script queues outcomes for an operation. They are consumed one per call, in order.
+func (s *Simulator) Script(operation Operation, outcomes ...Outcome) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts[operation] = append(s.scripts[operation], outcomes...)
+}
+
+// Reset clears scripted outcomes and known transactions and starts a new
+// sequence of transaction IDs
+func (s *Simulator) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.run = strings.ToLower(utils.GenerateRandomString(8))
+	s.sequence = 0
+	s.defaultOutcome = OutcomeApprove
+	s.scripts = make(map[Operation][]Outcome)
+	s.transactions = make(map[string]*simulatedTransaction)
+}
+
+func (s *Simulator) Name() string {
+	return SimulatorName
+}
+
+func (s *Simulator) Authorize(ctx context.Context, req entity.GatewayAuthorizeRequest) (*entity.GatewayResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.begin(ctx, OperationAuthorize); err != nil {
+		return nil, err
+	}
+
+	s.sequence++
+	transactionID := fmt.Sprintf("sim_%s_%06d", s.run, s.sequence)
+	if s.outcome(OperationAuthorize) == OutcomeDecline {
+		return s.result(OperationAuthorize, transactionID, req.Amount, entity.GatewayStatusDeclined, "card_declined", "The card was declined"), nil
+	}
+
+	s.transactions[transactionID] = &simulatedTransaction{
+		authorized: req.Amount,
+		captured:   money.Zero(req.Amount.Currency),
+		refunded:   money.Zero(req.Amount.Currency),
+	}
+	return s.result(OperationAuthorize, transactionID, req.Amount, entity.GatewayStatusApproved, "", "Authorized"), nil
+}
+
+func (s *Simulator) Capture(ctx context.Context, transactionID string, amount money.Money) (*entity.GatewayResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.begin(ctx, OperationCapture); err != nil {
+		return nil, err
+	}
+
+	txn, ok := s.transactions[transactionID]
+	if !ok {
+		return s.result(OperationCapture, transactionID, amount, entity.GatewayStatusDeclined, "unknown_transaction", "Transaction not found"), nil
+	}
+	if txn.voided || !txn.captured.IsZero() {
+		return s.result(OperationCapture, transactionID, amount, entity.GatewayStatusDeclined, "invalid_state", "Transaction cannot be captured"), nil
+	}
+	if cmp, err := amount.Cmp(txn.authorized); err != nil || cmp > 0 {
+		return s.result(OperationCapture, transactionID, amount, entity.GatewayStatusDeclined, "amount_exceeds_authorized", "Capture exceeds the authorized amount"), nil
+	}
+	if s.outcome(OperationCapture) == OutcomeDecline {
+		return s.result(OperationCapture, transactionID, amount, entity.GatewayStatusDeclined, "capture_declined", "The capture was declined"), nil
+	}
+
+	txn.captured = amount
+	return s.result(OperationCapture, transactionID, amount, entity.GatewayStatusApproved, "", "Captured"), nil
+}
+
+func (s *Simulator) Void(ctx context.Context, transactionID string) (*entity.GatewayResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.begin(ctx, OperationVoid); err != nil {
+		return nil, err
+	}
+
+	txn, ok := s.transactions[transactionID]
+	if !ok {
+		return s.result(OperationVoid, transactionID, money.Zero(""), entity.GatewayStatusDeclined, "unknown_transaction", "Transaction not found"), nil
+	}
+	if txn.voided || !txn.captured.IsZero() {
+		return s.result(OperationVoid, transactionID, txn.authorized, entity.GatewayStatusDeclined, "invalid_state", "Transaction cannot be voided"), nil
+	}
+	if s.outcome(OperationVoid) == OutcomeDecline {
+		return s.result(OperationVoid, transactionID, txn.authorized, entity.GatewayStatusDeclined, "void_declined", "The void was declined"), nil
+	}
+
+	txn.voided = true
+	return s.result(OperationVoid, transactionID, txn.authorized, entity.GatewayStatusApproved, "", "Voided"), nil
+}
+
+func (s *Simulator) Refund(ctx context.Context, transactionID string, amount money.Money) (*entity.GatewayResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.begin(ctx, OperationRefund); err != nil {
+		return nil, err
+	}
+
+	txn, ok := s.transactions[transactionID]
+	if !ok {
+		return s.result(OperationRefund, transactionID, amount, entity.GatewayStatusDeclined, "unknown_transaction", "Transaction not found"), nil
+	}
+	refundable, err := txn.captured.Sub(txn.refunded)
+	if err != nil {
+		return nil, err
+	}
+	if cmp, err := amount.Cmp(refundable); err != nil || cmp > 0 || !amount.IsPositive() {
+		return s.result(OperationRefund, transactionID, amount, entity.GatewayStatusDeclined, "amount_exceeds_captured", "Refund exceeds the captured amount"), nil
+	}
+	if s.outcome(OperationRefund) == OutcomeDecline {
+		return s.result(OperationRefund, transactionID, amount, entity.GatewayStatusDeclined, "refund_declined", "The refund was declined"), nil
+	}
+
+	txn.refunded, _ = txn.refunded.Add(amount)
+	return s.result(OperationRefund, transactionID, amount, entity.GatewayStatusApproved, "", "Refunded"), nil
+}
+
+// begin honours cancellation and scripted timeouts before an operation runs
+func (s *Simulator) begin(ctx context.Context, operation Operation) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrGatewayTimeout, err)
+	}
+	if s.peek(operation) == OutcomeTimeout {
+		s.outcome(operation)
+		return fmt.Errorf("%w: simulated %s timeout", domain.ErrGatewayTimeout, operation)
+	}
+	return nil
+}
+
+// peek returns the next outcome for an operation without consuming it
+func (s *Simulator) peek(operation Operation) Outcome {
+	if queued := s.scripts[operation]; len(queued) > 0 {
+		return queued[0]
+	}
+	return s.defaultOutcome
+}
+
+// outcome consumes the next outcome for an operation
+func (s *Simulator) outcome(operation Operation) Outcome {
+	if queued := s.scripts[operation]; len(queued) > 0 {
+		s.scripts[operation] = queued[1:]
+		return queued[0]
+	}
+	return s.defaultOutcome
+}
+
+func (s *Simulator) result(operation Operation, transactionID string, amount money.Money, status, code, message string) *entity.GatewayResult {
+	return &entity.GatewayResult{
+		Provider:      SimulatorName,
+		Operation:     string(operation),
+		TransactionID: transactionID,
+		Status:        status,
+		Code:          code,
+		Message:       message,
+		Amount:        amount,
+		ProcessedAt:   s.now(),
+	}
+}
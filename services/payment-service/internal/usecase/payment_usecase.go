@@ -2,37 +2,48 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
+
 	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain"
 	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain/entity"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
 )
 
 type PaymentUseCase struct {
 	paymentRepo domain.PaymentRepository
 	refundRepo  domain.RefundRepository
+	gateway     domain.PaymentGateway
 }
 
 func NewPaymentUseCase(
 	paymentRepo domain.PaymentRepository,
 	refundRepo domain.RefundRepository,
+	gateway domain.PaymentGateway,
 ) *PaymentUseCase {
 	return &PaymentUseCase{
 		paymentRepo: paymentRepo,
 		refundRepo:  refundRepo,
+		gateway:     gateway,
 	}
 }
 
+// CreatePayment authorizes the amount with the gateway and, unless the request
+// asks for authorization only, captures it straight away. Cash on delivery
+// payments skip the gateway and stay pending until they are captured.
 func (uc *PaymentUseCase) CreatePayment(ctx context.Context, req *entity.PaymentCreateRequest) (*entity.Payment, error) {
-	// Check if payment already exists for this order
+	// Check if payment already exists for this order. A failed or declined
+	// attempt does not count, so the customer can try again.
 	existing, err := uc.paymentRepo.GetByOrderID(ctx, req.OrderID)
-	if err == nil && existing != nil {
+	if err == nil && existing != nil && existing.PaymentStatus != "failed" {
 		return nil, errors.New("payment already exists for this order")
 	}
 
-	// Generate a transaction ID if not provided
-	transactionID := fmt.Sprintf("TXN-%d", time.Now().Unix())
+	if !req.Amount.IsPositive() {
+		return nil, errors.New("payment amount must be greater than zero")
+	}
 
 	payment := &entity.Payment{
 		OrderID:       req.OrderID,
@@ -40,7 +51,12 @@ func (uc *PaymentUseCase) CreatePayment(ctx context.Context, req *entity.Payment
 		Amount:        req.Amount,
 		PaymentMethod: req.PaymentMethod,
 		PaymentStatus: "pending",
-		TransactionID: transactionID,
+		Provider:      uc.gateway.Name(),
+		TransactionID: fmt.Sprintf("PENDING-%d-%d", req.OrderID, time.Now().UnixNano()),
+	}
+	if req.PaymentMethod == "cod" {
+		payment.Provider = "cod"
+		payment.TransactionID = fmt.Sprintf("COD-%d", req.OrderID)
 	}
 
 	err = uc.paymentRepo.Create(ctx, payment)
@@ -48,72 +64,163 @@ func (uc *PaymentUseCase) CreatePayment(ctx context.Context, req *entity.Payment
 		return nil, fmt.Errorf("failed to create payment: %w", err)
 	}
 
-	return payment, nil
-}
+	if payment.PaymentMethod == "cod" {
+		return payment, nil
+	}
 
-func (uc *PaymentUseCase) GetPayment(ctx context.Context, id uint) (*entity.Payment, error) {
-	return uc.paymentRepo.GetByID(ctx, id)
-}
+	result, err := uc.gateway.Authorize(ctx, entity.GatewayAuthorizeRequest{
+		PaymentID:     payment.ID,
+		OrderID:       payment.OrderID,
+		UserID:        payment.UserID,
+		Amount:        payment.Amount,
+		PaymentMethod: payment.PaymentMethod,
+	})
+	if err != nil {
+		payment.PaymentStatus = "failed"
+		recordGatewayError(payment, "authorize", err)
+		return payment, uc.savePayment(ctx, payment, fmt.Errorf("authorization failed: %w", err))
+	}
 
-func (uc *PaymentUseCase) GetPaymentByOrderID(ctx context.Context, orderID uint) (*entity.Payment, error) {
-	return uc.paymentRepo.GetByOrderID(ctx, orderID)
-}
+	payment.TransactionID = result.TransactionID
+	recordGatewayResult(payment, result)
+	if !result.Approved() {
+		payment.PaymentStatus = "failed"
+		return payment, uc.savePayment(ctx, payment, fmt.Errorf("%w: %s", domain.ErrPaymentDeclined, result.Message))
+	}
 
-func (uc *PaymentUseCase) GetPaymentByTransactionID(ctx context.Context, transactionID string) (*entity.Payment, error) {
-	return uc.paymentRepo.GetByTransactionID(ctx, transactionID)
-}
+	payment.PaymentStatus = "authorized"
+	if req.AuthorizeOnly {
+		return payment, uc.savePayment(ctx, payment, nil)
+	}
 
-func (uc *PaymentUseCase) GetPaymentsByUserID(ctx context.Context, userID uint) ([]entity.Payment, error) {
-	return uc.paymentRepo.GetByUserID(ctx, userID)
+	if err := uc.capture(ctx, payment); err != nil {
+		// Release the hold so the customer's funds are not left blocked
+		if voidResult, voidErr := uc.gateway.Void(ctx, payment.TransactionID); voidErr == nil && voidResult.Approved() {
+			recordGatewayResult(payment, voidResult)
+		}
+		payment.PaymentStatus = "failed"
+		return payment, uc.savePayment(ctx, payment, err)
+	}
+	return payment, uc.savePayment(ctx, payment, nil)
 }
 
-func (uc *PaymentUseCase) GetPaymentsByStatus(ctx context.Context, status string) ([]entity.Payment, error) {
-	return uc.paymentRepo.GetByStatus(ctx, status)
+// CapturePayment captures an authorized payment, or records the collection of a
+// cash on delivery payment
+func (uc *PaymentUseCase) CapturePayment(ctx context.Context, id uint) (*entity.Payment, error) {
+	payment, err := uc.paymentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case payment.PaymentMethod == "cod" && payment.PaymentStatus == "pending":
+		payment.PaymentStatus = "success"
+		return payment, uc.savePayment(ctx, payment, nil)
+	case payment.PaymentStatus != "authorized":
+		return nil, fmt.Errorf("payment in status %s cannot be captured", payment.PaymentStatus)
+	}
+
+	if err := uc.capture(ctx, payment); err != nil {
+		return payment, uc.savePayment(ctx, payment, err)
+	}
+	return payment, uc.savePayment(ctx, payment, nil)
 }
 
-func (uc *PaymentUseCase) UpdatePayment(ctx context.Context, id uint, req *entity.PaymentUpdateRequest) (*entity.Payment, error) {
+// VoidPayment releases an authorization that has not been captured
+func (uc *PaymentUseCase) VoidPayment(ctx context.Context, id uint) (*entity.Payment, error) {
 	payment, err := uc.paymentRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Update fields if provided in request
-	if req.PaymentStatus != nil {
-		payment.PaymentStatus = *req.PaymentStatus
+	switch {
+	case payment.PaymentMethod == "cod" && payment.PaymentStatus == "pending":
+		payment.PaymentStatus = "voided"
+		return payment, uc.savePayment(ctx, payment, nil)
+	case payment.PaymentStatus != "authorized":
+		return nil, fmt.Errorf("payment in status %s cannot be voided", payment.PaymentStatus)
 	}
-	if req.TransactionID != nil {
-		payment.TransactionID = *req.TransactionID
+
+	result, err := uc.gateway.Void(ctx, payment.TransactionID)
+	if err != nil {
+		recordGatewayError(payment, "void", err)
+		return payment, uc.savePayment(ctx, payment, fmt.Errorf("void failed: %w", err))
 	}
-	if req.GatewayResponse != nil {
-		payment.GatewayResponse = req.GatewayResponse
+	recordGatewayResult(payment, result)
+	if !result.Approved() {
+		return payment, uc.savePayment(ctx, payment, fmt.Errorf("%w: %s", domain.ErrPaymentDeclined, result.Message))
 	}
 
-	err = uc.paymentRepo.Update(ctx, payment)
+	payment.PaymentStatus = "voided"
+	return payment, uc.savePayment(ctx, payment, nil)
+}
+
+// capture captures the full amount of an authorized payment and sets its status
+func (uc *PaymentUseCase) capture(ctx context.Context, payment *entity.Payment) error {
+	result, err := uc.gateway.Capture(ctx, payment.TransactionID, payment.Amount)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update payment: %w", err)
+		recordGatewayError(payment, "capture", err)
+		return fmt.Errorf("capture failed: %w", err)
 	}
-
-	return payment, nil
+	recordGatewayResult(payment, result)
+	if !result.Approved() {
+		return fmt.Errorf("%w: %s", domain.ErrPaymentDeclined, result.Message)
+	}
+	payment.PaymentStatus = "success"
+	return nil
 }
 
-func (uc *PaymentUseCase) UpdatePaymentStatus(ctx context.Context, id uint, status string) error {
-	// Validate status
-	validStatuses := map[string]bool{
-		"pending":   true,
-		"success":   true,
-		"failed":    true,
-		"refunded":  true,
+// savePayment persists the payment and returns cause, or the save error if there was no cause
+func (uc *PaymentUseCase) savePayment(ctx context.Context, payment *entity.Payment, cause error) error {
+	if err := uc.paymentRepo.Update(ctx, payment); err != nil {
+		if cause != nil {
+			return fmt.Errorf("%w (also failed to save payment: %v)", cause, err)
+		}
+		return fmt.Errorf("failed to update payment: %w", err)
 	}
-	if !validStatuses[status] {
-		return errors.New("invalid payment status")
+	return cause
+}
+
+// recordGatewayResult stores the gateway's answer on the payment for auditing
+func recordGatewayResult(payment *entity.Payment, result *entity.GatewayResult) {
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return
 	}
+	response := string(encoded)
+	payment.GatewayResponse = &response
+}
 
-	err := uc.paymentRepo.UpdateStatus(ctx, id, status)
+func recordGatewayError(payment *entity.Payment, operation string, cause error) {
+	encoded, err := json.Marshal(map[string]string{
+		"operation": operation,
+		"error":     cause.Error(),
+	})
 	if err != nil {
-		return fmt.Errorf("failed to update payment status: %w", err)
+		return
 	}
+	response := string(encoded)
+	payment.GatewayResponse = &response
+}
 
-	return nil
+func (uc *PaymentUseCase) GetPayment(ctx context.Context, id uint) (*entity.Payment, error) {
+	return uc.paymentRepo.GetByID(ctx, id)
+}
+
+func (uc *PaymentUseCase) GetPaymentByOrderID(ctx context.Context, orderID uint) (*entity.Payment, error) {
+	return uc.paymentRepo.GetByOrderID(ctx, orderID)
+}
+
+func (uc *PaymentUseCase) GetPaymentByTransactionID(ctx context.Context, transactionID string) (*entity.Payment, error) {
+	return uc.paymentRepo.GetByTransactionID(ctx, transactionID)
+}
+
+func (uc *PaymentUseCase) GetPaymentsByUserID(ctx context.Context, userID uint) ([]entity.Payment, error) {
+	return uc.paymentRepo.GetByUserID(ctx, userID)
+}
+
+func (uc *PaymentUseCase) GetPaymentsByStatus(ctx context.Context, status string) ([]entity.Payment, error) {
+	return uc.paymentRepo.GetByStatus(ctx, status)
 }
 
 func (uc *PaymentUseCase) DeletePayment(ctx context.Context, id uint) error {
@@ -124,45 +231,151 @@ func (uc *PaymentUseCase) GetPayments(ctx context.Context, limit, offset int) ([
 	return uc.paymentRepo.GetAll(ctx, limit, offset)
 }
 
+// CreateRefund refunds part or all of a captured payment. Refunds may be
+// repeated until the payment is fully refunded; each one is checked against the
+// refundable balance while the payment row is locked, so concurrent refunds
+// cannot together exceed the amount paid.
 func (uc *PaymentUseCase) CreateRefund(ctx context.Context, req *entity.RefundCreateRequest) (*entity.Refund, error) {
 	// Check if payment exists and is eligible for refund
 	payment, err := uc.paymentRepo.GetByID(ctx, req.PaymentID)
 	if err != nil {
-		return nil, fmt.Errorf("payment not found: %w", err)
+		return nil, err
 	}
 
-	if payment.PaymentStatus != "success" && payment.PaymentStatus != "refunded" {
-		return nil, errors.New("payment must be successful to create a refund")
+	if payment.PaymentStatus != "success" && payment.PaymentStatus != "partially_refunded" {
+		return nil, fmt.Errorf("%w: payment must be successful to create a refund", domain.ErrInvalidRefund)
+	}
+	if req.OrderID != payment.OrderID {
+		return nil, fmt.Errorf("%w: refund order does not match the payment", domain.ErrInvalidRefund)
 	}
 
 	// Check if the refund amount is valid
-	if req.Amount > payment.Amount {
-		return nil, errors.New("refund amount exceeds payment amount")
+	if !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("%w: refund amount must be greater than zero", domain.ErrInvalidRefund)
+	}
+	if !req.Amount.SameCurrency(payment.Amount) {
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidRefund, money.ErrCurrencyMismatch)
 	}
 
 	refund := &entity.Refund{
-		PaymentID: req.PaymentID,
-		OrderID:   req.OrderID,
+		PaymentID: payment.ID,
+		OrderID:   payment.OrderID,
 		Amount:    req.Amount,
 		Reason:    req.Reason,
 		Status:    "pending",
 	}
 
-	err = uc.refundRepo.Create(ctx, refund)
-	if err != nil {
+	if err := uc.refundRepo.CreateWithinBalance(ctx, refund); err != nil {
+		if errors.Is(err, domain.ErrRefundExceedsBalance) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to create refund: %w", err)
 	}
 
-	// Update payment status to refunded if it was successful
-	if payment.PaymentStatus == "success" {
-		payment.PaymentStatus = "refunded"
-		err = uc.paymentRepo.Update(ctx, payment)
+	return refund, uc.processRefund(ctx, payment, refund)
+}
+
+// RetryRefund sends a pending refund to the gateway again, typically after the
+// previous attempt timed out
+func (uc *PaymentUseCase) RetryRefund(ctx context.Context, id uint) (*entity.Refund, error) {
+	refund, err := uc.refundRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if refund.Status != "pending" {
+		return nil, fmt.Errorf("%w: refund in status %s cannot be retried", domain.ErrInvalidRefund, refund.Status)
+	}
+
+	payment, err := uc.paymentRepo.GetByID(ctx, refund.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	return refund, uc.processRefund(ctx, payment, refund)
+}
+
+// processRefund moves a pending refund to processed or failed through the
+// gateway and then brings the payment status in line with the refunded total.
+// When the gateway times out the refund stays pending and still holds its share
+// of the balance.
+func (uc *PaymentUseCase) processRefund(ctx context.Context, payment *entity.Payment, refund *entity.Refund) error {
+	// Cash on delivery refunds are paid out offline; everything else goes back through the gateway
+	if payment.PaymentMethod != "cod" {
+		result, err := uc.gateway.Refund(ctx, payment.TransactionID, refund.Amount)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update payment status to refunded: %w", err)
+			return fmt.Errorf("refund failed: %w", err)
+		}
+		recordRefundResult(refund, result)
+		if !result.Approved() {
+			refund.Status = "failed"
+			if err := uc.refundRepo.Update(ctx, refund); err != nil {
+				return fmt.Errorf("failed to update refund: %w", err)
+			}
+			return fmt.Errorf("%w: %s", domain.ErrPaymentDeclined, result.Message)
 		}
 	}
 
-	return refund, nil
+	processedAt := time.Now()
+	refund.Status = "processed"
+	refund.ProcessedAt = &processedAt
+	if err := uc.refundRepo.Update(ctx, refund); err != nil {
+		return fmt.Errorf("failed to update refund: %w", err)
+	}
+
+	refunded, err := uc.refundRepo.SumByPaymentID(ctx, payment.ID, "processed")
+	if err != nil {
+		return fmt.Errorf("failed to total refunds: %w", err)
+	}
+	status := "partially_refunded"
+	if refunded >= payment.Amount.Amount {
+		status = "refunded"
+	}
+	if payment.PaymentStatus != status {
+		if err := uc.paymentRepo.UpdateStatus(ctx, payment.ID, status); err != nil {
+			return fmt.Errorf("failed to update payment status to %s: %w", status, err)
+		}
+		payment.PaymentStatus = status
+	}
+	return nil
+}
+
+// GetRefundBalance reports how much of a payment has been refunded and what is left
+func (uc *PaymentUseCase) GetRefundBalance(ctx context.Context, paymentID uint) (*entity.RefundBalance, error) {
+	payment, err := uc.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	refunded, err := uc.refundRepo.SumByPaymentID(ctx, paymentID, "processed")
+	if err != nil {
+		return nil, err
+	}
+	pending, err := uc.refundRepo.SumByPaymentID(ctx, paymentID, "pending")
+	if err != nil {
+		return nil, err
+	}
+
+	currency := payment.Amount.Currency
+	balance := &entity.RefundBalance{
+		PaymentID:  payment.ID,
+		Paid:       payment.Amount,
+		Refunded:   money.New(refunded, currency),
+		Pending:    money.New(pending, currency),
+		Refundable: money.New(payment.Amount.Amount-refunded-pending, currency),
+	}
+	// Only captured money can be refunded
+	if payment.PaymentStatus != "success" && payment.PaymentStatus != "partially_refunded" {
+		balance.Refundable = money.Zero(currency)
+	}
+	return balance, nil
+}
+
+func recordRefundResult(refund *entity.Refund, result *entity.GatewayResult) {
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	response := string(encoded)
+	refund.GatewayResponse = &response
 }
 
 func (uc *PaymentUseCase) GetRefund(ctx context.Context, id uint) (*entity.Refund, error) {
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/payment-service/internal/domain/entity"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+)
+
+// webhookStatuses maps provider event types to the payment status they report
+var webhookStatuses = map[string]string{
+	"payment.authorized": "authorized",
+	"payment.captured":   "success",
+	"payment.succeeded":  "success",
+	"payment.failed":     "failed",
+	"payment.voided":     "voided",
+}
+
+// webhookTransitions lists the statuses a webhook may move a payment to. Events
+// arrive out of order, so anything that would move a payment backwards is ignored.
+// A failed payment may still succeed: a capture that timed out on our side can
+// complete at the provider.
+var webhookTransitions = map[string][]string{
+	"pending":    {"authorized", "success", "failed", "voided"},
+	"authorized": {"success", "failed", "voided"},
+	"failed":     {"success"},
+}
+
+type WebhookUseCase struct {
+	paymentRepo domain.PaymentRepository
+	eventRepo   domain.PaymentEventRepository
+	secrets     map[string]string
+	tolerance   time.Duration
+	now         func() time.Time
+}
+
+// NewWebhookUseCase creates a webhook processor. secrets holds the signing secret
+// per provider; providers without one are rejected.
+func NewWebhookUseCase(
+	paymentRepo domain.PaymentRepository,
+	eventRepo domain.PaymentEventRepository,
+	secrets map[string]string,
+	tolerance time.Duration,
+) *WebhookUseCase {
+	return &WebhookUseCase{
+		paymentRepo: paymentRepo,
+		eventRepo:   eventRepo,
+		secrets:     secrets,
+		tolerance:   tolerance,
+		now:         time.Now,
+	}
+}
+
+// HandleWebhook verifies, stores and applies a provider event. It reports
+// duplicate as true when the event was already handled, in which case nothing
+// is changed.
+func (uc *WebhookUseCase) HandleWebhook(ctx context.Context, provider, signature, timestamp string, body []byte) (*entity.PaymentEvent, bool, error) {
+	provider = strings.ToLower(provider)
+	secret, ok := uc.secrets[provider]
+	if !ok || secret == "" {
+		return nil, false, fmt.Errorf("%w: %s", domain.ErrUnknownProvider, provider)
+	}
+	if err := utils.VerifyWebhookSignature(secret, signature, timestamp, body, uc.tolerance, uc.now()); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", domain.ErrInvalidSignature, err)
+	}
+
+	var webhook entity.WebhookEvent
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", domain.ErrInvalidWebhook, err)
+	}
+	if webhook.ID == "" || webhook.Type == "" {
+		return nil, false, fmt.Errorf("%w: id and type are required", domain.ErrInvalidWebhook)
+	}
+
+	event, duplicate, err := uc.recordEvent(ctx, provider, &webhook, body)
+	if err != nil || duplicate {
+		return event, duplicate, err
+	}
+
+	applyErr := uc.applyEvent(ctx, event, &webhook)
+	if applyErr != nil {
+		event.Status = "failed"
+		event.Error = applyErr.Error()
+	} else {
+		processedAt := uc.now()
+		event.ProcessedAt = &processedAt
+	}
+	if err := uc.eventRepo.Update(ctx, event); err != nil {
+		return event, false, fmt.Errorf("failed to update payment event: %w", err)
+	}
+	return event, false, applyErr
+}
+
+// recordEvent stores the raw event. Events already processed or ignored are
+// duplicates; failed ones are picked up again so provider retries can succeed.
+func (uc *WebhookUseCase) recordEvent(ctx context.Context, provider string, webhook *entity.WebhookEvent, body []byte) (*entity.PaymentEvent, bool, error) {
+	if existing, err := uc.eventRepo.GetByProviderEventID(ctx, provider, webhook.ID); err == nil {
+		if existing.Status != "failed" {
+			return existing, true, nil
+		}
+		existing.Payload = string(body)
+		existing.Status = "received"
+		existing.Error = ""
+		return existing, false, nil
+	}
+
+	event := &entity.PaymentEvent{
+		Provider:      provider,
+		EventID:       webhook.ID,
+		EventType:     webhook.Type,
+		TransactionID: webhook.Data.TransactionID,
+		Payload:       string(body),
+		Status:        "received",
+	}
+	if err := uc.eventRepo.Create(ctx, event); err != nil {
+		// A concurrent delivery of the same event won the unique index
+		if existing, getErr := uc.eventRepo.GetByProviderEventID(ctx, provider, webhook.ID); getErr == nil {
+			return existing, true, nil
+		}
+		return nil, false, fmt.Errorf("failed to store payment event: %w", err)
+	}
+	return event, false, nil
+}
+
+// applyEvent moves the matching payment to the status the event reports and
+// sets event.Status to processed or ignored
+func (uc *WebhookUseCase) applyEvent(ctx context.Context, event *entity.PaymentEvent, webhook *entity.WebhookEvent) error {
+	status, known := webhookStatuses[webhook.Type]
+	if !known {
+		event.Status = "ignored"
+		return nil
+	}
+	if webhook.Data.TransactionID == "" {
+		return fmt.Errorf("%w: transaction_id is required", domain.ErrInvalidWebhook)
+	}
+
+	payment, err := uc.paymentRepo.GetByTransactionID(ctx, webhook.Data.TransactionID)
+	if errors.Is(err, domain.ErrPaymentNotFound) {
+		return fmt.Errorf("%w: %s", domain.ErrUnmatchedWebhook, webhook.Data.TransactionID)
+	}
+	if err != nil {
+		return err
+	}
+	event.PaymentID = &payment.ID
+
+	if payment.Provider != event.Provider {
+		event.Status = "ignored"
+		event.Error = fmt.Sprintf("payment belongs to provider %s", payment.Provider)
+		return nil
+	}
+	if payment.PaymentStatus == status || !canApplyWebhookStatus(payment.PaymentStatus, status) {
+		event.Status = "ignored"
+		return nil
+	}
+
+	payment.PaymentStatus = status
+	payload := event.Payload
+	payment.GatewayResponse = &payload
+	if err := uc.paymentRepo.Update(ctx, payment); err != nil {
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+	event.Status = "processed"
+	return nil
+}
+
+func canApplyWebhookStatus(from, to string) bool {
+	for _, allowed := range webhookTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
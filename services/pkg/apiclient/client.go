@@ -0,0 +1,172 @@
+// Package apiclient calls the other services over HTTP, passing the caller
+// headers the gateway would set and decoding the utils.APIResponse envelope
+// they answer with.
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SystemRole is the role of calls that no end user is behind
+const SystemRole = "system"
+
+// Identity carries the caller headers that downstream services expect from the gateway
+type Identity struct {
+	UserID uint
+	Email  string
+	Role   string
+}
+
+// System is the identity of background work and service to service calls
+var System = Identity{Role: SystemRole}
+
+// response mirrors utils.APIResponse with the data left undecoded
+type response struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Error   *struct {
+		Code    string      `json:"code"`
+		Message string      `json:"message"`
+		Details interface{} `json:"details"`
+	} `json:"error"`
+}
+
+// ServiceError is returned when a downstream service answers with an error response
+type ServiceError struct {
+	Service    string
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *ServiceError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%s returned %d (%s): %s", e.Service, e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("%s returned %d: %s", e.Service, e.StatusCode, e.Message)
+}
+
+// Client calls one downstream service
+type Client struct {
+	service    string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// New creates a client for the service at baseURL, named service in errors
+func New(service, baseURL string, timeout time.Duration) *Client {
+	return &Client{
+		service:    service,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// URL returns the absolute URL of a path on the service
+func (c *Client) URL(path string) string {
+	return c.baseURL + path
+}
+
+// Do sends payload as JSON to path and decodes the data field of the response into out
+func (c *Client) Do(ctx context.Context, method, path string, identity Identity, payload, out interface{}) error {
+	return c.DoWithHeaders(ctx, method, path, identity, nil, payload, out)
+}
+
+// DoWithHeaders is Do with extra request headers, such as an Idempotency-Key
+func (c *Client) DoWithHeaders(ctx context.Context, method, path string, identity Identity, headers map[string]string, payload, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.URL(path), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	return c.send(req, identity, out)
+}
+
+// Upload posts data as the "file" field of a multipart form and decodes the
+// data field of the response into out
+func (c *Client) Upload(ctx context.Context, path string, identity Identity, fileName, contentType string, data []byte, out interface{}) error {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, fileName))
+	header.Set("Content-Type", contentType)
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(data); err != nil {
+		return err
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL(path), &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return c.send(req, identity, out)
+}
+
+func (c *Client) send(req *http.Request, identity Identity, out interface{}) error {
+	req.Header.Set("X-User-ID", strconv.FormatUint(uint64(identity.UserID), 10))
+	req.Header.Set("X-User-Email", identity.Email)
+	req.Header.Set("X-User-Role", identity.Role)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", c.service, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var decoded response
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return fmt.Errorf("%s returned an invalid response: %w", c.service, err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest || !decoded.Success {
+		serviceErr := &ServiceError{Service: c.service, StatusCode: resp.StatusCode, Message: decoded.Message}
+		if decoded.Error != nil {
+			serviceErr.Code = decoded.Error.Code
+			serviceErr.Message = decoded.Error.Message
+			if details, ok := decoded.Error.Details.(string); ok && details != "" {
+				serviceErr.Message = fmt.Sprintf("%s: %s", decoded.Error.Message, details)
+			}
+		}
+		return serviceErr
+	}
+
+	if out == nil || len(decoded.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(decoded.Data, out)
+}
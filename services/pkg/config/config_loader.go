@@ -24,6 +24,10 @@ type Config struct {
 	External     ExternalConfig
 	Cors         CorsConfig
 	Storage      StorageConfig
+	Webhooks     WebhookConfig
+	Tax          TaxConfig
+	Shipping     ShippingConfig
+	Events       EventsConfig
 }
 
 // Server Configuration
@@ -31,6 +35,9 @@ type ServerConfig struct {
 	Port         string
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+	// TrustedProxies are the proxies whose X-Forwarded-For a service believes
+	// when it works out a client's IP; with none it uses the connection's address
+	TrustedProxies []string
 }
 
 // Database Configuration
@@ -111,6 +118,44 @@ type StorageConfig struct {
 	ImagePath string
 }
 
+// Webhook Configuration
+type WebhookConfig struct {
+	// PaymentSecrets holds the signing secret per payment provider, read from
+	// PAYMENT_WEBHOOK_SECRET_<PROVIDER> (e.g. PAYMENT_WEBHOOK_SECRET_SIMULATOR)
+	PaymentSecrets map[string]string
+	// CarrierSecrets holds the signing secret per shipping carrier, read from
+	// CARRIER_WEBHOOK_SECRET_<CARRIER> (e.g. CARRIER_WEBHOOK_SECRET_LOCAL_COURIER)
+	CarrierSecrets map[string]string
+	Tolerance      time.Duration
+	// StockAlertURL receives low-stock alerts from inventory-service; alerts are
+	// only logged when it is empty. StockAlertSecret signs them when set.
+	StockAlertURL    string
+	StockAlertSecret string
+}
+
+// Tax Configuration
+type TaxConfig struct {
+	// GSTPercent is the GST rate applied to cart and order totals
+	GSTPercent float64
+}
+
+// Shipping Configuration
+type ShippingConfig struct {
+	// FlatFee is the shipping charge per order in minor units of the default
+	// currency; free-shipping promotions waive it
+	FlatFee int64
+}
+
+// Events Configuration
+type EventsConfig struct {
+	// Secret signs the domain events services post to each other
+	Secret string
+	// Subscribers lists the event URLs each service posts its events to, keyed
+	// by service and read from EVENT_SUBSCRIBERS_<SERVICE> as comma separated
+	// URLs (e.g. EVENT_SUBSCRIBERS_PAYMENT=http://localhost:8085/order/events)
+	Subscribers map[string][]string
+}
+
 // Load loads the unified configuration
 func Load() (*Config, error) {
 	// Find project root and load .env
@@ -137,9 +182,10 @@ func Load() (*Config, error) {
 
 		// Server Configuration
 		Server: ServerConfig{
-			Port:         getEnv("GATEWAY_PORT", getEnv("SERVER_PORT", "8080")),
-			ReadTimeout:  getDuration("GATEWAY_READ_TIMEOUT", 30),
-			WriteTimeout: getDuration("GATEWAY_WRITE_TIMEOUT", 30),
+			Port:           getEnv("GATEWAY_PORT", getEnv("SERVER_PORT", "8080")),
+			ReadTimeout:    getDuration("GATEWAY_READ_TIMEOUT", 30),
+			WriteTimeout:   getDuration("GATEWAY_WRITE_TIMEOUT", 30),
+			TrustedProxies: getEnvList("TRUSTED_PROXIES"),
 		},
 
 		// Database Configuration
@@ -256,6 +302,31 @@ func Load() (*Config, error) {
 		Storage: StorageConfig{
 			ImagePath: getEnv("IMAGE_PATH", getEnv("IMAGE_GALLERY", "../../image_gallery/")),
 		},
+
+		// Webhook Configuration
+		Webhooks: WebhookConfig{
+			PaymentSecrets:   getEnvPrefixMap("PAYMENT_WEBHOOK_SECRET_"),
+			CarrierSecrets:   getEnvPrefixMap("CARRIER_WEBHOOK_SECRET_"),
+			Tolerance:        getDuration("WEBHOOK_TOLERANCE", 300),
+			StockAlertURL:    getEnv("STOCK_ALERT_WEBHOOK_URL", ""),
+			StockAlertSecret: getEnv("STOCK_ALERT_WEBHOOK_SECRET", ""),
+		},
+
+		// Tax Configuration
+		Tax: TaxConfig{
+			GSTPercent: getEnvFloat("GST_PERCENT", 18),
+		},
+
+		// Shipping Configuration
+		Shipping: ShippingConfig{
+			FlatFee: int64(getEnvInt("SHIPPING_FEE", 0)),
+		},
+
+		// Events Configuration
+		Events: EventsConfig{
+			Secret:      getEnv("EVENT_SECRET", ""),
+			Subscribers: getEnvPrefixListMap("EVENT_SUBSCRIBERS_"),
+		},
 	}
 
 	// Debug: Print loaded config
@@ -303,6 +374,54 @@ func getEnvInt(key string, fallback int) int {
 	return fallback
 }
 
+func getEnvFloat(key string, fallback float64) float64 {
+	if value, exists := os.LookupEnv(key); exists {
+		var result float64
+		if _, err := fmt.Sscanf(value, "%g", &result); err == nil {
+			return result
+		}
+	}
+	return fallback
+}
+
+// getEnvPrefixMap collects every variable starting with prefix, keyed by the
+// lower-cased remainder of its name
+func getEnvPrefixMap(prefix string) map[string]string {
+	values := make(map[string]string)
+	for _, entry := range os.Environ() {
+		key, value, found := strings.Cut(entry, "=")
+		if !found || !strings.HasPrefix(key, prefix) || value == "" {
+			continue
+		}
+		values[strings.ToLower(strings.TrimPrefix(key, prefix))] = value
+	}
+	return values
+}
+
+// getEnvPrefixListMap is getEnvPrefixMap for comma separated lists
+func getEnvPrefixListMap(prefix string) map[string][]string {
+	lists := make(map[string][]string)
+	for key, value := range getEnvPrefixMap(prefix) {
+		lists[key] = splitList(value)
+	}
+	return lists
+}
+
+// getEnvList reads a comma separated list, which is empty when key is not set
+func getEnvList(key string) []string {
+	return splitList(os.Getenv(key))
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func getEnvBool(key string, fallback bool) bool {
 	if value, exists := os.LookupEnv(key); exists {
 		return strings.ToLower(value) == "true" || value == "1"
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// AllEvents subscribes a handler to every event type
+const AllEvents = "*"
+
+// Handler processes one event. Returning an error makes the relay deliver the
+// event again later.
+type Handler func(ctx context.Context, event Event) error
+
+// Broker delivers events to subscribers
+type Broker interface {
+	Publish(ctx context.Context, event Event) error
+	Subscribe(eventType string, handler Handler)
+}
+
+// MemoryBroker delivers events to handlers in the same process. It is meant for
+// local runs and tests; a networked broker can replace it behind Broker.
+type MemoryBroker struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe registers a handler for an event type, or for AllEvents
+func (b *MemoryBroker) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish calls every matching handler in turn. All handlers run even if one
+// fails; the event is reported as failed if any of them did.
+func (b *MemoryBroker) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	handlers := append(append([]Handler{}, b.handlers[event.Type]...), b.handlers[AllEvents]...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("events: %s %s: %w", event.Type, event.ID, errors.Join(errs...))
+	}
+	return nil
+}
+
+// LogHandler logs every event it receives
+func LogHandler(_ context.Context, event Event) error {
+	log.Printf("event %s %s %s/%d: %s", event.ID, event.Type, event.AggregateType, event.AggregateID, event.Payload)
+	return nil
+}
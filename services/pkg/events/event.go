@@ -0,0 +1,72 @@
+// Package events carries domain events between services through a
+// transactional outbox.
+//
+// A service writes events with Enqueue in the same GORM transaction as the
+// state change they describe, so an event exists if and only if the change was
+// committed. A Relay then reads the outbox and hands each event to a Broker,
+// retrying until it is accepted. Delivery is at least once: subscribers must
+// tolerate seeing an event twice.
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Event types published by the services
+const (
+	OrderCreated       = "order.created"
+	OrderStatusChanged = "order.status_changed"
+
+	PaymentAuthorized = "payment.authorized"
+	PaymentSucceeded  = "payment.succeeded"
+	PaymentFailed     = "payment.failed"
+	PaymentVoided     = "payment.voided"
+	PaymentRefunded   = "payment.refunded"
+
+	ShipmentStatusChanged = "shipment.status_changed"
+	ShipmentDelivered     = "shipment.delivered"
+)
+
+// Event is a fact about one aggregate, such as an order or a payment
+type Event struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uint            `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// New creates an event with a random ID and the payload encoded as JSON
+func New(eventType, aggregateType string, aggregateID uint, payload interface{}) (Event, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, fmt.Errorf("events: encode %s payload: %w", eventType, err)
+	}
+
+	return Event{
+		ID:            newEventID(),
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       encoded,
+		OccurredAt:    time.Now().UTC(),
+	}, nil
+}
+
+// Decode unmarshals the payload into out
+func (e Event) Decode(out interface{}) error {
+	return json.Unmarshal(e.Payload, out)
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("evt_%d", time.Now().UnixNano())
+	}
+	return "evt_" + hex.EncodeToString(b)
+}
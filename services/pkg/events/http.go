@@ -0,0 +1,153 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+)
+
+// maxEventSize is the largest event body a Receiver accepts
+const maxEventSize = 1 << 20
+
+// HTTPBroker delivers events to the handlers subscribed in this process, like
+// MemoryBroker, and POSTs them to the Receiver of every subscribing service,
+// signed with the secret the services share. An event counts as delivered once
+// all of them answered 2xx, so while one subscriber is down the relay sends the
+// event again to all of them.
+type HTTPBroker struct {
+	*MemoryBroker
+	urls       []string
+	secret     string
+	httpClient *http.Client
+}
+
+// NewHTTPBroker creates a broker that posts events to urls. Without a secret
+// events cannot be signed, so they only reach handlers in this process.
+func NewHTTPBroker(urls []string, secret string, timeout time.Duration) *HTTPBroker {
+	if secret == "" && len(urls) > 0 {
+		log.Printf("events: EVENT_SECRET is not set, so events are not sent to %v", urls)
+		urls = nil
+	}
+	return &HTTPBroker{
+		MemoryBroker: NewMemoryBroker(),
+		urls:         urls,
+		secret:       secret,
+		httpClient:   &http.Client{Timeout: timeout},
+	}
+}
+
+// Publish calls the local handlers and then posts the event to each subscriber
+func (b *HTTPBroker) Publish(ctx context.Context, event Event) error {
+	var errs []error
+	if err := b.MemoryBroker.Publish(ctx, event); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(b.urls) > 0 {
+		body, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("events: encode %s %s: %w", event.Type, event.ID, err)
+		}
+		for _, url := range b.urls {
+			if err := b.post(ctx, url, body); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", url, err))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("events: %s %s: %w", event.Type, event.ID, errors.Join(errs...))
+	}
+	return nil
+}
+
+func (b *HTTPBroker) post(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(utils.WebhookTimestampHeader, timestamp)
+	req.Header.Set(utils.WebhookSignatureHeader, utils.SignWebhook(b.secret, timestamp, body))
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("subscriber returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Receiver accepts the events another service's HTTPBroker posts and hands
+// them to the handlers subscribed on broker. It answers 500 when a handler
+// fails, so that the sender delivers the event again later.
+type Receiver struct {
+	broker    Broker
+	secret    string
+	tolerance time.Duration
+}
+
+// NewReceiver creates a receiver for events signed with secret within
+// tolerance of now. Without a secret every event is turned away.
+func NewReceiver(broker Broker, secret string, tolerance time.Duration) *Receiver {
+	return &Receiver{broker: broker, secret: secret, tolerance: tolerance}
+}
+
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	requestID := utils.GenerateRequestID()
+	if req.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, utils.ErrorResponse(utils.ErrInvalidInput, "Events must be POSTed", nil, requestID))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxEventSize))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, "Failed to read event", err.Error(), requestID))
+		return
+	}
+
+	if r.secret == "" {
+		writeJSON(w, http.StatusUnauthorized, utils.ErrorResponse(utils.ErrUnauthorized, "Events are not accepted without EVENT_SECRET", nil, requestID))
+		return
+	}
+	signature := req.Header.Get(utils.WebhookSignatureHeader)
+	timestamp := req.Header.Get(utils.WebhookTimestampHeader)
+	if err := utils.VerifyWebhookSignature(r.secret, signature, timestamp, body, r.tolerance, time.Now()); err != nil {
+		writeJSON(w, http.StatusUnauthorized, utils.ErrorResponse(utils.ErrUnauthorized, "Invalid event signature", err.Error(), requestID))
+		return
+	}
+
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil || event.ID == "" || event.Type == "" {
+		writeJSON(w, http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, "Invalid event", nil, requestID))
+		return
+	}
+
+	if err := r.broker.Publish(req.Context(), event); err != nil {
+		log.Printf("events: handling %s %s failed: %v", event.Type, event.ID, err)
+		writeJSON(w, http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to handle event", err.Error(), requestID))
+		return
+	}
+	writeJSON(w, http.StatusOK, utils.SuccessResponse(nil, "Event received", requestID))
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OutboxMessage is an event waiting in a service's outbox table. Every service
+// that publishes events migrates it into its own database.
+type OutboxMessage struct {
+	ID            uint       `gorm:"primaryKey"`
+	EventID       string     `gorm:"size:64;not null;uniqueIndex"`
+	EventType     string     `gorm:"size:100;not null;index"`
+	AggregateType string     `gorm:"size:50;not null"`
+	AggregateID   uint       `gorm:"not null;index"`
+	Payload       string     `gorm:"type:text;not null"`
+	OccurredAt    time.Time  `gorm:"not null"`
+	PublishedAt   *time.Time `gorm:"index"`
+	Attempts      int        `gorm:"not null;default:0"`
+	LastError     string     `gorm:"size:500"`
+	NextAttemptAt time.Time  `gorm:"not null;index"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime"`
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox_events"
+}
+
+// Enqueue writes events to the outbox. Call it with the transaction that makes
+// the state change so that both commit or roll back together.
+func Enqueue(tx *gorm.DB, evts ...Event) error {
+	if len(evts) == 0 {
+		return nil
+	}
+
+	messages := make([]OutboxMessage, 0, len(evts))
+	for _, e := range evts {
+		messages = append(messages, OutboxMessage{
+			EventID:       e.ID,
+			EventType:     e.Type,
+			AggregateType: e.AggregateType,
+			AggregateID:   e.AggregateID,
+			Payload:       string(e.Payload),
+			OccurredAt:    e.OccurredAt,
+			NextAttemptAt: e.OccurredAt,
+		})
+	}
+	return tx.Create(&messages).Error
+}
+
+// event rebuilds the event stored in the message
+func (m OutboxMessage) event() Event {
+	return Event{
+		ID:            m.EventID,
+		Type:          m.EventType,
+		AggregateType: m.AggregateType,
+		AggregateID:   m.AggregateID,
+		Payload:       []byte(m.Payload),
+		OccurredAt:    m.OccurredAt,
+	}
+}
@@ -0,0 +1,48 @@
+package events
+
+import (
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+// OrderCreatedPayload is the payload of order.created
+type OrderCreatedPayload struct {
+	OrderID     uint        `json:"order_id"`
+	UserID      uint        `json:"user_id"`
+	TotalAmount money.Money `json:"total_amount"`
+	ItemCount   int         `json:"item_count"`
+}
+
+// OrderStatusChangedPayload is the payload of order.status_changed
+type OrderStatusChangedPayload struct {
+	OrderID       uint   `json:"order_id"`
+	From          string `json:"from"`
+	To            string `json:"to"`
+	ChangedBy     uint   `json:"changed_by"`
+	ChangedByRole string `json:"changed_by_role"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// PaymentStatusPayload is the payload of the payment.* events
+type PaymentStatusPayload struct {
+	PaymentID     uint        `json:"payment_id"`
+	OrderID       uint        `json:"order_id"`
+	UserID        uint        `json:"user_id"`
+	Amount        money.Money `json:"amount"`
+	Provider      string      `json:"provider"`
+	TransactionID string      `json:"transaction_id"`
+	From          string      `json:"from"`
+	To            string      `json:"to"`
+}
+
+// ShipmentStatusPayload is the payload of shipment.status_changed and shipment.delivered
+type ShipmentStatusPayload struct {
+	ShipmentID     uint       `json:"shipment_id"`
+	OrderID        uint       `json:"order_id"`
+	TrackingNumber string     `json:"tracking_number"`
+	Carrier        string     `json:"carrier"`
+	From           string     `json:"from"`
+	To             string     `json:"to"`
+	ActualDelivery *time.Time `json:"actual_delivery,omitempty"`
+}
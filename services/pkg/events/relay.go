@@ -0,0 +1,129 @@
+package events
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultBatchSize    = 100
+	defaultPollInterval = time.Second
+	maxRetryDelay       = 5 * time.Minute
+	// claimTimeout is how long a relay has to publish the events it claimed
+	// before another instance may pick them up
+	claimTimeout = time.Minute
+)
+
+// Relay moves events from the outbox to a broker
+type Relay struct {
+	db           *gorm.DB
+	broker       Broker
+	batchSize    int
+	pollInterval time.Duration
+}
+
+func NewRelay(db *gorm.DB, broker Broker) *Relay {
+	return &Relay{
+		db:           db,
+		broker:       broker,
+		batchSize:    defaultBatchSize,
+		pollInterval: defaultPollInterval,
+	}
+}
+
+// Run relays events until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while batches come back full
+		for {
+			n, err := r.RelayOnce(ctx)
+			if err != nil {
+				log.Printf("events: relay failed: %v", err)
+				break
+			}
+			if n < r.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce publishes one batch of due events and returns how many it picked
+// up. The batch is claimed in a short transaction, locking rows with SKIP
+// LOCKED and pushing their next attempt claimTimeout ahead, so several
+// instances of a service can relay the same outbox without sending an event
+// twice at the same time. The events are published after that transaction
+// commits, so no locks are held while a subscriber answers; an instance that
+// dies in between leaves its claim to expire. A failed event is retried with
+// exponential backoff; it does not hold back later events.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	var messages []OutboxMessage
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND next_attempt_at <= ?", now).
+			Order("id").
+			Limit(r.batchSize).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+		return tx.Model(&OutboxMessage{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(claimTimeout)).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, message := range messages {
+		updates := map[string]interface{}{"attempts": message.Attempts + 1}
+		if err := r.broker.Publish(ctx, message.event()); err != nil {
+			updates["last_error"] = truncate(err.Error(), 500)
+			updates["next_attempt_at"] = time.Now().UTC().Add(retryDelay(message.Attempts + 1))
+		} else {
+			updates["published_at"] = time.Now().UTC()
+			updates["last_error"] = ""
+		}
+		if err := r.db.WithContext(ctx).Model(&OutboxMessage{}).Where("id = ?", message.ID).Updates(updates).Error; err != nil {
+			return len(messages), err
+		}
+	}
+	return len(messages), nil
+}
+
+// retryDelay doubles from one second up to maxRetryDelay
+func retryDelay(attempts int) time.Duration {
+	delay := time.Second
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
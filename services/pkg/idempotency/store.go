@@ -0,0 +1,95 @@
+// Package idempotency stores the records of middleware.IdempotencyMiddleware
+// in a service's own database through GORM.
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/middleware"
+	"gorm.io/gorm"
+)
+
+// Key is a stored request and its response. Every service that uses Store
+// migrates it into its own database.
+type Key struct {
+	ID           uint      `gorm:"primaryKey"`
+	Scope        string    `gorm:"size:191;not null;uniqueIndex:idx_idempotency_scope_key"`
+	Key          string    `gorm:"column:idempotency_key;size:255;not null;uniqueIndex:idx_idempotency_scope_key"`
+	Fingerprint  string    `gorm:"size:64;not null"`
+	Completed    bool      `gorm:"not null;default:false"`
+	StatusCode   int       `gorm:"not null;default:0"`
+	ContentType  string    `gorm:"size:100"`
+	ResponseBody []byte    `gorm:"type:mediumblob"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+}
+
+func (Key) TableName() string {
+	return "idempotency_keys"
+}
+
+// Store is a middleware.IdempotencyStore backed by the idempotency_keys table
+type Store struct {
+	db *gorm.DB
+}
+
+// NewStore creates a store on the service's database
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+var _ middleware.IdempotencyStore = (*Store)(nil)
+
+func (s *Store) Reserve(ctx context.Context, record *middleware.IdempotencyRecord) (*middleware.IdempotencyRecord, bool, error) {
+	db := s.db.WithContext(ctx)
+
+	// An expired key may be reused for a new request
+	db.Where("scope = ? AND idempotency_key = ? AND expires_at < ?", record.Scope, record.Key, time.Now()).
+		Delete(&Key{})
+
+	row := Key{
+		Scope:       record.Scope,
+		Key:         record.Key,
+		Fingerprint: record.Fingerprint,
+		ExpiresAt:   record.ExpiresAt,
+	}
+	if err := db.Create(&row).Error; err != nil {
+		// The unique index turned the insert down when the key is taken
+		var existing Key
+		if findErr := db.Where("scope = ? AND idempotency_key = ?", record.Scope, record.Key).First(&existing).Error; findErr != nil {
+			return nil, false, err
+		}
+		return toRecord(&existing), false, nil
+	}
+	record.ID = row.ID
+	return record, true, nil
+}
+
+func (s *Store) Complete(ctx context.Context, record *middleware.IdempotencyRecord) error {
+	return s.db.WithContext(ctx).Model(&Key{ID: record.ID}).Updates(map[string]interface{}{
+		"completed":     true,
+		"status_code":   record.StatusCode,
+		"content_type":  record.ContentType,
+		"response_body": record.ResponseBody,
+	}).Error
+}
+
+func (s *Store) Release(ctx context.Context, record *middleware.IdempotencyRecord) error {
+	return s.db.WithContext(ctx).Delete(&Key{}, record.ID).Error
+}
+
+func toRecord(k *Key) *middleware.IdempotencyRecord {
+	return &middleware.IdempotencyRecord{
+		ID:           k.ID,
+		Scope:        k.Scope,
+		Key:          k.Key,
+		Fingerprint:  k.Fingerprint,
+		Completed:    k.Completed,
+		StatusCode:   k.StatusCode,
+		ContentType:  k.ContentType,
+		ResponseBody: k.ResponseBody,
+		ExpiresAt:    k.ExpiresAt,
+	}
+}
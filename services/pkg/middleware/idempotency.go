@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header clients set to make a POST safe to retry
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayHeader is set on responses replayed from a stored result
+	IdempotentReplayHeader = "Idempotent-Replayed"
+	// IdempotencyTTL is how long a key and its stored response are kept
+	IdempotencyTTL = 24 * time.Hour
+
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyRecord is a stored request and, once it has completed, its response
+type IdempotencyRecord struct {
+	ID           uint
+	Scope        string
+	Key          string
+	Fingerprint  string
+	Completed    bool
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+	ExpiresAt    time.Time
+}
+
+// IdempotencyStore keeps the records of IdempotencyMiddleware. Each service
+// keeps them in its own database; pkg/idempotency has a GORM implementation.
+type IdempotencyStore interface {
+	// Reserve saves a new record, first removing an expired one with the same
+	// scope and key. When a live record already holds the key it returns that
+	// record and false instead.
+	Reserve(ctx context.Context, record *IdempotencyRecord) (*IdempotencyRecord, bool, error)
+	// Complete stores the response of a reserved record
+	Complete(ctx context.Context, record *IdempotencyRecord) error
+	// Release removes a reserved record so that its key can be used again
+	Release(ctx context.Context, record *IdempotencyRecord) error
+}
+
+// IdempotencyMiddleware makes a handler safe to retry. Requests without an
+// Idempotency-Key header pass through untouched. The first request with a key
+// runs normally and its response is stored; repeats with the same body get the
+// stored response back, while repeats with a different body, or arriving while
+// the first is still running, get 409. Server errors are not stored so that the
+// client can retry them with the same key.
+//
+// Keys are scoped to the caller and the route, so it must run after
+// ServiceAuthMiddleware.
+func IdempotencyMiddleware(store IdempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, "Idempotency-Key is too long", nil, utils.GenerateRequestID()))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, "Failed to read request body", err.Error(), utils.GenerateRequestID()))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		record := &IdempotencyRecord{
+			Scope:       idempotencyScope(c),
+			Key:         key,
+			Fingerprint: requestFingerprint(c.Request.Method, c.Request.URL.Path, body),
+			ExpiresAt:   time.Now().Add(IdempotencyTTL),
+		}
+
+		existing, reserved, err := store.Reserve(ctx, record)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrDatabaseError, "Failed to store Idempotency-Key", err.Error(), utils.GenerateRequestID()))
+			c.Abort()
+			return
+		}
+		if !reserved {
+			replayIdempotentResponse(c, existing, record.Fingerprint)
+			return
+		}
+
+		writer := &capturingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// Use a fresh context: the request's may already be cancelled
+		saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			if err := store.Release(saveCtx, record); err != nil {
+				log.Printf("failed to release idempotency key %q: %v", record.Key, err)
+			}
+			return
+		}
+
+		record.Completed = true
+		record.StatusCode = status
+		record.ContentType = writer.Header().Get("Content-Type")
+		record.ResponseBody = writer.body.Bytes()
+		if err := store.Complete(saveCtx, record); err != nil {
+			log.Printf("failed to store response for idempotency key %q: %v", record.Key, err)
+		}
+	}
+}
+
+// replayIdempotentResponse answers a repeated key from the stored record
+func replayIdempotentResponse(c *gin.Context, existing *IdempotencyRecord, fingerprint string) {
+	defer c.Abort()
+
+	switch {
+	case existing.Fingerprint != fingerprint:
+		c.JSON(http.StatusConflict, utils.ErrorResponse(utils.ErrConflict, "Idempotency-Key was already used with a different request", nil, utils.GenerateRequestID()))
+	case !existing.Completed:
+		c.Header("Retry-After", "1")
+		c.JSON(http.StatusConflict, utils.ErrorResponse(utils.ErrConflict, "A request with this Idempotency-Key is still being processed", nil, utils.GenerateRequestID()))
+	default:
+		c.Header(IdempotentReplayHeader, "true")
+		c.Data(existing.StatusCode, existing.ContentType, existing.ResponseBody)
+	}
+}
+
+// idempotencyScope separates keys of different callers and routes
+func idempotencyScope(c *gin.Context) string {
+	userID, _ := c.Get("user_id")
+	return fmt.Sprintf("%v:%s %s", userID, c.Request.Method, c.FullPath())
+}
+
+func requestFingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// capturingWriter keeps a copy of the response body as it is written
+type capturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *capturingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
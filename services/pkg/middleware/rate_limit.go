@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitIdle is how long a client's bucket is kept after its last request
+const rateLimitIdle = 10 * time.Minute
+
+type tokenBucket struct {
+	tokens float64
+	seen   time.Time
+}
+
+// rateLimiter is a token bucket per client: it holds up to burst tokens and
+// refills at rate tokens per second
+type rateLimiter struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// allow takes a token from the client's bucket. When the bucket is empty it
+// returns how long until the next token is available.
+func (l *rateLimiter) allow(client string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > rateLimitIdle {
+		for key, bucket := range l.buckets {
+			if now.Sub(bucket.seen) > rateLimitIdle {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	bucket, ok := l.buckets[client]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, seen: now}
+		l.buckets[client] = bucket
+	}
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.seen).Seconds()*l.rate)
+	bucket.seen = now
+
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+		return false, wait
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// RateLimitMiddleware limits each client IP to requestsPerMinute, allowing
+// bursts of up to burst requests. Limits are kept in memory, so they apply per
+// service instance. Rejected requests get 429 with a Retry-After header.
+func RateLimitMiddleware(requestsPerMinute, burst int) gin.HandlerFunc {
+	if requestsPerMinute < 1 {
+		requestsPerMinute = 1
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	limiter := &rateLimiter{
+		rate:    float64(requestsPerMinute) / 60,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+	}
+
+	return func(c *gin.Context) {
+		allowed, wait := limiter.allow(c.ClientIP(), time.Now())
+		if !allowed {
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, utils.ErrorResponse(utils.ErrRateLimitExceeded, "Too many requests", nil, utils.GenerateRequestID()))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
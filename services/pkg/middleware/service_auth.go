@@ -53,7 +53,7 @@ func ServiceAuthMiddleware() gin.HandlerFunc {
 		c.Set("role", role)
 
 		fmt.Printf("[SUCCESS] User authenticated: ID=%d, Email=%s, Role=%s\n", userID, email, role)
-		fmt.Println("============================================\n")
+		fmt.Print("============================================\n\n")
 
 		c.Next()
 	}
@@ -0,0 +1,247 @@
+// Package money provides an exact monetary amount stored in integer minor units
+// (paisa, cents) together with its ISO 4217 currency code.
+//
+// Money is stored by GORM as two columns when embedded with a prefix:
+//
+//	Price money.Money `gorm:"embedded;embeddedPrefix:price_"` // price_minor, price_currency
+//
+// and encoded as JSON as {"amount": 199900, "currency": "INR"} where amount is in minor units.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DefaultCurrency is used when an amount arrives without a currency code
+const DefaultCurrency = "INR"
+
+var (
+	ErrCurrencyMismatch = errors.New("money: currency mismatch")
+	ErrUnknownCurrency  = errors.New("money: unknown currency")
+)
+
+type currency struct {
+	exponent int
+	symbol   string
+}
+
+// currencies lists the supported ISO 4217 codes and their minor unit exponent
+var currencies = map[string]currency{
+	"INR": {exponent: 2, symbol: "₹"},
+	"USD": {exponent: 2, symbol: "$"},
+	"EUR": {exponent: 2, symbol: "€"},
+	"GBP": {exponent: 2, symbol: "£"},
+	"JPY": {exponent: 0, symbol: "¥"},
+}
+
+// Money is an amount in minor units of a currency
+type Money struct {
+	Amount   int64  `json:"amount" gorm:"column:minor;not null;default:0"`
+	Currency string `json:"currency" gorm:"column:currency;type:char(3);not null;default:'INR'"`
+}
+
+// New creates an amount from minor units
+func New(minor int64, code string) Money {
+	return Money{Amount: minor, Currency: normalize(code)}
+}
+
+// Zero returns a zero amount in the given currency
+func Zero(code string) Money {
+	return New(0, code)
+}
+
+// FromMajor converts a major unit amount such as 19.99 rupees, rounding half away from zero
+func FromMajor(major float64, code string) Money {
+	code = normalize(code)
+	factor := math.Pow10(exponent(code))
+	return Money{Amount: int64(math.Round(major * factor)), Currency: code}
+}
+
+// IsKnownCurrency reports whether code is a supported ISO currency
+func IsKnownCurrency(code string) bool {
+	_, ok := currencies[strings.ToUpper(code)]
+	return ok
+}
+
+// Exponent returns the number of minor unit digits of a currency, 2 for
+// codes it does not know
+func Exponent(code string) int {
+	return exponent(code)
+}
+
+// Currencies returns the supported currency codes in alphabetical order
+func Currencies() []string {
+	codes := make([]string, 0, len(currencies))
+	for code := range currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Major returns the amount in major units. Use it for display only.
+func (m Money) Major() float64 {
+	return float64(m.Amount) / math.Pow10(exponent(m.Currency))
+}
+
+func (m Money) IsZero() bool     { return m.Amount == 0 }
+func (m Money) IsPositive() bool { return m.Amount > 0 }
+func (m Money) IsNegative() bool { return m.Amount < 0 }
+
+// SameCurrency reports whether both amounts use the same currency
+func (m Money) SameCurrency(o Money) bool {
+	return normalize(m.Currency) == normalize(o.Currency)
+}
+
+// Add returns m + o
+func (m Money) Add(o Money) (Money, error) {
+	if !m.SameCurrency(o) {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: normalize(m.Currency)}, nil
+}
+
+// Sub returns m - o
+func (m Money) Sub(o Money) (Money, error) {
+	return m.Add(o.Neg())
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: normalize(m.Currency)}
+}
+
+// Mul returns m multiplied by a whole quantity
+func (m Money) Mul(quantity int64) Money {
+	return Money{Amount: m.Amount * quantity, Currency: normalize(m.Currency)}
+}
+
+// Percent returns pct percent of m, rounded half away from zero to the minor unit
+func (m Money) Percent(pct float64) Money {
+	return Money{Amount: int64(math.Round(float64(m.Amount) * pct / 100)), Currency: normalize(m.Currency)}
+}
+
+// Cmp compares two amounts and returns -1, 0 or +1
+func (m Money) Cmp(o Money) (int, error) {
+	if !m.SameCurrency(o) {
+		return 0, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Allocate splits m into parts proportional to ratios. The remainder left by
+// integer division is handed out one minor unit at a time from the first part,
+// so the parts always add up to m exactly.
+func (m Money) Allocate(ratios ...int64) []Money {
+	var total int64
+	for _, r := range ratios {
+		total += r
+	}
+
+	parts := make([]Money, len(ratios))
+	if total == 0 {
+		for i := range parts {
+			parts[i] = Zero(m.Currency)
+		}
+		return parts
+	}
+
+	remainder := m.Amount
+	for i, r := range ratios {
+		share := m.Amount * r / total
+		parts[i] = Money{Amount: share, Currency: normalize(m.Currency)}
+		remainder -= share
+	}
+
+	step := int64(1)
+	if remainder < 0 {
+		step = -1
+	}
+	for i := 0; remainder != 0; i = (i + 1) % len(parts) {
+		parts[i].Amount += step
+		remainder -= step
+	}
+	return parts
+}
+
+// Sum adds amounts that must all share the given currency
+func Sum(code string, amounts ...Money) (Money, error) {
+	total := Zero(code)
+	for _, a := range amounts {
+		var err error
+		if total, err = total.Add(a); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// String formats the amount as "INR 1999.00"
+func (m Money) String() string {
+	code := normalize(m.Currency)
+	return fmt.Sprintf("%s %.*f", code, exponent(code), m.Major())
+}
+
+// Format formats the amount with its currency symbol, e.g. "₹1999.00"
+func (m Money) Format() string {
+	code := normalize(m.Currency)
+	c, ok := currencies[code]
+	if !ok {
+		return m.String()
+	}
+	return fmt.Sprintf("%s%.*f", c.symbol, c.exponent, m.Major())
+}
+
+// UnmarshalJSON decodes {"amount": 199900, "currency": "INR"} and rejects unknown currencies
+func (m *Money) UnmarshalJSON(data []byte) error {
+	type plain Money
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	code := normalize(decoded.Currency)
+	if !IsKnownCurrency(code) {
+		return fmt.Errorf("%w: %q", ErrUnknownCurrency, decoded.Currency)
+	}
+	*m = Money{Amount: decoded.Amount, Currency: code}
+	return nil
+}
+
+// ValidationValue lets go-playground/validator check Money fields by their minor
+// amount, so tags like `validate:"required,gt=0"` keep working:
+//
+//	validate.RegisterCustomTypeFunc(money.ValidationValue, money.Money{})
+func ValidationValue(field reflect.Value) interface{} {
+	if m, ok := field.Interface().(Money); ok {
+		return m.Amount
+	}
+	return nil
+}
+
+func normalize(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency
+	}
+	return code
+}
+
+func exponent(code string) int {
+	if c, ok := currencies[normalize(code)]; ok {
+		return c.exponent
+	}
+	return 2
+}
@@ -0,0 +1,245 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestFromMajor(t *testing.T) {
+	tests := []struct {
+		name  string
+		major float64
+		code  string
+		want  Money
+	}{
+		{"whole rupees", 1999, "INR", Money{Amount: 199900, Currency: "INR"}},
+		{"paisa", 19.99, "INR", Money{Amount: 1999, Currency: "INR"}},
+		{"float error", 0.29, "INR", Money{Amount: 29, Currency: "INR"}},
+		{"half rounds up", 0.005, "USD", Money{Amount: 1, Currency: "USD"}},
+		{"negative half rounds away from zero", -0.005, "USD", Money{Amount: -1, Currency: "USD"}},
+		{"no minor unit", 1500, "JPY", Money{Amount: 1500, Currency: "JPY"}},
+		{"lower case code", 1, "eur", Money{Amount: 100, Currency: "EUR"}},
+		{"default currency", 2.5, "", Money{Amount: 250, Currency: DefaultCurrency}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromMajor(tt.major, tt.code); got != tt.want {
+				t.Errorf("FromMajor(%v, %q) = %+v, want %+v", tt.major, tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExponent(t *testing.T) {
+	tests := []struct {
+		code string
+		want int
+	}{
+		{"INR", 2},
+		{"usd", 2},
+		{"JPY", 0},
+		{"", 2},
+		{"XYZ", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := Exponent(tt.code); got != tt.want {
+				t.Errorf("Exponent(%q) = %d, want %d", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddSub(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Money
+		sum     Money
+		diff    Money
+		wantErr error
+	}{
+		{"same currency", New(1000, "INR"), New(250, "INR"), New(1250, "INR"), New(750, "INR"), nil},
+		{"codes are normalized", New(100, "inr"), New(1, " INR "), New(101, "INR"), New(99, "INR"), nil},
+		{"negative result", New(100, "USD"), New(250, "USD"), New(350, "USD"), New(-150, "USD"), nil},
+		{"currency mismatch", New(100, "INR"), New(100, "USD"), Money{}, Money{}, ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum, err := tt.a.Add(tt.b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Add error = %v, want %v", err, tt.wantErr)
+			}
+			if sum != tt.sum {
+				t.Errorf("Add = %+v, want %+v", sum, tt.sum)
+			}
+			diff, err := tt.a.Sub(tt.b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Sub error = %v, want %v", err, tt.wantErr)
+			}
+			if diff != tt.diff {
+				t.Errorf("Sub = %+v, want %+v", diff, tt.diff)
+			}
+		})
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Money
+		pct  float64
+		want int64
+	}{
+		{"exact", New(10000, "INR"), 18, 1800},
+		{"rounds half up", New(250, "INR"), 10, 25},
+		{"rounds to nearest", New(999, "INR"), 18, 180},
+		{"fractional percent", New(10000, "INR"), 2.5, 250},
+		{"negative", New(-999, "INR"), 18, -180},
+		{"zero", New(0, "INR"), 18, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.Percent(tt.pct); got.Amount != tt.want {
+				t.Errorf("%v.Percent(%v) = %d, want %d", tt.m, tt.pct, got.Amount, tt.want)
+			}
+		})
+	}
+}
+
+func TestCmp(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Money
+		want    int
+		wantErr error
+	}{
+		{"less", New(1, "INR"), New(2, "INR"), -1, nil},
+		{"equal", New(2, "INR"), New(2, "inr"), 0, nil},
+		{"greater", New(3, "INR"), New(2, "INR"), 1, nil},
+		{"currency mismatch", New(1, "INR"), New(1, "GBP"), 0, ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.Cmp(tt.b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Cmp error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Cmp = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name   string
+		m      Money
+		ratios []int64
+		want   []int64
+	}{
+		{"even split", New(900, "INR"), []int64{1, 1, 1}, []int64{300, 300, 300}},
+		{"remainder goes to the first parts", New(1000, "INR"), []int64{1, 1, 1}, []int64{334, 333, 333}},
+		{"weighted", New(1000, "INR"), []int64{3, 1}, []int64{750, 250}},
+		{"weighted with remainder", New(101, "INR"), []int64{2, 1, 1}, []int64{51, 25, 25}},
+		{"negative amount", New(-1000, "INR"), []int64{1, 1, 1}, []int64{-334, -333, -333}},
+		{"zero ratio", New(500, "INR"), []int64{0, 1}, []int64{0, 500}},
+		{"all zero ratios", New(500, "INR"), []int64{0, 0}, []int64{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := tt.m.Allocate(tt.ratios...)
+			if len(parts) != len(tt.want) {
+				t.Fatalf("Allocate returned %d parts, want %d", len(parts), len(tt.want))
+			}
+			for i, part := range parts {
+				if part.Amount != tt.want[i] || part.Currency != tt.m.Currency {
+					t.Errorf("part %d = %+v, want %d %s", i, part, tt.want[i], tt.m.Currency)
+				}
+			}
+		})
+	}
+}
+
+func TestSum(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		amounts []Money
+		want    Money
+		wantErr error
+	}{
+		{"no amounts", "INR", nil, New(0, "INR"), nil},
+		{"several amounts", "INR", []Money{New(100, "INR"), New(250, "INR"), New(-50, "INR")}, New(300, "INR"), nil},
+		{"currency mismatch", "INR", []Money{New(100, "INR"), New(100, "USD")}, Money{}, ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sum(tt.code, tt.amounts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Sum error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Sum = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	tests := []struct {
+		m      Money
+		str    string
+		format string
+	}{
+		{New(199900, "INR"), "INR 1999.00", "₹1999.00"},
+		{New(5, "USD"), "USD 0.05", "$0.05"},
+		{New(-1999, "EUR"), "EUR -19.99", "€-19.99"},
+		{New(1500, "JPY"), "JPY 1500", "¥1500"},
+		{New(1999, "CHF"), "CHF 19.99", "CHF 19.99"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			if got := tt.m.String(); got != tt.str {
+				t.Errorf("String() = %q, want %q", got, tt.str)
+			}
+			if got := tt.m.Format(); got != tt.format {
+				t.Errorf("Format() = %q, want %q", got, tt.format)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Money
+		wantErr error
+	}{
+		{"amount and currency", `{"amount": 199900, "currency": "INR"}`, New(199900, "INR"), nil},
+		{"lower case currency", `{"amount": 5, "currency": "usd"}`, New(5, "USD"), nil},
+		{"missing currency", `{"amount": 5}`, New(5, DefaultCurrency), nil},
+		{"unknown currency", `{"amount": 5, "currency": "XYZ"}`, Money{}, ErrUnknownCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Unmarshal error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("not a number", func(t *testing.T) {
+		var got Money
+		if err := json.Unmarshal([]byte(`{"amount": "19.99", "currency": "INR"}`), &got); err == nil {
+			t.Errorf("Unmarshal accepted a string amount: %+v", got)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+// Package moneydb migrates the columns GORM stores money.Money in, kept apart
+// from package money so that using the type does not pull in GORM.
+package moneydb
+
+import (
+	"math"
+	"strings"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MigrateLegacyAmount copies a decimal major unit column, such as the rupee
+// price columns used before amounts were stored in minor units, into the
+// columns of the money.Money embedded with prefix and drops it. Each row is
+// scaled by the minor unit exponent of its currency. Rows whose minor amount
+// is already set are left alone, so it is safe to run again after a partial
+// migration.
+func MigrateLegacyAmount(db *gorm.DB, table, legacyColumn, prefix string) error {
+	if !db.Migrator().HasColumn(table, legacyColumn) {
+		return nil
+	}
+
+	minor := clause.Column{Name: prefix + "minor"}
+	currency := clause.Column{Name: prefix + "currency"}
+
+	var factor strings.Builder
+	factor.WriteString("CASE UPPER(?)")
+	args := []interface{}{clause.Table{Name: table}, minor, clause.Column{Name: legacyColumn}, currency}
+	for _, code := range money.Currencies() {
+		factor.WriteString(" WHEN ? THEN ?")
+		args = append(args, code, int64(math.Pow10(money.Exponent(code))))
+	}
+	factor.WriteString(" ELSE ? END")
+	args = append(args, int64(math.Pow10(money.Exponent(""))), minor)
+
+	query := "UPDATE ? SET ? = ROUND(? * " + factor.String() + ") WHERE ? = 0"
+	if err := db.Exec(query, args...).Error; err != nil {
+		return err
+	}
+	return db.Migrator().DropColumn(table, legacyColumn)
+}
@@ -26,18 +26,31 @@ const (
 	OrderStatusPending    = "pending"
 	OrderStatusConfirmed  = "confirmed"
 	OrderStatusProcessing = "processing"
+	OrderStatusPacked     = "packed"
 	OrderStatusShipped    = "shipped"
 	OrderStatusDelivered  = "delivered"
 	OrderStatusCancelled  = "cancelled"
 	OrderStatusReturned   = "returned"
 )
 
+// Order Fulfilment Status, rolled up by shipping-service from the order's shipments
+const (
+	FulfilmentStatusUnfulfilled      = "unfulfilled"
+	FulfilmentStatusPartiallyShipped = "partially_shipped"
+	FulfilmentStatusShipped          = "shipped"
+	FulfilmentStatusDelivered        = "delivered"
+)
+
 // Payment Status
 const (
-	PaymentStatusPending  = "pending"
-	PaymentStatusSuccess  = "success"
-	PaymentStatusFailed   = "failed"
-	PaymentStatusRefunded = "refunded"
+	PaymentStatusPending    = "pending"
+	PaymentStatusAuthorized = "authorized"
+	PaymentStatusSuccess    = "success"
+	PaymentStatusFailed     = "failed"
+	PaymentStatusVoided     = "voided"
+	PaymentStatusRefunded   = "refunded"
+	// PaymentStatusPartiallyRefunded is a captured payment refunded for less than its full amount
+	PaymentStatusPartiallyRefunded = "partially_refunded"
 )
 
 // Payment Methods
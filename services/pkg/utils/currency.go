@@ -3,6 +3,8 @@ package utils
 import (
 	"fmt"
 	"math"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
 )
 
 // FormatINR formats amount in Indian Rupees
@@ -43,6 +45,12 @@ func CalculateGST(price, gstPercent float64) float64 {
 	return price * (gstPercent / 100)
 }
 
+// GST works out GST on an amount, rounded to the minor unit. Carts and orders
+// charge it once on what is paid for the items after all discounts.
+func GST(amount money.Money, gstPercent float64) money.Money {
+	return amount.Percent(gstPercent)
+}
+
 // RoundToDecimal rounds float to n decimal places
 func RoundToDecimal(value float64, places int) float64 {
 	multiplier := math.Pow(10, float64(places))
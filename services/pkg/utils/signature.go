@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers carrying a webhook signature and the unix timestamp it was made at
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+)
+
+var (
+	ErrMissingSignature  = errors.New("missing signature or timestamp")
+	ErrSignatureMismatch = errors.New("signature mismatch")
+	ErrStaleSignature    = errors.New("timestamp outside tolerance")
+)
+
+// SignWebhook returns the "sha256=<hex>" HMAC of "<timestamp>.<body>"
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks a signature produced by SignWebhook. The timestamp
+// is unix seconds and must be within tolerance of now to block replays.
+func VerifyWebhookSignature(secret, signature, timestamp string, body []byte, tolerance time.Duration, now time.Time) error {
+	if signature == "" || timestamp == "" {
+		return ErrMissingSignature
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrSignatureMismatch
+	}
+	sentAt := time.Unix(seconds, 0)
+	if tolerance > 0 && (now.Sub(sentAt) > tolerance || sentAt.Sub(now) > tolerance) {
+		return ErrStaleSignature
+	}
+
+	expected := SignWebhook(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(strings.TrimSpace(signature))) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
@@ -0,0 +1,387 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+// csvColumns are the columns of a CSV file, in the order they are exported.
+// A file has one row per variant, and consecutive rows with the same sku are
+// one product whose product columns are taken from its first row. A product
+// without variants is a single row with the variant columns empty. Prices
+// are in major units, such as 799.50, in the product's currency; attributes
+// are written key=value;key=value and images as URLs separated by |.
+var csvColumns = []string{
+	"sku", "name", "slug", "description", "price", "currency", "discount", "stock", "status",
+	"category_id", "brand", "uom_id", "quantity_value", "attributes", "images",
+	"variant_sku", "variant_name", "variant_price", "variant_stock", "variant_size",
+	"variant_color", "variant_weight", "variant_quantity_value", "variant_uom_id",
+}
+
+// variantColumns are the columns that make a row describe a variant
+var variantColumns = csvColumns[15:]
+
+const (
+	attributeSeparator = ";"
+	imageSeparator     = "|"
+)
+
+// csvReader reads items from a CSV file with a header row. Columns may come
+// in any order and only sku is required.
+type csvReader struct {
+	csv     *csv.Reader
+	columns map[string]int
+	// pending is the row read ahead that starts the next item
+	pending     []string
+	pendingLine int
+	err         error
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("the file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("header: %w", err)
+	}
+
+	known := make(map[string]bool, len(csvColumns))
+	for _, column := range csvColumns {
+		known[column] = true
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// Spreadsheets often save a byte order mark at the start
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !known[name] {
+			return nil, fmt.Errorf("header: unknown column %q", name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("header: column %q appears twice", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["sku"]; !ok {
+		return nil, errors.New("header: the sku column is required")
+	}
+	return &csvReader{csv: reader, columns: columns}, nil
+}
+
+// read returns the next row and the line it is on
+func (r *csvReader) read() ([]string, int, error) {
+	row, err := r.csv.Read()
+	if err != nil {
+		return nil, 0, err
+	}
+	line, _ := r.csv.FieldPos(0)
+	return row, line, nil
+}
+
+func (r *csvReader) value(row []string, column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func (r *csvReader) Next() (*Entry, error) {
+	row, line := r.pending, r.pendingLine
+	r.pending = nil
+	if row == nil {
+		err := r.err
+		r.err = nil
+		if err == nil {
+			row, line, err = r.read()
+		}
+		if err != nil {
+			return parseErrorEntry(err)
+		}
+	}
+
+	entry := &Entry{Line: line}
+	r.parseProduct(entry, row)
+	r.parseVariant(entry, row, line)
+	sku := entry.Item.SKU
+	if sku == "" {
+		return entry, nil
+	}
+
+	for {
+		next, nextLine, err := r.read()
+		if err != nil {
+			// Return the item read so far and the error on the next call
+			r.err = err
+			return entry, nil
+		}
+		if r.value(next, "sku") != sku {
+			r.pending, r.pendingLine = next, nextLine
+			return entry, nil
+		}
+		r.parseVariant(entry, next, nextLine)
+	}
+}
+
+// parseErrorEntry turns a row the CSV reader could not parse into an entry
+// with the error, as the rows after it can still be read. Other errors end
+// the file.
+func parseErrorEntry(err error) (*Entry, error) {
+	var parseErr *csv.ParseError
+	if !errors.As(err, &parseErr) {
+		return nil, err
+	}
+	return &Entry{
+		Line:   parseErr.StartLine,
+		Errors: []FieldError{{Line: parseErr.Line, Message: parseErr.Err.Error()}},
+	}, nil
+}
+
+// rowParser reads the values of a row into an entry, collecting the errors
+type rowParser struct {
+	reader *csvReader
+	entry  *Entry
+	row    []string
+	line   int
+}
+
+func (p *rowParser) fail(column, message string) {
+	p.entry.Errors = append(p.entry.Errors, FieldError{Line: p.line, Field: column, Message: message})
+}
+
+func (p *rowParser) text(column string) string {
+	return p.reader.value(p.row, column)
+}
+
+func (p *rowParser) optionalText(column string) *string {
+	value := p.text(column)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func (p *rowParser) int(column string) int {
+	value := p.text(column)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		p.fail(column, fmt.Sprintf("%q is not a whole number", value))
+	}
+	return n
+}
+
+func (p *rowParser) float(column string) float64 {
+	value := p.text(column)
+	if value == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		p.fail(column, fmt.Sprintf("%q is not a number", value))
+		return 0
+	}
+	return f
+}
+
+func (p *rowParser) optionalFloat(column string) *float64 {
+	if p.text(column) == "" {
+		return nil
+	}
+	f := p.float(column)
+	return &f
+}
+
+func (p *rowParser) id(column string) uint64 {
+	value := p.text(column)
+	if value == "" {
+		return 0
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		p.fail(column, fmt.Sprintf("%q is not an ID", value))
+	}
+	return id
+}
+
+func (p *rowParser) optionalID(column string) *uint64 {
+	if p.text(column) == "" {
+		return nil
+	}
+	id := p.id(column)
+	return &id
+}
+
+func (p *rowParser) money(column, currency string) money.Money {
+	return money.FromMajor(p.float(column), currency)
+}
+
+func (r *csvReader) parseProduct(entry *Entry, row []string) {
+	p := &rowParser{reader: r, entry: entry, row: row, line: entry.Line}
+
+	currency := strings.ToUpper(p.text("currency"))
+	if currency == "" {
+		currency = money.DefaultCurrency
+	} else if !money.IsKnownCurrency(currency) {
+		p.fail("currency", fmt.Sprintf("%q is not a supported currency", currency))
+	}
+
+	entry.Item = Item{
+		SKU:           p.text("sku"),
+		Name:          p.text("name"),
+		Slug:          p.text("slug"),
+		Description:   p.text("description"),
+		Price:         p.money("price", currency),
+		Discount:      p.float("discount"),
+		Stock:         p.int("stock"),
+		Status:        p.text("status"),
+		CategoryID:    p.id("category_id"),
+		Brand:         p.text("brand"),
+		UoMID:         p.optionalID("uom_id"),
+		QuantityValue: p.optionalFloat("quantity_value"),
+	}
+	if entry.Item.SKU == "" {
+		p.fail("sku", "is required")
+	}
+
+	for _, pair := range strings.Split(p.text("attributes"), attributeSeparator) {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, found := strings.Cut(pair, "=")
+		if !found {
+			p.fail("attributes", fmt.Sprintf("%q is not key=value", strings.TrimSpace(pair)))
+			continue
+		}
+		entry.Item.Attributes = append(entry.Item.Attributes, Attribute{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value)})
+	}
+
+	for _, url := range strings.Split(p.text("images"), imageSeparator) {
+		if url = strings.TrimSpace(url); url != "" {
+			entry.Item.Images = append(entry.Item.Images, url)
+		}
+	}
+}
+
+// parseVariant adds the variant a row describes, if it describes one. A
+// variant without a price costs the same as its product.
+func (r *csvReader) parseVariant(entry *Entry, row []string, line int) {
+	p := &rowParser{reader: r, entry: entry, row: row, line: line}
+
+	described := false
+	for _, column := range variantColumns {
+		if p.text(column) != "" {
+			described = true
+			break
+		}
+	}
+	if !described {
+		return
+	}
+
+	price := entry.Item.Price
+	if p.text("variant_price") != "" {
+		price = p.money("variant_price", price.Currency)
+	}
+	entry.Item.Variants = append(entry.Item.Variants, Variant{
+		SKU:           p.text("variant_sku"),
+		Name:          p.text("variant_name"),
+		Price:         price,
+		Stock:         p.int("variant_stock"),
+		Size:          p.optionalText("variant_size"),
+		Color:         p.optionalText("variant_color"),
+		Weight:        p.optionalText("variant_weight"),
+		QuantityValue: p.float("variant_quantity_value"),
+		UoMID:         p.optionalID("variant_uom_id"),
+	})
+}
+
+// csvWriter writes items with every column, repeating the product columns on
+// each of its variant rows
+type csvWriter struct {
+	csv *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvColumns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{csv: writer}, nil
+}
+
+func formatMajor(m money.Money) string {
+	return strconv.FormatFloat(m.Major(), 'f', -1, 64)
+}
+
+func formatOptionalID(id *uint64) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(*id, 10)
+}
+
+func formatOptionalText(text *string) string {
+	if text == nil {
+		return ""
+	}
+	return *text
+}
+
+func (w *csvWriter) Write(item *Item) error {
+	attributes := make([]string, len(item.Attributes))
+	for i, attribute := range item.Attributes {
+		attributes[i] = attribute.Key + "=" + attribute.Value
+	}
+	quantityValue := ""
+	if item.QuantityValue != nil {
+		quantityValue = strconv.FormatFloat(*item.QuantityValue, 'f', -1, 64)
+	}
+	categoryID := ""
+	if item.CategoryID != 0 {
+		categoryID = strconv.FormatUint(item.CategoryID, 10)
+	}
+
+	product := []string{
+		item.SKU, item.Name, item.Slug, item.Description, formatMajor(item.Price), item.Price.Currency,
+		strconv.FormatFloat(item.Discount, 'f', -1, 64), strconv.Itoa(item.Stock), item.Status,
+		categoryID, item.Brand, formatOptionalID(item.UoMID), quantityValue,
+		strings.Join(attributes, attributeSeparator), strings.Join(item.Images, imageSeparator),
+	}
+
+	if len(item.Variants) == 0 {
+		return w.csv.Write(append(product, make([]string, len(variantColumns))...))
+	}
+	for _, variant := range item.Variants {
+		row := append(product[:len(product):len(product)],
+			variant.SKU, variant.Name, formatMajor(variant.Price), strconv.Itoa(variant.Stock),
+			formatOptionalText(variant.Size), formatOptionalText(variant.Color), formatOptionalText(variant.Weight),
+			strconv.FormatFloat(variant.QuantityValue, 'f', -1, 64), formatOptionalID(variant.UoMID),
+		)
+		if err := w.csv.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *csvWriter) Flush() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
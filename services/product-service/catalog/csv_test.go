@@ -0,0 +1,103 @@
+package catalog
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+func TestCSVReaderNext(t *testing.T) {
+	file := strings.Join([]string{
+		"sku,name,price,variant_sku,variant_size",
+		"TSHIRT,Tee,499,TS-S,S",
+		"TSHIRT,Tee,499,TS-M,M",
+		"MUG,Mug,199,,",
+		`BAD,Ba"d,1,,`,
+		"CAP,Cap,abc,,",
+		"PEN,Pen,10,,",
+	}, "\n")
+	reader, err := NewReader(FormatCSV, strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+
+	tests := []struct {
+		line       int
+		sku        string
+		price      int64
+		variants   []string
+		errorLines []int
+	}{
+		{line: 2, sku: "TSHIRT", price: 49900, variants: []string{"TS-S", "TS-M"}},
+		{line: 4, sku: "MUG", price: 19900},
+		// The bare quote is reported on its line and the file is read on
+		{line: 5, errorLines: []int{5}},
+		{line: 6, sku: "CAP", errorLines: []int{6}},
+		{line: 7, sku: "PEN", price: 1000},
+	}
+	for _, tt := range tests {
+		entry, err := reader.Next()
+		if err != nil {
+			t.Fatalf("Next at line %d: %v", tt.line, err)
+		}
+		if entry.Line != tt.line || entry.Item.SKU != tt.sku {
+			t.Errorf("Next() = %s on line %d, want %s on line %d", entry.Item.SKU, entry.Line, tt.sku, tt.line)
+		}
+		if tt.errorLines == nil && (entry.Item.Price.Amount != tt.price || entry.Item.Price.Currency != money.DefaultCurrency) {
+			t.Errorf("%s price = %v, want %d in %s", tt.sku, entry.Item.Price, tt.price, money.DefaultCurrency)
+		}
+
+		variants := make([]string, len(entry.Item.Variants))
+		for i, variant := range entry.Item.Variants {
+			variants[i] = variant.SKU
+		}
+		if strings.Join(variants, ",") != strings.Join(tt.variants, ",") {
+			t.Errorf("%s variants = %v, want %v", tt.sku, variants, tt.variants)
+		}
+
+		errorLines := make([]int, len(entry.Errors))
+		for i, fieldErr := range entry.Errors {
+			errorLines[i] = fieldErr.Line
+		}
+		if len(errorLines) != len(tt.errorLines) {
+			t.Errorf("line %d errors = %v, want them on lines %v", tt.line, entry.Errors, tt.errorLines)
+			continue
+		}
+		for i := range errorLines {
+			if errorLines[i] != tt.errorLines[i] {
+				t.Errorf("line %d errors = %v, want them on lines %v", tt.line, entry.Errors, tt.errorLines)
+				break
+			}
+		}
+	}
+
+	if _, err := reader.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("Next() after the last row = %v, want io.EOF", err)
+	}
+}
+
+func TestNewCSVReaderHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		wantErr bool
+	}{
+		{"sku only", "sku", false},
+		{"byte order mark and case", "\ufeffSKU, Name", false},
+		{"no sku", "name,price", true},
+		{"unknown column", "sku,colour", true},
+		{"repeated column", "sku,name,name", true},
+		{"empty file", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewReader(FormatCSV, strings.NewReader(tt.header))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewReader(%q) error = %v, want error %v", tt.header, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package catalog
+
+import (
+	"fmt"
+	"io"
+)
+
+// Reader reads the items of an import file one at a time
+type Reader interface {
+	// Next returns the next item, or io.EOF after the last one. Any other
+	// error means the rest of the file cannot be read; an item that cannot be
+	// read on its own is returned with its Errors set instead.
+	Next() (*Entry, error)
+}
+
+// Writer writes the items of an export file
+type Writer interface {
+	Write(item *Item) error
+	// Flush writes any buffered items to the underlying writer
+	Flush() error
+}
+
+// NewReader returns a reader for a file in the given format
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatJSONL:
+		return newJSONLReader(r), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+// NewWriter returns a writer for a file in the given format
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatJSONL:
+		return newJSONLWriter(w), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
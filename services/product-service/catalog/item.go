@@ -0,0 +1,226 @@
+// Package catalog reads and writes products for bulk import and export, as
+// CSV or as JSON Lines. Both formats carry the same Item: a product with its
+// variants, attributes and image URLs, identified by its SKU.
+package catalog
+
+import (
+	"errors"
+	"html"
+	"mime"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
+)
+
+// File formats
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// ErrUnknownFormat is returned for a format other than csv or jsonl
+var ErrUnknownFormat = errors.New("unknown catalog format")
+
+// Item is one product of an import or export. Images are URLs, the first of
+// which is the primary image. IDs are left out so that a file exported from
+// one store can be imported into another.
+type Item struct {
+	SKU           string      `json:"sku"`
+	Name          string      `json:"name"`
+	Slug          string      `json:"slug,omitempty"`
+	Description   string      `json:"description"`
+	Price         money.Money `json:"price"`
+	Discount      float64     `json:"discount"`
+	Stock         int         `json:"stock"`
+	Status        string      `json:"status,omitempty"`
+	CategoryID    uint64      `json:"category_id,omitempty"`
+	Brand         string      `json:"brand,omitempty"`
+	UoMID         *uint64     `json:"uom_id,omitempty"`
+	QuantityValue *float64    `json:"quantity_value,omitempty"`
+	Attributes    []Attribute `json:"attributes,omitempty"`
+	Images        []string    `json:"images,omitempty"`
+	Variants      []Variant   `json:"variants,omitempty"`
+}
+
+// Attribute is a product attribute such as material=cotton
+type Attribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Variant is a product variant such as a size or color
+type Variant struct {
+	SKU           string      `json:"sku,omitempty"`
+	Name          string      `json:"name"`
+	Price         money.Money `json:"price"`
+	Stock         int         `json:"stock"`
+	Size          *string     `json:"size,omitempty"`
+	Color         *string     `json:"color,omitempty"`
+	Weight        *string     `json:"weight,omitempty"`
+	QuantityValue float64     `json:"quantity_value,omitempty"`
+	UoMID         *uint64     `json:"uom_id,omitempty"`
+}
+
+// FieldError is a value in a file that could not be read or is not valid.
+// Field is empty when the error is about the whole line, and Line is zero
+// when it is the line the item starts on.
+type FieldError struct {
+	Line    int
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// Entry is an item read from a file. Line is the line it starts on and Errors
+// lists the values that could not be read; the item is not imported when
+// there are any.
+type Entry struct {
+	Line   int
+	Item   Item
+	Errors []FieldError
+}
+
+// NewItem builds the item for a product loaded with its attributes, variants
+// and images
+func NewItem(product *models.Product) Item {
+	item := Item{
+		SKU:           product.SKU,
+		Name:          product.Name,
+		Slug:          product.Slug,
+		Description:   html.UnescapeString(product.Description),
+		Price:         product.Price,
+		Discount:      product.Discount,
+		Stock:         product.Stock,
+		Status:        product.Status,
+		CategoryID:    product.CategoryID,
+		Brand:         product.Brand,
+		UoMID:         product.UoMID,
+		QuantityValue: product.QuantityValue,
+	}
+	for _, attribute := range product.Attributes {
+		item.Attributes = append(item.Attributes, Attribute{Key: attribute.Key, Value: attribute.Value})
+	}
+
+	images := make([]models.ProductImage, len(product.Images))
+	copy(images, product.Images)
+	sort.SliceStable(images, func(i, j int) bool { return images[i].IsPrimary && !images[j].IsPrimary })
+	for _, image := range images {
+		item.Images = append(item.Images, image.ImageURL)
+	}
+
+	for _, variant := range product.Variants {
+		item.Variants = append(item.Variants, Variant{
+			SKU:           variant.SKU,
+			Name:          variant.Name,
+			Price:         variant.Price,
+			Stock:         variant.Stock,
+			Size:          variant.Size,
+			Color:         variant.Color,
+			Weight:        variant.Weight,
+			QuantityValue: variant.QuantityValue,
+			UoMID:         variant.UoMID,
+		})
+	}
+	return item
+}
+
+// Product builds the product the item describes. Images get the product name
+// as their alt text, and the first one is made primary.
+func (item *Item) Product() *models.Product {
+	product := &models.Product{
+		Name:          strings.TrimSpace(item.Name),
+		Slug:          strings.TrimSpace(item.Slug),
+		Description:   item.Description,
+		Price:         item.Price,
+		Discount:      item.Discount,
+		Stock:         item.Stock,
+		SKU:           strings.TrimSpace(item.SKU),
+		Status:        item.Status,
+		CategoryID:    item.CategoryID,
+		Brand:         strings.TrimSpace(item.Brand),
+		UoMID:         item.UoMID,
+		QuantityValue: item.QuantityValue,
+	}
+	if product.Price.Currency == "" {
+		product.Price.Currency = money.DefaultCurrency
+	}
+
+	for _, attribute := range item.Attributes {
+		product.Attributes = append(product.Attributes, models.ProductAttribute{
+			Key:   strings.TrimSpace(attribute.Key),
+			Value: strings.TrimSpace(attribute.Value),
+		})
+	}
+
+	for _, url := range item.Images {
+		url = strings.TrimSpace(url)
+		if url == "" {
+			continue
+		}
+		primary := product.PrimaryImage == nil
+		if primary {
+			product.PrimaryImage = &url
+		}
+		product.Images = append(product.Images, models.ProductImage{
+			ImageURL:  url,
+			AltText:   product.Name,
+			IsPrimary: primary,
+		})
+	}
+
+	for _, variant := range item.Variants {
+		price := variant.Price
+		if price.Currency == "" {
+			price.Currency = product.Price.Currency
+		}
+		product.Variants = append(product.Variants, models.ProductVariant{
+			SKU:           strings.TrimSpace(variant.SKU),
+			Name:          strings.TrimSpace(variant.Name),
+			Price:         price,
+			Stock:         variant.Stock,
+			Size:          variant.Size,
+			Color:         variant.Color,
+			Weight:        variant.Weight,
+			QuantityValue: variant.QuantityValue,
+			UoMID:         variant.UoMID,
+		})
+	}
+	return product
+}
+
+// DetectFormat works out the format of an upload from its file name or,
+// failing that, its content type. It returns an empty string when neither
+// says.
+func DetectFormat(filename, contentType string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return FormatCSV
+	case "application/jsonl", "application/x-jsonlines", "application/x-ndjson", "application/ndjson":
+		return FormatJSONL
+	}
+	return ""
+}
+
+// ContentType is the media type a file of the format is served as
+func ContentType(format string) string {
+	if format == FormatJSONL {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
@@ -0,0 +1,69 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// maxJSONLLine is the longest line a JSON Lines file may have
+const maxJSONLLine = 1 << 20
+
+// jsonlReader reads one item per line. Blank lines are skipped.
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newJSONLReader(r io.Reader) *jsonlReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxJSONLLine)
+	return &jsonlReader{scanner: scanner}
+}
+
+func (r *jsonlReader) Next() (*Entry, error) {
+	for r.scanner.Scan() {
+		r.line++
+		data := bytes.TrimSpace(r.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		entry := &Entry{Line: r.line}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&entry.Item); err != nil {
+			entry.Errors = append(entry.Errors, FieldError{Message: fmt.Sprintf("invalid JSON: %v", err)})
+		} else if decoder.More() {
+			entry.Errors = append(entry.Errors, FieldError{Message: "invalid JSON: more than one value on the line"})
+		}
+		return entry, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", r.line+1, err)
+	}
+	return nil, io.EOF
+}
+
+// jsonlWriter writes one item per line
+type jsonlWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	buffer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	return &jsonlWriter{buffer: buffer, encoder: encoder}
+}
+
+func (w *jsonlWriter) Write(item *Item) error {
+	return w.encoder.Encode(item)
+}
+
+func (w *jsonlWriter) Flush() error {
+	return w.buffer.Flush()
+}
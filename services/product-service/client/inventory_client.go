@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/apiclient"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+)
+
+// InventoryClient handles communication with the inventory service
+type InventoryClient struct {
+	api *apiclient.Client
+}
+
+// NewInventoryClient creates a new inventory service client
+func NewInventoryClient(cfg *config.Config) *InventoryClient {
+	return &InventoryClient{
+		api: apiclient.New("inventory-service", cfg.Services.InventoryService.URL, cfg.Services.InventoryService.Timeout),
+	}
+}
+
+// GetInStockProductIDs returns the products with stock that is not reserved
+// in some warehouse. It calls inventory-service as the "system" role, since
+// no end user is behind the request.
+func (c *InventoryClient) GetInStockProductIDs(ctx context.Context) (map[uint64]bool, error) {
+	var productIDs []uint64
+	if err := c.api.Do(ctx, http.MethodGet, "/inventory/in-stock", apiclient.System, nil, &productIDs); err != nil {
+		return nil, err
+	}
+	inStock := make(map[uint64]bool, len(productIDs))
+	for _, id := range productIDs {
+		inStock[id] = true
+	}
+	return inStock, nil
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/apiclient"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+)
+
+// productOrderCount is one product's order count from order-service
+type productOrderCount struct {
+	ProductID uint64 `json:"product_id"`
+	Orders    int64  `json:"orders"`
+}
+
+// OrderClient handles communication with the order service
+type OrderClient struct {
+	api *apiclient.Client
+}
+
+// NewOrderClient creates a new order service client
+func NewOrderClient(cfg *config.Config) *OrderClient {
+	return &OrderClient{
+		api: apiclient.New("order-service", cfg.Services.OrderService.URL, cfg.Services.OrderService.Timeout),
+	}
+}
+
+// GetProductOrderCounts returns how many orders included each product over
+// the last days, keyed by product ID. It calls order-service as the "system"
+// role, since no end user is behind the request.
+func (c *OrderClient) GetProductOrderCounts(ctx context.Context, days int) (map[uint64]int64, error) {
+	path := fmt.Sprintf("/order/products/order-counts?days=%d", days)
+	var counts []productOrderCount
+	if err := c.api.Do(ctx, http.MethodGet, path, apiclient.System, nil, &counts); err != nil {
+		return nil, err
+	}
+	orders := make(map[uint64]int64, len(counts))
+	for _, count := range counts {
+		orders[count.ProductID] = count.Orders
+	}
+	return orders, nil
+}
@@ -72,7 +72,7 @@ func main() {
 	r.StaticFS("/image_gallery", gin.Dir(config.Storage.ImagePath, true))
 
 	r.Use(gin.Recovery())
-	routes.SetupRoutes(r, db)
+	routes.SetupRoutes(r, db, &config)
 
 	port := ":" + config.Services.ProductService.Port
 	if err := r.Run(port); err != nil {
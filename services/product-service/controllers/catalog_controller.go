@@ -0,0 +1,257 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/catalog"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/services"
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize is the largest file an import accepts
+const maxImportSize = 20 << 20
+
+type CatalogController struct {
+	service services.CatalogService
+}
+
+func NewCatalogController(service services.CatalogService) *CatalogController {
+	return &CatalogController{service: service}
+}
+
+// requireAdmin writes a 403 response and returns false when the caller is not an admin
+func (cc *CatalogController) requireAdmin(c *gin.Context, message string) bool {
+	if c.GetString("role") == utils.RoleAdmin {
+		return true
+	}
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusForbidden, utils.ErrorResponse(utils.ErrForbidden, message, nil, requestID))
+	return false
+}
+
+// readImportFile reads the uploaded file from the "file" field of a
+// multipart form, or else the whole request body, with its name and content type
+func readImportFile(c *gin.Context) ([]byte, string, string, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	if !strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		data, err := io.ReadAll(c.Request.Body)
+		return data, "", c.ContentType(), err
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, "", "", err
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, "", "", err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	return data, header.Filename, header.Header.Get("Content-Type"), err
+}
+
+// Import godoc
+// @Summary Import products in bulk
+// @Description Upload a CSV or JSON Lines file of products with their variants, attributes and image URLs, as the "file" field of a form or as the request body. Products are validated like ones created one at a time and upserted by SKU: an existing product has its variants updated by SKU, with new ones added and missing ones removed, its attributes replaced and new images added. The import runs in the background; poll the returned job for progress and per-row errors. With dry_run nothing is saved. CSV files have a header row and one row per variant; rows with the same sku in a row are one product, prices are in major units, attributes are key=value;key=value and images are URLs separated by |. Admin only.
+// @Tags products
+// @Accept mpfd
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Param file formData file false "CSV or JSON Lines file"
+// @Param format query string false "csv or jsonl, when the file name or content type does not say"
+// @Param dry_run query bool false "Validate without saving"
+// @Success 202 {object} models.ImportJob
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /products/import [post]
+func (cc *CatalogController) Import(c *gin.Context) {
+	if !cc.requireAdmin(c, "Only admins can import products") {
+		return
+	}
+
+	var req models.ImportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		validationErrors := utils.ParseValidationErrors(err.Error())
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return
+	}
+
+	data, filename, contentType, err := readImportFile(c)
+	if err != nil {
+		requestID := utils.GenerateRequestID()
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			message := fmt.Sprintf("The file is larger than %d MB", maxImportSize>>20)
+			c.JSON(http.StatusRequestEntityTooLarge, utils.ErrorResponse(utils.ErrInvalidInput, message, nil, requestID))
+			return
+		}
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, "Failed to read the import file", err.Error(), requestID))
+		return
+	}
+	if len(data) == 0 {
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, "The import file is empty", nil, requestID))
+		return
+	}
+
+	format := req.Format
+	if format == "" {
+		format = catalog.DetectFormat(filename, contentType)
+	}
+	if format == "" {
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidFormat, "Unknown file format", "Pass format=csv or format=jsonl", requestID))
+		return
+	}
+
+	job, err := cc.service.StartImport(data, format, req.DryRun, uint64(c.GetUint("user_id")))
+	if err != nil {
+		requestID := utils.GenerateRequestID()
+		if errors.Is(err, services.ErrInvalidImport) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidFormat, "Invalid import file", err.Error(), requestID))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to start import", err.Error(), requestID))
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusAccepted, utils.SuccessResponse(job, "Import started", requestID))
+}
+
+// GetImportJob godoc
+// @Summary Get an import job
+// @Description Get the progress of an import and why each rejected product was rejected. Admin only.
+// @Tags products
+// @Produce json
+// @Param id path int true "Import job ID"
+// @Success 200 {object} models.ImportJob
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /products/imports/{id} [get]
+func (cc *CatalogController) GetImportJob(c *gin.Context) {
+	if !cc.requireAdmin(c, "Only admins can view imports") {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, "Invalid import job ID", err.Error(), requestID))
+		return
+	}
+
+	job, err := cc.service.GetImportJob(id)
+	if err != nil {
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(utils.ErrNotFound, "Import job not found", "Import job not found", requestID))
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(job, "Import job retrieved successfully", requestID))
+}
+
+// GetImportJobs godoc
+// @Summary List import jobs
+// @Description List imports, newest first, without their row errors. Admin only.
+// @Tags products
+// @Produce json
+// @Param page query int false "Page number" default=1
+// @Param limit query int false "Items per page" default=10
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /products/imports [get]
+func (cc *CatalogController) GetImportJobs(c *gin.Context) {
+	if !cc.requireAdmin(c, "Only admins can view imports") {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	jobs, total, err := cc.service.GetImportJobs(limit, (page-1)*limit)
+	if err != nil {
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to fetch import jobs", err.Error(), requestID))
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(map[string]interface{}{
+		"data":       jobs,
+		"page":       page,
+		"limit":      limit,
+		"total":      total,
+		"totalPages": int((total + int64(limit) - 1) / int64(limit)),
+	}, "Import jobs retrieved successfully", requestID))
+}
+
+// Export godoc
+// @Summary Export products
+// @Description Stream every product, or those with a status, with their variants, attributes and image URLs as CSV or JSON Lines, in the format the import accepts. Admin only.
+// @Tags products
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param format query string false "csv (default) or jsonl"
+// @Param status query string false "active, inactive or draft"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /products/export [get]
+func (cc *CatalogController) Export(c *gin.Context) {
+	if !cc.requireAdmin(c, "Only admins can export products") {
+		return
+	}
+
+	var req models.ExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		validationErrors := utils.ParseValidationErrors(err.Error())
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return
+	}
+	if req.Format == "" {
+		req.Format = catalog.FormatCSV
+	}
+
+	filename := fmt.Sprintf("products-%s.%s", time.Now().Format("20060102-150405"), req.Format)
+	c.Header("Content-Type", catalog.ContentType(req.Format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	if err := cc.service.Export(c.Request.Context(), req.Format, req.Status, c.Writer); err != nil {
+		if c.Writer.Written() {
+			// The status has been sent, so all that can be done is to cut the file short
+			log.Printf("Product export failed part way: %v", err)
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to export products", err.Error(), requestID))
+	}
+}
@@ -54,6 +54,8 @@ func (c *FrontendController) GetProductData(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": products})
 }
 
+// ProductSearch answers the older product search from the search index, with
+// full products in the response the storefront expects. New clients use /frontend/search.
 func (c *FrontendController) ProductSearch(ctx *gin.Context) {
 
 	search := ctx.Query("query")
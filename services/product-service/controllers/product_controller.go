@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
 	"github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
 	"github.com/DurgaPratapRajbhar/e-commerce/product-service/services"
@@ -31,6 +32,7 @@ func NewProductController(productService services.ProductService) *ProductContro
 		match, err := regexp.MatchString(pattern, fl.Field().String())
 		return err == nil && match
 	})
+	validate.RegisterCustomTypeFunc(money.ValidationValue, money.Money{})
 
 	return &ProductController{
 		productService: productService,
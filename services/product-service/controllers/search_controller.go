@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/services"
+	"github.com/gin-gonic/gin"
+)
+
+type SearchController struct {
+	service services.SearchService
+}
+
+func NewSearchController(service services.SearchService) *SearchController {
+	return &SearchController{service: service}
+}
+
+// Search godoc
+// @Summary Search products
+// @Description Full-text search over active products, ranked by relevance, with highlighted snippets and facet counts for category, brand, price range and attribute values. Repeat category_id, brand and attr to match any of several values.
+// @Tags search
+// @Produce json
+// @Param q query string false "Search text"
+// @Param category_id query []int false "Category IDs" collectionFormat(multi)
+// @Param brand query []string false "Brands" collectionFormat(multi)
+// @Param min_price query int false "Lowest price in minor units"
+// @Param max_price query int false "Highest price in minor units"
+// @Param attr query []string false "Attribute filters as key:value" collectionFormat(multi)
+// @Param in_stock query bool false "Only products in stock"
+// @Param sort query string false "relevance, price_asc, price_desc, newest or name"
+// @Param limit query int false "Hits per page, up to 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} search.Result
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /frontend/search [get]
+func (sc *SearchController) Search(c *gin.Context) {
+	var req models.SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		validationErrors := utils.ParseValidationErrors(err.Error())
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return
+	}
+
+	result, err := sc.service.Search(&req)
+	if err != nil {
+		requestID := utils.GenerateRequestID()
+		if errors.Is(err, services.ErrInvalidSearch) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrValidationFailed, "Invalid search", err.Error(), requestID))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to search products", err.Error(), requestID))
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(result, "Products retrieved successfully", requestID))
+}
+
+// Suggest godoc
+// @Summary Suggest search text
+// @Description Complete what the shopper has typed from product names, brands and category names. The text is matched from the start of any word, with typos allowed in longer text, and suggestions are ranked by how often the products were ordered.
+// @Tags search
+// @Produce json
+// @Param q query string true "Text typed so far"
+// @Param limit query int false "Suggestions to return, up to 20"
+// @Success 200 {array} search.Suggestion
+// @Failure 400 {object} map[string]string
+// @Router /frontend/suggest [get]
+func (sc *SearchController) Suggest(c *gin.Context) {
+	var req models.SuggestRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		validationErrors := utils.ParseValidationErrors(err.Error())
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+		return
+	}
+
+	suggestions := sc.service.Suggest(&req)
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(suggestions, "Suggestions retrieved successfully", requestID))
+}
+
+// Reindex godoc
+// @Summary Rebuild the search index
+// @Description Index every active product afresh from the database. Admin only.
+// @Tags search
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /products/search/reindex [post]
+func (sc *SearchController) Reindex(c *gin.Context) {
+	if c.GetString("role") != utils.RoleAdmin {
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusForbidden, utils.ErrorResponse(utils.ErrForbidden, "Only admins can rebuild the search index", nil, requestID))
+		return
+	}
+
+	count, err := sc.service.Rebuild()
+	if err != nil {
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to rebuild search index", err.Error(), requestID))
+		return
+	}
+
+	requestID := utils.GenerateRequestID()
+	c.JSON(http.StatusOK, utils.SuccessResponse(gin.H{"indexed": count}, "Search index rebuilt successfully", requestID))
+}
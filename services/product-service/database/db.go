@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/logger"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/moneydb"
 
 	"github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
 
@@ -58,11 +59,20 @@ func Migrate(db *gorm.DB) error {
 		&models.ProductVariant{},
 		&models.UnitOfMeasurement{},
 		&models.ProductAttribute{},
+		&models.ImportJob{},
+		&models.ImportRowError{},
 	); err != nil {
 		logger.Logger.Error("Error migrating MySQL database:", err)
 		return fmt.Errorf("error migrating MySQL database: %w", err)
 	}
 
+	for _, table := range []string{"products", "product_variants"} {
+		if err := moneydb.MigrateLegacyAmount(db.WithContext(ctx), table, "price", "price_"); err != nil {
+			logger.Logger.Error("Error migrating legacy price column:", err)
+			return fmt.Errorf("error migrating %s.price: %w", table, err)
+		}
+	}
+
 	logger.Logger.Info("Database migrated successfully.")
 	return nil
 }
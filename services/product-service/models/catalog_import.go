@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Import job statuses
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+// ImportJob is a bulk product import running in the background. Products
+// counts the products read from the file, of which Created and Updated were
+// saved, or would have been in a dry run, and Failed were rejected. Error is
+// set when the file could not be read to the end.
+type ImportJob struct {
+	ID              uint64           `gorm:"primaryKey;autoIncrement" json:"id"`
+	Format          string           `gorm:"type:varchar(10);not null" json:"format"`
+	DryRun          bool             `gorm:"not null;default:false" json:"dry_run"`
+	Status          string           `gorm:"type:varchar(20);not null;index" json:"status"`
+	Products        int              `gorm:"not null;default:0" json:"products"`
+	Created         int              `gorm:"not null;default:0" json:"created"`
+	Updated         int              `gorm:"not null;default:0" json:"updated"`
+	Failed          int              `gorm:"not null;default:0" json:"failed"`
+	ErrorsTruncated bool             `gorm:"not null;default:false" json:"errors_truncated"`
+	Error           string           `gorm:"type:text" json:"error,omitempty"`
+	CreatedBy       uint64           `gorm:"index" json:"created_by"`
+	StartedAt       *time.Time       `json:"started_at,omitempty"`
+	FinishedAt      *time.Time       `json:"finished_at,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+	Errors          []ImportRowError `gorm:"foreignKey:JobID;references:ID" json:"errors,omitempty"`
+}
+
+// ImportRowError is why a product in an import file was rejected. Line is
+// the line of the file the error is on and Field the column or JSON field,
+// empty when the error is about the whole line.
+type ImportRowError struct {
+	ID      uint64 `gorm:"primaryKey;autoIncrement" json:"-"`
+	JobID   uint64 `gorm:"index;not null" json:"-"`
+	Line    int    `gorm:"not null" json:"line"`
+	SKU     string `gorm:"type:varchar(100)" json:"sku,omitempty"`
+	Field   string `gorm:"type:varchar(100)" json:"field,omitempty"`
+	Message string `gorm:"type:text;not null" json:"message"`
+}
+
+func (ImportJob) TableName() string      { return "product_import_jobs" }
+func (ImportRowError) TableName() string { return "product_import_errors" }
+
+func (j *ImportJob) BeforeCreate(tx *gorm.DB) (err error) {
+	j.CreatedAt = time.Now()
+	j.UpdatedAt = time.Now()
+	return nil
+}
+
+func (j *ImportJob) BeforeUpdate(tx *gorm.DB) (err error) {
+	j.UpdatedAt = time.Now()
+	return nil
+}
+
+// ImportRequest is the query string of a product import. Format may be left
+// out when the file name or content type says which it is.
+type ImportRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv jsonl"`
+	DryRun bool   `form:"dry_run"`
+}
+
+// ExportRequest is the query string of a product export
+type ExportRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv jsonl"`
+	Status string `form:"status" binding:"omitempty,oneof=active inactive draft"`
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
 	"github.com/go-playground/validator/v10"
 	"gorm.io/gorm"
 )
@@ -16,7 +17,7 @@ type Product struct {
 	Name          string             `gorm:"type:varchar(255);not null" json:"name" validate:"required,min=3,max=255"`
 	Slug          string             `gorm:"type:varchar(255);unique;not null" json:"slug" validate:"required,regexp=^[a-z0-9]+(?:-[a-z0-9]+)*$"`
 	Description   string             `gorm:"type:text" json:"description" validate:"required,min=5"`
-	Price         float64            `gorm:"type:decimal(10,2);not null" json:"price" validate:"required,gt=0"`
+	Price         money.Money        `gorm:"embedded;embeddedPrefix:price_" json:"price" validate:"required,gt=0"`
 	Discount      float64            `gorm:"type:decimal(5,2);default:0" json:"discount" validate:"gte=0,lte=100"`
 	Stock         int                `gorm:"type:int;not null" json:"stock" validate:"gte=0"`
 	SKU           string             `gorm:"type:varchar(100);unique;not null" json:"sku" validate:"required"`
@@ -47,7 +48,7 @@ type ProductVariant struct {
 	ProductID     uint64            `gorm:"index;not null" json:"product_id"`
 	UoMID         *uint64           `gorm:"index" json:"uom_id,omitempty"`
 	Name          string            `gorm:"type:varchar(100)" json:"name" validate:"required"`
-	Price         float64           `gorm:"type:decimal(10,2);not null" json:"price" validate:"gt=0"`
+	Price         money.Money       `gorm:"embedded;embeddedPrefix:price_" json:"price" validate:"gt=0"`
 	Stock         int               `gorm:"type:int;not null" json:"stock" validate:"gte=0"`
 	SKU           string            `gorm:"type:varchar(100); " json:"sku"`
 	QuantityValue float64           `gorm:"type:decimal(10,2)" json:"quantity_value,omitempty" validate:"omitempty,gte=0"`
@@ -66,15 +67,15 @@ func (ProductVariant) TableName() string   { return "product_variants" }
 // Validation
 func (p *Product) ValidateBasic(validate *validator.Validate) error {
 	type ProductValidation struct {
-		Name          string   `validate:"required,min=3,max=255"`
-		Slug          string   `validate:"required,regexp=^[a-z0-9]+(?:-[a-z0-9]+)*$"`
-		Description   string   `validate:"required,min=5"`
-		Price         float64  `validate:"required,gt=0"`
-		Discount      float64  `validate:"gte=0,lte=100"`
-		Stock         int      `validate:"gte=0"`
-		SKU           string   `validate:"required"`
-		Status        string   `validate:"oneof=active inactive draft"`
-		QuantityValue *float64 `validate:"omitempty,gte=0"`
+		Name          string      `validate:"required,min=3,max=255"`
+		Slug          string      `validate:"required,regexp=^[a-z0-9]+(?:-[a-z0-9]+)*$"`
+		Description   string      `validate:"required,min=5"`
+		Price         money.Money `validate:"required,gt=0"`
+		Discount      float64     `validate:"gte=0,lte=100"`
+		Stock         int         `validate:"gte=0"`
+		SKU           string      `validate:"required"`
+		Status        string      `validate:"oneof=active inactive draft"`
+		QuantityValue *float64    `validate:"omitempty,gte=0"`
 	}
 
 	validate.RegisterValidation("regexp", func(fl validator.FieldLevel) bool {
@@ -82,6 +83,7 @@ func (p *Product) ValidateBasic(validate *validator.Validate) error {
 		match, err := regexp.MatchString(pattern, fl.Field().String())
 		return err == nil && match
 	})
+	validate.RegisterCustomTypeFunc(money.ValidationValue, money.Money{})
 
 	pv := ProductValidation{
 		Name:          strings.TrimSpace(p.Name),
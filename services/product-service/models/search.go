@@ -0,0 +1,22 @@
+package models
+
+// SearchRequest is the query string of a product search. Prices are in minor
+// units and attribute filters are written key:value, for example color:Red.
+type SearchRequest struct {
+	Query       string   `form:"q" binding:"max=200"`
+	CategoryIDs []uint64 `form:"category_id"`
+	Brands      []string `form:"brand"`
+	MinPrice    *int64   `form:"min_price" binding:"omitempty,gte=0"`
+	MaxPrice    *int64   `form:"max_price" binding:"omitempty,gte=0"`
+	Attributes  []string `form:"attr"`
+	InStock     bool     `form:"in_stock"`
+	Sort        string   `form:"sort" binding:"omitempty,oneof=relevance price_asc price_desc newest name"`
+	Limit       int      `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Cursor      string   `form:"cursor"`
+}
+
+// SuggestRequest is the query string of a search suggestion request
+type SuggestRequest struct {
+	Query string `form:"q" binding:"required,max=100"`
+	Limit int    `form:"limit" binding:"omitempty,gte=1,lte=20"`
+}
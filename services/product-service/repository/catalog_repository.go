@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
+)
+
+// ErrSlugTaken is returned when an imported product's slug belongs to a product with another SKU
+var ErrSlugTaken = errors.New("slug is already used by another product")
+
+// CatalogRepository saves imported products, loads products for export and
+// keeps track of import jobs
+type CatalogRepository interface {
+	// UpsertProduct creates the product with its variants, attributes and
+	// images, or, when a product with its SKU exists, updates that one,
+	// updating its variants by SKU, replacing its attributes and adding the
+	// images it does not have. In a dry run the writes are rolled back, so that they are checked
+	// by the database without being kept. It reports whether the product was new.
+	UpsertProduct(product *models.Product, dryRun bool) (bool, error)
+	// GetExportProducts returns up to limit products with IDs above afterID,
+	// in ID order, with their attributes, variants and images. An empty status
+	// returns products of every status.
+	GetExportProducts(afterID uint64, limit int, status string) ([]models.Product, error)
+	CategoryExists(id uint64) (bool, error)
+	UnitExists(id uint64) (bool, error)
+
+	CreateImportJob(job *models.ImportJob) error
+	UpdateImportJob(job *models.ImportJob) error
+	AddImportRowErrors(rowErrors []models.ImportRowError) error
+	// GetImportJob returns a job with its row errors in line order
+	GetImportJob(id uint64) (*models.ImportJob, error)
+	GetImportJobs(limit, offset int) ([]models.ImportJob, int64, error)
+	// FailUnfinishedImportJobs marks the jobs left pending or running by a
+	// restart as failed and returns how many there were
+	FailUnfinishedImportJobs() (int64, error)
+}
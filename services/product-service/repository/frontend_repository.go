@@ -5,5 +5,6 @@ import "github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
 type FrontendRepository interface {
 	GetProductData(slug string) ([]models.Product, error)
 	GetProductsByCategorySlug(slug string) ([]models.Product, error)
-	ProductSearch(search string) ([]models.Product, error)
+	// GetActiveProductsByIDs returns the active products among ids with their images and category
+	GetActiveProductsByIDs(ids []uint64) ([]models.Product, error)
 }
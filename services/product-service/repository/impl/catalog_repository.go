@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errDryRun rolls back the transaction of a dry run
+var errDryRun = errors.New("dry run")
+
+type catalogRepository struct {
+	db *gorm.DB
+}
+
+func NewCatalogRepository(db *gorm.DB) repository.CatalogRepository {
+	return &catalogRepository{db: db}
+}
+
+func (r *catalogRepository) UpsertProduct(product *models.Product, dryRun bool) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.Product
+		err := tx.Where("sku = ?", product.SKU).First(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to fetch product: %v", err)
+		}
+		created = err != nil
+
+		slugOwners := tx.Model(&models.Product{}).Where("slug = ?", product.Slug)
+		if !created {
+			slugOwners = slugOwners.Where("id <> ?", existing.ID)
+		}
+		var taken int64
+		if err := slugOwners.Count(&taken).Error; err != nil {
+			return fmt.Errorf("failed to check slug: %v", err)
+		}
+		if taken > 0 {
+			return fmt.Errorf("%w: %q", repository.ErrSlugTaken, product.Slug)
+		}
+
+		if created {
+			if err := tx.Create(product).Error; err != nil {
+				return fmt.Errorf("failed to create product: %v", err)
+			}
+		} else if err := r.updateProduct(tx, &existing, product); err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		err = nil
+	}
+	return created, err
+}
+
+// updateProduct overwrites an existing product with an imported one. The
+// images and primary image are left alone when the import has none.
+func (r *catalogRepository) updateProduct(tx *gorm.DB, existing, product *models.Product) error {
+	product.ID = existing.ID
+	updates := map[string]interface{}{
+		"Name":           product.Name,
+		"Slug":           product.Slug,
+		"Description":    html.EscapeString(strings.TrimSpace(product.Description)),
+		"price_minor":    product.Price.Amount,
+		"price_currency": product.Price.Currency,
+		"Discount":       product.Discount,
+		"Stock":          product.Stock,
+		"Status":         product.Status,
+		"Brand":          product.Brand,
+		"CategoryID":     product.CategoryID,
+		"UoMID":          product.UoMID,
+		"QuantityValue":  product.QuantityValue,
+	}
+	if product.PrimaryImage != nil {
+		updates["PrimaryImage"] = product.PrimaryImage
+	}
+	if err := tx.Model(existing).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update product fields: %v", err)
+	}
+
+	if err := r.upsertVariants(tx, existing.ID, product.Variants); err != nil {
+		return err
+	}
+
+	// Replace attributes
+	if err := tx.Where("product_id = ?", existing.ID).Delete(&models.ProductAttribute{}).Error; err != nil {
+		return fmt.Errorf("failed to delete existing attributes: %v", err)
+	}
+	for i := range product.Attributes {
+		product.Attributes[i].ProductID = existing.ID
+		if err := tx.Create(&product.Attributes[i]).Error; err != nil {
+			return fmt.Errorf("failed to create attribute %d: %v", i, err)
+		}
+	}
+
+	if len(product.Images) == 0 {
+		return nil
+	}
+	var urls []string
+	if err := tx.Model(&models.ProductImage{}).Where("product_id = ?", existing.ID).Pluck("image_url", &urls).Error; err != nil {
+		return fmt.Errorf("failed to fetch images: %v", err)
+	}
+	known := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		known[url] = true
+	}
+	for i := range product.Images {
+		image := &product.Images[i]
+		if known[image.ImageURL] {
+			continue
+		}
+		known[image.ImageURL] = true
+		image.ProductID = existing.ID
+		image.IsPrimary = false
+		if err := tx.Create(image).Error; err != nil {
+			return fmt.Errorf("failed to create image %d: %v", i, err)
+		}
+	}
+
+	// The first image of the import becomes the primary one
+	if err := tx.Model(&models.ProductImage{}).Where("product_id = ?", existing.ID).Update("is_primary", false).Error; err != nil {
+		return fmt.Errorf("failed to update images: %v", err)
+	}
+	err := tx.Model(&models.ProductImage{}).
+		Where("product_id = ? AND image_url = ?", existing.ID, *product.PrimaryImage).
+		Update("is_primary", true).Error
+	if err != nil {
+		return fmt.Errorf("failed to update images: %v", err)
+	}
+	return nil
+}
+
+// upsertVariants updates the variants of a product that match an imported
+// one by SKU, keeping their IDs, creates the rest and deletes the variants
+// the import left out
+func (r *catalogRepository) upsertVariants(tx *gorm.DB, productID uint64, variants []models.ProductVariant) error {
+	var current []models.ProductVariant
+	if err := tx.Where("product_id = ?", productID).Find(&current).Error; err != nil {
+		return fmt.Errorf("failed to fetch variants: %v", err)
+	}
+
+	stale := matchVariants(current, variants)
+	for i := range variants {
+		variant := &variants[i]
+		variant.ProductID = productID
+		if variant.ID != 0 {
+			if err := tx.Save(variant).Error; err != nil {
+				return fmt.Errorf("failed to update variant %d: %v", i, err)
+			}
+			continue
+		}
+		if err := tx.Create(variant).Error; err != nil {
+			return fmt.Errorf("failed to create variant %d: %v", i, err)
+		}
+	}
+
+	if len(stale) > 0 {
+		if err := tx.Delete(&models.ProductVariant{}, stale).Error; err != nil {
+			return fmt.Errorf("failed to delete old variants: %v", err)
+		}
+	}
+	return nil
+}
+
+// matchVariants gives each imported variant the ID of the current variant
+// with its SKU, or zero when it is new, and returns the IDs of the current
+// variants left over. Variants without a SKU are always new, and a SKU
+// repeated in the import only updates one variant.
+func matchVariants(current, variants []models.ProductVariant) []uint64 {
+	bySKU := make(map[string]uint64, len(current))
+	for _, variant := range current {
+		if variant.SKU != "" {
+			bySKU[variant.SKU] = variant.ID
+		}
+	}
+
+	kept := make(map[uint64]bool, len(variants))
+	for i := range variants {
+		variant := &variants[i]
+		variant.ID = 0
+		if id, ok := bySKU[variant.SKU]; ok && variant.SKU != "" && !kept[id] {
+			variant.ID = id
+			kept[id] = true
+		}
+	}
+
+	var stale []uint64
+	for _, variant := range current {
+		if !kept[variant.ID] {
+			stale = append(stale, variant.ID)
+		}
+	}
+	return stale
+}
+
+func (r *catalogRepository) GetExportProducts(afterID uint64, limit int, status string) ([]models.Product, error) {
+	query := r.db.
+		Preload("Attributes", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Images", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("id > ?", afterID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var products []models.Product
+	if err := query.Order("id").Limit(limit).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *catalogRepository) CategoryExists(id uint64) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.Category{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *catalogRepository) UnitExists(id uint64) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.UnitOfMeasurement{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *catalogRepository) CreateImportJob(job *models.ImportJob) error {
+	return r.db.Create(job).Error
+}
+
+func (r *catalogRepository) UpdateImportJob(job *models.ImportJob) error {
+	return r.db.Omit(clause.Associations).Save(job).Error
+}
+
+func (r *catalogRepository) AddImportRowErrors(rowErrors []models.ImportRowError) error {
+	if len(rowErrors) == 0 {
+		return nil
+	}
+	return r.db.Create(&rowErrors).Error
+}
+
+func (r *catalogRepository) GetImportJob(id uint64) (*models.ImportJob, error) {
+	var job models.ImportJob
+	err := r.db.
+		Preload("Errors", func(db *gorm.DB) *gorm.DB { return db.Order("line, id") }).
+		Where("id = ?", id).
+		First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *catalogRepository) GetImportJobs(limit, offset int) ([]models.ImportJob, int64, error) {
+	var jobs []models.ImportJob
+	var total int64
+
+	if err := r.db.Model(&models.ImportJob{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := r.db.Order("id DESC").Limit(limit).Offset(offset).Find(&jobs).Error; err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+func (r *catalogRepository) FailUnfinishedImportJobs() (int64, error) {
+	result := r.db.Model(&models.ImportJob{}).
+		Where("status IN ?", []string{models.ImportStatusPending, models.ImportStatusRunning}).
+		Updates(map[string]interface{}{
+			"status":      models.ImportStatusFailed,
+			"error":       "the service restarted before the import finished",
+			"finished_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
+)
+
+func TestMatchVariants(t *testing.T) {
+	current := []models.ProductVariant{
+		{ID: 10, SKU: "TS-S"},
+		{ID: 11, SKU: "TS-M"},
+		{ID: 12, SKU: ""},
+	}
+	tests := []struct {
+		name      string
+		skus      []string
+		wantIDs   []uint64
+		wantStale []uint64
+	}{
+		{"same variants", []string{"TS-S", "TS-M"}, []uint64{10, 11}, []uint64{12}},
+		{"reordered", []string{"TS-M", "TS-S"}, []uint64{11, 10}, []uint64{12}},
+		{"new and removed", []string{"TS-S", "TS-L"}, []uint64{10, 0}, []uint64{11, 12}},
+		{"no sku is always new", []string{""}, []uint64{0}, []uint64{10, 11, 12}},
+		{"repeated sku updates one variant", []string{"TS-S", "TS-S"}, []uint64{10, 0}, []uint64{11, 12}},
+		{"none", nil, []uint64{}, []uint64{10, 11, 12}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variants := make([]models.ProductVariant, len(tt.skus))
+			for i, sku := range tt.skus {
+				// IDs sent with the import are not trusted
+				variants[i] = models.ProductVariant{ID: 99, SKU: sku}
+			}
+
+			stale := matchVariants(current, variants)
+
+			ids := make([]uint64, len(variants))
+			for i, variant := range variants {
+				ids[i] = variant.ID
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.wantIDs) {
+				t.Errorf("matchVariants(%v) IDs = %v, want %v", tt.skus, ids, tt.wantIDs)
+			}
+			if fmt.Sprint(stale) != fmt.Sprint(tt.wantStale) {
+				t.Errorf("matchVariants(%v) stale = %v, want %v", tt.skus, stale, tt.wantStale)
+			}
+		})
+	}
+}
@@ -98,22 +98,16 @@ func (r *frontendRepository) GetProductData(slug string) ([]models.Product, erro
 	return products, nil
 }
 
-func (r *frontendRepository) ProductSearch(search string) ([]models.Product, error) {
+func (r *frontendRepository) GetActiveProductsByIDs(ids []uint64) ([]models.Product, error) {
 	var products []models.Product
+	if len(ids) == 0 {
+		return products, nil
+	}
 
-	query := r.db.
+	err := r.db.
 		Preload("Images").
 		Preload("Category").
-		Joins("LEFT JOIN categories ON categories.id = products.category_id").
-		Where("products.status = ?", "active")
-
-	if search != "" {
-		query = query.Where("products.name LIKE ? OR products.description LIKE ? OR categories.name LIKE ?", "%"+search+"%", "%"+search+"%", "%"+search+"%")
-	}
-
-	if err := query.Find(&products).Error; err != nil {
-		return nil, err
-	}
-
-	return products, nil
+		Where("id IN ? AND status = ?", ids, "active").
+		Find(&products).Error
+	return products, err
 }
@@ -131,20 +131,21 @@ func (r *productRepository) UpdateProduct(id uint, product *models.Product) erro
 
 	// Update scalar fields, including zero values
 	updates := map[string]interface{}{
-		"Name":          product.Name,
-		"Slug":          product.Slug,
-		"Description":   product.Description,
-		"Price":         product.Price,
-		"Discount":      product.Discount,
-		"Stock":         product.Stock,
-		"SKU":           product.SKU,
-		"Status":        product.Status,
-		"Brand":         product.Brand,
-		"CategoryID":    product.CategoryID,
-		"UoMID":         product.UoMID,
-		"QuantityValue": product.QuantityValue, // Handles nil correctly
-		"PrimaryImage":  product.PrimaryImage,
-		"UpdatedAt":     product.UpdatedAt,
+		"Name":           product.Name,
+		"Slug":           product.Slug,
+		"Description":    product.Description,
+		"price_minor":    product.Price.Amount,
+		"price_currency": product.Price.Currency,
+		"Discount":       product.Discount,
+		"Stock":          product.Stock,
+		"SKU":            product.SKU,
+		"Status":         product.Status,
+		"Brand":          product.Brand,
+		"CategoryID":     product.CategoryID,
+		"UoMID":          product.UoMID,
+		"QuantityValue":  product.QuantityValue, // Handles nil correctly
+		"PrimaryImage":   product.PrimaryImage,
+		"UpdatedAt":      product.UpdatedAt,
 	}
 
 	// Use a transaction for atomicity
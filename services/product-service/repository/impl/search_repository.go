@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/repository"
+
+	"gorm.io/gorm"
+)
+
+type searchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) repository.SearchRepository {
+	return &searchRepository{db: db}
+}
+
+func (r *searchRepository) preload() *gorm.DB {
+	return r.db.
+		Preload("Category").
+		Preload("Attributes").
+		Preload("Variants").
+		Preload("Images")
+}
+
+func (r *searchRepository) GetIndexableProducts(afterID uint64, limit int) ([]models.Product, error) {
+	var products []models.Product
+	err := r.preload().
+		Where("id > ? AND status = ?", afterID, "active").
+		Order("id").
+		Limit(limit).
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *searchRepository) GetIndexableProduct(id uint64) (*models.Product, error) {
+	var product models.Product
+	if err := r.preload().Where("id = ?", id).First(&product).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *searchRepository) GetProductIDsByCategory(categoryID uint64) ([]uint64, error) {
+	var ids []uint64
+	if err := r.db.Model(&models.Product{}).Where("category_id = ?", categoryID).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
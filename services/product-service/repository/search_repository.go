@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
+
+// SearchRepository loads products with everything the search index needs
+type SearchRepository interface {
+	// GetIndexableProducts returns up to limit active products with IDs above afterID, in ID order
+	GetIndexableProducts(afterID uint64, limit int) ([]models.Product, error)
+	GetIndexableProduct(id uint64) (*models.Product, error)
+	GetProductIDsByCategory(categoryID uint64) ([]uint64, error)
+}
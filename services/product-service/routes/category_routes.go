@@ -8,13 +8,14 @@ import (
 	"gorm.io/gorm"
 
 	repository "github.com/DurgaPratapRajbhar/e-commerce/product-service/repository/impl"
+	servicesapi "github.com/DurgaPratapRajbhar/e-commerce/product-service/services"
 	services "github.com/DurgaPratapRajbhar/e-commerce/product-service/services/impl"
 )
 
-func CategoryRoutes(r *gin.RouterGroup, db *gorm.DB) {
+func CategoryRoutes(r *gin.RouterGroup, db *gorm.DB, indexer servicesapi.ProductIndexer) {
 
 	catRepo := repository.NewCategoryRepository(db)
-	catService := services.NewCategoryService(catRepo)
+	catService := services.NewCategoryService(catRepo, indexer)
 
 	Controller := controllers.NewCategoryController(catService)
 	userGroup := r.Group("/categories")
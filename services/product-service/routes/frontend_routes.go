@@ -7,14 +7,16 @@ import (
 	"gorm.io/gorm"
 
 	repository "github.com/DurgaPratapRajbhar/e-commerce/product-service/repository/impl"
+	servicesapi "github.com/DurgaPratapRajbhar/e-commerce/product-service/services"
 	services "github.com/DurgaPratapRajbhar/e-commerce/product-service/services/impl"
 )
 
-func FrontendRoutes(r *gin.RouterGroup, db *gorm.DB) {
+func FrontendRoutes(r *gin.RouterGroup, db *gorm.DB, searchService servicesapi.SearchService) {
 
 	prodRepo := repository.NewFrontendRepository(db)
-	prodService := services.NewFrontendService(prodRepo)
+	prodService := services.NewFrontendService(prodRepo, searchService)
 	prodController := controllers.NewFrontendController(prodService)
+	searchController := controllers.NewSearchController(searchService)
 
 	if prodController == nil {
 		panic("prodController is nil in FrontendRoutes")
@@ -26,6 +28,8 @@ func FrontendRoutes(r *gin.RouterGroup, db *gorm.DB) {
 		productGroup.GET("/category/:slug", prodController.GetProductsByCategorySlug)
 		productGroup.GET("/product/:slug", prodController.GetProductData)
 		productGroup.GET("/products", prodController.ProductSearch)
+		productGroup.GET("/search", searchController.Search)
+		productGroup.GET("/suggest", searchController.Suggest)
 
 	}
 }
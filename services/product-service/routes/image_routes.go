@@ -7,13 +7,14 @@ import (
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/middleware"
 	"github.com/DurgaPratapRajbhar/e-commerce/product-service/controllers"
 	repository "github.com/DurgaPratapRajbhar/e-commerce/product-service/repository/impl"
+	servicesapi "github.com/DurgaPratapRajbhar/e-commerce/product-service/services"
 	services "github.com/DurgaPratapRajbhar/e-commerce/product-service/services/impl"
 )
 
-func ImageRoutes(route *gin.RouterGroup, db *gorm.DB) {
+func ImageRoutes(route *gin.RouterGroup, db *gorm.DB, indexer servicesapi.ProductIndexer) {
 
 	proRepo := repository.NewProductImagesRepository(db)
-	proService := services.NewProductImagesService(proRepo)
+	proService := services.NewProductImagesService(proRepo, indexer)
 
 	Controller := controllers.NewProductImageController(proService)
 	imageGroup := route.Group("/product-images")
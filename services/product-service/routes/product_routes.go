@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"log"
+
 	"github.com/DurgaPratapRajbhar/e-commerce/product-service/controllers"
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/middleware"
 
@@ -8,15 +10,23 @@ import (
 	"gorm.io/gorm"
 
 	repository "github.com/DurgaPratapRajbhar/e-commerce/product-service/repository/impl"
+	servicesapi "github.com/DurgaPratapRajbhar/e-commerce/product-service/services"
 	services "github.com/DurgaPratapRajbhar/e-commerce/product-service/services/impl"
 )
 
-func ProductRoutes(r *gin.RouterGroup, db *gorm.DB) {
+func ProductRoutes(r *gin.RouterGroup, db *gorm.DB, searchService servicesapi.SearchService) {
 
 	proRepo := repository.NewProductRepository(db)
-	proService := services.NewProductService(proRepo)
+	proService := services.NewProductService(proRepo, searchService)
+
+	catalogService := services.NewCatalogService(repository.NewCatalogRepository(db), searchService)
+	if err := catalogService.FailUnfinishedImports(); err != nil {
+		log.Printf("Failed to close unfinished import jobs: %v", err)
+	}
 
 	Controller := controllers.NewProductController(proService)
+	searchController := controllers.NewSearchController(searchService)
+	catalogController := controllers.NewCatalogController(catalogService)
 
 	userGroup := r.Group("/products")
 	userGroup.Use(middleware.ServiceAuthMiddleware())
@@ -26,5 +36,10 @@ func ProductRoutes(r *gin.RouterGroup, db *gorm.DB) {
 		userGroup.GET("", Controller.GetAllProducts)
 		userGroup.PUT("/:id", Controller.UpdateProduct)
 		userGroup.DELETE("/:id", Controller.DeleteProduct)
+		userGroup.POST("/search/reindex", searchController.Reindex)
+		userGroup.POST("/import", catalogController.Import)
+		userGroup.GET("/imports", catalogController.GetImportJobs)
+		userGroup.GET("/imports/:id", catalogController.GetImportJob)
+		userGroup.GET("/export", catalogController.Export)
 	}
 }
@@ -1,19 +1,46 @@
 package routes
 
 import (
+	"context"
+	"log"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	"github.com/swaggo/gin-swagger"
 	"gorm.io/gorm"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/client"
+	repository "github.com/DurgaPratapRajbhar/e-commerce/product-service/repository/impl"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/search"
+	services "github.com/DurgaPratapRajbhar/e-commerce/product-service/services/impl"
 )
 
-func SetupRoutes(r *gin.Engine, db *gorm.DB) {
+func SetupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// The search index and suggestions live in memory and are filled from the
+	// database on start. Stock is owned by inventory-service and fetched in the
+	// background, so the service starts with it stale, then every few minutes.
+	searchService := services.NewSearchService(
+		repository.NewSearchRepository(db),
+		search.NewMemoryIndex(),
+		search.NewSuggester(),
+		client.NewOrderClient(cfg),
+		client.NewInventoryClient(cfg),
+	)
+	if _, err := searchService.Rebuild(); err != nil {
+		log.Printf("Failed to build search index: %v", err)
+	}
+	go searchService.RunPopularityRefresh(context.Background(), time.Hour)
+	go searchService.RunStockRefresh(context.Background(), 5*time.Minute)
+
 	v1 := r.Group("/product")
-	CategoryRoutes(v1, db)
-	ProductRoutes(v1, db)
-	ImageRoutes(v1, db)
-	FrontendRoutes(v1, db)
+	CategoryRoutes(v1, db, searchService)
+	ProductRoutes(v1, db, searchService)
+	ImageRoutes(v1, db, searchService)
+	FrontendRoutes(v1, db, searchService)
 	ReviewRoutes(v1, db)
 	UnitRoutes(v1, db)
 }
@@ -0,0 +1,110 @@
+package search
+
+import (
+	"html"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
+)
+
+// Document is what the index holds for a product. Price is the lowest price
+// of the product or any of its variants after the product discount, and
+// Attributes maps lower-case attribute keys, including the variants' colors
+// and sizes, to their values.
+type Document struct {
+	ID           uint64
+	Name         string
+	Slug         string
+	SKU          string
+	Brand        string
+	Description  string
+	CategoryID   uint64
+	CategoryName string
+	Attributes   map[string][]string
+	Variants     []string
+	Price        money.Money
+	Discount     float64
+	InStock      bool
+	Image        string
+	CreatedAt    time.Time
+}
+
+// NewDocument builds the document for a product loaded with its category,
+// attributes, variants and images. Whether it is in stock comes from
+// inventory-service, which owns the stock.
+func NewDocument(product *models.Product, inStock bool) Document {
+	doc := Document{
+		ID:           product.ID,
+		Name:         product.Name,
+		Slug:         product.Slug,
+		SKU:          product.SKU,
+		Brand:        product.Brand,
+		Description:  html.UnescapeString(product.Description),
+		CategoryID:   product.CategoryID,
+		CategoryName: product.Category.Name,
+		Attributes:   make(map[string][]string),
+		Price:        salePrice(product.Price, product.Discount),
+		Discount:     product.Discount,
+		InStock:      inStock,
+		Image:        primaryImage(product),
+		CreatedAt:    product.CreatedAt,
+	}
+
+	for _, attribute := range product.Attributes {
+		doc.addAttribute(attribute.Key, attribute.Value)
+	}
+	for _, variant := range product.Variants {
+		doc.Variants = append(doc.Variants, variant.Name, variant.SKU)
+		if variant.Color != nil {
+			doc.addAttribute("color", *variant.Color)
+		}
+		if variant.Size != nil {
+			doc.addAttribute("size", *variant.Size)
+		}
+		price := salePrice(variant.Price, product.Discount)
+		if cmp, err := price.Cmp(doc.Price); err == nil && cmp < 0 {
+			doc.Price = price
+		}
+	}
+	return doc
+}
+
+// addAttribute records an attribute value once, whatever its case
+func (d *Document) addAttribute(key, value string) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	value = strings.TrimSpace(value)
+	if key == "" || value == "" {
+		return
+	}
+	if slices.ContainsFunc(d.Attributes[key], func(v string) bool { return strings.EqualFold(v, value) }) {
+		return
+	}
+	d.Attributes[key] = append(d.Attributes[key], value)
+}
+
+func salePrice(price money.Money, discount float64) money.Money {
+	sale, err := price.Sub(price.Percent(discount))
+	if err != nil {
+		return price
+	}
+	return sale
+}
+
+// primaryImage returns the product's primary image, falling back to its first one
+func primaryImage(product *models.Product) string {
+	for _, image := range product.Images {
+		if image.IsPrimary {
+			return image.ImageURL
+		}
+	}
+	if len(product.Images) > 0 {
+		return product.Images[0].ImageURL
+	}
+	if product.PrimaryImage != nil {
+		return *product.PrimaryImage
+	}
+	return ""
+}
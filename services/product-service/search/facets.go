@@ -0,0 +1,192 @@
+package search
+
+import (
+	"html"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Filter dimensions. Attribute filters are one dimension per key, named
+// dimensionAttribute followed by the key.
+const (
+	dimensionCategory  = "category"
+	dimensionBrand     = "brand"
+	dimensionPrice     = "price"
+	dimensionStock     = "stock"
+	dimensionAttribute = "attribute:"
+)
+
+func escape(text string) string {
+	return html.EscapeString(text)
+}
+
+// queryFilter holds a query's filters with brands and attribute values in lower case
+type queryFilter struct {
+	categories []uint64
+	brands     []string
+	minPrice   *int64
+	maxPrice   *int64
+	attributes map[string][]string
+	inStock    bool
+}
+
+func newQueryFilter(query Query) *queryFilter {
+	f := &queryFilter{
+		categories: query.CategoryIDs,
+		minPrice:   query.MinPrice,
+		maxPrice:   query.MaxPrice,
+		attributes: make(map[string][]string),
+		inStock:    query.InStock,
+	}
+	for _, brand := range query.Brands {
+		f.brands = append(f.brands, strings.ToLower(strings.TrimSpace(brand)))
+	}
+	for key, values := range query.Attributes {
+		key = strings.ToLower(strings.TrimSpace(key))
+		for _, value := range values {
+			f.attributes[key] = append(f.attributes[key], strings.ToLower(strings.TrimSpace(value)))
+		}
+	}
+	return f
+}
+
+// failing returns the dimensions whose filter the document does not pass
+func (f *queryFilter) failing(doc *Document) []string {
+	var failed []string
+	if len(f.categories) > 0 && !slices.Contains(f.categories, doc.CategoryID) {
+		failed = append(failed, dimensionCategory)
+	}
+	if len(f.brands) > 0 && !slices.Contains(f.brands, strings.ToLower(doc.Brand)) {
+		failed = append(failed, dimensionBrand)
+	}
+	if (f.minPrice != nil && doc.Price.Amount < *f.minPrice) || (f.maxPrice != nil && doc.Price.Amount > *f.maxPrice) {
+		failed = append(failed, dimensionPrice)
+	}
+	if f.inStock && !doc.InStock {
+		failed = append(failed, dimensionStock)
+	}
+	for key, wanted := range f.attributes {
+		found := slices.ContainsFunc(doc.Attributes[key], func(value string) bool {
+			return slices.Contains(wanted, strings.ToLower(value))
+		})
+		if !found {
+			failed = append(failed, dimensionAttribute+key)
+		}
+	}
+	return failed
+}
+
+// counts reports whether a document counts towards a facet: it passes every
+// filter, or fails only the facet's own
+func counts(failed []string, dimension string) bool {
+	return len(failed) == 0 || (len(failed) == 1 && failed[0] == dimension)
+}
+
+type facetCounter struct {
+	categories  map[uint64]*CategoryFacet
+	brands      map[string]*FacetValue
+	priceRanges []int
+	attributes  map[string]map[string]*FacetValue
+}
+
+func newFacetCounter() *facetCounter {
+	return &facetCounter{
+		categories:  make(map[uint64]*CategoryFacet),
+		brands:      make(map[string]*FacetValue),
+		priceRanges: make([]int, len(priceRangeBounds)),
+		attributes:  make(map[string]map[string]*FacetValue),
+	}
+}
+
+func (c *facetCounter) add(doc *Document, failed []string) {
+	if doc.CategoryID != 0 && counts(failed, dimensionCategory) {
+		facet, ok := c.categories[doc.CategoryID]
+		if !ok {
+			facet = &CategoryFacet{ID: doc.CategoryID, Name: doc.CategoryName}
+			c.categories[doc.CategoryID] = facet
+		}
+		facet.Count++
+	}
+	if doc.Brand != "" && counts(failed, dimensionBrand) {
+		countValue(c.brands, doc.Brand)
+	}
+	if counts(failed, dimensionPrice) {
+		for i := len(priceRangeBounds) - 1; i >= 0; i-- {
+			if doc.Price.Amount >= priceRangeBounds[i] {
+				c.priceRanges[i]++
+				break
+			}
+		}
+	}
+	for key, values := range doc.Attributes {
+		if !counts(failed, dimensionAttribute+key) {
+			continue
+		}
+		if c.attributes[key] == nil {
+			c.attributes[key] = make(map[string]*FacetValue)
+		}
+		for _, value := range values {
+			countValue(c.attributes[key], value)
+		}
+	}
+}
+
+// countValue counts a value case-insensitively, showing it as first seen
+func countValue(values map[string]*FacetValue, value string) {
+	key := strings.ToLower(value)
+	facet, ok := values[key]
+	if !ok {
+		facet = &FacetValue{Value: value}
+		values[key] = facet
+	}
+	facet.Count++
+}
+
+func (c *facetCounter) facets() Facets {
+	facets := Facets{
+		Categories:  make([]CategoryFacet, 0, len(c.categories)),
+		Brands:      topValues(c.brands),
+		PriceRanges: make([]PriceRangeFacet, len(priceRangeBounds)),
+		Attributes:  make(map[string][]FacetValue, len(c.attributes)),
+	}
+	for _, facet := range c.categories {
+		facets.Categories = append(facets.Categories, *facet)
+	}
+	sort.Slice(facets.Categories, func(i, j int) bool {
+		a, b := facets.Categories[i], facets.Categories[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Name < b.Name
+	})
+	for i, from := range priceRangeBounds {
+		facets.PriceRanges[i] = PriceRangeFacet{From: from, Count: c.priceRanges[i]}
+		if i+1 < len(priceRangeBounds) {
+			to := priceRangeBounds[i+1]
+			facets.PriceRanges[i].To = &to
+		}
+	}
+	for key, values := range c.attributes {
+		facets.Attributes[key] = topValues(values)
+	}
+	return facets
+}
+
+// topValues returns the most frequent values, ties in alphabetical order
+func topValues(values map[string]*FacetValue) []FacetValue {
+	top := make([]FacetValue, 0, len(values))
+	for _, facet := range values {
+		top = append(top, *facet)
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return strings.ToLower(top[i].Value) < strings.ToLower(top[j].Value)
+	})
+	if len(top) > maxFacetValues {
+		top = top[:maxFacetValues]
+	}
+	return top
+}
@@ -0,0 +1,120 @@
+// Package search is the product search index. Index is the extension point;
+// NewMemoryIndex is an embedded inverted index that ranks matches with BM25.
+package search
+
+import (
+	"errors"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+// Sort orders
+const (
+	// SortRelevance ranks the best matches first, or the newest products when there is no text
+	SortRelevance = "relevance"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+	SortNewest    = "newest"
+	SortName      = "name"
+)
+
+const (
+	// DefaultLimit is how many hits a page holds when the query does not say
+	DefaultLimit = 20
+	// MaxLimit is the most hits a page can hold
+	MaxLimit = 100
+	// maxFacetValues is how many values a brand or attribute facet lists
+	maxFacetValues = 20
+)
+
+// ErrInvalidCursor is returned for a cursor that is malformed or from a search with another sort
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Index stores product documents and searches them
+type Index interface {
+	// Index adds a document, replacing any with the same ID
+	Index(doc Document) error
+	// Delete removes a document; removing one that is not indexed is not an error
+	Delete(id uint64) error
+	// Replace swaps the whole content of the index for the given documents
+	Replace(docs []Document) error
+	Search(query Query) (*Result, error)
+	Count() int
+}
+
+// Query is a search over the index. Text must match every term; the filters
+// narrow the matches further. Several values of one filter match any of
+// them, while different filters must all match.
+type Query struct {
+	Text        string
+	CategoryIDs []uint64
+	Brands      []string
+	// MinPrice and MaxPrice bound Document.Price, in minor units
+	MinPrice   *int64
+	MaxPrice   *int64
+	Attributes map[string][]string
+	InStock    bool
+	Sort       string
+	Limit      int
+	Cursor     string
+}
+
+// Result is a page of hits with the facets of every match. Each facet is
+// counted with the other filters applied but not its own, so that the counts
+// show what selecting another value would give.
+type Result struct {
+	Hits       []Hit  `json:"hits"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Facets     Facets `json:"facets"`
+}
+
+// Hit is a matching product. Highlights holds snippets of the matching
+// fields, HTML-escaped with the matched words in <mark> tags.
+type Hit struct {
+	ID           uint64            `json:"id"`
+	Name         string            `json:"name"`
+	Slug         string            `json:"slug"`
+	Brand        string            `json:"brand,omitempty"`
+	CategoryID   uint64            `json:"category_id"`
+	CategoryName string            `json:"category_name,omitempty"`
+	Price        money.Money       `json:"price"`
+	Discount     float64           `json:"discount"`
+	InStock      bool              `json:"in_stock"`
+	Image        string            `json:"image,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	Score        float64           `json:"score"`
+	Highlights   map[string]string `json:"highlights,omitempty"`
+}
+
+// Facets counts the matches by category, brand, price range and attribute value
+type Facets struct {
+	Categories  []CategoryFacet         `json:"categories"`
+	Brands      []FacetValue            `json:"brands"`
+	PriceRanges []PriceRangeFacet       `json:"price_ranges"`
+	Attributes  map[string][]FacetValue `json:"attributes"`
+}
+
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type CategoryFacet struct {
+	ID    uint64 `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// PriceRangeFacet counts the matches priced from From up to, but not
+// including, To, in minor units. The last range has no To.
+type PriceRangeFacet struct {
+	From  int64  `json:"from"`
+	To    *int64 `json:"to,omitempty"`
+	Count int    `json:"count"`
+}
+
+// priceRangeBounds are the lower bounds of the price range facets in minor
+// units: under 500, 500 to 1,000, 1,000 to 5,000, 5,000 to 10,000 and above
+var priceRangeBounds = []int64{0, 50000, 100000, 500000, 1000000}
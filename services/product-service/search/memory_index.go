@@ -0,0 +1,456 @@
+package search
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// field is a part of a document that is searched, with its own weight
+type field int
+
+const (
+	fieldName field = iota
+	fieldBrand
+	fieldCategory
+	fieldSKU
+	fieldAttributes
+	fieldVariants
+	fieldDescription
+	numFields
+)
+
+// fieldBoosts weighs a match in the name, brand, category or SKU above one in
+// the attributes, variants or description
+var fieldBoosts = [numFields]float64{3, 2, 2, 2, 1.5, 1, 1}
+
+// BM25 parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Snippet sizes in bytes for highlighted descriptions
+const (
+	snippetLead   = 60
+	snippetLength = 200
+)
+
+func fieldTexts(doc *Document) [numFields]string {
+	var attributes []string
+	for key, values := range doc.Attributes {
+		attributes = append(attributes, key)
+		attributes = append(attributes, values...)
+	}
+	return [numFields]string{
+		fieldName:        doc.Name,
+		fieldBrand:       doc.Brand,
+		fieldCategory:    doc.CategoryName,
+		fieldSKU:         doc.SKU,
+		fieldAttributes:  strings.Join(attributes, " "),
+		fieldVariants:    strings.Join(doc.Variants, " "),
+		fieldDescription: doc.Description,
+	}
+}
+
+// termFrequencies counts a term's occurrences in each field of a document
+type termFrequencies [numFields]int
+
+type indexedDoc struct {
+	doc    Document
+	length [numFields]int
+	terms  []string
+}
+
+// memoryIndex is an inverted index held in memory. It is safe for concurrent use.
+type memoryIndex struct {
+	mu          sync.RWMutex
+	docs        map[uint64]*indexedDoc
+	postings    map[string]map[uint64]*termFrequencies
+	totalLength [numFields]int
+}
+
+// NewMemoryIndex returns an empty in-memory index. It is not persisted and
+// has to be filled again, with Replace, when the service starts.
+func NewMemoryIndex() Index {
+	return &memoryIndex{
+		docs:     make(map[uint64]*indexedDoc),
+		postings: make(map[string]map[uint64]*termFrequencies),
+	}
+}
+
+func (ix *memoryIndex) Index(doc Document) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.remove(doc.ID)
+	ix.add(doc)
+	return nil
+}
+
+func (ix *memoryIndex) Delete(id uint64) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.remove(id)
+	return nil
+}
+
+func (ix *memoryIndex) Replace(docs []Document) error {
+	fresh := NewMemoryIndex().(*memoryIndex)
+	for _, doc := range docs {
+		fresh.remove(doc.ID)
+		fresh.add(doc)
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.docs = fresh.docs
+	ix.postings = fresh.postings
+	ix.totalLength = fresh.totalLength
+	return nil
+}
+
+func (ix *memoryIndex) Count() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.docs)
+}
+
+// add indexes a document; the caller holds the write lock
+func (ix *memoryIndex) add(doc Document) {
+	indexed := &indexedDoc{doc: doc}
+	frequencies := make(map[string]*termFrequencies)
+	for f, text := range fieldTexts(&doc) {
+		tokens := tokenize(text)
+		indexed.length[f] = len(tokens)
+		ix.totalLength[f] += len(tokens)
+		for _, t := range tokens {
+			tf, ok := frequencies[t.term]
+			if !ok {
+				tf = &termFrequencies{}
+				frequencies[t.term] = tf
+				indexed.terms = append(indexed.terms, t.term)
+			}
+			tf[f]++
+		}
+	}
+
+	for term, tf := range frequencies {
+		if ix.postings[term] == nil {
+			ix.postings[term] = make(map[uint64]*termFrequencies)
+		}
+		ix.postings[term][doc.ID] = tf
+	}
+	ix.docs[doc.ID] = indexed
+}
+
+// remove drops a document; the caller holds the write lock
+func (ix *memoryIndex) remove(id uint64) {
+	indexed, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+	for _, term := range indexed.terms {
+		delete(ix.postings[term], id)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	for f := range numFields {
+		ix.totalLength[f] -= indexed.length[f]
+	}
+	delete(ix.docs, id)
+}
+
+// sortKey holds the values hits are ordered by; a cursor carries the last one of a page
+type sortKey struct {
+	Score   float64 `json:"s,omitempty"`
+	Price   int64   `json:"p,omitempty"`
+	Created int64   `json:"c,omitempty"`
+	Name    string  `json:"n,omitempty"`
+	ID      uint64  `json:"i"`
+}
+
+type cursor struct {
+	Sort string  `json:"o"`
+	Key  sortKey `json:"k"`
+}
+
+func encodeCursor(sortBy string, key sortKey) string {
+	raw, _ := json.Marshal(cursor{Sort: sortBy, Key: key})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(value, sortBy string) (*sortKey, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Sort != sortBy {
+		return nil, ErrInvalidCursor
+	}
+	return &c.Key, nil
+}
+
+// before reports whether a hit with key a comes before one with key b. IDs
+// break ties so that the order is total and cursors are stable.
+func before(sortBy string, a, b sortKey) bool {
+	switch sortBy {
+	case SortRelevance:
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+	case SortPriceAsc:
+		if a.Price != b.Price {
+			return a.Price < b.Price
+		}
+	case SortPriceDesc:
+		if a.Price != b.Price {
+			return a.Price > b.Price
+		}
+	case SortNewest:
+		if a.Created != b.Created {
+			return a.Created > b.Created
+		}
+		return a.ID > b.ID
+	case SortName:
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+	}
+	return a.ID < b.ID
+}
+
+type match struct {
+	doc *indexedDoc
+	key sortKey
+}
+
+func (ix *memoryIndex) Search(query Query) (*Result, error) {
+	sortBy := query.Sort
+	if sortBy == "" {
+		sortBy = SortRelevance
+	}
+	switch sortBy {
+	case SortRelevance, SortPriceAsc, SortPriceDesc, SortNewest, SortName:
+	default:
+		return nil, fmt.Errorf("unknown sort %q", sortBy)
+	}
+	terms := distinct(Analyze(query.Text))
+	if sortBy == SortRelevance && len(terms) == 0 {
+		sortBy = SortNewest
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	limit = min(limit, MaxLimit)
+
+	var after *sortKey
+	if query.Cursor != "" {
+		var err error
+		if after, err = decodeCursor(query.Cursor, sortBy); err != nil {
+			return nil, err
+		}
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	filter := newQueryFilter(query)
+	facets := newFacetCounter()
+	var hits []match
+	for _, m := range ix.match(terms) {
+		failed := filter.failing(&m.doc.doc)
+		if len(failed) == 0 {
+			hits = append(hits, m)
+		}
+		facets.add(&m.doc.doc, failed)
+	}
+	sort.Slice(hits, func(i, j int) bool { return before(sortBy, hits[i].key, hits[j].key) })
+
+	start := 0
+	if after != nil {
+		start = sort.Search(len(hits), func(i int) bool { return before(sortBy, *after, hits[i].key) })
+	}
+	end := min(start+limit, len(hits))
+
+	result := &Result{
+		Hits:   make([]Hit, 0, end-start),
+		Total:  len(hits),
+		Facets: facets.facets(),
+	}
+	for _, m := range hits[start:end] {
+		result.Hits = append(result.Hits, newHit(m, terms))
+	}
+	if end < len(hits) {
+		result.NextCursor = encodeCursor(sortBy, hits[end-1].key)
+	}
+	return result, nil
+}
+
+// match finds the documents holding every term, scored with BM25. Without
+// terms every document matches with a score of zero.
+func (ix *memoryIndex) match(terms []string) []match {
+	var matches []match
+	if len(terms) == 0 {
+		for _, indexed := range ix.docs {
+			matches = append(matches, newMatch(indexed, 0))
+		}
+		return matches
+	}
+
+	// Walk the rarest term's postings and check the others
+	postings := make([]map[uint64]*termFrequencies, len(terms))
+	for i, term := range terms {
+		postings[i] = ix.postings[term]
+		if len(postings[i]) == 0 {
+			return nil
+		}
+	}
+	slices.SortFunc(postings, func(a, b map[uint64]*termFrequencies) int { return len(a) - len(b) })
+
+	n := float64(len(ix.docs))
+	var average [numFields]float64
+	for f := range numFields {
+		average[f] = float64(ix.totalLength[f]) / n
+	}
+
+	for id := range postings[0] {
+		indexed := ix.docs[id]
+		score := 0.0
+		for _, posting := range postings {
+			tf, ok := posting[id]
+			if !ok {
+				score = -1
+				break
+			}
+			df := float64(len(posting))
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			for f := range numFields {
+				if tf[f] == 0 {
+					continue
+				}
+				freq := float64(tf[f])
+				norm := 1 - bm25B + bm25B*float64(indexed.length[f])/average[f]
+				score += fieldBoosts[f] * idf * freq * (bm25K1 + 1) / (freq + bm25K1*norm)
+			}
+		}
+		if score >= 0 {
+			matches = append(matches, newMatch(indexed, score))
+		}
+	}
+	return matches
+}
+
+func newMatch(indexed *indexedDoc, score float64) match {
+	return match{
+		doc: indexed,
+		key: sortKey{
+			Score:   score,
+			Price:   indexed.doc.Price.Amount,
+			Created: indexed.doc.CreatedAt.UnixNano(),
+			Name:    strings.ToLower(indexed.doc.Name),
+			ID:      indexed.doc.ID,
+		},
+	}
+}
+
+func newHit(m match, terms []string) Hit {
+	doc := &m.doc.doc
+	hit := Hit{
+		ID:           doc.ID,
+		Name:         doc.Name,
+		Slug:         doc.Slug,
+		Brand:        doc.Brand,
+		CategoryID:   doc.CategoryID,
+		CategoryName: doc.CategoryName,
+		Price:        doc.Price,
+		Discount:     doc.Discount,
+		InStock:      doc.InStock,
+		Image:        doc.Image,
+		CreatedAt:    doc.CreatedAt,
+		Score:        m.key.Score,
+	}
+	if len(terms) == 0 {
+		return hit
+	}
+	highlights := make(map[string]string)
+	if snippet, ok := highlight(doc.Name, terms, false); ok {
+		highlights["name"] = snippet
+	}
+	if snippet, ok := highlight(doc.Description, terms, true); ok {
+		highlights["description"] = snippet
+	}
+	if len(highlights) > 0 {
+		hit.Highlights = highlights
+	}
+	return hit
+}
+
+// highlight escapes text and marks the words matching the terms. A long text
+// is cut to a snippet around its first match.
+func highlight(text string, terms []string, snippet bool) (string, bool) {
+	tokens := tokenize(text)
+	first := slices.IndexFunc(tokens, func(t token) bool { return slices.Contains(terms, t.term) })
+	if first < 0 {
+		return "", false
+	}
+
+	start, end := 0, len(text)
+	if snippet {
+		from := tokens[first].start - snippetLead
+		for _, t := range tokens {
+			if t.start >= from {
+				start = t.start
+				break
+			}
+		}
+		end = start
+		for _, t := range tokens {
+			if t.start >= start && t.end <= start+snippetLength {
+				end = t.end
+			}
+		}
+		end = max(end, tokens[first].end)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, t := range tokens {
+		if t.start < start || t.end > end {
+			continue
+		}
+		if !slices.Contains(terms, t.term) {
+			continue
+		}
+		b.WriteString(escape(text[pos:t.start]))
+		b.WriteString("<mark>")
+		b.WriteString(escape(text[t.start:t.end]))
+		b.WriteString("</mark>")
+		pos = t.end
+	}
+	b.WriteString(escape(text[pos:end]))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
+
+func distinct(terms []string) []string {
+	var unique []string
+	for _, term := range terms {
+		if !slices.Contains(unique, term) {
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
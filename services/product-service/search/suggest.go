@@ -0,0 +1,403 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Suggestion types
+const (
+	SuggestionProduct  = "product"
+	SuggestionBrand    = "brand"
+	SuggestionCategory = "category"
+)
+
+const (
+	// DefaultSuggestLimit is how many suggestions are returned when the request does not say
+	DefaultSuggestLimit = 10
+	// MaxSuggestLimit is the most suggestions a request can ask for
+	MaxSuggestLimit = 20
+	// startBoost favours text that starts with the prefix over text with a later word that does
+	startBoost = 1.5
+)
+
+// Suggestion is a completion for what the shopper has typed. ID is the
+// product or category ID and Slug the product's slug. Corrected is set when
+// the text matched only with typos allowed.
+type Suggestion struct {
+	Text      string  `json:"text"`
+	Type      string  `json:"type"`
+	ID        uint64  `json:"id,omitempty"`
+	Slug      string  `json:"slug,omitempty"`
+	Corrected bool    `json:"corrected"`
+	Score     float64 `json:"score"`
+}
+
+// suggestEntry is a suggestible product name, brand or category name.
+// Products counts the indexed products behind a brand or category, and
+// Orders is the popularity of the product, or of all of them.
+type suggestEntry struct {
+	kind     string
+	id       uint64
+	text     string
+	slug     string
+	products int
+	orders   int64
+}
+
+// trieNode ends the keys of the entries it holds. An entry maps to true when
+// the key is its whole text rather than starting at a later word.
+type trieNode struct {
+	children map[rune]*trieNode
+	entries  map[*suggestEntry]bool
+}
+
+func newTrieNode() *trieNode {
+	return &trieNode{children: make(map[rune]*trieNode)}
+}
+
+// productRef is what the suggester remembers about an indexed product
+type productRef struct {
+	entry      *suggestEntry
+	brand      string
+	categoryID uint64
+}
+
+// Suggester completes search text from product names, brands and category
+// names. It keeps an in-memory trie that is updated one product at a time,
+// finds prefixes with a few typos allowed and ranks suggestions by how often
+// the products were ordered. It is safe for concurrent use.
+type Suggester struct {
+	mu         sync.RWMutex
+	root       *trieNode
+	products   map[uint64]*productRef
+	brands     map[string]*suggestEntry
+	categories map[uint64]*suggestEntry
+	orders     map[uint64]int64
+}
+
+func NewSuggester() *Suggester {
+	return &Suggester{
+		root:       newTrieNode(),
+		products:   make(map[uint64]*productRef),
+		brands:     make(map[string]*suggestEntry),
+		categories: make(map[uint64]*suggestEntry),
+		orders:     make(map[uint64]int64),
+	}
+}
+
+// suggestText lower-cases text and reduces it to words of letters and digits
+// separated by single spaces
+func suggestText(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// suggestKeys returns the keys an entry is found under: its text from each word on
+func suggestKeys(text string) []string {
+	normalized := suggestText(text)
+	if normalized == "" {
+		return nil
+	}
+	keys := []string{normalized}
+	for i, r := range normalized {
+		if r == ' ' {
+			keys = append(keys, normalized[i+1:])
+		}
+	}
+	return keys
+}
+
+// insert adds an entry under its keys; the caller holds the write lock
+func (s *Suggester) insert(entry *suggestEntry) {
+	for i, key := range suggestKeys(entry.text) {
+		node := s.root
+		for _, r := range key {
+			child, ok := node.children[r]
+			if !ok {
+				child = newTrieNode()
+				node.children[r] = child
+			}
+			node = child
+		}
+		if node.entries == nil {
+			node.entries = make(map[*suggestEntry]bool)
+		}
+		node.entries[entry] = i == 0
+	}
+}
+
+// delete removes an entry from under its keys, pruning the nodes left empty;
+// the caller holds the write lock
+func (s *Suggester) delete(entry *suggestEntry) {
+	for _, key := range suggestKeys(entry.text) {
+		path := []*trieNode{s.root}
+		runes := []rune(key)
+		for _, r := range runes {
+			child, ok := path[len(path)-1].children[r]
+			if !ok {
+				break
+			}
+			path = append(path, child)
+		}
+		if len(path) != len(runes)+1 {
+			continue
+		}
+		delete(path[len(path)-1].entries, entry)
+		for i := len(path) - 1; i > 0; i-- {
+			if len(path[i].entries) > 0 || len(path[i].children) > 0 {
+				break
+			}
+			delete(path[i-1].children, runes[i-1])
+		}
+	}
+}
+
+// Add adds a product, or updates it, together with its brand and category
+func (s *Suggester) Add(doc Document) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(doc.ID)
+	s.add(doc)
+}
+
+// Remove drops a product, and its brand and category once no product has them
+func (s *Suggester) Remove(id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(id)
+}
+
+// Replace swaps the whole content of the suggester for the given products,
+// keeping the popularity last set
+func (s *Suggester) Replace(docs []Document) {
+	fresh := NewSuggester()
+	s.mu.RLock()
+	fresh.orders = s.orders
+	s.mu.RUnlock()
+	for _, doc := range docs {
+		fresh.remove(doc.ID)
+		fresh.add(doc)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.root = fresh.root
+	s.products = fresh.products
+	s.brands = fresh.brands
+	s.categories = fresh.categories
+}
+
+// SetPopularity replaces the order counts of the products, keyed by product ID
+func (s *Suggester) SetPopularity(orders map[uint64]int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orders = orders
+	for _, brand := range s.brands {
+		brand.orders = 0
+	}
+	for _, category := range s.categories {
+		category.orders = 0
+	}
+	for id, ref := range s.products {
+		ref.entry.orders = orders[id]
+		if brand, ok := s.brands[ref.brand]; ok {
+			brand.orders += orders[id]
+		}
+		if category, ok := s.categories[ref.categoryID]; ok {
+			category.orders += orders[id]
+		}
+	}
+}
+
+// add indexes a product that is not in the suggester; the caller holds the write lock
+func (s *Suggester) add(doc Document) {
+	orders := s.orders[doc.ID]
+	ref := &productRef{
+		entry: &suggestEntry{kind: SuggestionProduct, id: doc.ID, text: doc.Name, slug: doc.Slug, orders: orders},
+		brand: strings.ToLower(strings.TrimSpace(doc.Brand)),
+	}
+	s.insert(ref.entry)
+
+	if ref.brand != "" {
+		brand, ok := s.brands[ref.brand]
+		if !ok {
+			brand = &suggestEntry{kind: SuggestionBrand, text: strings.TrimSpace(doc.Brand)}
+			s.brands[ref.brand] = brand
+			s.insert(brand)
+		}
+		brand.products++
+		brand.orders += orders
+	}
+
+	if doc.CategoryID != 0 && doc.CategoryName != "" {
+		ref.categoryID = doc.CategoryID
+		category, ok := s.categories[doc.CategoryID]
+		if !ok {
+			category = &suggestEntry{kind: SuggestionCategory, id: doc.CategoryID, text: doc.CategoryName}
+			s.categories[doc.CategoryID] = category
+			s.insert(category)
+		} else if category.text != doc.CategoryName {
+			// The category has been renamed
+			s.delete(category)
+			category.text = doc.CategoryName
+			s.insert(category)
+		}
+		category.products++
+		category.orders += orders
+	}
+	s.products[doc.ID] = ref
+}
+
+// remove drops a product; the caller holds the write lock
+func (s *Suggester) remove(id uint64) {
+	ref, ok := s.products[id]
+	if !ok {
+		return
+	}
+	s.delete(ref.entry)
+	if brand, ok := s.brands[ref.brand]; ok {
+		brand.products--
+		brand.orders -= ref.entry.orders
+		if brand.products == 0 {
+			s.delete(brand)
+			delete(s.brands, ref.brand)
+		}
+	}
+	if category, ok := s.categories[ref.categoryID]; ok {
+		category.products--
+		category.orders -= ref.entry.orders
+		if category.products == 0 {
+			s.delete(category)
+			delete(s.categories, ref.categoryID)
+		}
+	}
+	delete(s.products, id)
+}
+
+// maxEdits is how many typos a prefix of the given length may have
+func maxEdits(length int) int {
+	switch {
+	case length < 3:
+		return 0
+	case length < 6:
+		return 1
+	}
+	return 2
+}
+
+// trieMatch is a node whose path matches the prefix within distance edits
+type trieMatch struct {
+	node     *trieNode
+	distance int
+}
+
+// Suggest completes the prefix, best first. Suggestions matching without
+// typos rank above ones that need them, and popular products, and brands and
+// categories with popular products, rank higher.
+func (s *Suggester) Suggest(prefix string, limit int) []Suggestion {
+	query := []rune(suggestText(prefix))
+	if len(query) == 0 {
+		return []Suggestion{}
+	}
+	if limit <= 0 {
+		limit = DefaultSuggestLimit
+	}
+	limit = min(limit, MaxSuggestLimit)
+	edits := maxEdits(len(query))
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Walk the trie with a row of the Levenshtein table, so that a path
+	// matches when the prefix can be turned into it with few enough edits
+	row := make([]int, len(query)+1)
+	for i := range row {
+		row[i] = i
+	}
+	var matches []trieMatch
+	var walk func(node *trieNode, previous []int)
+	walk = func(node *trieNode, previous []int) {
+		for r, child := range node.children {
+			current := make([]int, len(query)+1)
+			current[0] = previous[0] + 1
+			best := current[0]
+			for j := 1; j <= len(query); j++ {
+				cost := 1
+				if query[j-1] == r {
+					cost = 0
+				}
+				current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+				best = min(best, current[j])
+			}
+			if current[len(query)] <= edits {
+				matches = append(matches, trieMatch{node: child, distance: current[len(query)]})
+			}
+			if best <= edits {
+				walk(child, current)
+			}
+		}
+	}
+	walk(s.root, row)
+
+	// An entry is scored by the closest of its matches
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].distance < matches[j].distance })
+	type candidate struct {
+		distance int
+		start    bool
+	}
+	candidates := make(map[*suggestEntry]candidate)
+	var collect func(node *trieNode, distance int)
+	collect = func(node *trieNode, distance int) {
+		for entry, start := range node.entries {
+			found, ok := candidates[entry]
+			if !ok || distance < found.distance || (distance == found.distance && start && !found.start) {
+				candidates[entry] = candidate{distance: distance, start: start}
+			}
+		}
+		for _, child := range node.children {
+			collect(child, distance)
+		}
+	}
+	for _, m := range matches {
+		collect(m.node, m.distance)
+	}
+
+	suggestions := make([]Suggestion, 0, len(candidates))
+	for entry, c := range candidates {
+		score := (1 + math.Log1p(float64(max(entry.orders, 0)))) / float64(1+c.distance)
+		if c.start {
+			score *= startBoost
+		}
+		suggestions = append(suggestions, Suggestion{
+			Text:      entry.text,
+			Type:      entry.kind,
+			ID:        entry.id,
+			Slug:      entry.slug,
+			Corrected: c.distance > 0,
+			Score:     score,
+		})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if len(a.Text) != len(b.Text) {
+			return len(a.Text) < len(b.Text)
+		}
+		if a.Text != b.Text {
+			return a.Text < b.Text
+		}
+		return a.Type+strconv.FormatUint(a.ID, 10) < b.Type+strconv.FormatUint(b.ID, 10)
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
+)
+
+// ErrInvalidImport is returned for an import file that cannot be read at all,
+// such as a CSV file without a usable header
+var ErrInvalidImport = errors.New("invalid import")
+
+// CatalogService imports products in bulk from CSV or JSON Lines files and
+// exports them in the same formats
+type CatalogService interface {
+	// StartImport records an import job for the file and runs it in the
+	// background. Each product is validated like one created through the API
+	// and upserted by SKU; in a dry run nothing is kept.
+	StartImport(data []byte, format string, dryRun bool, createdBy uint64) (*models.ImportJob, error)
+	GetImportJob(id uint64) (*models.ImportJob, error)
+	GetImportJobs(limit, offset int) ([]models.ImportJob, int64, error)
+	// FailUnfinishedImports marks the jobs cut short by a restart as failed
+	FailUnfinishedImports() error
+	// Export writes the products with the status, or every product when it is
+	// empty, to w in batches, flushing w after each when it is an http.Flusher
+	Export(ctx context.Context, format, status string, w io.Writer) error
+}
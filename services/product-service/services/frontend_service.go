@@ -5,5 +5,7 @@ import "github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
 type FrontendService interface {
 	GetProductData(slug string) ([]models.Product, error)
 	GetProductsByCategorySlug(slug string) ([]models.Product, error)
+	// ProductSearch returns the best matches for the text from the search
+	// index, up to search.MaxLimit, as full products
 	ProductSearch(search string) ([]models.Product, error)
 }
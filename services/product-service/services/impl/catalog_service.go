@@ -0,0 +1,376 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/catalog"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/repository"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/services"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// importProgressEvery is how many products an import reads between saving its progress
+	importProgressEvery = 50
+	// maxImportRowErrors is how many row errors an import job keeps
+	maxImportRowErrors = 1000
+	// exportBatchSize is how many products Export loads at a time
+	exportBatchSize = 500
+)
+
+type catalogService struct {
+	repo    repository.CatalogRepository
+	indexer services.ProductIndexer
+}
+
+func NewCatalogService(repo repository.CatalogRepository, indexer services.ProductIndexer) services.CatalogService {
+	return &catalogService{repo: repo, indexer: indexer}
+}
+
+// newProductValidator returns a validator for products and their variants and
+// attributes. Each import gets its own, as registering rules is not safe
+// while another goroutine validates.
+func newProductValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterValidation("regexp", func(fl validator.FieldLevel) bool {
+		match, err := regexp.MatchString(fl.Param(), fl.Field().String())
+		return err == nil && match
+	})
+	validate.RegisterCustomTypeFunc(money.ValidationValue, money.Money{})
+	return validate
+}
+
+func (s *catalogService) StartImport(data []byte, format string, dryRun bool, createdBy uint64) (*models.ImportJob, error) {
+	reader, err := catalog.NewReader(format, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", services.ErrInvalidImport, err)
+	}
+
+	job := &models.ImportJob{
+		Format:    format,
+		DryRun:    dryRun,
+		Status:    models.ImportStatusPending,
+		CreatedBy: createdBy,
+	}
+	if err := s.repo.CreateImportJob(job); err != nil {
+		return nil, err
+	}
+
+	// The goroutine works on its own copy so the caller can return the job
+	running := *job
+	go s.runImport(&running, reader)
+	return job, nil
+}
+
+// importRun is the state of an import while it runs
+type importRun struct {
+	job        *models.ImportJob
+	validate   *validator.Validate
+	categories map[uint64]bool
+	units      map[uint64]bool
+	// slugs maps the slugs of the products saved so far to their SKUs, so a
+	// dry run catches two new products with one slug
+	slugs map[string]string
+	// skus holds the SKUs saved so far, so a dry run counts a SKU that comes
+	// again in the file as updated, like a real run does
+	skus      map[string]bool
+	rowErrors []models.ImportRowError
+	stored    int
+}
+
+func (s *catalogService) runImport(job *models.ImportJob, reader catalog.Reader) {
+	run := &importRun{
+		job:        job,
+		validate:   newProductValidator(),
+		categories: make(map[uint64]bool),
+		units:      make(map[uint64]bool),
+		slugs:      make(map[string]string),
+		skus:       make(map[string]bool),
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Import job %d panicked: %v", job.ID, r)
+			job.Status = models.ImportStatusFailed
+			job.Error = "the import stopped unexpectedly"
+			s.finishImport(run)
+		}
+	}()
+
+	started := time.Now()
+	job.Status = models.ImportStatusRunning
+	job.StartedAt = &started
+	s.saveImportProgress(run)
+
+	for {
+		entry, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			job.Status = models.ImportStatusCompleted
+			break
+		}
+		if err != nil {
+			job.Status = models.ImportStatusFailed
+			job.Error = err.Error()
+			break
+		}
+
+		job.Products++
+		s.importEntry(run, entry)
+		if job.Products%importProgressEvery == 0 {
+			s.saveImportProgress(run)
+		}
+	}
+	s.finishImport(run)
+}
+
+// importEntry validates and saves one product, counting it as created,
+// updated or failed
+func (s *catalogService) importEntry(run *importRun, entry *catalog.Entry) {
+	fieldErrors := entry.Errors
+	var product *models.Product
+	if len(fieldErrors) == 0 {
+		product = entry.Item.Product()
+		fieldErrors = s.checkProduct(run, product)
+	}
+
+	if len(fieldErrors) == 0 {
+		created, err := s.repo.UpsertProduct(product, run.job.DryRun)
+		switch {
+		case errors.Is(err, repository.ErrSlugTaken):
+			fieldErrors = append(fieldErrors, catalog.FieldError{Field: "slug", Message: err.Error()})
+		case err != nil:
+			fieldErrors = append(fieldErrors, catalog.FieldError{Message: err.Error()})
+		case created && !run.skus[product.SKU]:
+			run.job.Created++
+		default:
+			run.job.Updated++
+		}
+		if err == nil {
+			run.slugs[product.Slug] = product.SKU
+			run.skus[product.SKU] = true
+		}
+		if err == nil && !run.job.DryRun {
+			logIndexError(s.indexer.IndexProduct(product.ID), fmt.Sprintf("product %d", product.ID))
+		}
+	}
+
+	if len(fieldErrors) == 0 {
+		return
+	}
+	run.job.Failed++
+	for _, fieldError := range fieldErrors {
+		if run.stored == maxImportRowErrors {
+			run.job.ErrorsTruncated = true
+			break
+		}
+		line := fieldError.Line
+		if line == 0 {
+			line = entry.Line
+		}
+		run.rowErrors = append(run.rowErrors, models.ImportRowError{
+			JobID:   run.job.ID,
+			Line:    line,
+			SKU:     entry.Item.SKU,
+			Field:   fieldError.Field,
+			Message: fieldError.Message,
+		})
+		run.stored++
+	}
+}
+
+// checkProduct fills in the slug and status when they are left out and
+// checks the product with the same rules as the create product API, and that
+// its category and units exist
+func (s *catalogService) checkProduct(run *importRun, product *models.Product) []catalog.FieldError {
+	if product.Slug == "" {
+		product.Slug = utils.GenerateSlug(product.Name)
+	}
+	if product.Status == "" {
+		product.Status = "active"
+	}
+
+	var fieldErrors []catalog.FieldError
+	if err := product.ValidateBasic(run.validate); err != nil {
+		fieldErrors = append(fieldErrors, validationErrors("", err)...)
+	}
+	for i := range product.Variants {
+		if err := run.validate.Struct(&product.Variants[i]); err != nil {
+			fieldErrors = append(fieldErrors, validationErrors(fmt.Sprintf("variants[%d].", i), err)...)
+		}
+	}
+	for i := range product.Attributes {
+		if err := run.validate.Struct(&product.Attributes[i]); err != nil {
+			fieldErrors = append(fieldErrors, validationErrors(fmt.Sprintf("attributes[%d].", i), err)...)
+		}
+	}
+
+	if sku, ok := run.slugs[product.Slug]; ok && sku != product.SKU {
+		fieldErrors = append(fieldErrors, catalog.FieldError{Field: "slug", Message: fmt.Sprintf("slug %q is already used by %s in this file", product.Slug, sku)})
+	}
+
+	if product.CategoryID != 0 {
+		fieldErrors = append(fieldErrors, s.checkReference(run.categories, s.repo.CategoryExists, product.CategoryID, "category_id", "category")...)
+	}
+	if product.UoMID != nil {
+		fieldErrors = append(fieldErrors, s.checkReference(run.units, s.repo.UnitExists, *product.UoMID, "uom_id", "unit")...)
+	}
+	for i, variant := range product.Variants {
+		if variant.UoMID != nil {
+			fieldErrors = append(fieldErrors, s.checkReference(run.units, s.repo.UnitExists, *variant.UoMID, fmt.Sprintf("variants[%d].uom_id", i), "unit")...)
+		}
+	}
+	return fieldErrors
+}
+
+// checkReference checks that a category or unit exists, remembering the
+// answer for the rest of the import
+func (s *catalogService) checkReference(known map[uint64]bool, exists func(uint64) (bool, error), id uint64, field, kind string) []catalog.FieldError {
+	found, ok := known[id]
+	if !ok {
+		var err error
+		if found, err = exists(id); err != nil {
+			return []catalog.FieldError{{Field: field, Message: fmt.Sprintf("failed to check %s %d: %v", kind, id, err)}}
+		}
+		known[id] = found
+	}
+	if !found {
+		return []catalog.FieldError{{Field: field, Message: fmt.Sprintf("%s %d does not exist", kind, id)}}
+	}
+	return nil
+}
+
+// validationErrors turns a validation error into one error per field, named
+// in snake case after prefix
+func validationErrors(prefix string, err error) []catalog.FieldError {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return []catalog.FieldError{{Message: err.Error()}}
+	}
+
+	fieldErrors := make([]catalog.FieldError, 0, len(errs))
+	for _, e := range errs {
+		var message string
+		switch e.Tag() {
+		case "required":
+			message = "is required"
+		case "regexp":
+			message = "is not in the expected format"
+		case "oneof":
+			message = fmt.Sprintf("must be one of %s", e.Param())
+		case "gt":
+			message = fmt.Sprintf("must be greater than %s", e.Param())
+		case "gte":
+			message = fmt.Sprintf("must be at least %s", e.Param())
+		case "lte":
+			message = fmt.Sprintf("must be at most %s", e.Param())
+		case "min":
+			message = fmt.Sprintf("must have at least %s characters", e.Param())
+		case "max":
+			message = fmt.Sprintf("must have at most %s characters", e.Param())
+		default:
+			message = fmt.Sprintf("failed the %s rule", e.Tag())
+		}
+		fieldErrors = append(fieldErrors, catalog.FieldError{Field: prefix + snakeCase(e.Field()), Message: message})
+	}
+	return fieldErrors
+}
+
+// snakeCase turns a Go field name such as QuantityValue or SKU into quantity_value or sku
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && unicode.IsLower(runes[i-1]) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// saveImportProgress stores the row errors gathered so far and the job's
+// counts. Failures are logged, as the import goes on either way.
+func (s *catalogService) saveImportProgress(run *importRun) {
+	if err := s.repo.AddImportRowErrors(run.rowErrors); err != nil {
+		log.Printf("Import job %d: failed to save row errors: %v", run.job.ID, err)
+	}
+	run.rowErrors = nil
+	if err := s.repo.UpdateImportJob(run.job); err != nil {
+		log.Printf("Import job %d: failed to save progress: %v", run.job.ID, err)
+	}
+}
+
+func (s *catalogService) finishImport(run *importRun) {
+	finished := time.Now()
+	run.job.FinishedAt = &finished
+	s.saveImportProgress(run)
+	log.Printf("Import job %d %s: %d products, %d created, %d updated, %d failed",
+		run.job.ID, run.job.Status, run.job.Products, run.job.Created, run.job.Updated, run.job.Failed)
+}
+
+func (s *catalogService) GetImportJob(id uint64) (*models.ImportJob, error) {
+	return s.repo.GetImportJob(id)
+}
+
+func (s *catalogService) GetImportJobs(limit, offset int) ([]models.ImportJob, int64, error) {
+	return s.repo.GetImportJobs(limit, offset)
+}
+
+func (s *catalogService) FailUnfinishedImports() error {
+	count, err := s.repo.FailUnfinishedImportJobs()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		log.Printf("Marked %d unfinished import jobs as failed", count)
+	}
+	return nil
+}
+
+func (s *catalogService) Export(ctx context.Context, format, status string, w io.Writer) error {
+	writer, err := catalog.NewWriter(format, w)
+	if err != nil {
+		return err
+	}
+	flusher, _ := w.(http.Flusher)
+
+	var afterID uint64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		products, err := s.repo.GetExportProducts(afterID, exportBatchSize, status)
+		if err != nil {
+			return err
+		}
+		for i := range products {
+			item := catalog.NewItem(&products[i])
+			if err := writer.Write(&item); err != nil {
+				return err
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if len(products) < exportBatchSize {
+			return nil
+		}
+		afterID = products[len(products)-1].ID
+	}
+}
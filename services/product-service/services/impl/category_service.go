@@ -10,11 +10,12 @@ import (
 )
 
 type categoryService struct {
-	repo repository.CategoryRepository
+	repo    repository.CategoryRepository
+	indexer services.ProductIndexer
 }
 
-func NewCategoryService(repo repository.CategoryRepository) services.CategoryService {
-	return &categoryService{repo: repo}
+func NewCategoryService(repo repository.CategoryRepository, indexer services.ProductIndexer) services.CategoryService {
+	return &categoryService{repo: repo, indexer: indexer}
 }
 
 func (s *categoryService) CreateCategory(category *models.Category) error {
@@ -33,12 +34,22 @@ func (s *categoryService) GetCategory(id uint) (*models.Category, error) {
 	return s.repo.GetCategory(id)
 }
 
+// UpdateCategory saves a category and indexes its products again, as they are
+// searchable by its name
 func (s *categoryService) UpdateCategory(id uint, category *models.Category) error {
-	return s.repo.UpdateCategory(id, category)
+	if err := s.repo.UpdateCategory(id, category); err != nil {
+		return err
+	}
+	logIndexError(s.indexer.IndexCategory(uint64(id)), fmt.Sprintf("category %d", id))
+	return nil
 }
 
 func (s *categoryService) DeleteCategory(id uint) error {
-	return s.repo.DeleteCategory(id)
+	if err := s.repo.DeleteCategory(id); err != nil {
+		return err
+	}
+	logIndexError(s.indexer.IndexCategory(uint64(id)), fmt.Sprintf("category %d", id))
+	return nil
 }
 
 func (s *categoryService) GetAllCategories() ([]models.Category, error) {
@@ -3,15 +3,17 @@ package services
 import (
 	"github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
 	"github.com/DurgaPratapRajbhar/e-commerce/product-service/repository"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/search"
 	"github.com/DurgaPratapRajbhar/e-commerce/product-service/services"
 )
 
 type frontendService struct {
-	repo repository.FrontendRepository
+	repo     repository.FrontendRepository
+	searcher services.SearchService
 }
 
-func NewFrontendService(repo repository.FrontendRepository) services.FrontendService {
-	return &frontendService{repo: repo}
+func NewFrontendService(repo repository.FrontendRepository, searcher services.SearchService) services.FrontendService {
+	return &frontendService{repo: repo, searcher: searcher}
 }
 
 func (s *frontendService) GetProductData(slug string) ([]models.Product, error) {
@@ -22,6 +24,40 @@ func (s *frontendService) GetProductsByCategorySlug(slug string) ([]models.Produ
 	return s.repo.GetProductsByCategorySlug(slug)
 }
 
-func (s *frontendService) ProductSearch(search string) ([]models.Product, error) {
-	return s.repo.ProductSearch(search)
+// ProductSearch returns every active product matching text, or every active
+// product when text is empty, in the index's ranking. It pages through the
+// whole result, as the endpoint it serves has never been paged.
+func (s *frontendService) ProductSearch(text string) ([]models.Product, error) {
+	ranked := make([]models.Product, 0)
+	req := &models.SearchRequest{Query: text, Limit: search.MaxLimit}
+	for {
+		result, err := s.searcher.Search(req)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]uint64, len(result.Hits))
+		for i, hit := range result.Hits {
+			ids[i] = hit.ID
+		}
+
+		products, err := s.repo.GetActiveProductsByIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		// Keep the index's ranking
+		byID := make(map[uint64]models.Product, len(products))
+		for _, product := range products {
+			byID[product.ID] = product
+		}
+		for _, id := range ids {
+			if product, ok := byID[id]; ok {
+				ranked = append(ranked, product)
+			}
+		}
+
+		if result.NextCursor == "" {
+			return ranked, nil
+		}
+		req.Cursor = result.NextCursor
+	}
 }
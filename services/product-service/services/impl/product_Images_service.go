@@ -14,17 +14,27 @@ import (
 
 // ProductImagesService implementation
 type productImagesService struct {
-	repo repository.ProductImagesRepository
+	repo    repository.ProductImagesRepository
+	indexer services.ProductIndexer
 }
 
 // NewProductImagesService creates a new service instance
-func NewProductImagesService(repo repository.ProductImagesRepository) services.ProductImagesServices {
-	return &productImagesService{repo: repo}
+func NewProductImagesService(repo repository.ProductImagesRepository, indexer services.ProductIndexer) services.ProductImagesServices {
+	return &productImagesService{repo: repo, indexer: indexer}
+}
+
+// reindex indexes the product again, as search results show its primary image
+func (s *productImagesService) reindex(productID uint64) {
+	logIndexError(s.indexer.IndexProduct(productID), fmt.Sprintf("product %d", productID))
 }
 
 // CreateProductImage saves metadata in the database
 func (s *productImagesService) CreateProductImage(productImage *models.ProductImage) error {
-	return s.repo.CreateProductImage(productImage)
+	if err := s.repo.CreateProductImage(productImage); err != nil {
+		return err
+	}
+	s.reindex(productImage.ProductID)
+	return nil
 }
 
 // GetProductImage retrieves a single product image by ID
@@ -39,17 +49,42 @@ func (s *productImagesService) GetAllProductImages(productID uint64) ([]models.P
 
 // UpdateProductImage updates image metadata
 func (s *productImagesService) UpdateProductImage(id uint64, productImage *models.ProductImage) error {
-	return s.repo.UpdateProductImage(id, productImage)
+	existing, err := s.repo.GetProductImage(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdateProductImage(id, productImage); err != nil {
+		return err
+	}
+	s.reindex(existing.ProductID)
+	if productImage.ProductID != 0 && productImage.ProductID != existing.ProductID {
+		s.reindex(productImage.ProductID)
+	}
+	return nil
 }
 
 // DeleteProductImage removes an image from storage and the database
 func (s *productImagesService) DeleteProductImage(id uint64) error {
-	return s.repo.DeleteProductImage(id)
+	existing, err := s.repo.GetProductImage(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteProductImage(id); err != nil {
+		return err
+	}
+	s.reindex(existing.ProductID)
+	return nil
 }
 
 // SetPrimaryProductImage marks an image as the primary one
 func (s *productImagesService) SetPrimaryProductImage(id uint64) error {
-	return s.repo.SetPrimaryProductImage(id)
+	if err := s.repo.SetPrimaryProductImage(id); err != nil {
+		return err
+	}
+	if image, err := s.repo.GetProductImage(id); err == nil {
+		s.reindex(image.ProductID)
+	}
+	return nil
 }
 
 // ValidateAndStoreImage validates an image file and saves it
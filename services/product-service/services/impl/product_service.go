@@ -1,21 +1,28 @@
 package services
 
 import (
+	"fmt"
+
 	"github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
 	"github.com/DurgaPratapRajbhar/e-commerce/product-service/repository"
 	"github.com/DurgaPratapRajbhar/e-commerce/product-service/services"
 )
 
 type productService struct {
-	repo repository.ProductRepository
+	repo    repository.ProductRepository
+	indexer services.ProductIndexer
 }
 
-func NewProductService(repo repository.ProductRepository) services.ProductService {
-	return &productService{repo: repo}
+func NewProductService(repo repository.ProductRepository, indexer services.ProductIndexer) services.ProductService {
+	return &productService{repo: repo, indexer: indexer}
 }
 
 func (s *productService) CreateProduct(product *models.Product) error {
-	return s.repo.CreateProduct(product)
+	if err := s.repo.CreateProduct(product); err != nil {
+		return err
+	}
+	logIndexError(s.indexer.IndexProduct(product.ID), fmt.Sprintf("product %d", product.ID))
+	return nil
 }
 
 func (s *productService) GetProduct(id uint) (*models.Product, error) {
@@ -23,11 +30,19 @@ func (s *productService) GetProduct(id uint) (*models.Product, error) {
 }
 
 func (s *productService) UpdateProduct(id uint, product *models.Product) error {
-	return s.repo.UpdateProduct(id, product)
+	if err := s.repo.UpdateProduct(id, product); err != nil {
+		return err
+	}
+	logIndexError(s.indexer.IndexProduct(uint64(id)), fmt.Sprintf("product %d", id))
+	return nil
 }
 
 func (s *productService) DeleteProduct(id uint) error {
-	return s.repo.DeleteProduct(id)
+	if err := s.repo.DeleteProduct(id); err != nil {
+		return err
+	}
+	logIndexError(s.indexer.RemoveProduct(uint64(id)), fmt.Sprintf("product %d", id))
+	return nil
 }
 
 func (s *productService) GetAllProducts(limit, offset int) ([]models.Product, int64, error) {
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/client"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/repository"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/search"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/services"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// rebuildBatchSize is how many products Rebuild loads at a time
+	rebuildBatchSize = 500
+	// popularityDays is how far back orders count towards a product's popularity
+	popularityDays = 90
+	// staleStockRetry is how soon the stock is fetched again while the index
+	// has never had it
+	staleStockRetry = 15 * time.Second
+)
+
+type searchService struct {
+	repo            repository.SearchRepository
+	index           search.Index
+	suggester       *search.Suggester
+	orderClient     *client.OrderClient
+	inventoryClient *client.InventoryClient
+
+	// inStock is the set of products inventory-service last reported in stock.
+	// Until it first answers the stock is stale and every product counts as in
+	// stock, so that none is hidden for want of an answer.
+	stockMu    sync.RWMutex
+	inStock    map[uint64]bool
+	stockStale bool
+}
+
+func NewSearchService(repo repository.SearchRepository, index search.Index, suggester *search.Suggester, orderClient *client.OrderClient, inventoryClient *client.InventoryClient) services.SearchService {
+	return &searchService{
+		repo:            repo,
+		index:           index,
+		suggester:       suggester,
+		orderClient:     orderClient,
+		inventoryClient: inventoryClient,
+		inStock:         make(map[uint64]bool),
+		stockStale:      true,
+	}
+}
+
+func (s *searchService) Search(req *models.SearchRequest) (*search.Result, error) {
+	query := search.Query{
+		Text:        req.Query,
+		CategoryIDs: req.CategoryIDs,
+		Brands:      req.Brands,
+		MinPrice:    req.MinPrice,
+		MaxPrice:    req.MaxPrice,
+		Attributes:  make(map[string][]string),
+		InStock:     req.InStock,
+		Sort:        req.Sort,
+		Limit:       req.Limit,
+		Cursor:      req.Cursor,
+	}
+	for _, attribute := range req.Attributes {
+		key, value, found := strings.Cut(attribute, ":")
+		if !found || strings.TrimSpace(key) == "" || strings.TrimSpace(value) == "" {
+			return nil, fmt.Errorf("%w: attribute filter %q is not key:value", services.ErrInvalidSearch, attribute)
+		}
+		query.Attributes[key] = append(query.Attributes[key], value)
+	}
+	if req.MinPrice != nil && req.MaxPrice != nil && *req.MinPrice > *req.MaxPrice {
+		return nil, fmt.Errorf("%w: min_price is above max_price", services.ErrInvalidSearch)
+	}
+
+	result, err := s.index.Search(query)
+	if errors.Is(err, search.ErrInvalidCursor) {
+		return nil, fmt.Errorf("%w: %v", services.ErrInvalidSearch, err)
+	}
+	return result, err
+}
+
+func (s *searchService) Rebuild() (int, error) {
+	var docs []search.Document
+	var afterID uint64
+	for {
+		products, err := s.repo.GetIndexableProducts(afterID, rebuildBatchSize)
+		if err != nil {
+			return 0, err
+		}
+		for i := range products {
+			docs = append(docs, search.NewDocument(&products[i], s.isInStock(products[i].ID)))
+		}
+		if len(products) < rebuildBatchSize {
+			break
+		}
+		afterID = products[len(products)-1].ID
+	}
+
+	if err := s.index.Replace(docs); err != nil {
+		return 0, err
+	}
+	s.suggester.Replace(docs)
+	log.Printf("Search index rebuilt with %d products", len(docs))
+	return len(docs), nil
+}
+
+func (s *searchService) IndexProduct(id uint64) error {
+	product, err := s.repo.GetIndexableProduct(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return s.RemoveProduct(id)
+	}
+	if err != nil {
+		return err
+	}
+	if product.Status != "active" {
+		return s.RemoveProduct(id)
+	}
+	doc := search.NewDocument(product, s.isInStock(product.ID))
+	if err := s.index.Index(doc); err != nil {
+		return err
+	}
+	s.suggester.Add(doc)
+	return nil
+}
+
+func (s *searchService) RemoveProduct(id uint64) error {
+	s.suggester.Remove(id)
+	return s.index.Delete(id)
+}
+
+func (s *searchService) IndexCategory(categoryID uint64) error {
+	ids, err := s.repo.GetProductIDsByCategory(categoryID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := s.IndexProduct(id); err != nil {
+			return fmt.Errorf("product %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+func (s *searchService) Suggest(req *models.SuggestRequest) []search.Suggestion {
+	return s.suggester.Suggest(req.Query, req.Limit)
+}
+
+func (s *searchService) RefreshPopularity(ctx context.Context) error {
+	orders, err := s.orderClient.GetProductOrderCounts(ctx, popularityDays)
+	if err != nil {
+		return err
+	}
+	s.suggester.SetPopularity(orders)
+	return nil
+}
+
+func (s *searchService) RunPopularityRefresh(ctx context.Context, interval time.Duration) {
+	if err := s.RefreshPopularity(ctx); err != nil {
+		log.Printf("suggestion popularity refresh failed: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RefreshPopularity(ctx); err != nil {
+				log.Printf("suggestion popularity refresh failed: %v", err)
+			}
+		}
+	}
+}
+
+func (s *searchService) isInStock(productID uint64) bool {
+	s.stockMu.RLock()
+	defer s.stockMu.RUnlock()
+	return s.stockStale || s.inStock[productID]
+}
+
+func (s *searchService) isStockStale() bool {
+	s.stockMu.RLock()
+	defer s.stockMu.RUnlock()
+	return s.stockStale
+}
+
+// loadStock replaces the set of products in stock and returns the products
+// whose stock ran out or came back
+func (s *searchService) loadStock(ctx context.Context) ([]uint64, error) {
+	inStock, err := s.inventoryClient.GetInStockProductIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.stockMu.Lock()
+	defer s.stockMu.Unlock()
+	var changed []uint64
+	for id := range inStock {
+		if !s.inStock[id] {
+			changed = append(changed, id)
+		}
+	}
+	for id := range s.inStock {
+		if !inStock[id] {
+			changed = append(changed, id)
+		}
+	}
+	s.inStock = inStock
+	s.stockStale = false
+	return changed, nil
+}
+
+func (s *searchService) RefreshStock(ctx context.Context) error {
+	wasStale := s.isStockStale()
+	changed, err := s.loadStock(ctx)
+	if err != nil {
+		return err
+	}
+	if wasStale {
+		// Every product was indexed as in stock until now
+		_, err := s.Rebuild()
+		return err
+	}
+	for _, id := range changed {
+		logIndexError(s.IndexProduct(id), fmt.Sprintf("product %d", id))
+	}
+	return nil
+}
+
+func (s *searchService) RunStockRefresh(ctx context.Context, interval time.Duration) {
+	for {
+		wait := interval
+		if err := s.RefreshStock(ctx); err != nil {
+			log.Printf("search stock refresh failed: %v", err)
+			if s.isStockStale() {
+				wait = staleStockRetry
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// logIndexError reports an index update that failed. The write it followed
+// has succeeded, so the error is not returned; the product is picked up again
+// by its next write or the next rebuild.
+func logIndexError(err error, subject string) {
+	if err != nil {
+		log.Printf("Search index: failed to update %s: %v", subject, err)
+	}
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/models"
+	"github.com/DurgaPratapRajbhar/e-commerce/product-service/search"
+)
+
+// ErrInvalidSearch is returned for a search with a malformed filter or cursor
+var ErrInvalidSearch = errors.New("invalid search")
+
+// ProductIndexer keeps the search index in step with writes to products,
+// their categories and their images
+type ProductIndexer interface {
+	// IndexProduct indexes a product again, or removes it when it is no longer active
+	IndexProduct(id uint64) error
+	RemoveProduct(id uint64) error
+	// IndexCategory indexes the products of a category again
+	IndexCategory(categoryID uint64) error
+}
+
+type SearchService interface {
+	ProductIndexer
+	Search(req *models.SearchRequest) (*search.Result, error)
+	// Suggest completes what the shopper has typed from product names, brands
+	// and category names
+	Suggest(req *models.SuggestRequest) []search.Suggestion
+	// Rebuild indexes every active product afresh with the stock last
+	// reported, returning how many were indexed
+	Rebuild() (int, error)
+	// RefreshStock reloads which products are in stock from inventory-service
+	// and indexes again those whose stock ran out or came back, or every
+	// product when the stock was stale
+	RefreshStock(ctx context.Context) error
+	// RunStockRefresh calls RefreshStock straight away and then every interval
+	// until ctx is done. While inventory-service has never answered it tries
+	// again sooner.
+	RunStockRefresh(ctx context.Context, interval time.Duration)
+	// RefreshPopularity reloads the product order counts that rank suggestions
+	RefreshPopularity(ctx context.Context) error
+	// RunPopularityRefresh calls RefreshPopularity now and then every interval until ctx is done
+	RunPopularityRefresh(ctx context.Context, interval time.Duration)
+}
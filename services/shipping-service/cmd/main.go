@@ -15,10 +15,21 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/events"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/middleware"
 	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/delivery/http"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/infrastructure/carrier"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/infrastructure/client"
 	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/infrastructure/database"
 	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/infrastructure/database/mysql"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/infrastructure/storage"
 	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/usecase"
 
 )
@@ -39,11 +50,35 @@ func main() {
 	shipmentRepo := mysql.NewShipmentRepository(db)
 	trackingRepo := mysql.NewTrackingEventRepository(db)
 
+	// Initialize carriers; the first one is the default
+	carriers := []domain.Carrier{
+		carrier.NewLocalCourier(carrier.LocalCourierConfig{
+			OriginPostalCode: os.Getenv("SHIPPING_ORIGIN_POSTAL_CODE"),
+			Currency:         os.Getenv("SHIPPING_CURRENCY"),
+		}),
+	}
+
 	// Initialize use cases
-	shippingUseCase := usecase.NewShippingUseCase(shipmentRepo, trackingRepo)
+	globalConfig := config.LoadConfig()
+	labelStore := storage.NewLabelStore(globalConfig)
+	orderClient := client.NewOrderClient(globalConfig)
+	shippingUseCase := usecase.NewShippingUseCase(shipmentRepo, trackingRepo, carriers, labelStore, orderClient)
+	trackingWebhookUseCase := usecase.NewTrackingWebhookUseCase(shipmentRepo, trackingRepo, carriers, globalConfig.Webhooks.CarrierSecrets, globalConfig.Webhooks.Tolerance)
+
+	// Deliver outbox events to the services listed in EVENT_SUBSCRIBERS_SHIPPING.
+	// Shipment status changes update the order's fulfilment status.
+	broker := events.NewHTTPBroker(globalConfig.Events.Subscribers["shipping"], globalConfig.Events.Secret, globalConfig.Services.ShippingService.Timeout)
+	broker.Subscribe(events.AllEvents, events.LogHandler)
+	broker.Subscribe(events.ShipmentStatusChanged, shippingUseCase.SyncOrderFulfilment)
+	broker.Subscribe(events.ShipmentDelivered, shippingUseCase.SyncOrderFulfilment)
+	go events.NewRelay(db, broker).Run(context.Background())
+
+	// Store the labels storage-service could not take when their shipment was created
+	go shippingUseCase.RunLabelRetries(context.Background(), time.Minute)
 
 	// Initialize HTTP server
-	server := http.NewServer(shippingUseCase)
+	publicLimit := middleware.RateLimitMiddleware(globalConfig.RateLimit.RequestsPerMinute, globalConfig.RateLimit.BurstSize)
+	server := http.NewServer(shippingUseCase, trackingWebhookUseCase, publicLimit, globalConfig.Server.TrustedProxies)
 
 	// The actual port is configured via environment variables and logged in the server.Start() method
 	log.Fatal(server.Start())
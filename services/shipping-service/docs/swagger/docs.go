@@ -684,9 +684,14 @@ const docTemplate = `{
                     "type": "string",
                     "enum": [
                         "pending",
+                        "label_created",
+                        "picked_up",
                         "in_transit",
+                        "out_for_delivery",
                         "delivered",
-                        "returned"
+                        "failed_attempt",
+                        "returned_to_sender",
+                        "cancelled"
                     ],
                     "example": "pending"
                 },
@@ -742,9 +747,14 @@ const docTemplate = `{
                     "type": "string",
                     "enum": [
                         "pending",
+                        "label_created",
+                        "picked_up",
                         "in_transit",
+                        "out_for_delivery",
                         "delivered",
-                        "returned"
+                        "failed_attempt",
+                        "returned_to_sender",
+                        "cancelled"
                     ]
                 },
                 "tracking_number": {
@@ -0,0 +1,115 @@
+// Package handlers provides HTTP handlers for carrier operations
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain/entity"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// QuoteShipment returns shipping rate options
+// @Summary Quote shipping rates
+// @Description Get rate options from the carriers for a parcel and destination, cheapest first
+// @Tags shipments
+// @Accept json
+// @Produce json
+// @Param quote body entity.QuoteRequest true "Quote Request"
+// @Success 200 {array} entity.RateQuote
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/shipments/quote [post]
+func QuoteShipment(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req entity.QuoteRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			validationErrors := utils.ParseValidationErrors(err.Error())
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		quotes, err := useCase.QuoteRates(c.Request.Context(), &req)
+		if err != nil {
+			respondShipmentError(c, "Failed to quote shipping rates", err)
+			return
+		}
+
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusOK, utils.SuccessResponse(quotes, "Shipping rates retrieved successfully", requestID))
+	}
+}
+
+// CancelShipment cancels a shipment
+// @Summary Cancel shipment
+// @Description Cancel a shipment that has not been picked up and void its carrier booking
+// @Tags shipments
+// @Accept json
+// @Produce json
+// @Param id path string true "Shipment ID"
+// @Success 200 {object} entity.Shipment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/shipments/{id}/cancel [post]
+func CancelShipment(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			validationErrors := []utils.ValidationError{
+				{Field: "id", Message: "invalid shipment ID"},
+			}
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		shipment, err := useCase.CancelShipment(c.Request.Context(), uint(id))
+		if err != nil {
+			respondShipmentError(c, "Failed to cancel shipment", err)
+			return
+		}
+
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusOK, utils.SuccessResponse(shipment, "Shipment cancelled successfully", requestID))
+	}
+}
+
+// SyncCarrierTracking pulls new scans from the shipment's carrier
+// @Summary Sync carrier tracking
+// @Description Record the carrier's scans that are newer than the shipment's latest tracking event
+// @Tags shipments
+// @Accept json
+// @Produce json
+// @Param id path string true "Shipment ID"
+// @Success 200 {object} entity.Shipment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/shipments/{id}/tracking/sync [post]
+func SyncCarrierTracking(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			validationErrors := []utils.ValidationError{
+				{Field: "id", Message: "invalid shipment ID"},
+			}
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		shipment, err := useCase.SyncCarrierTracking(c.Request.Context(), uint(id))
+		if err != nil {
+			respondShipmentError(c, "Failed to sync carrier tracking", err)
+			return
+		}
+
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusOK, utils.SuccessResponse(shipment, "Carrier tracking synced successfully", requestID))
+	}
+}
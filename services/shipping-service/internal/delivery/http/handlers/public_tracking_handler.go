@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// TrackShipment returns the public tracking timeline for a parcel
+// @Summary Track a parcel
+// @Description Public, rate-limited tracking by tracking number. Only the status and a bare timeline are returned unless postal_code matches the destination, in which case event locations, descriptions and the delivery time are included. After 5 wrong postal codes for a tracking number, postal codes for it get 429 for 15 minutes.
+// @Tags tracking
+// @Produce json
+// @Param tracking_number path string true "Tracking Number"
+// @Param postal_code query string false "Destination postal code"
+// @Success 200 {object} entity.PublicTracking
+// @Failure 404 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/track/{tracking_number} [get]
+func TrackShipment(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tracking, err := useCase.TrackShipment(c.Request.Context(), c.Param("tracking_number"), c.Query("postal_code"))
+		if err != nil {
+			requestID := utils.GenerateRequestID()
+			if errors.Is(err, domain.ErrShipmentNotFound) {
+				c.JSON(http.StatusNotFound, utils.ErrorResponse(utils.ErrNotFound, "Tracking number not found", nil, requestID))
+				return
+			}
+			if errors.Is(err, domain.ErrTooManyAttempts) {
+				c.Header("Retry-After", strconv.Itoa(int(usecase.PostalCodeFailureWindow.Seconds())))
+				c.JSON(http.StatusTooManyRequests, utils.ErrorResponse(utils.ErrRateLimitExceeded, "Too many wrong postal codes; try again later", nil, requestID))
+				return
+			}
+			// Internal errors are not passed on to anonymous callers
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to load tracking", nil, requestID))
+			return
+		}
+
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusOK, utils.SuccessResponse(tracking, "Tracking retrieved successfully", requestID))
+	}
+}
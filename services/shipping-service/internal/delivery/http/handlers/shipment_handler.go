@@ -2,10 +2,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain"
 	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/usecase"
 	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain/entity"
 	"github.com/gin-gonic/gin"
@@ -13,13 +15,15 @@ import (
 
 // CreateShipment creates a new shipment
 // @Summary Create shipment
-// @Description Create a new shipment
+// @Description Book a shipment with a carrier, which issues the tracking number and label. An order can be split over several shipments by listing the order items and quantities in each; without items the shipment takes everything not yet shipped. A shipment of type "return" is collected from the destination address and brings the listed items back.
 // @Tags shipments
 // @Accept json
 // @Produce json
 // @Param shipment body entity.ShipmentCreateRequest true "Shipment Create Request"
 // @Success 200 {object} entity.Shipment
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/shipments [post]
 func CreateShipment(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
@@ -34,8 +38,7 @@ func CreateShipment(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
 
 		shipment, err := useCase.CreateShipment(c.Request.Context(), &req)
 		if err != nil {
-			requestID := utils.GenerateRequestID()
-			c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to create shipment", err.Error(), requestID))
+			respondShipmentError(c, "Failed to create shipment", err)
 			return
 		}
 
@@ -79,18 +82,18 @@ func GetShipment(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
 	}
 }
 
-// GetShipmentByOrderID gets a shipment by order ID
-// @Summary Get shipment by order ID
-// @Description Get a shipment by order ID
+// GetShipmentsByOrderID gets the shipments of an order
+// @Summary Get shipments by order ID
+// @Description Get every shipment of an order with its items, oldest first
 // @Tags shipments
 // @Accept json
 // @Produce json
 // @Param order_id path string true "Order ID"
-// @Success 200 {object} entity.Shipment
+// @Success 200 {array} entity.Shipment
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/shipments/order/{order_id} [get]
-func GetShipmentByOrderID(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
+func GetShipmentsByOrderID(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		orderID, err := strconv.ParseUint(c.Param("order_id"), 10, 32)
 		if err != nil {
@@ -102,15 +105,50 @@ func GetShipmentByOrderID(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
 			return
 		}
 
-		shipment, err := useCase.GetShipmentByOrderID(c.Request.Context(), uint(orderID))
+		shipments, err := useCase.GetShipmentsByOrderID(c.Request.Context(), uint(orderID))
 		if err != nil {
 			requestID := utils.GenerateRequestID()
-			c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to get shipment", err.Error(), requestID))
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to get shipments", err.Error(), requestID))
 			return
 		}
 
 		requestID := utils.GenerateRequestID()
-		c.JSON(http.StatusOK, utils.SuccessResponse(shipment, "Shipment retrieved successfully", requestID))
+		c.JSON(http.StatusOK, utils.SuccessResponse(shipments, "Shipments retrieved successfully", requestID))
+	}
+}
+
+// GetOrderFulfilment gets the fulfilment rollup of an order
+// @Summary Get order fulfilment
+// @Description Get how much of each order item is in shipments, shipped and delivered, and the order's fulfilment status (unfulfilled, partially_shipped, shipped, delivered)
+// @Tags shipments
+// @Accept json
+// @Produce json
+// @Param order_id path string true "Order ID"
+// @Success 200 {object} entity.OrderFulfilment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/shipments/order/{order_id}/fulfilment [get]
+func GetOrderFulfilment(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID, err := strconv.ParseUint(c.Param("order_id"), 10, 32)
+		if err != nil {
+			validationErrors := []utils.ValidationError{
+				{Field: "order_id", Message: "invalid order ID"},
+			}
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ValidationErrorResponse(validationErrors, requestID))
+			return
+		}
+
+		fulfilment, err := useCase.GetOrderFulfilment(c.Request.Context(), uint(orderID))
+		if err != nil {
+			respondShipmentError(c, "Failed to get order fulfilment", err)
+			return
+		}
+
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusOK, utils.SuccessResponse(fulfilment, "Order fulfilment retrieved successfully", requestID))
 	}
 }
 
@@ -175,8 +213,7 @@ func UpdateShipment(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
 
 		shipment, err := useCase.UpdateShipment(c.Request.Context(), uint(id), &req)
 		if err != nil {
-			requestID := utils.GenerateRequestID()
-			c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to update shipment", err.Error(), requestID))
+			respondShipmentError(c, "Failed to update shipment", err)
 			return
 		}
 
@@ -187,14 +224,16 @@ func UpdateShipment(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
 
 // UpdateShipmentStatus updates a shipment status
 // @Summary Update shipment status
-// @Description Update a shipment status
+// @Description Move a shipment to a new status. Transitions the shipment state machine does not allow are rejected with 409.
 // @Tags shipments
 // @Accept json
 // @Produce json
 // @Param id path string true "Shipment ID"
 // @Param status body map[string]string true "Status"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} entity.Shipment
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/shipments/{id}/status [patch]
 func UpdateShipmentStatus(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
@@ -210,7 +249,7 @@ func UpdateShipmentStatus(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
 		}
 
 		var req struct {
-			Status string `json:"status" validate:"required,oneof=pending in_transit delivered returned"`
+			Status string `json:"status" validate:"required,oneof=pending label_created picked_up in_transit out_for_delivery delivered failed_attempt returned_to_sender cancelled"`
 		}
 		if err := c.ShouldBindJSON(&req); err != nil {
 			validationErrors := utils.ParseValidationErrors(err.Error())
@@ -219,15 +258,14 @@ func UpdateShipmentStatus(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
 			return
 		}
 
-		err = useCase.UpdateShipmentStatus(c.Request.Context(), uint(id), req.Status)
+		shipment, err := useCase.UpdateShipmentStatus(c.Request.Context(), uint(id), req.Status)
 		if err != nil {
-			requestID := utils.GenerateRequestID()
-			c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to update shipment status", err.Error(), requestID))
+			respondShipmentError(c, "Failed to update shipment status", err)
 			return
 		}
 
 		requestID := utils.GenerateRequestID()
-		c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Shipment status updated successfully", requestID))
+		c.JSON(http.StatusOK, utils.SuccessResponse(shipment, "Shipment status updated successfully", requestID))
 	}
 }
 
@@ -333,4 +371,20 @@ func GetShipmentsByStatus(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
 		requestID := utils.GenerateRequestID()
 		c.JSON(http.StatusOK, utils.SuccessResponse(shipments, "Shipments retrieved successfully", requestID))
 	}
-}
\ No newline at end of file
+}
+
+// respondShipmentError maps shipping domain errors to HTTP responses
+func respondShipmentError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrCarrierNotFound), errors.Is(err, domain.ErrUnsupportedService),
+		errors.Is(err, domain.ErrOrderItemNotFound), errors.Is(err, domain.ErrReturnItemsRequired),
+		errors.Is(err, domain.ErrReturnQuantityExceeded):
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, message, err.Error(), utils.GenerateRequestID()))
+	case errors.Is(err, domain.ErrInvalidStatusTransition), errors.Is(err, domain.ErrNothingToShip):
+		c.JSON(http.StatusConflict, utils.ErrorResponse(utils.ErrConflict, message, err.Error(), utils.GenerateRequestID()))
+	case errors.Is(err, domain.ErrShipmentNotFound), errors.Is(err, domain.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(utils.ErrNotFound, message, err.Error(), utils.GenerateRequestID()))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, message, err.Error(), utils.GenerateRequestID()))
+	}
+}
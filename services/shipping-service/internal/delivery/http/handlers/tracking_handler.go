@@ -13,13 +13,14 @@ import (
 
 // CreateTrackingEvent creates a new tracking event
 // @Summary Create tracking event
-// @Description Create a new tracking event
+// @Description Record a tracking event. Known event types (label_created, picked_up, in_transit, out_for_delivery, delivered, failed_attempt, returned_to_sender) also move the shipment to that status.
 // @Tags tracking
 // @Accept json
 // @Produce json
 // @Param tracking_event body entity.TrackingEventCreateRequest true "Tracking Event Create Request"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/tracking [post]
 func CreateTrackingEvent(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
@@ -34,8 +35,7 @@ func CreateTrackingEvent(useCase *usecase.ShippingUseCase) gin.HandlerFunc {
 
 		err := useCase.CreateTrackingEvent(c.Request.Context(), &req)
 		if err != nil {
-			requestID := utils.GenerateRequestID()
-			c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to create tracking event", err.Error(), requestID))
+			respondShipmentError(c, "Failed to create tracking event", err)
 			return
 		}
 
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookBody bounds the size of a carrier webhook delivery
+const maxWebhookBody = 1 << 20
+
+// CarrierTrackingWebhook receives a signed batch of tracking updates from a carrier
+// @Summary Carrier tracking webhook
+// @Description Verify and store a batch of carrier status updates and advance the shipments they belong to. Events already received are skipped. The body is signed with HMAC-SHA256 over "<timestamp>.<body>".
+// @Tags tracking
+// @Accept json
+// @Produce json
+// @Param carrier path string true "Carrier"
+// @Param X-Webhook-Signature header string true "sha256=<hex signature>"
+// @Param X-Webhook-Timestamp header string true "Unix timestamp"
+// @Param webhook body entity.CarrierWebhook true "Carrier Webhook"
+// @Success 200 {object} entity.TrackingWebhookResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /shipment/tracking/webhooks/{carrier} [post]
+func CarrierTrackingWebhook(useCase *usecase.TrackingWebhookUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBody))
+		if err != nil {
+			requestID := utils.GenerateRequestID()
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, "Failed to read webhook body", err.Error(), requestID))
+			return
+		}
+
+		result, err := useCase.HandleWebhook(
+			c.Request.Context(),
+			c.Param("carrier"),
+			c.GetHeader(utils.WebhookSignatureHeader),
+			c.GetHeader(utils.WebhookTimestampHeader),
+			body,
+		)
+		if err != nil {
+			requestID := utils.GenerateRequestID()
+			switch {
+			case errors.Is(err, domain.ErrCarrierNotFound):
+				c.JSON(http.StatusNotFound, utils.ErrorResponse(utils.ErrNotFound, "Unknown webhook carrier", err.Error(), requestID))
+			case errors.Is(err, domain.ErrInvalidSignature):
+				c.JSON(http.StatusUnauthorized, utils.ErrorResponse(utils.ErrUnauthorized, "Invalid webhook signature", err.Error(), requestID))
+			case errors.Is(err, domain.ErrInvalidWebhook):
+				c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, "Invalid webhook payload", err.Error(), requestID))
+			default:
+				c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to process webhook", err.Error(), requestID))
+			}
+			return
+		}
+
+		requestID := utils.GenerateRequestID()
+		c.JSON(http.StatusOK, utils.SuccessResponse(result, "Webhook processed successfully", requestID))
+	}
+}
@@ -9,7 +9,9 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRoutes(router *gin.Engine, shippingUseCase *usecase.ShippingUseCase) {
+// SetupRoutes registers the shipping routes. publicLimit rate-limits the
+// unauthenticated tracking page.
+func SetupRoutes(router *gin.Engine, shippingUseCase *usecase.ShippingUseCase, trackingWebhookUseCase *usecase.TrackingWebhookUseCase, publicLimit gin.HandlerFunc) {
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
@@ -18,6 +20,12 @@ func SetupRoutes(router *gin.Engine, shippingUseCase *usecase.ShippingUseCase) {
 	// Swagger docs
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Carrier webhooks authenticate with their signature instead of service headers
+	router.POST("/shipment/tracking/webhooks/:carrier", handlers.CarrierTrackingWebhook(trackingWebhookUseCase))
+
+	// Public tracking page for customers following a link; no service headers
+	router.GET("/track/:tracking_number", publicLimit, handlers.TrackShipment(shippingUseCase))
+
 	api := router.Group("/shipment")
 	api.Use(middleware.ServiceAuthMiddleware())
 	{
@@ -26,14 +34,18 @@ func SetupRoutes(router *gin.Engine, shippingUseCase *usecase.ShippingUseCase) {
 		{
 			// Static/specific routes first
 			shipments.GET("all", handlers.GetShipments(shippingUseCase))
-			shipments.GET("/order/:order_id", handlers.GetShipmentByOrderID(shippingUseCase))
+			shipments.GET("/order/:order_id", handlers.GetShipmentsByOrderID(shippingUseCase))
+			shipments.GET("/order/:order_id/fulfilment", handlers.GetOrderFulfilment(shippingUseCase))
 			shipments.GET("/status/:status", handlers.GetShipmentsByStatus(shippingUseCase))
+			shipments.POST("/quote", handlers.QuoteShipment(shippingUseCase))
 			
 			// Parameterized routes last
 			shipments.POST("", handlers.CreateShipment(shippingUseCase))
 			shipments.GET("/:id", handlers.GetShipment(shippingUseCase))
 			shipments.PUT("/:id", handlers.UpdateShipment(shippingUseCase))
 			shipments.PATCH("/:id/status", handlers.UpdateShipmentStatus(shippingUseCase))
+			shipments.POST("/:id/cancel", handlers.CancelShipment(shippingUseCase))
+			shipments.POST("/:id/tracking/sync", handlers.SyncCarrierTracking(shippingUseCase))
 			shipments.DELETE("/:id", handlers.DeleteShipment(shippingUseCase))
 		}
 
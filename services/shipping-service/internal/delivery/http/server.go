@@ -17,19 +17,29 @@ import (
 )
 
 type Server struct {
-	shippingUseCase *usecase.ShippingUseCase
-	router          *gin.Engine
+	shippingUseCase        *usecase.ShippingUseCase
+	trackingWebhookUseCase *usecase.TrackingWebhookUseCase
+	publicLimit            gin.HandlerFunc
+	router                 *gin.Engine
 }
 
-func NewServer(shippingUseCase *usecase.ShippingUseCase) *Server {
+// NewServer creates the HTTP server. Client IPs, which the public rate limit
+// is keyed on, are only taken from X-Forwarded-For when the request came
+// through one of trustedProxies.
+func NewServer(shippingUseCase *usecase.ShippingUseCase, trackingWebhookUseCase *usecase.TrackingWebhookUseCase, publicLimit gin.HandlerFunc, trustedProxies []string) *Server {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 
 	s := &Server{
-		shippingUseCase: shippingUseCase,
-		router:          router,
+		shippingUseCase:        shippingUseCase,
+		trackingWebhookUseCase: trackingWebhookUseCase,
+		publicLimit:            publicLimit,
+		router:                 router,
 	}
 
 	s.setupRoutes()
@@ -37,7 +47,7 @@ func NewServer(shippingUseCase *usecase.ShippingUseCase) *Server {
 }
 
 func (s *Server) setupRoutes() {
-	routes.SetupRoutes(s.router, s.shippingUseCase)
+	routes.SetupRoutes(s.router, s.shippingUseCase, s.trackingWebhookUseCase, s.publicLimit)
 }
 
 func (s *Server) Start() error {
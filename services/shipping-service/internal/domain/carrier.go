@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain/entity"
+)
+
+// Carrier is a shipping carrier. A carrier quotes rates, books shipments and
+// produces their labels, reports tracking scans and cancels shipments that have
+// not been picked up. TrackingEventType maps the carrier's status codes, as sent
+// to its tracking webhook, to TrackingEvent.EventType values.
+type Carrier interface {
+	Name() string
+	TrackingEventType(code string) (string, bool)
+	Quote(ctx context.Context, req entity.QuoteRequest) ([]entity.RateQuote, error)
+	CreateLabel(ctx context.Context, req entity.LabelRequest) (*entity.CarrierLabel, error)
+	Track(ctx context.Context, trackingNumber string) ([]entity.CarrierTrackingEvent, error)
+	Cancel(ctx context.Context, trackingNumber string) error
+}
+
+// LabelStore keeps generated shipping labels and returns the URL they are served from
+type LabelStore interface {
+	Save(ctx context.Context, fileName, contentType string, data []byte) (string, error)
+}
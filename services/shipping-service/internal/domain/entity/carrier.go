@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+// Label formats
+const (
+	LabelFormatPDF = "pdf"
+	LabelFormatZPL = "zpl"
+)
+
+// Parcel is the package being shipped. Dimensions are optional; when given they
+// are used for volumetric weight.
+type Parcel struct {
+	WeightGrams int `json:"weight_grams" binding:"required,gt=0"`
+	LengthCm    int `json:"length_cm,omitempty" binding:"gte=0"`
+	WidthCm     int `json:"width_cm,omitempty" binding:"gte=0"`
+	HeightCm    int `json:"height_cm,omitempty" binding:"gte=0"`
+}
+
+// Address is a delivery address as printed on a label
+type Address struct {
+	Name       string `json:"name" binding:"max=100"`
+	Line1      string `json:"line1" binding:"max=200"`
+	Line2      string `json:"line2,omitempty" binding:"max=200"`
+	City       string `json:"city" binding:"max=100"`
+	State      string `json:"state,omitempty" binding:"max=100"`
+	PostalCode string `json:"postal_code" binding:"max=20"`
+	Country    string `json:"country,omitempty" binding:"max=2"`
+	Phone      string `json:"phone,omitempty" binding:"max=20"`
+}
+
+// QuoteRequest asks the carriers for rates to ship a parcel to a destination.
+// Carrier limits the quote to one carrier.
+type QuoteRequest struct {
+	Carrier               string `json:"carrier,omitempty" binding:"max=100"`
+	OriginPostalCode      string `json:"origin_postal_code,omitempty" binding:"max=20"`
+	DestinationPostalCode string `json:"destination_postal_code" binding:"required,max=20"`
+	DestinationCountry    string `json:"destination_country,omitempty" binding:"max=2"`
+	Parcel                Parcel `json:"parcel"`
+}
+
+// RateQuote is one shipping option offered by a carrier
+type RateQuote struct {
+	Carrier           string      `json:"carrier"`
+	ServiceLevel      string      `json:"service_level"`
+	Amount            money.Money `json:"amount"`
+	EstimatedDays     int         `json:"estimated_days"`
+	EstimatedDelivery time.Time   `json:"estimated_delivery"`
+}
+
+// LabelRequest asks a carrier to book a shipment and produce its label. A
+// Return shipment is collected from Destination and delivered to the origin.
+type LabelRequest struct {
+	OrderID          uint
+	ServiceLevel     string
+	OriginPostalCode string
+	Destination      Address
+	Parcel           Parcel
+	Format           string
+	Return           bool
+}
+
+// CarrierLabel is a booked carrier shipment and its printable label
+type CarrierLabel struct {
+	TrackingNumber    string
+	ServiceLevel      string
+	Amount            money.Money
+	EstimatedDelivery time.Time
+	Format            string
+	ContentType       string
+	Data              []byte
+}
+
+// CarrierTrackingEvent is a scan reported by a carrier
+type CarrierTrackingEvent struct {
+	EventID     string    `json:"event_id"`
+	EventType   string    `json:"event_type"`
+	Location    string    `json:"location,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
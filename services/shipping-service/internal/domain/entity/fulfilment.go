@@ -0,0 +1,174 @@
+package entity
+
+import "github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+
+// ShipmentItem is a quantity of one order item packed in a shipment
+type ShipmentItem struct {
+	ID          uint `json:"id" gorm:"primaryKey"`
+	ShipmentID  uint `json:"shipment_id" gorm:"not null;index"`
+	OrderItemID uint `json:"order_item_id" gorm:"not null;index"`
+	Quantity    int  `json:"quantity" gorm:"not null"`
+}
+
+// ShipmentItemRequest names an order item and how many of it go in a shipment
+type ShipmentItemRequest struct {
+	OrderItemID uint `json:"order_item_id" binding:"required"`
+	Quantity    int  `json:"quantity" binding:"required,gt=0"`
+}
+
+// OrderLine is an order item as order-service reports it
+type OrderLine struct {
+	OrderItemID uint `json:"order_item_id"`
+	ProductID   uint `json:"product_id"`
+	VariantID   uint `json:"variant_id"`
+	Quantity    int  `json:"quantity"`
+}
+
+// ItemFulfilment is how much of an order item is in shipments, shipped and delivered
+type ItemFulfilment struct {
+	OrderItemID uint `json:"order_item_id"`
+	Ordered     int  `json:"ordered"`
+	Allocated   int  `json:"allocated"`
+	Shipped     int  `json:"shipped"`
+	Delivered   int  `json:"delivered"`
+}
+
+// OrderFulfilment is the rollup of an order's shipments
+type OrderFulfilment struct {
+	OrderID   uint             `json:"order_id"`
+	Status    string           `json:"status" example:"partially_shipped"`
+	Items     []ItemFulfilment `json:"items"`
+	Shipments []Shipment       `json:"shipments"`
+}
+
+// holdsItems reports whether a shipment's items count against the order.
+// Cancelled shipments and parcels returned to sender free their items to be
+// shipped again, and return shipments bring items back rather than send them.
+func holdsItems(shipment Shipment) bool {
+	if shipment.Type == ShipmentTypeReturn {
+		return false
+	}
+	return shipment.Status != ShipmentStatusCancelled && shipment.Status != ShipmentStatusReturnedToSender
+}
+
+// hasShipped reports whether a shipment has been handed to the carrier
+func hasShipped(status string) bool {
+	switch status {
+	case ShipmentStatusPickedUp, ShipmentStatusInTransit, ShipmentStatusOutForDelivery,
+		ShipmentStatusFailedAttempt, ShipmentStatusDelivered:
+		return true
+	}
+	return false
+}
+
+// shipmentQuantities returns the quantity per order item in a shipment. A
+// shipment without items predates split shipments and covers the whole order.
+func shipmentQuantities(shipment Shipment, lines []OrderLine) map[uint]int {
+	quantities := make(map[uint]int, len(lines))
+	if len(shipment.Items) == 0 {
+		for _, line := range lines {
+			quantities[line.OrderItemID] = line.Quantity
+		}
+		return quantities
+	}
+	for _, item := range shipment.Items {
+		quantities[item.OrderItemID] += item.Quantity
+	}
+	return quantities
+}
+
+// RemainingToShip returns, per order item, the quantity that is not yet in a
+// shipment holding items
+func RemainingToShip(lines []OrderLine, shipments []Shipment) map[uint]int {
+	remaining := make(map[uint]int, len(lines))
+	for _, line := range lines {
+		remaining[line.OrderItemID] = line.Quantity
+	}
+	for _, shipment := range shipments {
+		if !holdsItems(shipment) {
+			continue
+		}
+		for itemID, quantity := range shipmentQuantities(shipment, lines) {
+			remaining[itemID] -= quantity
+		}
+	}
+	return remaining
+}
+
+// RemainingToReturn returns, per order item, the quantity that is not yet in
+// a return shipment. Cancelled returns and returns sent back to the customer
+// free their items to be returned again.
+func RemainingToReturn(lines []OrderLine, shipments []Shipment) map[uint]int {
+	remaining := make(map[uint]int, len(lines))
+	for _, line := range lines {
+		remaining[line.OrderItemID] = line.Quantity
+	}
+	for _, shipment := range shipments {
+		if shipment.Type != ShipmentTypeReturn || shipment.Status == ShipmentStatusCancelled || shipment.Status == ShipmentStatusReturnedToSender {
+			continue
+		}
+		for _, item := range shipment.Items {
+			remaining[item.OrderItemID] -= item.Quantity
+		}
+	}
+	return remaining
+}
+
+// RollupFulfilment works out an order's fulfilment status from its shipments:
+// delivered once every item is delivered, shipped once every item has been
+// handed to a carrier, partially_shipped once any has, and unfulfilled before.
+func RollupFulfilment(orderID uint, lines []OrderLine, shipments []Shipment) *OrderFulfilment {
+	items := make([]ItemFulfilment, len(lines))
+	index := make(map[uint]int, len(lines))
+	for i, line := range lines {
+		items[i] = ItemFulfilment{OrderItemID: line.OrderItemID, Ordered: line.Quantity}
+		index[line.OrderItemID] = i
+	}
+
+	for _, shipment := range shipments {
+		if !holdsItems(shipment) {
+			continue
+		}
+		for itemID, quantity := range shipmentQuantities(shipment, lines) {
+			i, ok := index[itemID]
+			if !ok {
+				continue
+			}
+			items[i].Allocated += quantity
+			if hasShipped(shipment.Status) {
+				items[i].Shipped += quantity
+			}
+			if shipment.Status == ShipmentStatusDelivered {
+				items[i].Delivered += quantity
+			}
+		}
+	}
+
+	allShipped, allDelivered, anyShipped := len(items) > 0, len(items) > 0, false
+	for _, item := range items {
+		if item.Shipped < item.Ordered {
+			allShipped = false
+		}
+		if item.Delivered < item.Ordered {
+			allDelivered = false
+		}
+		if item.Shipped > 0 {
+			anyShipped = true
+		}
+	}
+
+	status := utils.FulfilmentStatusUnfulfilled
+	switch {
+	case allDelivered:
+		status = utils.FulfilmentStatusDelivered
+	case allShipped:
+		status = utils.FulfilmentStatusShipped
+	case anyShipped:
+		status = utils.FulfilmentStatusPartiallyShipped
+	}
+
+	if shipments == nil {
+		shipments = []Shipment{}
+	}
+	return &OrderFulfilment{OrderID: orderID, Status: status, Items: items, Shipments: shipments}
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "testing"
+
+func TestRemainingToReturn(t *testing.T) {
+	lines := []OrderLine{{OrderItemID: 1, Quantity: 3}, {OrderItemID: 2, Quantity: 1}}
+	returnOf := func(status string, quantity int) Shipment {
+		return Shipment{Type: ShipmentTypeReturn, Status: status, Items: []ShipmentItem{{OrderItemID: 1, Quantity: quantity}}}
+	}
+	tests := []struct {
+		name      string
+		shipments []Shipment
+		want      int
+	}{
+		{"no returns", nil, 3},
+		{"outbound shipments do not count", []Shipment{{Type: ShipmentTypeOutbound, Status: ShipmentStatusDelivered, Items: []ShipmentItem{{OrderItemID: 1, Quantity: 3}}}}, 3},
+		{"pending return", []Shipment{returnOf(ShipmentStatusPending, 1)}, 2},
+		{"earlier returns add up", []Shipment{returnOf(ShipmentStatusDelivered, 1), returnOf(ShipmentStatusInTransit, 2)}, 0},
+		{"cancelled return frees its items", []Shipment{returnOf(ShipmentStatusCancelled, 2)}, 3},
+		{"return sent back frees its items", []Shipment{returnOf(ShipmentStatusReturnedToSender, 2)}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remaining := RemainingToReturn(lines, tt.shipments)
+			if remaining[1] != tt.want {
+				t.Errorf("RemainingToReturn item 1 = %d, want %d", remaining[1], tt.want)
+			}
+			if remaining[2] != 1 {
+				t.Errorf("RemainingToReturn item 2 = %d, want 1", remaining[2])
+			}
+		})
+	}
+}
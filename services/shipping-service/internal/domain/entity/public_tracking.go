@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// PublicTracking is the customer-facing view of a shipment. Without the
+// destination postal code only the status and a bare timeline are shown;
+// Verified is set when the postal code matched and the full detail is included.
+type PublicTracking struct {
+	TrackingNumber    string                `json:"tracking_number"`
+	Carrier           string                `json:"carrier"`
+	Status            string                `json:"status"`
+	EstimatedDelivery *time.Time            `json:"estimated_delivery,omitempty"`
+	DeliveredAt       *time.Time            `json:"delivered_at,omitempty"`
+	Verified          bool                  `json:"verified"`
+	Events            []PublicTrackingEvent `json:"events"`
+}
+
+// PublicTrackingEvent is one step of the public timeline. Location and
+// Description are only filled in for verified requests.
+type PublicTrackingEvent struct {
+	EventType   string    `json:"event_type"`
+	Location    string    `json:"location,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
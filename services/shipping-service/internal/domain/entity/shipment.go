@@ -2,27 +2,56 @@ package entity
 
 import (
 	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+)
+
+// Shipment types. Return shipments bring items of an order back and do not
+// count towards its fulfilment.
+const (
+	ShipmentTypeOutbound = "outbound"
+	ShipmentTypeReturn   = "return"
 )
 
 type Shipment struct {
-	ID                 uint      `json:"id" gorm:"primaryKey"`
-	OrderID            uint      `json:"order_id" gorm:"not null;index" validate:"required"`
-	TrackingNumber     string    `json:"tracking_number" gorm:"unique;not null;size:100" validate:"required,max=100"`
-	Carrier            string    `json:"carrier" gorm:"size:100" validate:"max=100"`
-	ShippingMethod     string    `json:"shipping_method" gorm:"size:50" validate:"max=50"`
-	Status             string    `json:"status" gorm:"size:20;default:'pending'" validate:"oneof=pending in_transit delivered returned" example:"pending"`
-	EstimatedDelivery  *time.Time `json:"estimated_delivery,omitempty"`
-	ActualDelivery     *time.Time `json:"actual_delivery,omitempty"`
-	CreatedAt          time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt          time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID                    uint           `json:"id" gorm:"primaryKey"`
+	OrderID               uint           `json:"order_id" gorm:"not null;index" validate:"required"`
+	TrackingNumber        string         `json:"tracking_number" gorm:"unique;not null;size:100" validate:"required,max=100"`
+	Carrier               string         `json:"carrier" gorm:"size:100" validate:"max=100"`
+	ShippingMethod        string         `json:"shipping_method" gorm:"size:50" validate:"max=50"`
+	Type                  string         `json:"type" gorm:"size:20;not null;default:'outbound'" example:"outbound"`
+	Status                string         `json:"status" gorm:"size:20;default:'pending'" validate:"oneof=pending label_created picked_up in_transit out_for_delivery delivered failed_attempt returned_to_sender cancelled" example:"pending"`
+	EstimatedDelivery     *time.Time     `json:"estimated_delivery,omitempty"`
+	ActualDelivery        *time.Time     `json:"actual_delivery,omitempty"`
+	DestinationPostalCode string         `json:"destination_postal_code,omitempty" gorm:"size:20"`
+	WeightGrams           int            `json:"weight_grams,omitempty"`
+	ShippingCost          money.Money    `json:"shipping_cost" gorm:"embedded;embeddedPrefix:shipping_cost_"`
+	LabelURL              string         `json:"label_url,omitempty" gorm:"size:500"`
+	LabelFormat           string         `json:"label_format,omitempty" gorm:"size:10"`
+	LabelData             []byte         `json:"-" gorm:"type:mediumblob"` // a label not stored yet, until RetryPendingLabels stores it
+	LabelContentType      string         `json:"-" gorm:"size:100"`
+	Items                 []ShipmentItem `json:"items,omitempty" gorm:"foreignKey:ShipmentID;constraint:OnDelete:CASCADE"`
+	CreatedAt             time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt             time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
-// ShipmentCreateRequest represents the request to create a shipment
+// ShipmentCreateRequest represents the request to create a shipment. The
+// shipment is booked with the named carrier, or the default one, which issues
+// the tracking number and label. ShippingMethod is the carrier's service level.
+// Items lists the order items packed in this parcel; without items the shipment
+// takes everything on the order that is not in another shipment yet. A return
+// shipment is collected from Destination and brings the listed items back to
+// the origin; it needs items.
 type ShipmentCreateRequest struct {
-	OrderID           uint      `json:"order_id" validate:"required"`
-	Carrier           string    `json:"carrier" validate:"max=100"`
-	ShippingMethod    string    `json:"shipping_method" validate:"max=50"`
-	EstimatedDelivery *time.Time `json:"estimated_delivery,omitempty"`
+	OrderID           uint                  `json:"order_id" validate:"required"`
+	Type              string                `json:"type,omitempty" binding:"omitempty,oneof=outbound return"`
+	Carrier           string                `json:"carrier" validate:"max=100"`
+	ShippingMethod    string                `json:"shipping_method" validate:"max=50"`
+	EstimatedDelivery *time.Time            `json:"estimated_delivery,omitempty"`
+	Destination       Address               `json:"destination"`
+	Parcel            *Parcel               `json:"parcel,omitempty"`
+	LabelFormat       string                `json:"label_format,omitempty" binding:"omitempty,oneof=pdf zpl"`
+	Items             []ShipmentItemRequest `json:"items,omitempty" binding:"omitempty,dive"`
 }
 
 // ShipmentUpdateRequest represents the request to update a shipment
@@ -30,7 +59,7 @@ type ShipmentUpdateRequest struct {
 	TrackingNumber    *string    `json:"tracking_number,omitempty" validate:"omitempty,max=100"`
 	Carrier           *string    `json:"carrier,omitempty" validate:"omitempty,max=100"`
 	ShippingMethod    *string    `json:"shipping_method,omitempty" validate:"omitempty,max=50"`
-	Status            *string    `json:"status,omitempty" validate:"omitempty,oneof=pending in_transit delivered returned"`
+	Status            *string    `json:"status,omitempty" validate:"omitempty,oneof=pending label_created picked_up in_transit out_for_delivery delivered failed_attempt returned_to_sender cancelled"`
 	EstimatedDelivery *time.Time `json:"estimated_delivery,omitempty"`
 	ActualDelivery    *time.Time `json:"actual_delivery,omitempty"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,91 @@
+package entity
+
+// Shipment statuses. A shipment starts pending and only moves along
+// shipmentTransitions; delivered, returned_to_sender and cancelled are final.
+const (
+	ShipmentStatusPending          = "pending"
+	ShipmentStatusLabelCreated     = "label_created"
+	ShipmentStatusPickedUp         = "picked_up"
+	ShipmentStatusInTransit        = "in_transit"
+	ShipmentStatusOutForDelivery   = "out_for_delivery"
+	ShipmentStatusDelivered        = "delivered"
+	ShipmentStatusFailedAttempt    = "failed_attempt"
+	ShipmentStatusReturnedToSender = "returned_to_sender"
+	ShipmentStatusCancelled        = "cancelled"
+)
+
+// shipmentTransitions lists the statuses each status may move to. Carriers do
+// not always scan every step, so a shipment may skip ahead, but it never moves
+// back except from a failed attempt to another delivery run.
+var shipmentTransitions = map[string][]string{
+	ShipmentStatusPending: {
+		ShipmentStatusLabelCreated, ShipmentStatusPickedUp, ShipmentStatusInTransit,
+		ShipmentStatusOutForDelivery, ShipmentStatusDelivered, ShipmentStatusCancelled,
+	},
+	ShipmentStatusLabelCreated: {
+		ShipmentStatusPickedUp, ShipmentStatusInTransit, ShipmentStatusOutForDelivery,
+		ShipmentStatusDelivered, ShipmentStatusCancelled,
+	},
+	ShipmentStatusPickedUp: {
+		ShipmentStatusInTransit, ShipmentStatusOutForDelivery, ShipmentStatusDelivered,
+		ShipmentStatusReturnedToSender,
+	},
+	ShipmentStatusInTransit: {
+		ShipmentStatusOutForDelivery, ShipmentStatusDelivered, ShipmentStatusFailedAttempt,
+		ShipmentStatusReturnedToSender,
+	},
+	ShipmentStatusOutForDelivery: {
+		ShipmentStatusDelivered, ShipmentStatusFailedAttempt, ShipmentStatusReturnedToSender,
+	},
+	ShipmentStatusFailedAttempt: {
+		ShipmentStatusInTransit, ShipmentStatusOutForDelivery, ShipmentStatusDelivered,
+		ShipmentStatusReturnedToSender,
+	},
+	ShipmentStatusDelivered:        {},
+	ShipmentStatusReturnedToSender: {},
+	ShipmentStatusCancelled:        {},
+}
+
+// trackingEventStatuses maps tracking event types to the status they move a
+// shipment to. Other event types are recorded without changing the status.
+var trackingEventStatuses = map[string]string{
+	"label_created":       ShipmentStatusLabelCreated,
+	"picked_up":           ShipmentStatusPickedUp,
+	"in_transit":          ShipmentStatusInTransit,
+	"arrived_at_facility": ShipmentStatusInTransit,
+	"departed_facility":   ShipmentStatusInTransit,
+	"out_for_delivery":    ShipmentStatusOutForDelivery,
+	"delivered":           ShipmentStatusDelivered,
+	"failed_attempt":      ShipmentStatusFailedAttempt,
+	"delivery_failed":     ShipmentStatusFailedAttempt,
+	"returned_to_sender":  ShipmentStatusReturnedToSender,
+	"cancelled":           ShipmentStatusCancelled,
+}
+
+// IsValidShipmentStatus reports whether status is a known shipment status
+func IsValidShipmentStatus(status string) bool {
+	_, ok := shipmentTransitions[status]
+	return ok
+}
+
+// CanTransitionShipment reports whether a shipment may move from one status to another
+func CanTransitionShipment(from, to string) bool {
+	for _, next := range shipmentTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinalShipmentStatus reports whether no further transitions are possible
+func IsFinalShipmentStatus(status string) bool {
+	next, ok := shipmentTransitions[status]
+	return ok && len(next) == 0
+}
+
+// StatusForTrackingEvent returns the status a tracking event moves a shipment to
+func StatusForTrackingEvent(eventType string) (string, bool) {
+	status, ok := trackingEventStatuses[eventType]
+	return status, ok
+}
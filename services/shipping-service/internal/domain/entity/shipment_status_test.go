@@ -0,0 +1,87 @@
+package entity
+
+import "testing"
+
+func TestCanTransitionShipment(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     bool
+	}{
+		{"label after pending", ShipmentStatusPending, ShipmentStatusLabelCreated, true},
+		{"skip ahead to delivered", ShipmentStatusLabelCreated, ShipmentStatusDelivered, true},
+		{"cancel before pickup", ShipmentStatusLabelCreated, ShipmentStatusCancelled, true},
+		{"no cancel after pickup", ShipmentStatusPickedUp, ShipmentStatusCancelled, false},
+		{"failed attempt in transit", ShipmentStatusInTransit, ShipmentStatusFailedAttempt, true},
+		{"another run after a failed attempt", ShipmentStatusFailedAttempt, ShipmentStatusOutForDelivery, true},
+		{"no move back", ShipmentStatusOutForDelivery, ShipmentStatusPickedUp, false},
+		{"no failed attempt before pickup", ShipmentStatusLabelCreated, ShipmentStatusFailedAttempt, false},
+		{"no return before pickup", ShipmentStatusPending, ShipmentStatusReturnedToSender, false},
+		{"delivered is final", ShipmentStatusDelivered, ShipmentStatusReturnedToSender, false},
+		{"cancelled is final", ShipmentStatusCancelled, ShipmentStatusPending, false},
+		{"no change to the same status", ShipmentStatusInTransit, ShipmentStatusInTransit, false},
+		{"unknown from", "lost", ShipmentStatusDelivered, false},
+		{"unknown to", ShipmentStatusPending, "lost", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanTransitionShipment(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransitionShipment(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShipmentTransitionsAreKnown(t *testing.T) {
+	for from, next := range shipmentTransitions {
+		for _, to := range next {
+			if !IsValidShipmentStatus(to) {
+				t.Errorf("%s may move to unknown status %s", from, to)
+			}
+		}
+	}
+}
+
+func TestIsFinalShipmentStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{ShipmentStatusPending, false},
+		{ShipmentStatusInTransit, false},
+		{ShipmentStatusFailedAttempt, false},
+		{ShipmentStatusDelivered, true},
+		{ShipmentStatusReturnedToSender, true},
+		{ShipmentStatusCancelled, true},
+		{"lost", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			if got := IsFinalShipmentStatus(tt.status); got != tt.want {
+				t.Errorf("IsFinalShipmentStatus(%q) = %v, want %v", tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusForTrackingEvent(t *testing.T) {
+	tests := []struct {
+		eventType string
+		want      string
+		ok        bool
+	}{
+		{"picked_up", ShipmentStatusPickedUp, true},
+		{"arrived_at_facility", ShipmentStatusInTransit, true},
+		{"delivery_failed", ShipmentStatusFailedAttempt, true},
+		{"delivered", ShipmentStatusDelivered, true},
+		{"customs_hold", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			got, ok := StatusForTrackingEvent(tt.eventType)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("StatusForTrackingEvent(%q) = %q, %v, want %q, %v", tt.eventType, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -11,7 +11,11 @@ type TrackingEvent struct {
 	Location    string    `json:"location" gorm:"size:200" validate:"max=200"`
 	Description string    `json:"description" gorm:"size:500" validate:"max=500"`
 	Timestamp   time.Time `json:"timestamp" gorm:"not null;autoCreateTime"`
-	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	// Carrier and CarrierEventID identify events reported by a carrier webhook,
+	// so redelivered events are stored once
+	Carrier        string    `json:"carrier,omitempty" gorm:"size:100;uniqueIndex:idx_tracking_carrier_event"`
+	CarrierEventID *string   `json:"carrier_event_id,omitempty" gorm:"size:191;uniqueIndex:idx_tracking_carrier_event"`
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
 }
 
 // TrackingEventCreateRequest represents the request to create a tracking event
@@ -21,4 +25,4 @@ type TrackingEventCreateRequest struct {
 	Location    string    `json:"location,omitempty" validate:"max=200"`
 	Description string    `json:"description,omitempty" validate:"max=500"`
 	Timestamp   time.Time `json:"timestamp,omitempty"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// MaxWebhookEvents bounds the number of events in one carrier webhook delivery
+const MaxWebhookEvents = 500
+
+// CarrierWebhook is a batch of tracking updates pushed by a carrier
+type CarrierWebhook struct {
+	Events []CarrierWebhookEvent `json:"events"`
+}
+
+// CarrierWebhookEvent is one carrier status update. Code is the carrier's own
+// status code; EventID is unique per carrier and used to drop redeliveries.
+type CarrierWebhookEvent struct {
+	EventID        string    `json:"event_id"`
+	TrackingNumber string    `json:"tracking_number"`
+	Code           string    `json:"code"`
+	Location       string    `json:"location,omitempty"`
+	Description    string    `json:"description,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// TrackingWebhookResult summarises what a carrier webhook delivery changed
+type TrackingWebhookResult struct {
+	Received         int      `json:"received"`
+	Recorded         int      `json:"recorded"`
+	Duplicates       int      `json:"duplicates"`
+	Unmatched        []string `json:"unmatched,omitempty"`
+	ShipmentsUpdated int      `json:"shipments_updated"`
+}
+
+// ShipmentStatusChange asks for a shipment to move to Status as of At
+type ShipmentStatusChange struct {
+	ShipmentID uint
+	Status     string
+	At         time.Time
+}
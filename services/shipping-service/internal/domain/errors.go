@@ -0,0 +1,31 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrShipmentNotFound is returned when a shipment does not exist
+	ErrShipmentNotFound = errors.New("shipment not found")
+	// ErrOrderNotFound is returned when order-service does not know an order
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrOrderItemNotFound is returned when a shipment names an item that is not on its order
+	ErrOrderItemNotFound = errors.New("order item not found on this order")
+	// ErrReturnItemsRequired is returned when a return shipment does not list its items
+	ErrReturnItemsRequired = errors.New("return shipments need items")
+	// ErrReturnQuantityExceeded is returned when a return brings back more of
+	// an item than was ordered and not returned yet
+	ErrReturnQuantityExceeded = errors.New("return quantity exceeds what is left to return")
+	// ErrNothingToShip is returned when a shipment's items exceed what is left to ship on the order
+	ErrNothingToShip = errors.New("items exceed what is left to ship on this order")
+	// ErrInvalidStatusTransition is returned when a shipment cannot move to the requested status
+	ErrInvalidStatusTransition = errors.New("invalid shipment status transition")
+	// ErrCarrierNotFound is returned when no carrier is registered under a name
+	ErrCarrierNotFound = errors.New("carrier not found")
+	// ErrInvalidSignature is returned when a carrier webhook signature does not verify
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrInvalidWebhook is returned when a carrier webhook payload is malformed
+	ErrInvalidWebhook = errors.New("invalid webhook payload")
+	// ErrUnsupportedService is returned when a carrier does not offer the requested service level
+	ErrUnsupportedService = errors.New("carrier does not offer this service level")
+	// ErrTooManyAttempts is returned when a tracking number has had too many wrong postal codes
+	ErrTooManyAttempts = errors.New("too many wrong postal codes for this tracking number")
+)
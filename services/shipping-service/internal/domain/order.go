@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain/entity"
+)
+
+// OrderService is the part of order-service that shipping depends on: the
+// items of an order, and recording the order's fulfilment rollup
+type OrderService interface {
+	GetOrderLines(ctx context.Context, orderID uint) ([]entity.OrderLine, error)
+	UpdateFulfilmentStatus(ctx context.Context, orderID uint, status string) error
+}
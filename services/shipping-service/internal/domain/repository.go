@@ -2,23 +2,38 @@ package domain
 
 import (
 	"context"
+	"time"
 	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain/entity"
 )
 
 type ShipmentRepository interface {
 	Create(ctx context.Context, shipment *entity.Shipment) error
 	GetByID(ctx context.Context, id uint) (*entity.Shipment, error)
-	GetByOrderID(ctx context.Context, orderID uint) (*entity.Shipment, error)
+	// CreateForOrder creates a shipment and its items after checking, with the
+	// order's shipments locked, that the items fit in what is left of the order
+	CreateForOrder(ctx context.Context, shipment *entity.Shipment, lines []entity.OrderLine) error
+	GetByOrderID(ctx context.Context, orderID uint) ([]entity.Shipment, error)
 	GetByTrackingNumber(ctx context.Context, trackingNumber string) (*entity.Shipment, error)
 	Update(ctx context.Context, shipment *entity.Shipment) error
-	UpdateStatus(ctx context.Context, id uint, status string) error
+	UpdateStatus(ctx context.Context, id uint, status string, at time.Time) (*entity.Shipment, error)
+	// RecordTracking stores tracking events, silently skipping carrier events
+	// already stored, and moves shipments through the given changes, skipping
+	// changes the state machine does not allow, all in one transaction. It
+	// returns how many shipments changed status.
+	RecordTracking(ctx context.Context, events []entity.TrackingEvent, changes []entity.ShipmentStatusChange) (int, error)
+	GetByTrackingNumbers(ctx context.Context, trackingNumbers []string) ([]entity.Shipment, error)
 	Delete(ctx context.Context, id uint) error
 	GetByStatus(ctx context.Context, status string) ([]entity.Shipment, error)
 	GetAll(ctx context.Context, limit, offset int) ([]entity.Shipment, error)
+	// GetWithPendingLabels returns up to limit shipments whose label is not stored yet
+	GetWithPendingLabels(ctx context.Context, limit int) ([]entity.Shipment, error)
+	// SetLabelURL records where a pending label was stored and drops its data
+	SetLabelURL(ctx context.Context, id uint, labelURL string) error
 }
 
 type TrackingEventRepository interface {
 	Create(ctx context.Context, event *entity.TrackingEvent) error
+	GetCarrierEventIDs(ctx context.Context, carrier string, eventIDs []string) ([]string, error)
 	GetByShipmentID(ctx context.Context, shipmentID uint) ([]entity.TrackingEvent, error)
 	GetLatestByShipmentID(ctx context.Context, shipmentID uint) (*entity.TrackingEvent, error)
 	GetByEventType(ctx context.Context, eventType string) ([]entity.TrackingEvent, error)
@@ -0,0 +1,143 @@
+package carrier
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain/entity"
+)
+
+// labelContent is what gets printed on a 4x6 inch shipping label
+type labelContent struct {
+	Carrier        string
+	ServiceLevel   string
+	TrackingNumber string
+	OrderID        uint
+	OriginPostal   string
+	To             entity.Address
+	WeightGrams    int
+	ShipDate       time.Time
+}
+
+func (c labelContent) addressLines() []string {
+	lines := []string{c.To.Name, c.To.Line1, c.To.Line2}
+	cityLine := strings.TrimSpace(strings.Join([]string{c.To.City, c.To.State, c.To.PostalCode}, " "))
+	lines = append(lines, cityLine, c.To.Phone)
+
+	var nonEmpty []string
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			nonEmpty = append(nonEmpty, line)
+		}
+	}
+	return nonEmpty
+}
+
+func (c labelContent) detailLines() []string {
+	return []string{
+		fmt.Sprintf("Order #%d", c.OrderID),
+		fmt.Sprintf("Weight: %.2f kg", float64(c.WeightGrams)/1000),
+		fmt.Sprintf("From PIN: %s", c.OriginPostal),
+		fmt.Sprintf("Ship date: %s", c.ShipDate.Format("02 Jan 2006")),
+	}
+}
+
+// renderZPLLabel renders the label for a 203 dpi Zebra printer with a Code 128
+// barcode of the tracking number
+func renderZPLLabel(c labelContent) []byte {
+	var b strings.Builder
+	b.WriteString("^XA\n^CI28\n")
+	fmt.Fprintf(&b, "^CF0,40\n^FO40,40^FD%s^FS\n", zplText(c.Carrier))
+	fmt.Fprintf(&b, "^CF0,30\n^FO40,90^FD%s^FS\n", zplText(strings.ToUpper(c.ServiceLevel)))
+	b.WriteString("^FO40,135^GB732,3,3^FS\n")
+
+	y := 160
+	b.WriteString("^CF0,26\n")
+	fmt.Fprintf(&b, "^FO40,%d^FDSHIP TO:^FS\n", y)
+	for _, line := range c.addressLines() {
+		y += 34
+		fmt.Fprintf(&b, "^FO40,%d^FD%s^FS\n", y, zplText(line))
+	}
+
+	y += 50
+	b.WriteString("^CF0,22\n")
+	for _, line := range c.detailLines() {
+		fmt.Fprintf(&b, "^FO40,%d^FD%s^FS\n", y, zplText(line))
+		y += 30
+	}
+
+	fmt.Fprintf(&b, "^BY3,2,160\n^FO40,%d^BCN,160,Y,N,N^FD%s^FS\n", y+30, zplText(c.TrackingNumber))
+	b.WriteString("^XZ\n")
+	return []byte(b.String())
+}
+
+// zplText removes the ZPL command prefixes from field data
+func zplText(s string) string {
+	return strings.NewReplacer("^", "", "~", "").Replace(s)
+}
+
+// renderPDFLabel renders the label as a single 4x6 inch PDF page in Helvetica
+func renderPDFLabel(c labelContent) []byte {
+	var content strings.Builder
+	content.WriteString("BT\n")
+	fmt.Fprintf(&content, "/F2 18 Tf 18 396 Td (%s) Tj\n", pdfText(c.Carrier))
+	fmt.Fprintf(&content, "/F1 12 Tf 0 -18 Td (%s) Tj\n", pdfText(strings.ToUpper(c.ServiceLevel)))
+	content.WriteString("/F2 11 Tf 0 -36 Td (SHIP TO:) Tj\n")
+	content.WriteString("/F1 11 Tf 14 TL\n")
+	for _, line := range c.addressLines() {
+		fmt.Fprintf(&content, "T* (%s) Tj\n", pdfText(line))
+	}
+	content.WriteString("/F1 9 Tf 12 TL T*\n")
+	for _, line := range c.detailLines() {
+		fmt.Fprintf(&content, "T* (%s) Tj\n", pdfText(line))
+	}
+	content.WriteString("ET\n")
+	content.WriteString("BT /F1 9 Tf 18 84 Td (TRACKING NUMBER) Tj ET\n")
+	fmt.Fprintf(&content, "BT /F2 20 Tf 18 60 Td (%s) Tj ET\n", pdfText(c.TrackingNumber))
+	content.WriteString("18 110 m 270 110 l S\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 288 432] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return pdf.Bytes()
+}
+
+// pdfText escapes a string for a PDF literal; characters outside printable
+// ASCII are replaced because the standard fonts cannot be relied on for them
+func pdfText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,261 @@
+// Package carrier provides shipping carrier implementations
+package carrier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/money"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain/entity"
+)
+
+// LocalCourierName is the carrier name recorded on shipments booked with the local courier
+const LocalCourierName = "local_courier"
+
+// Local courier service levels
+const (
+	ServiceStandard = "standard"
+	ServiceExpress  = "express"
+)
+
+const (
+	// slabGrams is the weight step the local courier charges by
+	slabGrams = 500
+	// volumetricDivisor converts cubic centimetres to volumetric grams (L*W*H/5000 kg)
+	volumetricDivisor = 5
+)
+
+// Delivery zones, by how much of the postal code origin and destination share
+const (
+	zoneLocal    = "local"
+	zoneRegional = "regional"
+	zoneNational = "national"
+)
+
+type zoneRate struct {
+	base    int64 // first slab, in minor units
+	perSlab int64 // each further slab, in minor units
+	days    int
+}
+
+// localCourierRates is the rate card per service level and zone
+var localCourierRates = map[string]map[string]zoneRate{
+	ServiceStandard: {
+		zoneLocal:    {base: 4000, perSlab: 2000, days: 2},
+		zoneRegional: {base: 6000, perSlab: 3000, days: 4},
+		zoneNational: {base: 9000, perSlab: 4500, days: 6},
+	},
+	ServiceExpress: {
+		zoneLocal:    {base: 7000, perSlab: 3500, days: 1},
+		zoneRegional: {base: 11000, perSlab: 5500, days: 2},
+		zoneNational: {base: 16000, perSlab: 8000, days: 3},
+	},
+}
+
+// localCourierCodes maps the status codes the local courier sends to its
+// tracking webhook to tracking event types
+var localCourierCodes = map[string]string{
+	"LBL": "label_created",
+	"PKP": "picked_up",
+	"ITR": "in_transit",
+	"ARR": "arrived_at_facility",
+	"DEP": "departed_facility",
+	"OFD": "out_for_delivery",
+	"DLV": "delivered",
+	"UND": "failed_attempt",
+	"RTS": "returned_to_sender",
+	"CAN": "cancelled",
+}
+
+// LocalCourierConfig configures the local courier
+type LocalCourierConfig struct {
+	// OriginPostalCode is where parcels are picked up when a request names no origin
+	OriginPostalCode string
+	// Currency of the rate card; defaults to money.DefaultCurrency
+	Currency string
+}
+
+// LocalCourier is a built-in domestic courier. Rates come from a fixed rate card
+// by zone and chargeable weight, tracking numbers are random and labels are
+// rendered locally. The courier has no remote system, so the scans it reports
+// are only those it recorded itself since the process started.
+type LocalCourier struct {
+	mu               sync.Mutex
+	originPostalCode string
+	currency         string
+	scans            map[string][]entity.CarrierTrackingEvent
+	now              func() time.Time
+}
+
+// NewLocalCourier creates the local courier
+func NewLocalCourier(cfg LocalCourierConfig) *LocalCourier {
+	currency := cfg.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	return &LocalCourier{
+		originPostalCode: cfg.OriginPostalCode,
+		currency:         currency,
+		scans:            make(map[string][]entity.CarrierTrackingEvent),
+		now:              time.Now,
+	}
+}
+
+func (l *LocalCourier) Name() string {
+	return LocalCourierName
+}
+
+func (l *LocalCourier) TrackingEventType(code string) (string, bool) {
+	eventType, ok := localCourierCodes[strings.ToUpper(strings.TrimSpace(code))]
+	return eventType, ok
+}
+
+// Quote returns a rate for every service level. Only domestic destinations are served.
+func (l *LocalCourier) Quote(ctx context.Context, req entity.QuoteRequest) ([]entity.RateQuote, error) {
+	if !isDomestic(req.DestinationCountry) {
+		return nil, nil
+	}
+
+	origin := req.OriginPostalCode
+	if origin == "" {
+		origin = l.originPostalCode
+	}
+	quotes := make([]entity.RateQuote, 0, len(localCourierRates))
+	for _, service := range []string{ServiceStandard, ServiceExpress} {
+		quotes = append(quotes, l.rate(service, origin, req.DestinationPostalCode, req.Parcel))
+	}
+	return quotes, nil
+}
+
+func (l *LocalCourier) CreateLabel(ctx context.Context, req entity.LabelRequest) (*entity.CarrierLabel, error) {
+	service := req.ServiceLevel
+	if service == "" {
+		service = ServiceStandard
+	}
+	if _, ok := localCourierRates[service]; !ok || !isDomestic(req.Destination.Country) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnsupportedService, service)
+	}
+	format := req.Format
+	if format == "" {
+		format = entity.LabelFormatPDF
+	}
+
+	quote := l.rate(service, l.originPostalCode, req.Destination.PostalCode, req.Parcel)
+	trackingNumber := l.trackingNumber()
+	content := labelContent{
+		Carrier:        "Local Courier",
+		ServiceLevel:   service,
+		TrackingNumber: trackingNumber,
+		OrderID:        req.OrderID,
+		OriginPostal:   l.originPostalCode,
+		To:             req.Destination,
+		WeightGrams:    req.Parcel.WeightGrams,
+		ShipDate:       l.now(),
+	}
+	if req.Return {
+		content.ServiceLevel = service + " return"
+		content.OriginPostal = req.Destination.PostalCode
+		content.To = entity.Address{Name: "Returns Centre", PostalCode: l.originPostalCode}
+	}
+
+	label := &entity.CarrierLabel{
+		TrackingNumber:    trackingNumber,
+		ServiceLevel:      service,
+		Amount:            quote.Amount,
+		EstimatedDelivery: quote.EstimatedDelivery,
+		Format:            format,
+	}
+	switch format {
+	case entity.LabelFormatPDF:
+		label.ContentType = "application/pdf"
+		label.Data = renderPDFLabel(content)
+	case entity.LabelFormatZPL:
+		label.ContentType = "application/x-zpl"
+		label.Data = renderZPLLabel(content)
+	default:
+		return nil, fmt.Errorf("unsupported label format %q", format)
+	}
+
+	l.record(trackingNumber, "label_created", "Shipping label created")
+	return label, nil
+}
+
+// Track returns the scans recorded for a tracking number
+func (l *LocalCourier) Track(ctx context.Context, trackingNumber string) ([]entity.CarrierTrackingEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]entity.CarrierTrackingEvent(nil), l.scans[trackingNumber]...), nil
+}
+
+// Cancel voids a booking. The local courier accepts any cancellation; callers
+// are expected to cancel only shipments that have not been picked up.
+func (l *LocalCourier) Cancel(ctx context.Context, trackingNumber string) error {
+	l.record(trackingNumber, "cancelled", "Booking cancelled")
+	return nil
+}
+
+func (l *LocalCourier) rate(service, origin, destination string, parcel entity.Parcel) entity.RateQuote {
+	card := localCourierRates[service][deliveryZone(origin, destination)]
+	slabs := (chargeableGrams(parcel) + slabGrams - 1) / slabGrams
+	if slabs < 1 {
+		slabs = 1
+	}
+	amount := card.base + int64(slabs-1)*card.perSlab
+
+	return entity.RateQuote{
+		Carrier:           LocalCourierName,
+		ServiceLevel:      service,
+		Amount:            money.New(amount, l.currency),
+		EstimatedDays:     card.days,
+		EstimatedDelivery: l.now().AddDate(0, 0, card.days),
+	}
+}
+
+// trackingNumber returns "LC" followed by the ship date and eight random characters
+func (l *LocalCourier) trackingNumber() string {
+	return fmt.Sprintf("LC%s%s", l.now().Format("060102"), utils.GenerateVerificationCode(8))
+}
+
+func (l *LocalCourier) record(trackingNumber, eventType, description string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	scans := l.scans[trackingNumber]
+	l.scans[trackingNumber] = append(scans, entity.CarrierTrackingEvent{
+		EventID:     fmt.Sprintf("%s-%d", trackingNumber, len(scans)+1),
+		EventType:   eventType,
+		Description: description,
+		Timestamp:   l.now(),
+	})
+}
+
+// chargeableGrams is the greater of the actual and the volumetric weight
+func chargeableGrams(parcel entity.Parcel) int {
+	volumetric := parcel.LengthCm * parcel.WidthCm * parcel.HeightCm / volumetricDivisor
+	if volumetric > parcel.WeightGrams {
+		return volumetric
+	}
+	return parcel.WeightGrams
+}
+
+// deliveryZone compares postal codes: the same three-digit sorting district is
+// local, the same two-digit region is regional and anything else is national
+func deliveryZone(origin, destination string) string {
+	origin = strings.TrimSpace(origin)
+	destination = strings.TrimSpace(destination)
+	switch {
+	case len(origin) >= 3 && len(destination) >= 3 && origin[:3] == destination[:3]:
+		return zoneLocal
+	case len(origin) >= 2 && len(destination) >= 2 && origin[:2] == destination[:2]:
+		return zoneRegional
+	}
+	return zoneNational
+}
+
+func isDomestic(country string) bool {
+	return country == "" || strings.EqualFold(country, "IN")
+}
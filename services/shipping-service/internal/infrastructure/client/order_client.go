@@ -0,0 +1,69 @@
+// Package client talks to the other services shipping depends on
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/apiclient"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain/entity"
+)
+
+// OrderClient reads orders from and reports fulfilment to order-service
+type OrderClient struct {
+	api *apiclient.Client
+}
+
+func NewOrderClient(cfg config.Config) domain.OrderService {
+	return &OrderClient{
+		api: apiclient.New("order-service", cfg.Services.OrderService.URL, cfg.Services.OrderService.Timeout),
+	}
+}
+
+// order is the part of order-service's order that shipping needs
+type order struct {
+	ID         uint `json:"id"`
+	OrderItems []struct {
+		ID        uint `json:"id"`
+		ProductID uint `json:"product_id"`
+		VariantID uint `json:"variant_id"`
+		Quantity  int  `json:"quantity"`
+	} `json:"order_items"`
+}
+
+func (c *OrderClient) GetOrderLines(ctx context.Context, orderID uint) ([]entity.OrderLine, error) {
+	var o order
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/order/%d", orderID), nil, &o); err != nil {
+		return nil, err
+	}
+	lines := make([]entity.OrderLine, len(o.OrderItems))
+	for i, item := range o.OrderItems {
+		lines[i] = entity.OrderLine{
+			OrderItemID: item.ID,
+			ProductID:   item.ProductID,
+			VariantID:   item.VariantID,
+			Quantity:    item.Quantity,
+		}
+	}
+	return lines, nil
+}
+
+func (c *OrderClient) UpdateFulfilmentStatus(ctx context.Context, orderID uint, status string) error {
+	payload := map[string]string{"status": status}
+	return c.do(ctx, http.MethodPatch, fmt.Sprintf("/order/%d/fulfilment", orderID), payload, nil)
+}
+
+// do calls order-service as the "system" role, since no end user is behind
+// shipping's requests, and decodes the data field into out
+func (c *OrderClient) do(ctx context.Context, method, path string, payload, out interface{}) error {
+	err := c.api.Do(ctx, method, path, apiclient.System, payload, out)
+	var serviceErr *apiclient.ServiceError
+	if errors.As(err, &serviceErr) && serviceErr.StatusCode == http.StatusNotFound {
+		return domain.ErrOrderNotFound
+	}
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/events"
 	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain/entity"
 	"github.com/joho/godotenv"
 	"gorm.io/driver/mysql"
@@ -44,10 +45,22 @@ func NewMySQLConnection() *gorm.DB {
 func RunMigrations(db *gorm.DB) {
 	err := db.AutoMigrate(
 		&entity.Shipment{},
+		&entity.ShipmentItem{},
 		&entity.TrackingEvent{},
+		&events.OutboxMessage{},
 	)
 	if err != nil {
 		log.Fatal("Failed to run migrations:", err)
 	}
+	if err := migrateShipmentStatuses(db); err != nil {
+		log.Fatal("Failed to migrate shipment statuses:", err)
+	}
 	fmt.Println("Database migrations completed successfully")
-}
\ No newline at end of file
+}
+
+// migrateShipmentStatuses renames statuses used before the shipment state machine
+func migrateShipmentStatuses(db *gorm.DB) error {
+	return db.Model(&entity.Shipment{}).
+		Where("status = ?", "returned").
+		Update("status", entity.ShipmentStatusReturnedToSender).Error
+}
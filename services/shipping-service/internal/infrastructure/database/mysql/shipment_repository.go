@@ -3,9 +3,14 @@ package mysql
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"time"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain/entity"
 	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/events"
 )
 
 type shipmentRepository struct {
@@ -22,46 +27,192 @@ func (r *shipmentRepository) Create(ctx context.Context, shipment *entity.Shipme
 
 func (r *shipmentRepository) GetByID(ctx context.Context, id uint) (*entity.Shipment, error) {
 	var shipment entity.Shipment
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&shipment).Error
+	err := r.db.WithContext(ctx).Preload("Items").Where("id = ?", id).First(&shipment).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("shipment not found")
+			return nil, domain.ErrShipmentNotFound
 		}
 		return nil, err
 	}
 	return &shipment, nil
 }
 
-func (r *shipmentRepository) GetByOrderID(ctx context.Context, orderID uint) (*entity.Shipment, error) {
+func (r *shipmentRepository) CreateForOrder(ctx context.Context, shipment *entity.Shipment, lines []entity.OrderLine) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Locking the order's shipments also blocks concurrent inserts for the order
+		var existing []entity.Shipment
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Items").
+			Where("order_id = ?", shipment.OrderID).Find(&existing).Error
+		if err != nil {
+			return err
+		}
+
+		remaining := entity.RemainingToShip(lines, existing)
+		for _, item := range shipment.Items {
+			if item.Quantity > remaining[item.OrderItemID] {
+				return fmt.Errorf("%w: order item %d has %d left", domain.ErrNothingToShip, item.OrderItemID, max(remaining[item.OrderItemID], 0))
+			}
+			remaining[item.OrderItemID] -= item.Quantity
+		}
+		return tx.Create(shipment).Error
+	})
+}
+
+func (r *shipmentRepository) GetByOrderID(ctx context.Context, orderID uint) ([]entity.Shipment, error) {
+	var shipments []entity.Shipment
+	err := r.db.WithContext(ctx).Preload("Items").Where("order_id = ?", orderID).Order("id").Find(&shipments).Error
+	return shipments, err
+}
+
+func (r *shipmentRepository) GetByTrackingNumbers(ctx context.Context, trackingNumbers []string) ([]entity.Shipment, error) {
+	var shipments []entity.Shipment
+	if len(trackingNumbers) == 0 {
+		return shipments, nil
+	}
+	err := r.db.WithContext(ctx).Where("tracking_number IN ?", trackingNumbers).Find(&shipments).Error
+	return shipments, err
+}
+
+func (r *shipmentRepository) GetByTrackingNumber(ctx context.Context, trackingNumber string) (*entity.Shipment, error) {
 	var shipment entity.Shipment
-	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&shipment).Error
+	err := r.db.WithContext(ctx).Preload("Items").Where("tracking_number = ?", trackingNumber).First(&shipment).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("shipment not found")
+			return nil, domain.ErrShipmentNotFound
 		}
 		return nil, err
 	}
 	return &shipment, nil
 }
 
-func (r *shipmentRepository) GetByTrackingNumber(ctx context.Context, trackingNumber string) (*entity.Shipment, error) {
+// Update saves the shipment and, when its status changed, writes a shipment
+// event to the outbox in the same transaction. A status change that the state
+// machine does not allow is rejected with ErrInvalidStatusTransition.
+func (r *shipmentRepository) Update(ctx context.Context, shipment *entity.Shipment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previous string
+		err := tx.Model(&entity.Shipment{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", shipment.ID).Select("status").Scan(&previous).Error
+		if err != nil {
+			return err
+		}
+		if previous != shipment.Status && !entity.CanTransitionShipment(previous, shipment.Status) {
+			return fmt.Errorf("%w: %s to %s", domain.ErrInvalidStatusTransition, previous, shipment.Status)
+		}
+		if err := tx.Save(shipment).Error; err != nil {
+			return err
+		}
+		return enqueueStatusEvent(tx, shipment, previous)
+	})
+}
+
+// UpdateStatus moves a shipment to status under a row lock, stamping the
+// actual delivery time with at when it is delivered
+func (r *shipmentRepository) UpdateStatus(ctx context.Context, id uint, status string, at time.Time) (*entity.Shipment, error) {
 	var shipment entity.Shipment
-	err := r.db.WithContext(ctx).Where("tracking_number = ?", trackingNumber).First(&shipment).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("shipment not found")
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&shipment).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrShipmentNotFound
+			}
+			return err
+		}
+		if !entity.CanTransitionShipment(shipment.Status, status) {
+			return fmt.Errorf("%w: %s to %s", domain.ErrInvalidStatusTransition, shipment.Status, status)
 		}
+		return transitionShipment(tx, &shipment, status, at)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &shipment, nil
 }
 
-func (r *shipmentRepository) Update(ctx context.Context, shipment *entity.Shipment) error {
-	return r.db.WithContext(ctx).Save(shipment).Error
+func (r *shipmentRepository) RecordTracking(ctx context.Context, events []entity.TrackingEvent, changes []entity.ShipmentStatusChange) (int, error) {
+	byShipment := make(map[uint][]entity.ShipmentStatusChange)
+	ids := make([]uint, 0)
+	for _, change := range changes {
+		if _, ok := byShipment[change.ShipmentID]; !ok {
+			ids = append(ids, change.ShipmentID)
+		}
+		byShipment[change.ShipmentID] = append(byShipment[change.ShipmentID], change)
+	}
+	// Lock rows in id order so concurrent batches cannot deadlock
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var updated int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(events) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(events, 100).Error; err != nil {
+				return fmt.Errorf("failed to store tracking events: %w", err)
+			}
+		}
+
+		for _, id := range ids {
+			var shipment entity.Shipment
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&shipment).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			initial := shipment.Status
+			for _, change := range byShipment[id] {
+				if !entity.CanTransitionShipment(shipment.Status, change.Status) {
+					continue
+				}
+				if err := transitionShipment(tx, &shipment, change.Status, change.At); err != nil {
+					return err
+				}
+			}
+			if shipment.Status != initial {
+				updated++
+			}
+		}
+		return nil
+	})
+	return updated, err
 }
 
-func (r *shipmentRepository) UpdateStatus(ctx context.Context, id uint, status string) error {
-	return r.db.WithContext(ctx).Model(&entity.Shipment{}).Where("id = ?", id).Update("status", status).Error
+// transitionShipment saves a locked shipment's new status and its outbox event
+func transitionShipment(tx *gorm.DB, shipment *entity.Shipment, status string, at time.Time) error {
+	previous := shipment.Status
+	updates := map[string]interface{}{"status": status}
+	if status == entity.ShipmentStatusDelivered && shipment.ActualDelivery == nil {
+		updates["actual_delivery"] = at
+		shipment.ActualDelivery = &at
+	}
+	if err := tx.Model(shipment).Updates(updates).Error; err != nil {
+		return err
+	}
+	shipment.Status = status
+	return enqueueStatusEvent(tx, shipment, previous)
+}
+
+func enqueueStatusEvent(tx *gorm.DB, shipment *entity.Shipment, previous string) error {
+	if previous == shipment.Status {
+		return nil
+	}
+
+	eventType := events.ShipmentStatusChanged
+	if shipment.Status == entity.ShipmentStatusDelivered {
+		eventType = events.ShipmentDelivered
+	}
+	event, err := events.New(eventType, "shipment", shipment.ID, events.ShipmentStatusPayload{
+		ShipmentID:     shipment.ID,
+		OrderID:        shipment.OrderID,
+		TrackingNumber: shipment.TrackingNumber,
+		Carrier:        shipment.Carrier,
+		From:           previous,
+		To:             shipment.Status,
+		ActualDelivery: shipment.ActualDelivery,
+	})
+	if err != nil {
+		return err
+	}
+	return events.Enqueue(tx, event)
 }
 
 func (r *shipmentRepository) Delete(ctx context.Context, id uint) error {
@@ -70,12 +221,32 @@ func (r *shipmentRepository) Delete(ctx context.Context, id uint) error {
 
 func (r *shipmentRepository) GetByStatus(ctx context.Context, status string) ([]entity.Shipment, error) {
 	var shipments []entity.Shipment
-	err := r.db.WithContext(ctx).Where("status = ?", status).Find(&shipments).Error
+	err := r.db.WithContext(ctx).Preload("Items").Where("status = ?", status).Find(&shipments).Error
 	return shipments, err
 }
 
 func (r *shipmentRepository) GetAll(ctx context.Context, limit, offset int) ([]entity.Shipment, error) {
 	var shipments []entity.Shipment
-	err := r.db.WithContext(ctx).Limit(limit).Offset(offset).Find(&shipments).Error
+	err := r.db.WithContext(ctx).Preload("Items").Limit(limit).Offset(offset).Find(&shipments).Error
+	return shipments, err
+}
+
+func (r *shipmentRepository) GetWithPendingLabels(ctx context.Context, limit int) ([]entity.Shipment, error) {
+	var shipments []entity.Shipment
+	err := r.db.WithContext(ctx).
+		Where("label_url = ? AND label_data IS NOT NULL", "").
+		Order("id").
+		Limit(limit).
+		Find(&shipments).Error
 	return shipments, err
-}
\ No newline at end of file
+}
+
+func (r *shipmentRepository) SetLabelURL(ctx context.Context, id uint, labelURL string) error {
+	return r.db.WithContext(ctx).Model(&entity.Shipment{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"label_url":          labelURL,
+			"label_data":         nil,
+			"label_content_type": "",
+		}).Error
+}
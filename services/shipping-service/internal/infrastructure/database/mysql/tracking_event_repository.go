@@ -20,15 +20,26 @@ func (r *trackingEventRepository) Create(ctx context.Context, event *entity.Trac
 	return r.db.WithContext(ctx).Create(event).Error
 }
 
+func (r *trackingEventRepository) GetCarrierEventIDs(ctx context.Context, carrier string, eventIDs []string) ([]string, error) {
+	var existing []string
+	if len(eventIDs) == 0 {
+		return existing, nil
+	}
+	err := r.db.WithContext(ctx).Model(&entity.TrackingEvent{}).
+		Where("carrier = ? AND carrier_event_id IN ?", carrier, eventIDs).
+		Pluck("carrier_event_id", &existing).Error
+	return existing, err
+}
+
 func (r *trackingEventRepository) GetByShipmentID(ctx context.Context, shipmentID uint) ([]entity.TrackingEvent, error) {
 	var events []entity.TrackingEvent
-	err := r.db.WithContext(ctx).Where("shipment_id = ?", shipmentID).Order("timestamp ASC").Find(&events).Error
+	err := r.db.WithContext(ctx).Where("shipment_id = ?", shipmentID).Order("timestamp ASC, id ASC").Find(&events).Error
 	return events, err
 }
 
 func (r *trackingEventRepository) GetLatestByShipmentID(ctx context.Context, shipmentID uint) (*entity.TrackingEvent, error) {
 	var event entity.TrackingEvent
-	err := r.db.WithContext(ctx).Where("shipment_id = ?", shipmentID).Order("timestamp DESC").First(&event).Error
+	err := r.db.WithContext(ctx).Where("shipment_id = ?", shipmentID).Order("timestamp DESC, id DESC").First(&event).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("tracking event not found")
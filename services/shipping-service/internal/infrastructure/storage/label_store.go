@@ -0,0 +1,40 @@
+// Package storage stores shipping labels in storage-service
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/apiclient"
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/config"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain"
+)
+
+// LabelStore uploads labels to storage-service, which serves them publicly
+type LabelStore struct {
+	api *apiclient.Client
+}
+
+func NewLabelStore(cfg config.Config) domain.LabelStore {
+	return &LabelStore{
+		api: apiclient.New("storage-service", cfg.Services.StorageService.URL, cfg.Services.StorageService.Timeout),
+	}
+}
+
+// uploaded is the part of storage-service's upload response that is used
+type uploaded struct {
+	URL string `json:"url"`
+}
+
+// Save uploads a label and returns its absolute URL. The upload is made with
+// the "system" role since no end user is behind it.
+func (s *LabelStore) Save(ctx context.Context, fileName, contentType string, data []byte) (string, error) {
+	var file uploaded
+	if err := s.api.Upload(ctx, "/api/v1/upload/shipping-label", apiclient.System, fileName, contentType, data, &file); err != nil {
+		return "", err
+	}
+	if file.URL == "" {
+		return "", errors.New("storage-service returned no URL for the label")
+	}
+	return s.api.URL(file.URL), nil
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain/entity"
+)
+
+// defaultParcel is booked when a shipment is created without parcel details
+var defaultParcel = entity.Parcel{WeightGrams: 500}
+
+// QuoteRates asks every carrier, or only the named one, for rates to ship a
+// parcel and returns the options cheapest first
+func (uc *ShippingUseCase) QuoteRates(ctx context.Context, req *entity.QuoteRequest) ([]entity.RateQuote, error) {
+	carriers := make([]domain.Carrier, 0, len(uc.carriers))
+	if req.Carrier != "" {
+		carrier, err := uc.carrier(req.Carrier)
+		if err != nil {
+			return nil, err
+		}
+		carriers = append(carriers, carrier)
+	} else {
+		for _, carrier := range uc.carriers {
+			carriers = append(carriers, carrier)
+		}
+	}
+
+	quotes := []entity.RateQuote{}
+	for _, carrier := range carriers {
+		carrierQuotes, err := carrier.Quote(ctx, *req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rates from %s: %w", carrier.Name(), err)
+		}
+		quotes = append(quotes, carrierQuotes...)
+	}
+
+	sort.SliceStable(quotes, func(i, j int) bool {
+		if quotes[i].Amount.Amount != quotes[j].Amount.Amount {
+			return quotes[i].Amount.Amount < quotes[j].Amount.Amount
+		}
+		return quotes[i].EstimatedDays < quotes[j].EstimatedDays
+	})
+	return quotes, nil
+}
+
+// CancelShipment cancels a shipment that has not been picked up yet and voids
+// its booking with the carrier
+func (uc *ShippingUseCase) CancelShipment(ctx context.Context, id uint) (*entity.Shipment, error) {
+	shipment, err := uc.shipmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !entity.CanTransitionShipment(shipment.Status, entity.ShipmentStatusCancelled) {
+		return nil, fmt.Errorf("%w: %s to %s", domain.ErrInvalidStatusTransition, shipment.Status, entity.ShipmentStatusCancelled)
+	}
+
+	// Shipments booked before carriers were integrated have nothing to void
+	if carrier, ok := uc.carriers[shipment.Carrier]; ok {
+		if err := carrier.Cancel(ctx, shipment.TrackingNumber); err != nil {
+			return nil, fmt.Errorf("failed to cancel booking with %s: %w", carrier.Name(), err)
+		}
+	}
+
+	now := time.Now()
+	shipment, err = uc.shipmentRepo.UpdateStatus(ctx, id, entity.ShipmentStatusCancelled, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel shipment: %w", err)
+	}
+
+	event := &entity.TrackingEvent{
+		ShipmentID:  shipment.ID,
+		EventType:   "cancelled",
+		Description: "Shipment cancelled",
+		Timestamp:   now,
+	}
+	if err := uc.trackingRepo.Create(ctx, event); err != nil {
+		return nil, fmt.Errorf("failed to create tracking event: %w", err)
+	}
+
+	return shipment, nil
+}
+
+// SyncCarrierTracking pulls the carrier's scans for a shipment and records those
+// newer than the latest tracking event, moving the shipment along with them
+func (uc *ShippingUseCase) SyncCarrierTracking(ctx context.Context, id uint) (*entity.Shipment, error) {
+	shipment, err := uc.shipmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	carrier, err := uc.carrier(shipment.Carrier)
+	if err != nil {
+		return nil, err
+	}
+
+	scans, err := carrier.Track(ctx, shipment.TrackingNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to track shipment with %s: %w", carrier.Name(), err)
+	}
+
+	recorded, err := uc.trackingRepo.GetByShipmentID(ctx, shipment.ID)
+	if err != nil {
+		return nil, err
+	}
+	var latest time.Time
+	for _, event := range recorded {
+		if event.Timestamp.After(latest) {
+			latest = event.Timestamp
+		}
+	}
+
+	sort.SliceStable(scans, func(i, j int) bool { return scans[i].Timestamp.Before(scans[j].Timestamp) })
+	for _, scan := range scans {
+		if !scan.Timestamp.After(latest) {
+			continue
+		}
+		err := uc.CreateTrackingEvent(ctx, &entity.TrackingEventCreateRequest{
+			ShipmentID:  shipment.ID,
+			EventType:   scan.EventType,
+			Location:    scan.Location,
+			Description: scan.Description,
+			Timestamp:   scan.Timestamp,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return uc.shipmentRepo.GetByID(ctx, shipment.ID)
+}
+
+// carrier returns the named carrier, or the default one when name is empty
+func (uc *ShippingUseCase) carrier(name string) (domain.Carrier, error) {
+	if name == "" {
+		name = uc.defaultCarrier
+	}
+	carrier, ok := uc.carriers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", domain.ErrCarrierNotFound, name)
+	}
+	return carrier, nil
+}
+
+// cancelBooking voids a booking that will not be used. Failures are only logged
+// because the caller is already returning an error.
+func (uc *ShippingUseCase) cancelBooking(ctx context.Context, carrier domain.Carrier, trackingNumber string) {
+	if err := carrier.Cancel(context.WithoutCancel(ctx), trackingNumber); err != nil {
+		log.Printf("failed to cancel %s booking %s: %v", carrier.Name(), trackingNumber, err)
+	}
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/events"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain/entity"
+)
+
+// GetOrderFulfilment rolls up an order's shipments into per-item quantities and
+// a fulfilment status
+func (uc *ShippingUseCase) GetOrderFulfilment(ctx context.Context, orderID uint) (*entity.OrderFulfilment, error) {
+	lines, err := uc.orders.GetOrderLines(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", orderID, err)
+	}
+	shipments, err := uc.shipmentRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return entity.RollupFulfilment(orderID, lines, shipments), nil
+}
+
+// SyncOrderFulfilment handles shipment status events from the outbox by
+// recording the order's new fulfilment status in order-service. Returning the
+// error makes the relay retry the event.
+func (uc *ShippingUseCase) SyncOrderFulfilment(ctx context.Context, event events.Event) error {
+	var payload events.ShipmentStatusPayload
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+	err := uc.publishFulfilment(ctx, payload.OrderID)
+	if errors.Is(err, domain.ErrOrderNotFound) {
+		// Retrying cannot help an order that no longer exists
+		log.Printf("skipping fulfilment of unknown order %d for shipment %d", payload.OrderID, payload.ShipmentID)
+		return nil
+	}
+	return err
+}
+
+func (uc *ShippingUseCase) publishFulfilment(ctx context.Context, orderID uint) error {
+	fulfilment, err := uc.GetOrderFulfilment(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	return uc.orders.UpdateFulfilmentStatus(ctx, orderID, fulfilment.Status)
+}
+
+// shipmentItems resolves the items of a new shipment against the order. Without
+// requested items the shipment takes everything not yet in another shipment.
+func shipmentItems(requested []entity.ShipmentItemRequest, lines []entity.OrderLine, existing []entity.Shipment) ([]entity.ShipmentItem, error) {
+	remaining := entity.RemainingToShip(lines, existing)
+
+	var items []entity.ShipmentItem
+	if len(requested) == 0 {
+		for _, line := range lines {
+			if remaining[line.OrderItemID] > 0 {
+				items = append(items, entity.ShipmentItem{OrderItemID: line.OrderItemID, Quantity: remaining[line.OrderItemID]})
+			}
+		}
+		if len(items) == 0 {
+			return nil, fmt.Errorf("%w: every item is already in a shipment", domain.ErrNothingToShip)
+		}
+		return items, nil
+	}
+
+	// The same item may be listed more than once
+	position := make(map[uint]int, len(requested))
+	for _, req := range requested {
+		if _, ok := remaining[req.OrderItemID]; !ok {
+			return nil, fmt.Errorf("%w: %d", domain.ErrOrderItemNotFound, req.OrderItemID)
+		}
+		if i, ok := position[req.OrderItemID]; ok {
+			items[i].Quantity += req.Quantity
+			continue
+		}
+		position[req.OrderItemID] = len(items)
+		items = append(items, entity.ShipmentItem{OrderItemID: req.OrderItemID, Quantity: req.Quantity})
+	}
+	for _, item := range items {
+		if item.Quantity > remaining[item.OrderItemID] {
+			return nil, fmt.Errorf("%w: order item %d has %d left", domain.ErrNothingToShip, item.OrderItemID, max(remaining[item.OrderItemID], 0))
+		}
+	}
+	return items, nil
+}
+
+// returnItems resolves the items of a return shipment, which may bring back at
+// most what was ordered of each item and is not in an earlier return
+func returnItems(requested []entity.ShipmentItemRequest, lines []entity.OrderLine, existing []entity.Shipment) ([]entity.ShipmentItem, error) {
+	if len(requested) == 0 {
+		return nil, domain.ErrReturnItemsRequired
+	}
+	remaining := entity.RemainingToReturn(lines, existing)
+
+	quantities := make(map[uint]int, len(requested))
+	items := make([]entity.ShipmentItem, 0, len(requested))
+	for _, req := range requested {
+		if _, ok := remaining[req.OrderItemID]; !ok {
+			return nil, fmt.Errorf("%w: %d", domain.ErrOrderItemNotFound, req.OrderItemID)
+		}
+		if quantities[req.OrderItemID] == 0 {
+			items = append(items, entity.ShipmentItem{OrderItemID: req.OrderItemID})
+		}
+		quantities[req.OrderItemID] += req.Quantity
+	}
+	for i := range items {
+		items[i].Quantity = quantities[items[i].OrderItemID]
+		if items[i].Quantity > remaining[items[i].OrderItemID] {
+			return nil, fmt.Errorf("%w: order item %d has %d left to return", domain.ErrReturnQuantityExceeded, items[i].OrderItemID, max(remaining[items[i].OrderItemID], 0))
+		}
+	}
+	return items, nil
+}
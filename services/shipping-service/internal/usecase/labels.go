@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// pendingLabelBatch is how many pending labels RetryPendingLabels uploads per run
+const pendingLabelBatch = 50
+
+// labelFileName is the name a shipment's label is stored under
+func labelFileName(trackingNumber, format string) string {
+	return fmt.Sprintf("%s.%s", trackingNumber, format)
+}
+
+// RetryPendingLabels stores the labels that could not be stored when their
+// shipment was created and fills in the shipments' label URLs. It returns how
+// many were stored.
+func (uc *ShippingUseCase) RetryPendingLabels(ctx context.Context) (int, error) {
+	shipments, err := uc.shipmentRepo.GetWithPendingLabels(ctx, pendingLabelBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	stored := 0
+	for _, shipment := range shipments {
+		labelURL, err := uc.labelStore.Save(ctx, labelFileName(shipment.TrackingNumber, shipment.LabelFormat), shipment.LabelContentType, shipment.LabelData)
+		if err != nil {
+			// storage-service is most likely still down, so the rest can wait for the next run
+			return stored, fmt.Errorf("shipment %d: %w", shipment.ID, err)
+		}
+		if err := uc.shipmentRepo.SetLabelURL(ctx, shipment.ID, labelURL); err != nil {
+			return stored, fmt.Errorf("shipment %d: %w", shipment.ID, err)
+		}
+		stored++
+	}
+	return stored, nil
+}
+
+// RunLabelRetries retries pending labels every interval until ctx is cancelled
+func (uc *ShippingUseCase) RunLabelRetries(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stored, err := uc.RetryPendingLabels(ctx)
+			if stored > 0 {
+				log.Printf("stored %d pending shipping labels", stored)
+			}
+			if err != nil {
+				log.Printf("retrying pending shipping labels failed: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain/entity"
+)
+
+// maxPostalCodeFailures is how many wrong postal codes a tracking number takes
+// within PostalCodeFailureWindow before guesses are turned away
+const maxPostalCodeFailures = 5
+
+// PostalCodeFailureWindow is how long wrong postal codes are counted for
+const PostalCodeFailureWindow = 15 * time.Minute
+
+// TrackShipment builds the public tracking timeline for a tracking number.
+// postalCode is the second factor: when it matches the shipment's destination
+// the event locations, descriptions and delivery time are included. Shipments
+// stored without a destination postal code can only be tracked in summary.
+// After maxPostalCodeFailures wrong postal codes for a tracking number within
+// PostalCodeFailureWindow, postal codes for it are turned away with
+// ErrTooManyAttempts until the window has passed.
+func (uc *ShippingUseCase) TrackShipment(ctx context.Context, trackingNumber, postalCode string) (*entity.PublicTracking, error) {
+	now := time.Now()
+	if postalCode != "" && uc.postalCodeFailures.blocked(trackingNumber, now) {
+		return nil, domain.ErrTooManyAttempts
+	}
+
+	shipment, err := uc.shipmentRepo.GetByTrackingNumber(ctx, trackingNumber)
+	if err != nil {
+		return nil, err
+	}
+	events, err := uc.trackingRepo.GetByShipmentID(ctx, shipment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	verified := postalCodesMatch(shipment.DestinationPostalCode, postalCode)
+	if postalCode != "" && !verified {
+		uc.postalCodeFailures.record(trackingNumber, now)
+	}
+	tracking := &entity.PublicTracking{
+		TrackingNumber:    shipment.TrackingNumber,
+		Carrier:           shipment.Carrier,
+		Status:            shipment.Status,
+		EstimatedDelivery: shipment.EstimatedDelivery,
+		Verified:          verified,
+		Events:            make([]entity.PublicTrackingEvent, 0, len(events)),
+	}
+	if verified {
+		tracking.DeliveredAt = shipment.ActualDelivery
+	}
+	for _, event := range events {
+		public := entity.PublicTrackingEvent{
+			EventType: event.EventType,
+			Timestamp: event.Timestamp,
+		}
+		if verified {
+			public.Location = event.Location
+			public.Description = event.Description
+		}
+		tracking.Events = append(tracking.Events, public)
+	}
+	return tracking, nil
+}
+
+// postalCodesMatch compares postal codes ignoring case and spaces, in constant
+// time so the comparison does not hint at how much of a guess was right
+func postalCodesMatch(stored, given string) bool {
+	normalize := func(code string) string {
+		return strings.ToUpper(strings.Join(strings.Fields(code), ""))
+	}
+	stored, given = normalize(stored), normalize(given)
+	if stored == "" || given == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(stored), []byte(given)) == 1
+}
+
+// failureCount is the wrong guesses for one tracking number in the current window
+type failureCount struct {
+	count int
+	since time.Time
+}
+
+// failureLimiter counts wrong postal codes per tracking number. It is kept in
+// memory, like the rate limit, so it applies per service instance.
+type failureLimiter struct {
+	mu        sync.Mutex
+	failures  map[string]*failureCount
+	lastSweep time.Time
+}
+
+func newFailureLimiter() *failureLimiter {
+	return &failureLimiter{failures: make(map[string]*failureCount)}
+}
+
+// blocked reports whether the tracking number has used up its guesses
+func (l *failureLimiter) blocked(trackingNumber string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	failure, ok := l.failures[trackingNumber]
+	return ok && now.Sub(failure.since) < PostalCodeFailureWindow && failure.count >= maxPostalCodeFailures
+}
+
+// record counts a wrong guess, starting a new window when the last one has passed
+func (l *failureLimiter) record(trackingNumber string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > PostalCodeFailureWindow {
+		for key, failure := range l.failures {
+			if now.Sub(failure.since) >= PostalCodeFailureWindow {
+				delete(l.failures, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	failure, ok := l.failures[trackingNumber]
+	if !ok || now.Sub(failure.since) >= PostalCodeFailureWindow {
+		failure = &failureCount{since: now}
+		l.failures[trackingNumber] = failure
+	}
+	failure.count++
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"time"
 	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain"
 	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain/entity"
@@ -12,57 +13,152 @@ import (
 type ShippingUseCase struct {
 	shipmentRepo   domain.ShipmentRepository
 	trackingRepo   domain.TrackingEventRepository
+	carriers       map[string]domain.Carrier
+	defaultCarrier string
+	labelStore     domain.LabelStore
+	orders         domain.OrderService
+	// postalCodeFailures limits postal code guesses on public tracking
+	postalCodeFailures *failureLimiter
 }
 
+// NewShippingUseCase creates the shipping use case. The first carrier is the
+// default for shipments that do not name one.
 func NewShippingUseCase(
 	shipmentRepo domain.ShipmentRepository,
 	trackingRepo domain.TrackingEventRepository,
+	carriers []domain.Carrier,
+	labelStore domain.LabelStore,
+	orders domain.OrderService,
 ) *ShippingUseCase {
-	return &ShippingUseCase{
+	uc := &ShippingUseCase{
 		shipmentRepo:   shipmentRepo,
 		trackingRepo:   trackingRepo,
+		carriers:       make(map[string]domain.Carrier, len(carriers)),
+		labelStore:     labelStore,
+		orders:         orders,
+		postalCodeFailures: newFailureLimiter(),
 	}
+	for _, carrier := range carriers {
+		uc.carriers[carrier.Name()] = carrier
+	}
+	if len(carriers) > 0 {
+		uc.defaultCarrier = carriers[0].Name()
+	}
+	return uc
 }
 
+// CreateShipment books the shipment with its carrier, stores the label the
+// carrier produced and records the shipment as label_created. An order may be
+// split over any number of shipments; the items of each are checked against
+// what is left to ship on the order. Return shipments are checked against
+// what is left to return. A booking whose shipment cannot be saved is cancelled
+// with the carrier again. A label that cannot be stored does not hold the
+// shipment up: it is kept with the shipment until RetryPendingLabels stores it.
 func (uc *ShippingUseCase) CreateShipment(ctx context.Context, req *entity.ShipmentCreateRequest) (*entity.Shipment, error) {
-	// Check if a shipment already exists for this order
+	lines, err := uc.orders.GetOrderLines(ctx, req.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", req.OrderID, err)
+	}
+	isReturn := req.Type == entity.ShipmentTypeReturn
+
 	existing, err := uc.shipmentRepo.GetByOrderID(ctx, req.OrderID)
-	if err == nil && existing != nil {
-		return nil, errors.New("shipment already exists for this order")
+	if err != nil {
+		return nil, err
+	}
+	var items []entity.ShipmentItem
+	if isReturn {
+		items, err = returnItems(req.Items, lines, existing)
+	} else {
+		items, err = shipmentItems(req.Items, lines, existing)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	carrier, err := uc.carrier(req.Carrier)
+	if err != nil {
+		return nil, err
+	}
+	parcel := defaultParcel
+	if req.Parcel != nil {
+		parcel = *req.Parcel
+	}
+
+	label, err := carrier.CreateLabel(ctx, entity.LabelRequest{
+		OrderID:      req.OrderID,
+		ServiceLevel: req.ShippingMethod,
+		Destination:  req.Destination,
+		Parcel:       parcel,
+		Format:       req.LabelFormat,
+		Return:       isReturn,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to book shipment with %s: %w", carrier.Name(), err)
+	}
+
+	labelURL, labelErr := uc.labelStore.Save(ctx, labelFileName(label.TrackingNumber, label.Format), label.ContentType, label.Data)
+	if labelErr != nil {
+		log.Printf("failed to store shipping label %s, will retry: %v", label.TrackingNumber, labelErr)
 	}
 
-	// Generate tracking number if not provided
-	trackingNumber := req.Carrier + fmt.Sprintf("%d", time.Now().Unix())
-	if req.Carrier == "" {
-		trackingNumber = fmt.Sprintf("SHIP-%d", time.Now().Unix())
+	estimatedDelivery := req.EstimatedDelivery
+	if estimatedDelivery == nil && !label.EstimatedDelivery.IsZero() {
+		estimatedDelivery = &label.EstimatedDelivery
 	}
 
 	shipment := &entity.Shipment{
-		OrderID:           req.OrderID,
-		TrackingNumber:    trackingNumber,
-		Carrier:           req.Carrier,
-		ShippingMethod:    req.ShippingMethod,
-		Status:            "pending",
-		EstimatedDelivery: req.EstimatedDelivery,
+		OrderID:               req.OrderID,
+		TrackingNumber:        label.TrackingNumber,
+		Carrier:               carrier.Name(),
+		ShippingMethod:        label.ServiceLevel,
+		Type:                  entity.ShipmentTypeOutbound,
+		Status:                entity.ShipmentStatusLabelCreated,
+		EstimatedDelivery:     estimatedDelivery,
+		DestinationPostalCode: req.Destination.PostalCode,
+		WeightGrams:           parcel.WeightGrams,
+		ShippingCost:          label.Amount,
+		LabelURL:              labelURL,
+		LabelFormat:           label.Format,
+		Items:                 items,
+	}
+	if labelErr != nil {
+		shipment.LabelData = label.Data
+		shipment.LabelContentType = label.ContentType
 	}
 
-	err = uc.shipmentRepo.Create(ctx, shipment)
+	if isReturn {
+		shipment.Type = entity.ShipmentTypeReturn
+		err = uc.shipmentRepo.Create(ctx, shipment)
+	} else {
+		err = uc.shipmentRepo.CreateForOrder(ctx, shipment, lines)
+	}
 	if err != nil {
+		uc.cancelBooking(ctx, carrier, label.TrackingNumber)
 		return nil, fmt.Errorf("failed to create shipment: %w", err)
 	}
 
-	// Create initial tracking event
-	initialEvent := &entity.TrackingEvent{
-		ShipmentID:  shipment.ID,
-		EventType:   "created",
-		Location:    "Origin Facility",
-		Description: "Shipment created and awaiting processing",
-		Timestamp:   time.Now(),
+	// Create initial tracking events
+	now := time.Now()
+	initialEvents := []*entity.TrackingEvent{
+		{
+			ShipmentID:  shipment.ID,
+			EventType:   "created",
+			Location:    "Origin Facility",
+			Description: "Shipment created and awaiting processing",
+			Timestamp:   now,
+		},
+		{
+			ShipmentID:  shipment.ID,
+			EventType:   "label_created",
+			Location:    "Origin Facility",
+			Description: fmt.Sprintf("Shipping label created with %s", carrier.Name()),
+			Timestamp:   now,
+		},
 	}
-	
-	err = uc.trackingRepo.Create(ctx, initialEvent)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create initial tracking event: %w", err)
+	for _, event := range initialEvents {
+		if err := uc.trackingRepo.Create(ctx, event); err != nil {
+			return nil, fmt.Errorf("failed to create initial tracking event: %w", err)
+		}
 	}
 
 	return shipment, nil
@@ -72,7 +168,7 @@ func (uc *ShippingUseCase) GetShipment(ctx context.Context, id uint) (*entity.Sh
 	return uc.shipmentRepo.GetByID(ctx, id)
 }
 
-func (uc *ShippingUseCase) GetShipmentByOrderID(ctx context.Context, orderID uint) (*entity.Shipment, error) {
+func (uc *ShippingUseCase) GetShipmentsByOrderID(ctx context.Context, orderID uint) ([]entity.Shipment, error) {
 	return uc.shipmentRepo.GetByOrderID(ctx, orderID)
 }
 
@@ -97,6 +193,9 @@ func (uc *ShippingUseCase) UpdateShipment(ctx context.Context, id uint, req *ent
 		shipment.ShippingMethod = *req.ShippingMethod
 	}
 	if req.Status != nil {
+		if !entity.IsValidShipmentStatus(*req.Status) {
+			return nil, errors.New("invalid shipment status")
+		}
 		shipment.Status = *req.Status
 	}
 	if req.EstimatedDelivery != nil {
@@ -105,6 +204,10 @@ func (uc *ShippingUseCase) UpdateShipment(ctx context.Context, id uint, req *ent
 	if req.ActualDelivery != nil {
 		shipment.ActualDelivery = req.ActualDelivery
 	}
+	if shipment.Status == entity.ShipmentStatusDelivered && shipment.ActualDelivery == nil {
+		now := time.Now()
+		shipment.ActualDelivery = &now
+	}
 
 	err = uc.shipmentRepo.Update(ctx, shipment)
 	if err != nil {
@@ -114,28 +217,36 @@ func (uc *ShippingUseCase) UpdateShipment(ctx context.Context, id uint, req *ent
 	return shipment, nil
 }
 
-func (uc *ShippingUseCase) UpdateShipmentStatus(ctx context.Context, id uint, status string) error {
-	// Validate status
-	validStatuses := map[string]bool{
-		"pending":      true,
-		"in_transit":   true,
-		"delivered":    true,
-		"returned":     true,
-	}
-	if !validStatuses[status] {
-		return errors.New("invalid shipment status")
+// UpdateShipmentStatus moves a shipment to a new status. Only the transitions
+// allowed by the shipment state machine are accepted.
+func (uc *ShippingUseCase) UpdateShipmentStatus(ctx context.Context, id uint, status string) (*entity.Shipment, error) {
+	if !entity.IsValidShipmentStatus(status) {
+		return nil, errors.New("invalid shipment status")
 	}
 
-	err := uc.shipmentRepo.UpdateStatus(ctx, id, status)
+	shipment, err := uc.shipmentRepo.UpdateStatus(ctx, id, status, time.Now())
 	if err != nil {
-		return fmt.Errorf("failed to update shipment status: %w", err)
+		return nil, fmt.Errorf("failed to update shipment status: %w", err)
 	}
 
-	return nil
+	return shipment, nil
 }
 
+// DeleteShipment deletes a shipment and its items. Deleting writes no status
+// event, so the order's fulfilment is refreshed here; a failure to reach
+// order-service is logged rather than undoing the delete.
 func (uc *ShippingUseCase) DeleteShipment(ctx context.Context, id uint) error {
-	return uc.shipmentRepo.Delete(ctx, id)
+	shipment, err := uc.shipmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := uc.shipmentRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := uc.publishFulfilment(ctx, shipment.OrderID); err != nil {
+		log.Printf("failed to update fulfilment of order %d after deleting shipment %d: %v", shipment.OrderID, id, err)
+	}
+	return nil
 }
 
 func (uc *ShippingUseCase) GetShipmentsByStatus(ctx context.Context, status string) ([]entity.Shipment, error) {
@@ -146,7 +257,15 @@ func (uc *ShippingUseCase) GetShipments(ctx context.Context, limit, offset int)
 	return uc.shipmentRepo.GetAll(ctx, limit, offset)
 }
 
+// CreateTrackingEvent records a tracking event. Events of a known type move the
+// shipment to the matching status; events that arrive late or out of order are
+// kept in the history but leave the status alone.
 func (uc *ShippingUseCase) CreateTrackingEvent(ctx context.Context, req *entity.TrackingEventCreateRequest) error {
+	shipment, err := uc.shipmentRepo.GetByID(ctx, req.ShipmentID)
+	if err != nil {
+		return err
+	}
+
 	event := &entity.TrackingEvent{
 		ShipmentID:  req.ShipmentID,
 		EventType:   req.EventType,
@@ -159,11 +278,25 @@ func (uc *ShippingUseCase) CreateTrackingEvent(ctx context.Context, req *entity.
 		event.Timestamp = time.Now()
 	}
 
-	err := uc.trackingRepo.Create(ctx, event)
+	err = uc.trackingRepo.Create(ctx, event)
 	if err != nil {
 		return fmt.Errorf("failed to create tracking event: %w", err)
 	}
 
+	status, ok := entity.StatusForTrackingEvent(event.EventType)
+	if !ok || !entity.CanTransitionShipment(shipment.Status, status) {
+		return nil
+	}
+	_, err = uc.shipmentRepo.UpdateStatus(ctx, shipment.ID, status, event.Timestamp)
+	if errors.Is(err, domain.ErrInvalidStatusTransition) {
+		// Another event moved the shipment on since it was read
+		log.Printf("tracking event %d left shipment %d unchanged: %v", event.ID, shipment.ID, err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply tracking event: %w", err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain"
+	"github.com/DurgaPratapRajbhar/e-commerce/shipping-service/internal/domain/entity"
+)
+
+// maxEventTypeLength is the size of the tracking_events.event_type column
+const maxEventTypeLength = 50
+
+type TrackingWebhookUseCase struct {
+	shipmentRepo domain.ShipmentRepository
+	trackingRepo domain.TrackingEventRepository
+	carriers     map[string]domain.Carrier
+	secrets      map[string]string
+	tolerance    time.Duration
+	now          func() time.Time
+}
+
+// NewTrackingWebhookUseCase creates a carrier webhook processor. secrets holds
+// the signing secret per carrier; carriers without one are rejected.
+func NewTrackingWebhookUseCase(
+	shipmentRepo domain.ShipmentRepository,
+	trackingRepo domain.TrackingEventRepository,
+	carriers []domain.Carrier,
+	secrets map[string]string,
+	tolerance time.Duration,
+) *TrackingWebhookUseCase {
+	uc := &TrackingWebhookUseCase{
+		shipmentRepo: shipmentRepo,
+		trackingRepo: trackingRepo,
+		carriers:     make(map[string]domain.Carrier, len(carriers)),
+		secrets:      secrets,
+		tolerance:    tolerance,
+		now:          time.Now,
+	}
+	for _, carrier := range carriers {
+		uc.carriers[carrier.Name()] = carrier
+	}
+	return uc
+}
+
+// HandleWebhook verifies a carrier's batch of tracking updates, stores the
+// events not seen before and moves their shipments along in one transaction.
+// Events for unknown tracking numbers are reported back as unmatched.
+func (uc *TrackingWebhookUseCase) HandleWebhook(ctx context.Context, carrierName, signature, timestamp string, body []byte) (*entity.TrackingWebhookResult, error) {
+	carrierName = strings.ToLower(carrierName)
+	carrier, ok := uc.carriers[carrierName]
+	secret := uc.secrets[carrierName]
+	if !ok || secret == "" {
+		return nil, fmt.Errorf("%w: %s", domain.ErrCarrierNotFound, carrierName)
+	}
+	if err := utils.VerifyWebhookSignature(secret, signature, timestamp, body, uc.tolerance, uc.now()); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidSignature, err)
+	}
+
+	var webhook entity.CarrierWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidWebhook, err)
+	}
+	if len(webhook.Events) == 0 || len(webhook.Events) > entity.MaxWebhookEvents {
+		return nil, fmt.Errorf("%w: between 1 and %d events are required", domain.ErrInvalidWebhook, entity.MaxWebhookEvents)
+	}
+	for i, event := range webhook.Events {
+		if event.EventID == "" || event.TrackingNumber == "" || event.Code == "" {
+			return nil, fmt.Errorf("%w: event %d needs event_id, tracking_number and code", domain.ErrInvalidWebhook, i)
+		}
+	}
+
+	result := &entity.TrackingWebhookResult{Received: len(webhook.Events)}
+	fresh, err := uc.dropDuplicates(ctx, carrierName, webhook.Events, result)
+	if err != nil {
+		return nil, err
+	}
+
+	shipments, err := uc.shipmentsFor(ctx, fresh)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []entity.TrackingEvent
+	var changes []entity.ShipmentStatusChange
+	for _, update := range fresh {
+		shipment, ok := shipments[update.TrackingNumber]
+		if !ok {
+			result.Unmatched = append(result.Unmatched, update.EventID)
+			continue
+		}
+
+		eventType := uc.eventType(carrier, update.Code)
+		at := update.Timestamp
+		if at.IsZero() {
+			at = uc.now()
+		}
+		eventID := update.EventID
+		events = append(events, entity.TrackingEvent{
+			ShipmentID:     shipment.ID,
+			EventType:      eventType,
+			Location:       update.Location,
+			Description:    update.Description,
+			Timestamp:      at,
+			Carrier:        carrierName,
+			CarrierEventID: &eventID,
+		})
+		if status, ok := entity.StatusForTrackingEvent(eventType); ok {
+			changes = append(changes, entity.ShipmentStatusChange{ShipmentID: shipment.ID, Status: status, At: at})
+		}
+	}
+
+	// Carriers batch by time received, not by time scanned
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].At.Before(changes[j].At) })
+	result.ShipmentsUpdated, err = uc.shipmentRepo.RecordTracking(ctx, events, changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record tracking: %w", err)
+	}
+	result.Recorded = len(events)
+	return result, nil
+}
+
+// dropDuplicates removes events already stored and repeats within the batch
+func (uc *TrackingWebhookUseCase) dropDuplicates(ctx context.Context, carrier string, events []entity.CarrierWebhookEvent, result *entity.TrackingWebhookResult) ([]entity.CarrierWebhookEvent, error) {
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.EventID
+	}
+	stored, err := uc.trackingRepo.GetCarrierEventIDs(ctx, carrier, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(events))
+	for _, id := range stored {
+		seen[id] = true
+	}
+	fresh := make([]entity.CarrierWebhookEvent, 0, len(events))
+	for _, event := range events {
+		if seen[event.EventID] {
+			result.Duplicates++
+			continue
+		}
+		seen[event.EventID] = true
+		fresh = append(fresh, event)
+	}
+	return fresh, nil
+}
+
+// shipmentsFor loads the shipments the events refer to, keyed by tracking number
+func (uc *TrackingWebhookUseCase) shipmentsFor(ctx context.Context, events []entity.CarrierWebhookEvent) (map[string]entity.Shipment, error) {
+	numbers := make([]string, 0, len(events))
+	wanted := make(map[string]bool, len(events))
+	for _, event := range events {
+		if !wanted[event.TrackingNumber] {
+			wanted[event.TrackingNumber] = true
+			numbers = append(numbers, event.TrackingNumber)
+		}
+	}
+
+	found, err := uc.shipmentRepo.GetByTrackingNumbers(ctx, numbers)
+	if err != nil {
+		return nil, err
+	}
+	shipments := make(map[string]entity.Shipment, len(found))
+	for _, shipment := range found {
+		shipments[shipment.TrackingNumber] = shipment
+	}
+	return shipments, nil
+}
+
+// eventType maps a carrier code to a tracking event type. Codes the carrier does
+// not map are accepted when they already name an event type, and are otherwise
+// stored as-is so the history is complete; they do not change the status.
+func (uc *TrackingWebhookUseCase) eventType(carrier domain.Carrier, code string) string {
+	if eventType, ok := carrier.TrackingEventType(code); ok {
+		return eventType
+	}
+	eventType := strings.ToLower(strings.TrimSpace(code))
+	if len(eventType) > maxEventTypeLength {
+		eventType = eventType[:maxEventTypeLength]
+	}
+	return eventType
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"path/filepath"
 
 	"github.com/DurgaPratapRajbhar/e-commerce/pkg/utils"
 	"github.com/DurgaPratapRajbhar/e-commerce/storage-service/internal/storage"
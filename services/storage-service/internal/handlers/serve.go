@@ -178,6 +178,26 @@ func (h *ServeHandler) ServeReceipt(c *gin.Context) {
 	c.File(fullPath)
 }
 
+// ServeShippingLabel serves shipping labels
+func (h *ServeHandler) ServeShippingLabel(c *gin.Context) {
+	filename := c.Param("filename")
+	if filename == "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, "Filename is required", nil, utils.GenerateRequestID()))
+		return
+	}
+
+	filePath := filepath.Join("shipping", "labels", filename)
+	fullPath := filepath.Join(h.storage.BasePath, filePath)
+
+	// Check if file exists
+	if !h.storage.FileExists(filePath) {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse(utils.ErrNotFound, "Shipping label not found", nil, utils.GenerateRequestID()))
+		return
+	}
+
+	c.File(fullPath)
+}
+
 // ListAllFiles lists all files
 func (h *ServeHandler) ListAllFiles(c *gin.Context) {
 	// This would return a list of all files, but for now we'll return a simple response
@@ -189,4 +189,44 @@ func (h *UploadHandler) UploadCategoryBanner(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, utils.SuccessResponse(response, "Category banner uploaded successfully", utils.GenerateRequestID()))
-}
\ No newline at end of file
+}
+
+// UploadShippingLabel handles shipping label uploads (PDF or ZPL)
+func (h *UploadHandler) UploadShippingLabel(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, "File is required", nil, utils.GenerateRequestID()))
+		return
+	}
+
+	// Validate file
+	if err := h.validator.ValidateFile(file); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, err.Error(), nil, utils.GenerateRequestID()))
+		return
+	}
+
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if ext != ".pdf" && ext != ".zpl" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse(utils.ErrInvalidInput, "Shipping labels must be .pdf or .zpl files", nil, utils.GenerateRequestID()))
+		return
+	}
+
+	// Save file
+	filePath, err := h.storage.SaveFile(file, "shipping", "labels")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse(utils.ErrInternalServer, "Failed to save file", nil, utils.GenerateRequestID()))
+		return
+	}
+
+	response := models.UploadResponse{
+		Success:      true,
+		FileName:     filepath.Base(filePath),
+		OriginalName: file.Filename,
+		FileType:     file.Header.Get("Content-Type"),
+		FileSize:     file.Size,
+		URL:          fmt.Sprintf("/static/shipping/labels/%s", filepath.Base(filePath)),
+		Message:      "Shipping label uploaded successfully",
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(response, "Shipping label uploaded successfully", utils.GenerateRequestID()))
+}
@@ -43,7 +43,7 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config) {
 	{
 		// Protected routes - requires authentication
 		protected := v1.Group("/")
-		protected.Use(middleware.AuthMiddleware()) // Your global middleware from pkg
+		protected.Use(middleware.ServiceAuthMiddleware()) // X-User-* headers set by the API gateway, or "system" for other services
 		{
 			// Upload endpoints
 			upload := protected.Group("/upload")
@@ -52,6 +52,7 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config) {
 				upload.POST("/avatar", uploadHandler.UploadAvatar)
 				upload.POST("/document", uploadHandler.UploadDocument)
 				upload.POST("/category-banner", uploadHandler.UploadCategoryBanner)
+				upload.POST("/shipping-label", uploadHandler.UploadShippingLabel)
 			}
 
 			// File management (admin/authenticated users)
@@ -81,5 +82,8 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config) {
 		// Documents (consider protecting these in production)
 		static.GET("/documents/invoices/:filename", serveHandler.ServeInvoice)
 		static.GET("/documents/receipts/:filename", serveHandler.ServeReceipt)
+
+		// Shipping labels
+		static.GET("/shipping/labels/:filename", serveHandler.ServeShippingLabel)
 	}
 }
\ No newline at end of file
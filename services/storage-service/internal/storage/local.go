@@ -56,8 +56,8 @@ func (s *LocalStorage) SaveFile(file *multipart.FileHeader, category, subcategor
 	return filepath.Join(category, subcategory, uniqueFilename), nil
 }
 
-func (s *LocalStorage) GetFile(filepath string) ([]byte, error) {
-	fullPath := filepath.Join(s.BasePath, filepath)
+func (s *LocalStorage) GetFile(path string) ([]byte, error) {
+	fullPath := filepath.Join(s.BasePath, path)
 	data, err := os.ReadFile(fullPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
@@ -65,16 +65,16 @@ func (s *LocalStorage) GetFile(filepath string) ([]byte, error) {
 	return data, nil
 }
 
-func (s *LocalStorage) DeleteFile(filepath string) error {
-	fullPath := filepath.Join(s.BasePath, filepath)
+func (s *LocalStorage) DeleteFile(path string) error {
+	fullPath := filepath.Join(s.BasePath, path)
 	if err := os.Remove(fullPath); err != nil {
 		return fmt.Errorf("failed to delete file: %w", err)
 	}
 	return nil
 }
 
-func (s *LocalStorage) FileExists(filepath string) bool {
-	fullPath := filepath.Join(s.BasePath, filepath)
+func (s *LocalStorage) FileExists(path string) bool {
+	fullPath := filepath.Join(s.BasePath, path)
 	_, err := os.Stat(fullPath)
 	return err == nil
 }